@@ -0,0 +1,133 @@
+package acceptance_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("Validating a pipeline", func() {
+	var (
+		atcBin     string
+		tmpdir     string
+		configPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		atcBin, err = gexec.Build("github.com/concourse/atc/cmd/atc")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		tmpdir, err = ioutil.TempDir("", "validate-pipeline")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		configPath = filepath.Join(tmpdir, "pipeline.yml")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpdir)
+	})
+
+	validate := func(args ...string) *gexec.Session {
+		session, err := gexec.Start(
+			exec.Command(atcBin, append([]string{"validate"}, args...)...),
+			GinkgoWriter,
+			GinkgoWriter,
+		)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		return session
+	}
+
+	Context("with a valid pipeline", func() {
+		BeforeEach(func() {
+			err := ioutil.WriteFile(configPath, []byte(`---
+resources:
+- name: some-resource
+  type: git
+  source: {uri: "git://some-resource"}
+
+jobs:
+- name: some-job
+  plan:
+  - get: some-resource
+`), 0644)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("exits successfully without needing a database", func() {
+			session := validate("-pipeline", configPath)
+
+			Eventually(session).Should(gexec.Exit(0))
+			Ω(session.Out).Should(gbytes.Say("pipeline is valid"))
+		})
+	})
+
+	Context("with a pipeline referencing an unknown resource type", func() {
+		BeforeEach(func() {
+			err := ioutil.WriteFile(configPath, []byte(`---
+resources:
+- name: some-resource
+  type: gti
+  source: {uri: "git://some-resource"}
+
+jobs:
+- name: some-job
+  plan:
+  - get: some-resource
+`), 0644)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("lists the unknown types and exits non-zero when resource types are given", func() {
+			session := validate(
+				"-pipeline", configPath,
+				"-resourceTypes", `[{"type": "git", "image": "docker:///concourse/git-resource"}]`,
+			)
+
+			Eventually(session).Should(gexec.Exit(1))
+			Ω(session.Err).Should(gbytes.Say("unknown resource type: gti"))
+		})
+
+		It("does not check resource types when none are given", func() {
+			session := validate("-pipeline", configPath)
+
+			Eventually(session).Should(gexec.Exit(0))
+		})
+	})
+
+	Context("with an invalid pipeline", func() {
+		BeforeEach(func() {
+			err := ioutil.WriteFile(configPath, []byte(`---
+jobs:
+- name: some-job
+  plan:
+  - get: some-resource
+`), 0644)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("prints the errors and exits non-zero", func() {
+			session := validate("-pipeline", configPath)
+
+			Eventually(session).Should(gexec.Exit(1))
+			Ω(session.Err).Should(gbytes.Say("refers to a resource that does not exist"))
+		})
+	})
+
+	Context("without a pipeline", func() {
+		It("exits non-zero", func() {
+			session := validate()
+
+			Eventually(session).Should(gexec.Exit(2))
+			Ω(session.Err).Should(gbytes.Say("must specify -pipeline"))
+		})
+	})
+})
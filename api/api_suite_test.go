@@ -16,9 +16,14 @@ import (
 	"github.com/concourse/atc/api"
 	"github.com/concourse/atc/api/buildserver"
 	buildfakes "github.com/concourse/atc/api/buildserver/fakes"
+	"github.com/concourse/atc/api/jobserver"
+	jobserverfakes "github.com/concourse/atc/api/jobserver/fakes"
 	pipeserverfakes "github.com/concourse/atc/api/pipes/fakes"
+	"github.com/concourse/atc/api/resourceserver"
+	resourceserverfakes "github.com/concourse/atc/api/resourceserver/fakes"
 	workerserverfakes "github.com/concourse/atc/api/workerserver/fakes"
 	authfakes "github.com/concourse/atc/auth/fakes"
+	"github.com/concourse/atc/db"
 	dbfakes "github.com/concourse/atc/db/fakes"
 	enginefakes "github.com/concourse/atc/engine/fakes"
 	workerfakes "github.com/concourse/atc/worker/fakes"
@@ -28,6 +33,7 @@ var (
 	sink *lager.ReconfigurableSink
 
 	authValidator       *authfakes.FakeValidator
+	writeAuthValidator  *authfakes.FakeValidator
 	fakeEngine          *enginefakes.FakeEngine
 	fakeWorkerClient    *workerfakes.FakeClient
 	buildsDB            *buildfakes.FakeBuildsDB
@@ -36,6 +42,9 @@ var (
 	pipeDB              *pipeserverfakes.FakePipeDB
 	pipelineDBFactory   *dbfakes.FakePipelineDBFactory
 	pipelinesDB         *dbfakes.FakePipelinesDB
+	fakeScheduler       *jobserverfakes.FakeBuildScheduler
+	fakeCacheClearer    *resourceserverfakes.FakeCacheClearer
+	fakeVersionChecker  *resourceserverfakes.FakeVersionChecker
 	configValidationErr error
 	peerAddr            string
 	drain               chan struct{}
@@ -76,8 +85,15 @@ var _ = BeforeEach(func() {
 	workerDB = new(workerserverfakes.FakeWorkerDB)
 	pipeDB = new(pipeserverfakes.FakePipeDB)
 	pipelinesDB = new(dbfakes.FakePipelinesDB)
+	fakeScheduler = new(jobserverfakes.FakeBuildScheduler)
+	fakeCacheClearer = new(resourceserverfakes.FakeCacheClearer)
+	fakeVersionChecker = new(resourceserverfakes.FakeVersionChecker)
 
 	authValidator = new(authfakes.FakeValidator)
+
+	// writes are authenticated the same as reads, unless a test says otherwise
+	writeAuthValidator = new(authfakes.FakeValidator)
+	writeAuthValidator.IsAuthenticatedStub = authValidator.IsAuthenticated
 	configValidationErr = nil
 	peerAddr = "127.0.0.1:1234"
 	drain = make(chan struct{})
@@ -100,6 +116,7 @@ var _ = BeforeEach(func() {
 	handler, err := api.NewHandler(
 		logger,
 		authValidator,
+		writeAuthValidator,
 		pipelineDBFactory,
 
 		configDB,
@@ -112,6 +129,9 @@ var _ = BeforeEach(func() {
 		func(atc.Config) error { return configValidationErr },
 		peerAddr,
 		constructedEventHandler.Construct,
+		func(db.PipelineDB) jobserver.BuildScheduler { return fakeScheduler },
+		fakeCacheClearer,
+		func(db.PipelineDB) resourceserver.VersionChecker { return fakeVersionChecker },
 		drain,
 
 		fakeEngine,
@@ -0,0 +1,116 @@
+// Package apierror gives every API error response the same JSON body:
+//
+//	{"error": "job not found", "code": "job_not_found"}
+//
+// Handlers with a more specific code than their status implies write their
+// errors with Write; anything else is converted by Handler.
+package apierror
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/concourse/atc"
+)
+
+// Write responds with the given status and an error body.
+func Write(w http.ResponseWriter, status int, code string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(atc.ErrorResponse{
+		Message: message,
+		Code:    code,
+	})
+}
+
+// CodeFor returns the code used for errors with the given status when the
+// handler doesn't give a more specific one, e.g. "not_found".
+func CodeFor(status int) string {
+	return strings.Replace(strings.ToLower(http.StatusText(status)), " ", "_", -1)
+}
+
+// Handler converts error responses written by the wrapped handler as plain
+// text (or with no body at all) into the JSON error body, using the text as
+// the message.
+type Handler struct {
+	Handler http.Handler
+}
+
+func (handler Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	writer := &errorWriter{ResponseWriter: w}
+
+	handler.Handler.ServeHTTP(writer, r)
+
+	if writer.status == 0 {
+		return
+	}
+
+	message := strings.TrimSpace(writer.body.String())
+	if message == "" {
+		message = strings.ToLower(http.StatusText(writer.status))
+	}
+
+	Write(w, writer.status, CodeFor(writer.status), message)
+}
+
+// errorWriter holds back error responses not already written as JSON, so
+// that they can be rewritten once the handler is done. Everything else is
+// passed through as-is, including flushing and hijacking for event streams
+// and hijacked processes.
+type errorWriter struct {
+	http.ResponseWriter
+
+	wroteHeader bool
+
+	// set if an error response is being held back
+	status int
+	body   bytes.Buffer
+}
+
+func (writer *errorWriter) WriteHeader(status int) {
+	if writer.wroteHeader {
+		return
+	}
+
+	writer.wroteHeader = true
+
+	if status >= 400 && writer.Header().Get("Content-Type") != "application/json" {
+		writer.status = status
+		return
+	}
+
+	writer.ResponseWriter.WriteHeader(status)
+}
+
+func (writer *errorWriter) Write(data []byte) (int, error) {
+	if !writer.wroteHeader {
+		writer.WriteHeader(http.StatusOK)
+	}
+
+	if writer.status != 0 {
+		return writer.body.Write(data)
+	}
+
+	return writer.ResponseWriter.Write(data)
+}
+
+func (writer *errorWriter) Flush() {
+	if writer.status != 0 {
+		return
+	}
+
+	writer.ResponseWriter.(http.Flusher).Flush()
+}
+
+func (writer *errorWriter) CloseNotify() <-chan bool {
+	return writer.ResponseWriter.(http.CloseNotifier).CloseNotify()
+}
+
+func (writer *errorWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return writer.ResponseWriter.(http.Hijacker).Hijack()
+}
@@ -38,6 +38,10 @@ func NewEventHandler(buildsDB BuildsDB, buildID int) http.Handler {
 			start++
 		}
 
+		// only stream events from the step with the given name, along with
+		// events about the build as a whole
+		originFilter := r.URL.Query().Get("origin")
+
 		var responseWriter io.Writer = w
 		var responseFlusher *gzip.Writer
 
@@ -82,6 +86,13 @@ func NewEventHandler(buildsDB BuildsDB, buildID int) http.Handler {
 		for {
 			select {
 			case ev := <-es:
+				if originFilter != "" && !fromOrigin(ev, originFilter) {
+					// keep event IDs consistent with the unfiltered stream, so
+					// that Last-Event-ID still resumes from the right place
+					start++
+					continue
+				}
+
 				payload, err := json.Marshal(event.Message{ev})
 				if err != nil {
 					return
@@ -123,3 +134,8 @@ func NewEventHandler(buildsDB BuildsDB, buildID int) http.Handler {
 		return
 	})
 }
+
+func fromOrigin(ev atc.Event, name string) bool {
+	origin, found := event.OriginOf(ev)
+	return !found || origin.Name == name
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/concourse/atc/api/buildserver/fakes"
 	"github.com/concourse/atc/db"
 	dbfakes "github.com/concourse/atc/db/fakes"
+	"github.com/concourse/atc/event"
 	"github.com/vito/go-sse/sse"
 
 	. "github.com/onsi/ginkgo"
@@ -151,6 +152,79 @@ var _ = Describe("Handler", func() {
 			})
 		})
 
+		Context("when filtering by origin", func() {
+			BeforeEach(func() {
+				returnedEvents := []atc.Event{
+					event.Log{
+						Origin:  event.Origin{Name: "some-input", Type: event.OriginTypeGet},
+						Payload: "fetching",
+					},
+					event.Log{
+						Origin:  event.Origin{Name: "some-task", Type: event.OriginTypeTask},
+						Payload: "building",
+					},
+					event.Status{Status: atc.StatusSucceeded, Time: 42},
+					event.FinishTask{
+						Origin:     event.Origin{Name: "some-task", Type: event.OriginTypeTask},
+						ExitStatus: 0,
+					},
+				}
+
+				buildsDB.GetBuildEventsStub = func(buildID int, from uint) (db.EventSource, error) {
+					fakeEventSource := new(dbfakes.FakeEventSource)
+
+					fakeEventSource.NextStub = func() (atc.Event, error) {
+						if from >= uint(len(returnedEvents)) {
+							return nil, db.ErrEndOfBuildEventStream
+						}
+
+						from++
+
+						return returnedEvents[from-1], nil
+					}
+
+					return fakeEventSource, nil
+				}
+
+				request.URL.RawQuery = "origin=some-task"
+			})
+
+			It("only emits events from that step, and events without an origin", func() {
+				reader := sse.NewReadCloser(response.Body)
+
+				ev, err := reader.Next()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(ev.ID).Should(Equal("1"))
+				Ω(ev.Data).Should(MatchJSON(`{
+					"event": "log",
+					"version": "3.0",
+					"data": {
+						"origin": {"name": "some-task", "type": "task", "source": "", "location": {"parent_id": 0, "id": 0, "parallel_group": 0, "hook": ""}, "hook": ""},
+						"payload": "building"
+					}
+				}`))
+
+				ev, err = reader.Next()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(ev.ID).Should(Equal("2"))
+				Ω(ev.Data).Should(MatchJSON(`{
+					"event": "status",
+					"version": "1.0",
+					"data": {"status": "succeeded", "time": 42}
+				}`))
+
+				ev, err = reader.Next()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(ev.ID).Should(Equal("3"))
+				Ω(string(ev.Data)).Should(ContainSubstring(`"event":"finish-task"`))
+
+				Ω(reader.Next()).Should(Equal(sse.Event{
+					Name: "end",
+					Data: []byte{},
+				}))
+			})
+		})
+
 		Context("when subscribing to it fails", func() {
 			BeforeEach(func() {
 				buildsDB.GetBuildEventsReturns(nil, errors.New("nope"))
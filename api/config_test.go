@@ -126,15 +126,25 @@ var _ = Describe("Config API", func() {
 
 	Describe("GET /api/v1/pipelines/:name/config", func() {
 		var (
+			ifNoneMatch string
+
 			response *http.Response
 		)
 
+		BeforeEach(func() {
+			ifNoneMatch = ""
+		})
+
 		JustBeforeEach(func() {
 			req, err := requestGenerator.CreateRequest(atc.GetConfig, rata.Params{
 				"pipeline_name": "something-else",
 			}, nil)
 			Ω(err).ShouldNot(HaveOccurred())
 
+			if ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", ifNoneMatch)
+			}
+
 			response, err = client.Do(req)
 			Ω(err).ShouldNot(HaveOccurred())
 		})
@@ -144,6 +154,17 @@ var _ = Describe("Config API", func() {
 				authValidator.IsAuthenticatedReturns(true)
 			})
 
+			Context("but not for writes", func() {
+				BeforeEach(func() {
+					writeAuthValidator.IsAuthenticatedReturns(false)
+					configDB.GetConfigReturns(config, 1, nil)
+				})
+
+				It("still returns the config", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusOK))
+				})
+			})
+
 			Context("when the config can be loaded", func() {
 				BeforeEach(func() {
 					configDB.GetConfigReturns(config, 1, nil)
@@ -169,6 +190,131 @@ var _ = Describe("Config API", func() {
 					name := configDB.GetConfigArgsForCall(0)
 					Ω(name).Should(Equal("something-else"))
 				})
+
+				It("tags the response with the config version", func() {
+					Ω(response.Header.Get("ETag")).Should(Equal(`"1"`))
+				})
+
+				Context("when the client already has the config version", func() {
+					BeforeEach(func() {
+						ifNoneMatch = `"1"`
+					})
+
+					It("returns 304 with no body", func() {
+						Ω(response.StatusCode).Should(Equal(http.StatusNotModified))
+
+						body, err := ioutil.ReadAll(response.Body)
+						Ω(err).ShouldNot(HaveOccurred())
+						Ω(body).Should(BeEmpty())
+					})
+				})
+
+				Context("when the config has changed since the client last asked", func() {
+					BeforeEach(func() {
+						ifNoneMatch = `"0"`
+					})
+
+					It("returns 200 with the new tag", func() {
+						Ω(response.StatusCode).Should(Equal(http.StatusOK))
+						Ω(response.Header.Get("ETag")).Should(Equal(`"1"`))
+					})
+				})
+			})
+
+			Context("when getting the config fails", func() {
+				BeforeEach(func() {
+					configDB.GetConfigReturns(atc.Config{}, 0, errors.New("oh no!"))
+				})
+
+				It("returns 500", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("GET /api/v1/pipelines/:name/config/effective", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			req, err := requestGenerator.CreateRequest(atc.GetEffectiveConfig, rata.Params{
+				"pipeline_name": "something-else",
+			}, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			response, err = client.Do(req)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		Context("when authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+			})
+
+			Context("when the config can be loaded", func() {
+				BeforeEach(func() {
+					configDB.GetConfigReturns(atc.Config{
+						Resources: atc.ResourceConfigs{
+							{Name: "some-resource", Type: "git"},
+						},
+						Jobs: atc.JobConfigs{
+							{
+								Name: "some-job",
+								Plan: atc.PlanSequence{
+									{Get: "some-resource", Trigger: true},
+									{Get: "some-input", Resource: "some-resource"},
+								},
+							},
+						},
+					}, 1, nil)
+				})
+
+				It("returns 200", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusOK))
+				})
+
+				It("returns the config version as X-Concourse-Config-Version", func() {
+					Ω(response.Header.Get(atc.ConfigVersionHeader)).Should(Equal("1"))
+				})
+
+				It("returns the config with its defaults applied", func() {
+					var returnedConfig atc.Config
+					err := json.NewDecoder(response.Body).Decode(&returnedConfig)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(returnedConfig.Jobs[0].Plan).Should(Equal(atc.PlanSequence{
+						{Get: "some-resource", Resource: "some-resource", Trigger: true},
+						{Get: "some-input", Resource: "some-resource"},
+					}))
+				})
+
+				It("makes get steps that do not trigger say so", func() {
+					var returnedConfig struct {
+						Jobs []struct {
+							Plan []map[string]interface{} `json:"plan"`
+						} `json:"jobs"`
+					}
+
+					err := json.NewDecoder(response.Body).Decode(&returnedConfig)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(returnedConfig.Jobs[0].Plan[0]).Should(HaveKeyWithValue("trigger", true))
+					Ω(returnedConfig.Jobs[0].Plan[1]).Should(HaveKeyWithValue("trigger", false))
+				})
+
+				It("gets the config of the requested pipeline", func() {
+					Ω(configDB.GetConfigArgsForCall(0)).Should(Equal("something-else"))
+				})
 			})
 
 			Context("when getting the config fails", func() {
@@ -19,5 +19,90 @@ func (s *Server) GetConfig(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set(atc.ConfigVersionHeader, fmt.Sprintf("%d", id))
 
+	etag := fmt.Sprintf(`"%d"`, id)
+	w.Header().Set("ETag", etag)
+
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	json.NewEncoder(w).Encode(config)
 }
+
+// GetEffectiveConfig responds with the config as the scheduler and radar act
+// on it, i.e. with its defaults made explicit.
+func (s *Server) GetEffectiveConfig(w http.ResponseWriter, r *http.Request) {
+	pipelineName := rata.Param(r, "pipeline_name")
+	config, id, err := s.db.GetConfig(pipelineName)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	effective, err := effectiveConfig(config)
+	if err != nil {
+		s.logger.Error("failed-to-render-effective-config", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(atc.ConfigVersionHeader, fmt.Sprintf("%d", id))
+
+	json.NewEncoder(w).Encode(effective)
+}
+
+// effectiveConfig renders the config with its defaults applied. Get steps'
+// triggers are omitted from the config's JSON when false, which is their
+// default, so they are added back to make them explicit too.
+func effectiveConfig(config atc.Config) (map[string]interface{}, error) {
+	payload, err := json.Marshal(config.WithDefaults())
+	if err != nil {
+		return nil, err
+	}
+
+	var effective map[string]interface{}
+	err = json.Unmarshal(payload, &effective)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, _ := effective["jobs"].([]interface{})
+	for _, job := range jobs {
+		job, ok := job.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		steps, _ := job["plan"].([]interface{})
+		for _, step := range steps {
+			addGetTriggers(step)
+		}
+	}
+
+	return effective, nil
+}
+
+func addGetTriggers(step interface{}) {
+	plan, ok := step.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if _, isGet := plan["get"]; isGet {
+		if _, found := plan["trigger"]; !found {
+			plan["trigger"] = false
+		}
+	}
+
+	for _, key := range []string{"do", "aggregate"} {
+		steps, _ := plan[key].([]interface{})
+		for _, nested := range steps {
+			addGetTriggers(nested)
+		}
+	}
+
+	for _, key := range []string{"on_failure", "ensure", "on_success", "try"} {
+		addGetTriggers(plan[key])
+	}
+}
@@ -0,0 +1,169 @@
+package api_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	dbfakes "github.com/concourse/atc/db/fakes"
+)
+
+var _ = Describe("Dashboard API", func() {
+	Describe("GET /api/v1/dashboard", func() {
+		var pipelineDB *dbfakes.FakePipelineDB
+
+		var response *http.Response
+
+		BeforeEach(func() {
+			pipelineDB = new(dbfakes.FakePipelineDB)
+			pipelineDBFactory.BuildReturns(pipelineDB)
+
+			pipelinesDB.GetAllActivePipelinesReturns([]db.SavedPipeline{
+				{
+					ID:     1,
+					Paused: true,
+					Pipeline: db.Pipeline{
+						Name: "a-pipeline",
+						Config: atc.Config{
+							Jobs: atc.JobConfigs{
+								{Name: "job-1"},
+								{Name: "job-2"},
+								{Name: "job-3"},
+							},
+							Resources: atc.ResourceConfigs{
+								{Name: "resource-1"},
+								{Name: "resource-2"},
+							},
+						},
+					},
+				},
+			}, nil)
+
+			pipelineDB.GetJobsWithStatusReturns([]db.JobStatus{
+				{
+					SavedJob: db.SavedJob{
+						Paused: true,
+						Job:    db.Job{Name: "job-1"},
+					},
+					LatestFinishedStatus: db.StatusSucceeded,
+				},
+				{
+					SavedJob: db.SavedJob{
+						Job: db.Job{Name: "job-2"},
+					},
+					LatestFinishedStatus: db.StatusFailed,
+				},
+				{
+					SavedJob: db.SavedJob{
+						Job: db.Job{Name: "job-3"},
+					},
+				},
+			}, nil)
+
+			pipelineDB.GetResourcesWithStatusReturns([]db.ResourceStatus{
+				{
+					SavedResource: db.SavedResource{
+						LastChecked: time.Unix(42, 0),
+						Resource:    db.Resource{Name: "resource-1"},
+					},
+				},
+				{
+					SavedResource: db.SavedResource{
+						Paused:     true,
+						CheckError: errors.New("nope"),
+						Resource:   db.Resource{Name: "resource-2"},
+					},
+				},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/dashboard")
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("returns 200 OK", func() {
+			Ω(response.StatusCode).Should(Equal(http.StatusOK))
+		})
+
+		It("returns application/json", func() {
+			Ω(response.Header.Get("Content-Type")).Should(Equal("application/json"))
+		})
+
+		It("builds a db for each pipeline", func() {
+			Ω(pipelineDBFactory.BuildCallCount()).Should(Equal(1))
+			Ω(pipelineDBFactory.BuildArgsForCall(0).Name).Should(Equal("a-pipeline"))
+		})
+
+		It("returns every pipeline's jobs and resources", func() {
+			body, err := ioutil.ReadAll(response.Body)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(body).Should(MatchJSON(`{
+				"pipelines": [
+					{
+						"name": "a-pipeline",
+						"paused": true,
+						"jobs": [
+							{"name": "job-1", "paused": true, "status": "succeeded"},
+							{"name": "job-2", "status": "failed"},
+							{"name": "job-3"}
+						],
+						"resources": [
+							{"name": "resource-1", "last_checked": 42},
+							{"name": "resource-2", "paused": true, "failing": true}
+						]
+					}
+				]
+			}`))
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 200 OK", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusOK))
+			})
+		})
+
+		Context("when getting the pipelines fails", func() {
+			BeforeEach(func() {
+				pipelinesDB.GetAllActivePipelinesReturns(nil, errors.New("disaster"))
+			})
+
+			It("returns 500", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when getting a pipeline's jobs fails", func() {
+			BeforeEach(func() {
+				pipelineDB.GetJobsWithStatusReturns(nil, errors.New("disaster"))
+			})
+
+			It("returns 500", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when getting a pipeline's resources fails", func() {
+			BeforeEach(func() {
+				pipelineDB.GetResourcesWithStatusReturns(nil, errors.New("disaster"))
+			})
+
+			It("returns 500", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+})
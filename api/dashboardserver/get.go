@@ -0,0 +1,52 @@
+package dashboardserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/present"
+	"github.com/pivotal-golang/lager"
+)
+
+// GetDashboard summarizes every active pipeline's jobs and resources in one
+// response, taking two queries per pipeline.
+func (s *Server) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("get-dashboard")
+
+	pipelines, err := s.pipelinesDB.GetAllActivePipelines()
+	if err != nil {
+		logger.Error("failed-to-get-pipelines", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	dashboard := atc.Dashboard{
+		Pipelines: []atc.DashboardPipeline{},
+	}
+
+	for _, pipeline := range pipelines {
+		pipelineDB := s.pipelineDBFactory.Build(pipeline)
+
+		jobs, err := pipelineDB.GetJobsWithStatus()
+		if err != nil {
+			logger.Error("failed-to-get-jobs", err, lager.Data{"pipeline": pipeline.Name})
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		resources, err := pipelineDB.GetResourcesWithStatus()
+		if err != nil {
+			logger.Error("failed-to-get-resources", err, lager.Data{"pipeline": pipeline.Name})
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		dashboard.Pipelines = append(dashboard.Pipelines, present.DashboardPipeline(pipeline, jobs, resources))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(dashboard)
+}
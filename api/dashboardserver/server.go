@@ -0,0 +1,26 @@
+package dashboardserver
+
+import (
+	"github.com/concourse/atc/db"
+	"github.com/pivotal-golang/lager"
+)
+
+type Server struct {
+	logger lager.Logger
+
+	pipelinesDB       db.PipelinesDB
+	pipelineDBFactory db.PipelineDBFactory
+}
+
+func NewServer(
+	logger lager.Logger,
+	pipelinesDB db.PipelinesDB,
+	pipelineDBFactory db.PipelineDBFactory,
+) *Server {
+	return &Server{
+		logger: logger,
+
+		pipelinesDB:       pipelinesDB,
+		pipelineDBFactory: pipelineDBFactory,
+	}
+}
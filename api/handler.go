@@ -8,9 +8,11 @@ import (
 	"github.com/tedsuo/rata"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/buildserver"
 	"github.com/concourse/atc/api/cliserver"
 	"github.com/concourse/atc/api/configserver"
+	"github.com/concourse/atc/api/dashboardserver"
 	"github.com/concourse/atc/api/hijackserver"
 	"github.com/concourse/atc/api/jobserver"
 	"github.com/concourse/atc/api/loglevelserver"
@@ -25,9 +27,13 @@ import (
 	"github.com/concourse/atc/worker"
 )
 
+// NewHandler routes the API. Routes that only look at things are guarded by
+// readValidator. Routes that change things, consume them (as reading a pipe
+// does), or run anything in a container are guarded by writeValidator.
 func NewHandler(
 	logger lager.Logger,
-	validator auth.Validator,
+	readValidator auth.Validator,
+	writeValidator auth.Validator,
 	pipelineDBFactory db.PipelineDBFactory,
 
 	configDB db.ConfigDB,
@@ -40,6 +46,9 @@ func NewHandler(
 	configValidator configserver.ConfigValidator,
 	peerURL string,
 	eventHandlerFactory buildserver.EventHandlerFactory,
+	schedulerFactory jobserver.SchedulerFactory,
+	cacheClearer resourceserver.CacheClearer,
+	versionCheckerFactory resourceserver.VersionCheckerFactory,
 	drain <-chan struct{},
 
 	engine engine.Engine,
@@ -64,7 +73,7 @@ func NewHandler(
 		configDB,
 		eventHandlerFactory,
 		drain,
-		validator,
+		readValidator,
 	)
 
 	hijackServer := hijackserver.NewServer(
@@ -72,12 +81,14 @@ func NewHandler(
 		workerClient,
 	)
 
-	jobServer := jobserver.NewServer(logger)
-	resourceServer := resourceserver.NewServer(logger, validator)
+	jobServer := jobserver.NewServer(logger, schedulerFactory)
+	resourceServer := resourceserver.NewServer(logger, readValidator, cacheClearer, versionCheckerFactory)
 	pipeServer := pipes.NewServer(logger, peerURL, pipeDB)
 
 	pipelineServer := pipelineserver.NewServer(logger, pipelinesDB)
 
+	dashboardServer := dashboardserver.NewServer(logger, pipelinesDB, pipelineDBFactory)
+
 	configServer := configserver.NewServer(logger, configDB, configValidator)
 
 	workerServer := workerserver.NewServer(logger, workerDB)
@@ -86,16 +97,24 @@ func NewHandler(
 
 	cliServer := cliserver.NewServer(logger, absCLIDownloadsDir)
 
+	validateRead := func(handler http.Handler) http.Handler {
+		return auth.Handler{
+			Handler:   handler,
+			Validator: readValidator,
+		}
+	}
+
 	validate := func(handler http.Handler) http.Handler {
 		return auth.Handler{
 			Handler:   handler,
-			Validator: validator,
+			Validator: writeValidator,
 		}
 	}
 
 	handlers := map[string]http.Handler{
-		atc.GetConfig:  validate(http.HandlerFunc(configServer.GetConfig)),
-		atc.SaveConfig: validate(http.HandlerFunc(configServer.SaveConfig)),
+		atc.GetConfig:          validateRead(http.HandlerFunc(configServer.GetConfig)),
+		atc.GetEffectiveConfig: validateRead(http.HandlerFunc(configServer.GetEffectiveConfig)),
+		atc.SaveConfig:         validate(http.HandlerFunc(configServer.SaveConfig)),
 
 		atc.Hijack: validate(http.HandlerFunc(hijackServer.Hijack)),
 
@@ -105,12 +124,15 @@ func NewHandler(
 		atc.BuildEvents: http.HandlerFunc(buildServer.BuildEvents),
 		atc.AbortBuild:  validate(http.HandlerFunc(buildServer.AbortBuild)),
 
-		atc.ListJobs:      pipelineHandlerFactory.HandlerFor(jobServer.ListJobs),
-		atc.GetJob:        pipelineHandlerFactory.HandlerFor(jobServer.GetJob),
-		atc.ListJobBuilds: pipelineHandlerFactory.HandlerFor(jobServer.ListJobBuilds),
-		atc.GetJobBuild:   pipelineHandlerFactory.HandlerFor(jobServer.GetJobBuild),
-		atc.PauseJob:      validate(pipelineHandlerFactory.HandlerFor(jobServer.PauseJob)),
-		atc.UnpauseJob:    validate(pipelineHandlerFactory.HandlerFor(jobServer.UnpauseJob)),
+		atc.ListJobs:       pipelineHandlerFactory.HandlerFor(jobServer.ListJobs),
+		atc.GetJob:         pipelineHandlerFactory.HandlerFor(jobServer.GetJob),
+		atc.ListJobBuilds:  pipelineHandlerFactory.HandlerFor(jobServer.ListJobBuilds),
+		atc.GetJobBuild:    pipelineHandlerFactory.HandlerFor(jobServer.GetJobBuild),
+		atc.CreateJobBuild: validate(pipelineHandlerFactory.HandlerFor(jobServer.CreateJobBuild)),
+		atc.GetJobBadge:    pipelineHandlerFactory.HandlerFor(jobServer.GetJobBadge),
+		atc.PauseJob:       validate(pipelineHandlerFactory.HandlerFor(jobServer.PauseJob)),
+		atc.UnpauseJob:     validate(pipelineHandlerFactory.HandlerFor(jobServer.UnpauseJob)),
+		atc.GetJobPlan:     validateRead(pipelineHandlerFactory.HandlerFor(jobServer.GetJobPlan)),
 
 		atc.ListPipelines:   http.HandlerFunc(pipelineServer.ListPipelines),
 		atc.DeletePipeline:  validate(pipelineHandlerFactory.HandlerFor(pipelineServer.DeletePipeline)),
@@ -118,17 +140,25 @@ func NewHandler(
 		atc.PausePipeline:   validate(pipelineHandlerFactory.HandlerFor(pipelineServer.PausePipeline)),
 		atc.UnpausePipeline: validate(pipelineHandlerFactory.HandlerFor(pipelineServer.UnpausePipeline)),
 
-		atc.ListResources:          pipelineHandlerFactory.HandlerFor(resourceServer.ListResources),
-		atc.EnableResourceVersion:  validate(pipelineHandlerFactory.HandlerFor(resourceServer.EnableResourceVersion)),
-		atc.DisableResourceVersion: validate(pipelineHandlerFactory.HandlerFor(resourceServer.DisableResourceVersion)),
-		atc.PauseResource:          validate(pipelineHandlerFactory.HandlerFor(resourceServer.PauseResource)),
-		atc.UnpauseResource:        validate(pipelineHandlerFactory.HandlerFor(resourceServer.UnpauseResource)),
+		atc.GetDashboard: http.HandlerFunc(dashboardServer.GetDashboard),
+
+		atc.ListResources:             pipelineHandlerFactory.HandlerFor(resourceServer.ListResources),
+		atc.ListResourceVersions:      pipelineHandlerFactory.HandlerFor(resourceServer.ListResourceVersions),
+		atc.EnableResourceVersion:     validate(pipelineHandlerFactory.HandlerFor(resourceServer.EnableResourceVersion)),
+		atc.DisableResourceVersion:    validate(pipelineHandlerFactory.HandlerFor(resourceServer.DisableResourceVersion)),
+		atc.ClearResourceVersionCache: validate(pipelineHandlerFactory.HandlerFor(resourceServer.ClearResourceVersionCache)),
+		atc.CheckResourceVersion:      validate(pipelineHandlerFactory.HandlerFor(resourceServer.CheckResourceVersionExists)),
+		atc.DebugCheckResource:        validate(pipelineHandlerFactory.HandlerFor(resourceServer.DebugCheckResource)),
+		atc.PauseResource:             validate(pipelineHandlerFactory.HandlerFor(resourceServer.PauseResource)),
+		atc.UnpauseResource:           validate(pipelineHandlerFactory.HandlerFor(resourceServer.UnpauseResource)),
 
 		atc.CreatePipe: validate(http.HandlerFunc(pipeServer.CreatePipe)),
 		atc.WritePipe:  validate(http.HandlerFunc(pipeServer.WritePipe)),
-		atc.ReadPipe:   validate(http.HandlerFunc(pipeServer.ReadPipe)),
 
-		atc.ListWorkers:    validate(http.HandlerFunc(workerServer.ListWorkers)),
+		// reading a pipe consumes what was written to it
+		atc.ReadPipe: validate(http.HandlerFunc(pipeServer.ReadPipe)),
+
+		atc.ListWorkers:    validateRead(http.HandlerFunc(workerServer.ListWorkers)),
 		atc.RegisterWorker: validate(http.HandlerFunc(workerServer.RegisterWorker)),
 
 		atc.SetLogLevel: validate(http.HandlerFunc(logLevelServer.SetMinLevel)),
@@ -137,5 +167,10 @@ func NewHandler(
 		atc.DownloadCLI: http.HandlerFunc(cliServer.Download),
 	}
 
-	return rata.NewRouter(atc.Routes, handlers)
+	router, err := rata.NewRouter(atc.Routes, handlers)
+	if err != nil {
+		return nil, err
+	}
+
+	return apierror.Handler{Handler: router}, nil
 }
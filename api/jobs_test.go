@@ -1,6 +1,7 @@
 package api_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
@@ -12,6 +13,7 @@ import (
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
 	dbfakes "github.com/concourse/atc/db/fakes"
+	"github.com/concourse/atc/scheduler"
 )
 
 var _ = Describe("Jobs API", func() {
@@ -581,6 +583,403 @@ var _ = Describe("Jobs API", func() {
 		})
 	})
 
+	Describe("POST /api/v1/pipelines/:pipeline_name/jobs/:job_name/builds", func() {
+		var requestBody string
+		var response *http.Response
+
+		BeforeEach(func() {
+			requestBody = ""
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			request, err := http.NewRequest("POST", server.URL+"/api/v1/pipelines/some-pipeline/jobs/some-job/builds", bytes.NewBufferString(requestBody))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			response, err = client.Do(request)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		Context("when authenticated", func() {
+			var someJob atc.JobConfig
+			var someResources atc.ResourceConfigs
+
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+
+				someJob = atc.JobConfig{Name: "some-job"}
+				someResources = atc.ResourceConfigs{{Name: "some-resource"}}
+
+				pipelineDB.GetConfigReturns(atc.Config{
+					Jobs:      atc.JobConfigs{someJob},
+					Resources: someResources,
+				}, 1, nil)
+
+				fakeScheduler.TriggerWithInputOverridesReturns(db.Build{
+					ID:           42,
+					Name:         "1",
+					JobName:      "some-job",
+					PipelineName: "some-pipeline",
+					Status:       db.StatusPending,
+				}, nil)
+			})
+
+			It("injects the PipelineDB", func() {
+				Ω(pipelineDBFactory.BuildWithNameCallCount()).Should(Equal(1))
+				pipelineName := pipelineDBFactory.BuildWithNameArgsForCall(0)
+				Ω(pipelineName).Should(Equal("some-pipeline"))
+			})
+
+			It("returns 201 Created", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusCreated))
+			})
+
+			It("returns the pending build", func() {
+				body, err := ioutil.ReadAll(response.Body)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(body).Should(MatchJSON(`{
+					"id": 42,
+					"name": "1",
+					"job_name": "some-job",
+					"status": "pending",
+					"url": "/pipelines/some-pipeline/jobs/some-job/builds/1"
+				}`))
+			})
+
+			It("triggers the job with no overridden inputs", func() {
+				Ω(fakeScheduler.TriggerWithInputOverridesCallCount()).Should(Equal(1))
+
+				_, job, resources, overrides := fakeScheduler.TriggerWithInputOverridesArgsForCall(0)
+				Ω(job).Should(Equal(someJob))
+				Ω(resources).Should(Equal(someResources))
+				Ω(overrides).Should(BeEmpty())
+			})
+
+			Context("when the request overrides an input's version", func() {
+				BeforeEach(func() {
+					requestBody = `{"some-input":12}`
+				})
+
+				It("triggers the job with the overridden version", func() {
+					Ω(fakeScheduler.TriggerWithInputOverridesCallCount()).Should(Equal(1))
+
+					_, _, _, overrides := fakeScheduler.TriggerWithInputOverridesArgsForCall(0)
+					Ω(overrides).Should(Equal(scheduler.InputOverrides{"some-input": 12}))
+				})
+
+				Context("when the version cannot be used", func() {
+					BeforeEach(func() {
+						fakeScheduler.TriggerWithInputOverridesReturns(db.Build{}, scheduler.InvalidInputOverrideError{
+							Input:  "some-input",
+							Reason: "version 12 is disabled",
+						})
+					})
+
+					It("returns 400 Bad Request with the reason", func() {
+						Ω(response.StatusCode).Should(Equal(http.StatusBadRequest))
+
+						body, err := ioutil.ReadAll(response.Body)
+						Ω(err).ShouldNot(HaveOccurred())
+						Ω(body).Should(MatchJSON(`{
+							"error": "invalid version for input 'some-input': version 12 is disabled",
+							"code": "bad_request"
+						}`))
+					})
+				})
+			})
+
+			Context("when the request body is malformed", func() {
+				BeforeEach(func() {
+					requestBody = `{"some-input":`
+				})
+
+				It("returns 400 Bad Request", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusBadRequest))
+				})
+
+				It("does not trigger the job", func() {
+					Ω(fakeScheduler.TriggerWithInputOverridesCallCount()).Should(BeZero())
+				})
+			})
+
+			Context("when triggering the job fails", func() {
+				BeforeEach(func() {
+					fakeScheduler.TriggerWithInputOverridesReturns(db.Build{}, errors.New("oh no!"))
+				})
+
+				It("returns 500", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+				})
+			})
+
+			Context("when the job is not in the config", func() {
+				BeforeEach(func() {
+					pipelineDB.GetConfigReturns(atc.Config{}, 1, nil)
+				})
+
+				It("returns 404 Not Found", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusNotFound))
+				})
+
+				It("does not trigger anything", func() {
+					Ω(fakeScheduler.TriggerWithInputOverridesCallCount()).Should(BeZero())
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns Unauthorized", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusUnauthorized))
+			})
+
+			It("does not trigger anything", func() {
+				Ω(fakeScheduler.TriggerWithInputOverridesCallCount()).Should(BeZero())
+			})
+		})
+	})
+
+	Describe("GET /api/v1/pipelines/:pipeline_name/jobs/:job_name/plan", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/pipelines/some-pipeline/jobs/some-job/plan")
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		Context("when authenticated", func() {
+			var someJob atc.JobConfig
+			var someResources atc.ResourceConfigs
+
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+
+				someJob = atc.JobConfig{Name: "some-job"}
+				someResources = atc.ResourceConfigs{{Name: "some-resource"}}
+
+				pipelineDB.GetConfigReturns(atc.Config{
+					Jobs:      atc.JobConfigs{someJob},
+					Resources: someResources,
+				}, 1, nil)
+
+				fakeScheduler.PreviewPlanReturns(atc.Plan{
+					Get: &atc.GetPlan{
+						Name:     "some-input",
+						Resource: "some-resource",
+						Version:  atc.Version{"ref": "abc"},
+					},
+				}, nil)
+			})
+
+			It("returns 200 OK", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusOK))
+			})
+
+			It("previews the plan for the job", func() {
+				Ω(fakeScheduler.PreviewPlanCallCount()).Should(Equal(1))
+
+				_, job, resources := fakeScheduler.PreviewPlanArgsForCall(0)
+				Ω(job).Should(Equal(someJob))
+				Ω(resources).Should(Equal(someResources))
+			})
+
+			Context("when the job relies on defaults", func() {
+				BeforeEach(func() {
+					someJob = atc.JobConfig{
+						Name:   "some-job",
+						Serial: true,
+						Plan: atc.PlanSequence{
+							{Get: "some-resource"},
+							{Put: "some-resource"},
+						},
+					}
+
+					pipelineDB.GetConfigReturns(atc.Config{
+						Jobs:      atc.JobConfigs{someJob},
+						Resources: someResources,
+					}, 1, nil)
+				})
+
+				It("previews the plan for the job with the same defaults as the scheduler", func() {
+					_, job, _ := fakeScheduler.PreviewPlanArgsForCall(0)
+					Ω(job).Should(Equal(atc.JobConfig{
+						Name:         "some-job",
+						Serial:       true,
+						SerialGroups: []string{"some-job"},
+						Plan: atc.PlanSequence{
+							{Get: "some-resource", Resource: "some-resource"},
+							{Put: "some-resource", Resource: "some-resource"},
+						},
+					}))
+				})
+			})
+
+			It("returns the plan", func() {
+				var plan atc.Plan
+				err := json.NewDecoder(response.Body).Decode(&plan)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(plan).Should(Equal(atc.Plan{
+					Get: &atc.GetPlan{
+						Name:     "some-input",
+						Resource: "some-resource",
+						Version:  atc.Version{"ref": "abc"},
+					},
+				}))
+			})
+
+			It("does not trigger a build", func() {
+				Ω(fakeScheduler.TriggerWithInputOverridesCallCount()).Should(BeZero())
+			})
+
+			Context("when the job's inputs have no versions", func() {
+				BeforeEach(func() {
+					fakeScheduler.PreviewPlanReturns(atc.Plan{}, db.ErrNoVersions)
+				})
+
+				It("returns 404 with an error saying so", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusNotFound))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(body).Should(MatchJSON(`{
+						"error": "no versions are available for the job's inputs",
+						"code": "input_versions_not_found"
+					}`))
+				})
+			})
+
+			Context("when previewing the plan fails", func() {
+				BeforeEach(func() {
+					fakeScheduler.PreviewPlanReturns(atc.Plan{}, errors.New("oh no!"))
+				})
+
+				It("returns 500", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+				})
+			})
+
+			Context("when the job is not in the config", func() {
+				BeforeEach(func() {
+					pipelineDB.GetConfigReturns(atc.Config{}, 1, nil)
+				})
+
+				It("returns 404 Not Found", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusNotFound))
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns Unauthorized", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusUnauthorized))
+			})
+
+			It("does not preview anything", func() {
+				Ω(fakeScheduler.PreviewPlanCallCount()).Should(BeZero())
+			})
+		})
+	})
+
+	Describe("GET /api/v1/pipelines/:pipeline_name/jobs/:job_name/badge", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/pipelines/some-pipeline/jobs/some-job/badge")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(pipelineDBFactory.BuildWithNameCallCount()).Should(Equal(1))
+			pipelineName := pipelineDBFactory.BuildWithNameArgsForCall(0)
+			Ω(pipelineName).Should(Equal("some-pipeline"))
+		})
+
+		Context("when the job has a finished build", func() {
+			BeforeEach(func() {
+				pipelineDB.GetLatestFinishedBuildReturns(db.Build{
+					ID:      1,
+					Name:    "1",
+					JobName: "some-job",
+					Status:  db.StatusSucceeded,
+				}, true, nil)
+			})
+
+			It("looks up the job's latest finished build", func() {
+				Ω(pipelineDB.GetLatestFinishedBuildCallCount()).Should(Equal(1))
+				Ω(pipelineDB.GetLatestFinishedBuildArgsForCall(0)).Should(Equal("some-job"))
+			})
+
+			It("returns 200 OK", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusOK))
+			})
+
+			It("returns an SVG", func() {
+				Ω(response.Header.Get("Content-Type")).Should(Equal("image/svg+xml"))
+			})
+
+			It("reflects the build's status", func() {
+				body, err := ioutil.ReadAll(response.Body)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(string(body)).Should(ContainSubstring("passing"))
+			})
+
+			Context("and it failed", func() {
+				BeforeEach(func() {
+					pipelineDB.GetLatestFinishedBuildReturns(db.Build{
+						Status: db.StatusFailed,
+					}, true, nil)
+				})
+
+				It("reflects the failure", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(string(body)).Should(ContainSubstring("failing"))
+				})
+			})
+		})
+
+		Context("when the job has no finished builds", func() {
+			BeforeEach(func() {
+				pipelineDB.GetLatestFinishedBuildReturns(db.Build{}, false, nil)
+			})
+
+			It("returns 200 OK", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusOK))
+			})
+
+			It("returns an unknown badge", func() {
+				body, err := ioutil.ReadAll(response.Body)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(string(body)).Should(ContainSubstring("unknown"))
+			})
+		})
+
+		Context("when getting the build fails", func() {
+			BeforeEach(func() {
+				pipelineDB.GetLatestFinishedBuildReturns(db.Build{}, false, errors.New("oh no!"))
+			})
+
+			It("returns 500 Internal Server Error", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
 	Describe("PUT /api/v1/pipelines/:pipeline_name/jobs/:job_name/pause", func() {
 		var response *http.Response
 
@@ -0,0 +1,80 @@
+package jobserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/concourse/atc/db"
+)
+
+type badge struct {
+	status string
+	color  string
+}
+
+var (
+	badgePassing = badge{status: "passing", color: "#44cc11"}
+	badgeFailing = badge{status: "failing", color: "#e05d44"}
+	badgeErrored = badge{status: "errored", color: "#fe7d37"}
+	badgeAborted = badge{status: "aborted", color: "#8f4b2d"}
+	badgeUnknown = badge{status: "unknown", color: "#9f9f9f"}
+)
+
+func badgeForBuild(build db.Build, found bool) badge {
+	if !found {
+		return badgeUnknown
+	}
+
+	switch build.Status {
+	case db.StatusSucceeded:
+		return badgePassing
+	case db.StatusFailed:
+		return badgeFailing
+	case db.StatusErrored:
+		return badgeErrored
+	case db.StatusAborted:
+		return badgeAborted
+	default:
+		return badgeUnknown
+	}
+}
+
+const badgeTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="88" height="20">
+  <rect rx="3" width="88" height="20" fill="#555"/>
+  <rect rx="3" x="37" width="51" height="20" fill="%s"/>
+  <path fill="%s" d="M37 0h4v20h-4z"/>
+  <g fill="#fff" text-anchor="middle" font-family="DejaVu Sans,Verdana,Geneva,sans-serif" font-size="11">
+    <text x="18.5" y="14">build</text>
+    <text x="61.5" y="14">%s</text>
+  </g>
+</svg>
+`
+
+func (b badge) String() string {
+	return fmt.Sprintf(badgeTemplate, b.color, b.color, b.status)
+}
+
+// GetJobBadge renders an SVG badge reflecting the status of the job's latest
+// finished build, so that a running build does not flip it prematurely.
+func (s *Server) GetJobBadge(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("job-badge")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobName := r.FormValue(":job_name")
+
+		build, found, err := pipelineDB.GetLatestFinishedBuild(jobName)
+		if err != nil {
+			logger.Error("failed-to-get-latest-finished-build", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprint(w, badgeForBuild(build, found))
+	})
+}
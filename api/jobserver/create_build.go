@@ -0,0 +1,65 @@
+package jobserver
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pivotal-golang/lager"
+	"github.com/tedsuo/rata"
+
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/scheduler"
+)
+
+// CreateJobBuild triggers a build of the job. The request body may map input
+// names to the IDs of the versioned resources they should use; any other
+// inputs are determined as they would be for a triggered build.
+func (s *Server) CreateJobBuild(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("create-job-build")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobName := rata.Param(r, "job_name")
+
+		config, _, err := pipelineDB.GetConfig()
+		if err != nil {
+			logger.Error("failed-to-get-config", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		job, found := config.Jobs.Lookup(jobName)
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var overrides scheduler.InputOverrides
+		err = json.NewDecoder(r.Body).Decode(&overrides)
+		if err != nil && err != io.EOF {
+			logger.Info("malformed-request", lager.Data{"error": err.Error()})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		buildScheduler := s.schedulerFactory(pipelineDB)
+
+		build, err := buildScheduler.TriggerWithInputOverrides(logger, job, config.Resources, overrides)
+		if err != nil {
+			if _, ok := err.(scheduler.InvalidInputOverrideError); ok {
+				w.WriteHeader(http.StatusBadRequest)
+				io.WriteString(w, err.Error())
+				return
+			}
+
+			logger.Error("failed-to-trigger-build", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+
+		json.NewEncoder(w).Encode(present.Build(build))
+	})
+}
@@ -0,0 +1,111 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/jobserver"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/scheduler"
+	"github.com/pivotal-golang/lager"
+)
+
+type FakeBuildScheduler struct {
+	TriggerWithInputOverridesStub        func(lager.Logger, atc.JobConfig, atc.ResourceConfigs, scheduler.InputOverrides) (db.Build, error)
+	triggerWithInputOverridesMutex       sync.RWMutex
+	triggerWithInputOverridesArgsForCall []struct {
+		arg1 lager.Logger
+		arg2 atc.JobConfig
+		arg3 atc.ResourceConfigs
+		arg4 scheduler.InputOverrides
+	}
+	triggerWithInputOverridesReturns struct {
+		result1 db.Build
+		result2 error
+	}
+	PreviewPlanStub        func(lager.Logger, atc.JobConfig, atc.ResourceConfigs) (atc.Plan, error)
+	previewPlanMutex       sync.RWMutex
+	previewPlanArgsForCall []struct {
+		arg1 lager.Logger
+		arg2 atc.JobConfig
+		arg3 atc.ResourceConfigs
+	}
+	previewPlanReturns struct {
+		result1 atc.Plan
+		result2 error
+	}
+}
+
+func (fake *FakeBuildScheduler) TriggerWithInputOverrides(arg1 lager.Logger, arg2 atc.JobConfig, arg3 atc.ResourceConfigs, arg4 scheduler.InputOverrides) (db.Build, error) {
+	fake.triggerWithInputOverridesMutex.Lock()
+	fake.triggerWithInputOverridesArgsForCall = append(fake.triggerWithInputOverridesArgsForCall, struct {
+		arg1 lager.Logger
+		arg2 atc.JobConfig
+		arg3 atc.ResourceConfigs
+		arg4 scheduler.InputOverrides
+	}{arg1, arg2, arg3, arg4})
+	fake.triggerWithInputOverridesMutex.Unlock()
+	if fake.TriggerWithInputOverridesStub != nil {
+		return fake.TriggerWithInputOverridesStub(arg1, arg2, arg3, arg4)
+	} else {
+		return fake.triggerWithInputOverridesReturns.result1, fake.triggerWithInputOverridesReturns.result2
+	}
+}
+
+func (fake *FakeBuildScheduler) TriggerWithInputOverridesCallCount() int {
+	fake.triggerWithInputOverridesMutex.RLock()
+	defer fake.triggerWithInputOverridesMutex.RUnlock()
+	return len(fake.triggerWithInputOverridesArgsForCall)
+}
+
+func (fake *FakeBuildScheduler) TriggerWithInputOverridesArgsForCall(i int) (lager.Logger, atc.JobConfig, atc.ResourceConfigs, scheduler.InputOverrides) {
+	fake.triggerWithInputOverridesMutex.RLock()
+	defer fake.triggerWithInputOverridesMutex.RUnlock()
+	return fake.triggerWithInputOverridesArgsForCall[i].arg1, fake.triggerWithInputOverridesArgsForCall[i].arg2, fake.triggerWithInputOverridesArgsForCall[i].arg3, fake.triggerWithInputOverridesArgsForCall[i].arg4
+}
+
+func (fake *FakeBuildScheduler) TriggerWithInputOverridesReturns(result1 db.Build, result2 error) {
+	fake.TriggerWithInputOverridesStub = nil
+	fake.triggerWithInputOverridesReturns = struct {
+		result1 db.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildScheduler) PreviewPlan(arg1 lager.Logger, arg2 atc.JobConfig, arg3 atc.ResourceConfigs) (atc.Plan, error) {
+	fake.previewPlanMutex.Lock()
+	fake.previewPlanArgsForCall = append(fake.previewPlanArgsForCall, struct {
+		arg1 lager.Logger
+		arg2 atc.JobConfig
+		arg3 atc.ResourceConfigs
+	}{arg1, arg2, arg3})
+	fake.previewPlanMutex.Unlock()
+	if fake.PreviewPlanStub != nil {
+		return fake.PreviewPlanStub(arg1, arg2, arg3)
+	} else {
+		return fake.previewPlanReturns.result1, fake.previewPlanReturns.result2
+	}
+}
+
+func (fake *FakeBuildScheduler) PreviewPlanCallCount() int {
+	fake.previewPlanMutex.RLock()
+	defer fake.previewPlanMutex.RUnlock()
+	return len(fake.previewPlanArgsForCall)
+}
+
+func (fake *FakeBuildScheduler) PreviewPlanArgsForCall(i int) (lager.Logger, atc.JobConfig, atc.ResourceConfigs) {
+	fake.previewPlanMutex.RLock()
+	defer fake.previewPlanMutex.RUnlock()
+	return fake.previewPlanArgsForCall[i].arg1, fake.previewPlanArgsForCall[i].arg2, fake.previewPlanArgsForCall[i].arg3
+}
+
+func (fake *FakeBuildScheduler) PreviewPlanReturns(result1 atc.Plan, result2 error) {
+	fake.PreviewPlanStub = nil
+	fake.previewPlanReturns = struct {
+		result1 atc.Plan
+		result2 error
+	}{result1, result2}
+}
+
+var _ jobserver.BuildScheduler = new(FakeBuildScheduler)
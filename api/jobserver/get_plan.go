@@ -0,0 +1,56 @@
+package jobserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/tedsuo/rata"
+
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/db"
+)
+
+// GetJobPlan responds with the plan a build of the job triggered now would
+// run, without creating a build.
+func (s *Server) GetJobPlan(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("get-job-plan")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobName := rata.Param(r, "job_name")
+
+		config, _, err := pipelineDB.GetConfig()
+		if err != nil {
+			logger.Error("failed-to-get-config", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		// previewed as the scheduler would build it
+		config = config.WithDefaults()
+
+		job, found := config.Jobs.Lookup(jobName)
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		buildScheduler := s.schedulerFactory(pipelineDB)
+
+		plan, err := buildScheduler.PreviewPlan(logger, job, config.Resources)
+		if err == db.ErrNoVersions {
+			apierror.Write(w, http.StatusNotFound, "input_versions_not_found", "no versions are available for the job's inputs")
+			return
+		}
+
+		if err != nil {
+			logger.Error("failed-to-preview-plan", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(plan)
+	})
+}
@@ -1,15 +1,35 @@
 package jobserver
 
-import "github.com/pivotal-golang/lager"
+import (
+	"github.com/pivotal-golang/lager"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/scheduler"
+)
+
+//go:generate counterfeiter . BuildScheduler
+
+type BuildScheduler interface {
+	TriggerWithInputOverrides(lager.Logger, atc.JobConfig, atc.ResourceConfigs, scheduler.InputOverrides) (db.Build, error)
+	PreviewPlan(lager.Logger, atc.JobConfig, atc.ResourceConfigs) (atc.Plan, error)
+}
+
+type SchedulerFactory func(db.PipelineDB) BuildScheduler
 
 type Server struct {
 	logger lager.Logger
+
+	schedulerFactory SchedulerFactory
 }
 
 func NewServer(
 	logger lager.Logger,
+	schedulerFactory SchedulerFactory,
 ) *Server {
 	return &Server{
 		logger: logger,
+
+		schedulerFactory: schedulerFactory,
 	}
 }
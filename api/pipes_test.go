@@ -11,6 +11,7 @@ import (
 	"github.com/concourse/atc/db"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
 )
 
 var _ = Describe("Pipes API", func() {
@@ -132,6 +133,46 @@ var _ = Describe("Pipes API", func() {
 				})
 			})
 
+			Context("when the pipe was created on another ATC", func() {
+				var peerServer *ghttp.Server
+
+				BeforeEach(func() {
+					peerServer = ghttp.NewServer()
+
+					pipeDB.GetPipeReturns(db.Pipe{
+						ID:  "some-other-guid",
+						URL: peerServer.URL() + "/some/prefix",
+					}, nil)
+				})
+
+				AfterEach(func() {
+					peerServer.Close()
+				})
+
+				It("forwards the request to the peer's full URL, including its base path and credentials", func() {
+					peerServer.AppendHandlers(
+						ghttp.CombineHandlers(
+							ghttp.VerifyRequest("GET", "/some/prefix/api/v1/pipes/some-other-guid"),
+							ghttp.VerifyBasicAuth("username", "password"),
+							ghttp.RespondWith(http.StatusOK, "some data"),
+						),
+					)
+
+					req, err := http.NewRequest("GET", server.URL+"/api/v1/pipes/some-other-guid", nil)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					req.SetBasicAuth("username", "password")
+
+					response, err := client.Do(req)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					defer response.Body.Close()
+
+					Ω(ioutil.ReadAll(response.Body)).Should(Equal([]byte("some data")))
+					Ω(peerServer.ReceivedRequests()).Should(HaveLen(1))
+				})
+			})
+
 			Describe("with an invalid id", func() {
 				It("returns 404", func() {
 					readRes := readPipe("bogus-id")
@@ -148,6 +189,33 @@ var _ = Describe("Pipes API", func() {
 		})
 	})
 
+	Context("when only authenticated for reads", func() {
+		BeforeEach(func() {
+			authValidator.IsAuthenticatedReturns(true)
+			writeAuthValidator.IsAuthenticatedReturns(false)
+		})
+
+		Describe("GET /api/v1/pipes/:pipe", func() {
+			var response *http.Response
+
+			BeforeEach(func() {
+				req, err := http.NewRequest("GET", server.URL+"/api/v1/pipes/some-guid", nil)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				response, err = client.Do(req)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				response.Body.Close()
+			})
+
+			It("returns 401, as reading consumes the pipe", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
 	Context("when not authenticated", func() {
 		BeforeEach(func() {
 			authValidator.IsAuthenticatedReturns(false)
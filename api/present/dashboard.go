@@ -0,0 +1,52 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+// DashboardPipeline summarizes the pipeline's jobs and resources, in the order
+// they appear in its config. Jobs and resources that have since been removed
+// from the config are left out.
+func DashboardPipeline(pipeline db.SavedPipeline, jobs []db.JobStatus, resources []db.ResourceStatus) atc.DashboardPipeline {
+	jobStatuses := map[string]db.JobStatus{}
+	for _, job := range jobs {
+		jobStatuses[job.Name] = job
+	}
+
+	resourceStatuses := map[string]db.ResourceStatus{}
+	for _, resource := range resources {
+		resourceStatuses[resource.Name] = resource
+	}
+
+	presented := atc.DashboardPipeline{
+		Name:   pipeline.Name,
+		Paused: pipeline.Paused,
+
+		Jobs:      []atc.DashboardJob{},
+		Resources: []atc.DashboardResource{},
+	}
+
+	for _, job := range pipeline.Config.Jobs {
+		status := jobStatuses[job.Name]
+
+		presented.Jobs = append(presented.Jobs, atc.DashboardJob{
+			Name:   job.Name,
+			Paused: status.Paused,
+			Status: string(status.LatestFinishedStatus),
+		})
+	}
+
+	for _, resource := range pipeline.Config.Resources {
+		status := resourceStatuses[resource.Name]
+
+		presented.Resources = append(presented.Resources, atc.DashboardResource{
+			Name:        resource.Name,
+			Paused:      status.Paused,
+			Failing:     status.FailingToCheck(),
+			LastChecked: unixTime(status.LastChecked),
+		})
+	}
+
+	return presented
+}
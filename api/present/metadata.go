@@ -0,0 +1,78 @@
+package present
+
+import (
+	"strings"
+
+	"github.com/concourse/atc/db"
+)
+
+// MetadataField is a field of a resource version's metadata, marked up for
+// display based on its name.
+type MetadataField struct {
+	Name  string
+	Value string
+
+	// the value is a URL, and should be rendered as a link
+	Link bool
+
+	// an abbreviated form of the value, if it is too long to show in full
+	Short string
+}
+
+// how much of a message is shown before it is truncated
+const shortMessageLength = 72
+
+// how much of a commit SHA is shown
+const shortCommitLength = 10
+
+// MetadataProcessors mark up well-known metadata fields, keyed by field name.
+// Fields without a processor are shown as-is.
+var MetadataProcessors = map[string]func(*MetadataField){
+	"url":     markLink,
+	"message": shortenMessage,
+	"commit":  shortenCommit,
+}
+
+func Metadata(fields []db.MetadataField) []MetadataField {
+	presented := make([]MetadataField, len(fields))
+
+	for i, field := range fields {
+		presented[i] = MetadataField{
+			Name:  field.Name,
+			Value: field.Value,
+		}
+
+		if process, found := MetadataProcessors[field.Name]; found {
+			process(&presented[i])
+		}
+	}
+
+	return presented
+}
+
+func markLink(field *MetadataField) {
+	field.Link = strings.HasPrefix(field.Value, "http://") || strings.HasPrefix(field.Value, "https://")
+}
+
+// shortenMessage abbreviates a message to its first line, truncated if the
+// line itself is too long.
+func shortenMessage(field *MetadataField) {
+	short := field.Value
+	if newline := strings.Index(short, "\n"); newline != -1 {
+		short = short[:newline]
+	}
+
+	if len([]rune(short)) > shortMessageLength {
+		short = string([]rune(short)[:shortMessageLength]) + "…"
+	}
+
+	if short != field.Value {
+		field.Short = short
+	}
+}
+
+func shortenCommit(field *MetadataField) {
+	if len(field.Value) > shortCommitLength {
+		field.Short = field.Value[:shortCommitLength]
+	}
+}
@@ -1,6 +1,8 @@
 package present
 
 import (
+	"time"
+
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/web/routes"
@@ -43,5 +45,35 @@ func Resource(resource atc.ResourceConfig, groups atc.GroupConfigs, dbResource d
 
 		FailingToCheck: dbResource.FailingToCheck(),
 		CheckError:     checkErrString,
+
+		Checking: dbResource.Checking,
+
+		LastChecked:      unixTime(dbResource.LastChecked),
+		LastCheckErrored: unixTime(dbResource.LastCheckErrored),
+	}
+}
+
+func unixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
+
+func ResourceVersion(svr db.SavedVersionedResource) atc.ResourceVersion {
+	var metadata []atc.MetadataField
+	for _, field := range svr.Metadata {
+		metadata = append(metadata, atc.MetadataField{
+			Name:  field.Name,
+			Value: field.Value,
+		})
+	}
+
+	return atc.ResourceVersion{
+		ID:       svr.ID,
+		Version:  atc.Version(svr.Version),
+		Enabled:  svr.Enabled,
+		Metadata: metadata,
 	}
 }
@@ -2,15 +2,20 @@ package api_test
 
 import (
 	"errors"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/lager"
+	"github.com/vito/go-sse/sse"
 
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
 	dbfakes "github.com/concourse/atc/db/fakes"
+	"github.com/concourse/atc/resource"
 )
 
 var _ = Describe("Resources API", func() {
@@ -62,9 +67,11 @@ var _ = Describe("Resources API", func() {
 					pipelineDB.GetResourceStub = func(name string) (db.SavedResource, error) {
 						if name == "resource-2" {
 							return db.SavedResource{
-								ID:           1,
-								CheckError:   errors.New("sup"),
-								PipelineName: "a-pipeline",
+								ID:               1,
+								CheckError:       errors.New("sup"),
+								PipelineName:     "a-pipeline",
+								LastChecked:      time.Unix(1434000000, 0),
+								LastCheckErrored: time.Unix(1435000000, 0),
 								Resource: db.Resource{
 									Name: name,
 								},
@@ -91,7 +98,7 @@ var _ = Describe("Resources API", func() {
 						authValidator.IsAuthenticatedReturns(true)
 					})
 
-					It("returns each resource, including their check failure", func() {
+					It("returns each resource, including their check failure and check times", func() {
 						body, err := ioutil.ReadAll(response.Body)
 						Ω(err).ShouldNot(HaveOccurred())
 
@@ -109,7 +116,9 @@ var _ = Describe("Resources API", func() {
 								"groups": ["group-2"],
 								"url": "/pipelines/a-pipeline/resources/resource-2",
 								"failing_to_check": true,
-								"check_error": "sup"
+								"check_error": "sup",
+								"last_checked": 1434000000,
+								"last_check_errored": 1435000000
 							},
 							{
 								"name": "resource-3",
@@ -144,7 +153,9 @@ var _ = Describe("Resources API", func() {
 								"type": "type-2",
 								"groups": ["group-2"],
 								"url": "/pipelines/a-pipeline/resources/resource-2",
-								"failing_to_check": true
+								"failing_to_check": true,
+								"last_checked": 1434000000,
+								"last_check_errored": 1435000000
 							},
 							{
 								"name": "resource-3",
@@ -182,6 +193,145 @@ var _ = Describe("Resources API", func() {
 		})
 	})
 
+	Describe("GET /api/v1/pipelines/:pipeline_name/resources/:resource_name/versions", func() {
+		var ifNoneMatch string
+
+		var response *http.Response
+
+		BeforeEach(func() {
+			ifNoneMatch = ""
+
+			pipelineDB.GetConfigReturns(atc.Config{
+				Resources: atc.ResourceConfigs{
+					{Name: "some-resource"},
+				},
+			}, 1, nil)
+
+			pipelineDB.GetResourceReturns(db.SavedResource{ID: 42}, nil)
+			pipelineDB.GetResourceVersionsChecksumReturns("some-checksum", nil)
+
+			pipelineDB.GetResourceHistoryReturns([]*db.VersionHistory{
+				{
+					VersionedResource: db.SavedVersionedResource{
+						ID:      3,
+						Enabled: true,
+						VersionedResource: db.VersionedResource{
+							Resource: "some-resource",
+							Version:  db.Version{"ref": "def"},
+							Metadata: []db.MetadataField{
+								{Name: "commit", Value: "def"},
+							},
+						},
+					},
+				},
+				{
+					VersionedResource: db.SavedVersionedResource{
+						ID:      1,
+						Enabled: false,
+						VersionedResource: db.VersionedResource{
+							Resource: "some-resource",
+							Version:  db.Version{"ref": "abc"},
+						},
+					},
+				},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			request, err := http.NewRequest("GET", server.URL+"/api/v1/pipelines/a-pipeline/resources/some-resource/versions", nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			if ifNoneMatch != "" {
+				request.Header.Set("If-None-Match", ifNoneMatch)
+			}
+
+			response, err = client.Do(request)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("returns 200", func() {
+			Ω(response.StatusCode).Should(Equal(http.StatusOK))
+		})
+
+		It("returns the resource's versions, newest first", func() {
+			body, err := ioutil.ReadAll(response.Body)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(body).Should(MatchJSON(`[
+				{
+					"id": 3,
+					"version": {"ref": "def"},
+					"enabled": true,
+					"metadata": [{"name": "commit", "value": "def"}]
+				},
+				{
+					"id": 1,
+					"version": {"ref": "abc"},
+					"enabled": false
+				}
+			]`))
+		})
+
+		It("tags the response with the checksum of the resource's versions", func() {
+			Ω(response.Header.Get("ETag")).Should(Equal(`"some-checksum"`))
+			Ω(pipelineDB.GetResourceVersionsChecksumArgsForCall(0)).Should(Equal(42))
+		})
+
+		Context("when the client already has the current versions", func() {
+			BeforeEach(func() {
+				ifNoneMatch = `"some-checksum"`
+			})
+
+			It("returns 304 without loading the history", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusNotModified))
+				Ω(response.Header.Get("ETag")).Should(Equal(`"some-checksum"`))
+
+				Ω(pipelineDB.GetResourceHistoryCallCount()).Should(BeZero())
+			})
+		})
+
+		Context("when the versions have changed since the client last asked", func() {
+			BeforeEach(func() {
+				ifNoneMatch = `"some-old-checksum"`
+			})
+
+			It("returns 200 with the new tag", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusOK))
+				Ω(response.Header.Get("ETag")).Should(Equal(`"some-checksum"`))
+			})
+		})
+
+		Context("when the resource is not in the pipeline's config", func() {
+			BeforeEach(func() {
+				pipelineDB.GetConfigReturns(atc.Config{}, 1, nil)
+			})
+
+			It("returns 404", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when getting the latest version's ID fails", func() {
+			BeforeEach(func() {
+				pipelineDB.GetResourceVersionsChecksumReturns("", errors.New("oh no!"))
+			})
+
+			It("returns 500", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when getting the history fails", func() {
+			BeforeEach(func() {
+				pipelineDB.GetResourceHistoryReturns(nil, errors.New("oh no!"))
+			})
+
+			It("returns 500", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
 	Describe("PUT /api/v1/pipelines/:pipeline_name/resources/:resource_name/versions/:resource_version_id/enable", func() {
 		var response *http.Response
 
@@ -303,6 +453,347 @@ var _ = Describe("Resources API", func() {
 		})
 	})
 
+	Describe("POST /api/v1/pipelines/:pipeline_name/resources/:resource_name/versions/:resource_version_id/clear-cache", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			request, err := http.NewRequest("POST", server.URL+"/api/v1/pipelines/a-pipeline/resources/resource-name/versions/42/clear-cache", nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			response, err = client.Do(request)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		Context("when authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+			})
+
+			Context("when the versioned resource exists", func() {
+				BeforeEach(func() {
+					pipelineDB.GetVersionedResourceReturns(db.SavedVersionedResource{
+						ID: 42,
+						VersionedResource: db.VersionedResource{
+							Resource: "resource-name",
+							Type:     "some-type",
+							Source:   db.Source{"some": "source"},
+							Version:  db.Version{"some": "version"},
+						},
+					}, true, nil)
+
+					fakeCacheClearer.ClearReturns(2, nil)
+				})
+
+				It("looks up the right versioned resource", func() {
+					Ω(pipelineDBFactory.BuildWithNameArgsForCall(0)).Should(Equal("a-pipeline"))
+					Ω(pipelineDB.GetVersionedResourceArgsForCall(0)).Should(Equal(42))
+				})
+
+				It("clears the cache for the versioned resource's type, source, and version", func() {
+					Ω(fakeCacheClearer.ClearCallCount()).Should(Equal(1))
+					Ω(fakeCacheClearer.ClearArgsForCall(0)).Should(Equal(resource.CacheIdentifier{
+						Type:    "some-type",
+						Source:  atc.Source{"some": "source"},
+						Version: atc.Version{"some": "version"},
+					}))
+				})
+
+				It("returns 200 with the number of entries cleared", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusOK))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(body).Should(MatchJSON(`{"cleared":2}`))
+				})
+
+				Context("when clearing the cache fails", func() {
+					BeforeEach(func() {
+						fakeCacheClearer.ClearReturns(0, errors.New("welp"))
+					})
+
+					It("returns 500", func() {
+						Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+					})
+				})
+			})
+
+			Context("when the version belongs to a different resource", func() {
+				BeforeEach(func() {
+					pipelineDB.GetVersionedResourceReturns(db.SavedVersionedResource{
+						ID: 42,
+						VersionedResource: db.VersionedResource{
+							Resource: "some-other-resource",
+						},
+					}, true, nil)
+				})
+
+				It("returns 404 without clearing anything", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusNotFound))
+					Ω(fakeCacheClearer.ClearCallCount()).Should(BeZero())
+				})
+			})
+
+			Context("when the versioned resource does not exist", func() {
+				BeforeEach(func() {
+					pipelineDB.GetVersionedResourceReturns(db.SavedVersionedResource{}, false, nil)
+				})
+
+				It("returns 404", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when looking up the versioned resource fails", func() {
+				BeforeEach(func() {
+					pipelineDB.GetVersionedResourceReturns(db.SavedVersionedResource{}, false, errors.New("welp"))
+				})
+
+				It("returns 500", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns Unauthorized", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusUnauthorized))
+				Ω(fakeCacheClearer.ClearCallCount()).Should(BeZero())
+			})
+		})
+	})
+
+	Describe("GET /api/v1/pipelines/:pipeline_name/resources/:resource_name/versions/:resource_version_id/exists", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/pipelines/a-pipeline/resources/resource-name/versions/42/exists")
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		Context("when authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+			})
+
+			Context("when the versioned resource exists", func() {
+				BeforeEach(func() {
+					pipelineDB.GetVersionedResourceReturns(db.SavedVersionedResource{
+						ID: 42,
+						VersionedResource: db.VersionedResource{
+							Resource: "resource-name",
+							Version:  db.Version{"some": "version"},
+						},
+					}, true, nil)
+				})
+
+				It("checks for the version of the right resource", func() {
+					Ω(pipelineDB.GetVersionedResourceArgsForCall(0)).Should(Equal(42))
+
+					Ω(fakeVersionChecker.VersionExistsCallCount()).Should(Equal(1))
+					_, resourceName, version := fakeVersionChecker.VersionExistsArgsForCall(0)
+					Ω(resourceName).Should(Equal("resource-name"))
+					Ω(version).Should(Equal(atc.Version{"some": "version"}))
+				})
+
+				Context("when the version is still present upstream", func() {
+					BeforeEach(func() {
+						fakeVersionChecker.VersionExistsReturns(true, nil)
+					})
+
+					It("returns 200 with exists: true", func() {
+						Ω(response.StatusCode).Should(Equal(http.StatusOK))
+
+						body, err := ioutil.ReadAll(response.Body)
+						Ω(err).ShouldNot(HaveOccurred())
+
+						Ω(body).Should(MatchJSON(`{"exists":true}`))
+					})
+				})
+
+				Context("when the version has been removed upstream", func() {
+					BeforeEach(func() {
+						fakeVersionChecker.VersionExistsReturns(false, nil)
+					})
+
+					It("returns 200 with exists: false", func() {
+						Ω(response.StatusCode).Should(Equal(http.StatusOK))
+
+						body, err := ioutil.ReadAll(response.Body)
+						Ω(err).ShouldNot(HaveOccurred())
+
+						Ω(body).Should(MatchJSON(`{"exists":false}`))
+					})
+				})
+
+				Context("when checking fails", func() {
+					BeforeEach(func() {
+						fakeVersionChecker.VersionExistsReturns(false, errors.New("welp"))
+					})
+
+					It("returns 500", func() {
+						Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+					})
+				})
+			})
+
+			Context("when the version belongs to a different resource", func() {
+				BeforeEach(func() {
+					pipelineDB.GetVersionedResourceReturns(db.SavedVersionedResource{
+						ID: 42,
+						VersionedResource: db.VersionedResource{
+							Resource: "some-other-resource",
+						},
+					}, true, nil)
+				})
+
+				It("returns 404 without checking", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusNotFound))
+					Ω(fakeVersionChecker.VersionExistsCallCount()).Should(BeZero())
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns Unauthorized", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusUnauthorized))
+				Ω(fakeVersionChecker.VersionExistsCallCount()).Should(BeZero())
+			})
+		})
+	})
+
+	Describe("POST /api/v1/pipelines/:pipeline_name/resources/:resource_name/check/debug", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Post(server.URL+"/api/v1/pipelines/a-pipeline/resources/resource-name/check/debug", "", nil)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		Context("when authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+			})
+
+			Context("when the resource is configured", func() {
+				BeforeEach(func() {
+					pipelineDB.GetConfigReturns(atc.Config{
+						Resources: atc.ResourceConfigs{
+							{Name: "resource-name", Type: "git"},
+						},
+					}, 1, nil)
+				})
+
+				Context("when the check succeeds", func() {
+					BeforeEach(func() {
+						fakeVersionChecker.DebugCheckStub = func(logger lager.Logger, resourceName string, from atc.Version, stdout io.Writer, stderr io.Writer) ([]atc.Version, error) {
+							stdout.Write([]byte("some-stdout"))
+							stderr.Write([]byte("some-stderr"))
+							stderr.Write([]byte("more-stderr"))
+							return []atc.Version{{"ver": "1"}, {"ver": "2"}}, nil
+						}
+					})
+
+					It("checks the right resource", func() {
+						Ω(fakeVersionChecker.DebugCheckCallCount()).Should(Equal(1))
+
+						_, resourceName, from, _, _ := fakeVersionChecker.DebugCheckArgsForCall(0)
+						Ω(resourceName).Should(Equal("resource-name"))
+						Ω(from).Should(BeNil())
+					})
+
+					It("streams the script's stdout and stderr, ending with the versions found", func() {
+						Ω(response.StatusCode).Should(Equal(http.StatusOK))
+						Ω(response.Header.Get("Content-Type")).Should(Equal("text/event-stream; charset=utf-8"))
+
+						reader := sse.NewReadCloser(response.Body)
+
+						Ω(reader.Next()).Should(Equal(sse.Event{
+							Name: "stdout",
+							Data: []byte("some-stdout"),
+						}))
+
+						Ω(reader.Next()).Should(Equal(sse.Event{
+							Name: "stderr",
+							Data: []byte("some-stderr"),
+						}))
+
+						Ω(reader.Next()).Should(Equal(sse.Event{
+							Name: "stderr",
+							Data: []byte("more-stderr"),
+						}))
+
+						ev, err := reader.Next()
+						Ω(err).ShouldNot(HaveOccurred())
+						Ω(ev.Name).Should(Equal("versions"))
+						Ω(ev.Data).Should(MatchJSON(`[{"ver":"1"},{"ver":"2"}]`))
+
+						Ω(reader.Next()).Should(Equal(sse.Event{
+							Name: "end",
+							Data: []byte{},
+						}))
+					})
+				})
+
+				Context("when the check fails", func() {
+					BeforeEach(func() {
+						fakeVersionChecker.DebugCheckReturns(nil, errors.New("welp"))
+					})
+
+					It("streams the error", func() {
+						reader := sse.NewReadCloser(response.Body)
+
+						Ω(reader.Next()).Should(Equal(sse.Event{
+							Name: "error",
+							Data: []byte("welp"),
+						}))
+
+						Ω(reader.Next()).Should(Equal(sse.Event{
+							Name: "end",
+							Data: []byte{},
+						}))
+					})
+				})
+			})
+
+			Context("when the resource is not configured", func() {
+				BeforeEach(func() {
+					pipelineDB.GetConfigReturns(atc.Config{}, 1, nil)
+				})
+
+				It("returns 404 without checking", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusNotFound))
+					Ω(fakeVersionChecker.DebugCheckCallCount()).Should(BeZero())
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns Unauthorized", func() {
+				Ω(response.StatusCode).Should(Equal(http.StatusUnauthorized))
+				Ω(fakeVersionChecker.DebugCheckCallCount()).Should(BeZero())
+			})
+		})
+	})
+
 	Describe("PUT /api/v1/pipelines/:pipeline_name/resources/:resource_name/pause", func() {
 		var response *http.Response
 
@@ -319,6 +810,12 @@ var _ = Describe("Resources API", func() {
 		Context("when authenticated", func() {
 			BeforeEach(func() {
 				authValidator.IsAuthenticatedReturns(true)
+
+				pipelineDB.GetConfigReturns(atc.Config{
+					Resources: atc.ResourceConfigs{
+						{Name: "resource-name"},
+					},
+				}, 1, nil)
 			})
 
 			It("injects the proper pipelineDB", func() {
@@ -349,6 +846,49 @@ var _ = Describe("Resources API", func() {
 				It("returns 500", func() {
 					Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
 				})
+
+				It("returns a JSON error body", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(body).Should(MatchJSON(`{
+						"error": "internal server error",
+						"code": "internal_server_error"
+					}`))
+				})
+			})
+
+			Context("when the resource is not in the pipeline's config", func() {
+				BeforeEach(func() {
+					pipelineDB.GetConfigReturns(atc.Config{}, 1, nil)
+				})
+
+				It("returns 404 with a JSON error body", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusNotFound))
+					Ω(response.Header.Get("Content-Type")).Should(Equal("application/json"))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(body).Should(MatchJSON(`{
+						"error": "resource 'resource-name' not found",
+						"code": "resource_not_found"
+					}`))
+				})
+
+				It("does not pause anything", func() {
+					Ω(pipelineDB.PauseResourceCallCount()).Should(BeZero())
+				})
+			})
+
+			Context("when getting the config fails", func() {
+				BeforeEach(func() {
+					pipelineDB.GetConfigReturns(atc.Config{}, 0, errors.New("oh no!"))
+				})
+
+				It("returns 500", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+				})
 			})
 		})
 
@@ -360,6 +900,16 @@ var _ = Describe("Resources API", func() {
 			It("returns Unauthorized", func() {
 				Ω(response.StatusCode).Should(Equal(http.StatusUnauthorized))
 			})
+
+			It("returns a JSON error body", func() {
+				body, err := ioutil.ReadAll(response.Body)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(body).Should(MatchJSON(`{
+					"error": "not authorized",
+					"code": "unauthorized"
+				}`))
+			})
 		})
 	})
 
@@ -379,6 +929,12 @@ var _ = Describe("Resources API", func() {
 		Context("when authenticated", func() {
 			BeforeEach(func() {
 				authValidator.IsAuthenticatedReturns(true)
+
+				pipelineDB.GetConfigReturns(atc.Config{
+					Resources: atc.ResourceConfigs{
+						{Name: "resource-name"},
+					},
+				}, 1, nil)
 			})
 
 			It("injects the proper pipelineDB", func() {
@@ -409,6 +965,49 @@ var _ = Describe("Resources API", func() {
 				It("returns 500", func() {
 					Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
 				})
+
+				It("returns a JSON error body", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(body).Should(MatchJSON(`{
+						"error": "internal server error",
+						"code": "internal_server_error"
+					}`))
+				})
+			})
+
+			Context("when the resource is not in the pipeline's config", func() {
+				BeforeEach(func() {
+					pipelineDB.GetConfigReturns(atc.Config{}, 1, nil)
+				})
+
+				It("returns 404 with a JSON error body", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusNotFound))
+					Ω(response.Header.Get("Content-Type")).Should(Equal("application/json"))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(body).Should(MatchJSON(`{
+						"error": "resource 'resource-name' not found",
+						"code": "resource_not_found"
+					}`))
+				})
+
+				It("does not unpause anything", func() {
+					Ω(pipelineDB.UnpauseResourceCallCount()).Should(BeZero())
+				})
+			})
+
+			Context("when getting the config fails", func() {
+				BeforeEach(func() {
+					pipelineDB.GetConfigReturns(atc.Config{}, 0, errors.New("oh no!"))
+				})
+
+				It("returns 500", func() {
+					Ω(response.StatusCode).Should(Equal(http.StatusInternalServerError))
+				})
 			})
 		})
 
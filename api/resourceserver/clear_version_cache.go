@@ -0,0 +1,57 @@
+package resourceserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/resource"
+	"github.com/pivotal-golang/lager"
+	"github.com/tedsuo/rata"
+)
+
+func (s *Server) ClearResourceVersionCache(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("clear-resource-version-cache")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		versionedResourceID, err := strconv.Atoi(rata.Param(r, "resource_version_id"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		svr, found, err := pipelineDB.GetVersionedResource(versionedResourceID)
+		if err != nil {
+			logger.Error("failed-to-get-versioned-resource", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if !found || svr.Resource != rata.Param(r, "resource_name") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		cleared, err := s.cacheClearer.Clear(resource.CacheIdentifier{
+			Type:    resource.ResourceType(svr.Type),
+			Source:  atc.Source(svr.Source),
+			Version: atc.Version(svr.Version),
+		})
+		if err != nil {
+			logger.Error("failed-to-clear-cache", err, lager.Data{
+				"versioned-resource": versionedResourceID,
+			})
+
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(atc.ClearedCache{
+			Cleared: cleared,
+		})
+	})
+}
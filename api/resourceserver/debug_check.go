@@ -0,0 +1,114 @@
+package resourceserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/concourse/atc/db"
+	"github.com/tedsuo/rata"
+	"github.com/vito/go-sse/sse"
+)
+
+// DebugCheckResource runs a check for the resource and streams the check
+// script's stdout and stderr to the client as it runs, as 'stdout' and
+// 'stderr' events, followed by either a 'versions' event with the versions
+// found or an 'error' event, and finally an 'end' event.
+func (s *Server) DebugCheckResource(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("debug-check-resource")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resourceName := rata.Param(r, "resource_name")
+
+		config, _, err := pipelineDB.GetConfig()
+		if err != nil {
+			logger.Error("failed-to-get-config", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if _, found := config.Resources.Lookup(resourceName); !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Add("Content-Type", "text/event-stream; charset=utf-8")
+		w.Header().Add("Cache-Control", "no-cache, no-store, must-revalidate")
+		w.Header().Add("Connection", "keep-alive")
+
+		w.WriteHeader(http.StatusOK)
+
+		events := &eventWriter{
+			writer:  w,
+			flusher: w.(http.Flusher),
+		}
+
+		versions, err := s.versionCheckerFactory(pipelineDB).DebugCheck(
+			logger,
+			resourceName,
+			nil,
+			events.writerFor("stdout"),
+			events.writerFor("stderr"),
+		)
+		if err != nil {
+			events.write("error", []byte(err.Error()))
+		} else {
+			payload, err := json.Marshal(versions)
+			if err != nil {
+				logger.Error("failed-to-marshal-versions", err)
+				return
+			}
+
+			events.write("versions", payload)
+		}
+
+		events.write("end", nil)
+	})
+}
+
+type eventWriter struct {
+	writer  http.ResponseWriter
+	flusher http.Flusher
+
+	writeL sync.Mutex
+}
+
+func (writer *eventWriter) write(name string, data []byte) error {
+	writer.writeL.Lock()
+	defer writer.writeL.Unlock()
+
+	err := sse.Event{
+		Name: name,
+		Data: data,
+	}.Write(writer.writer)
+	if err != nil {
+		return err
+	}
+
+	writer.flusher.Flush()
+
+	return nil
+}
+
+func (writer *eventWriter) writerFor(name string) *namedEventWriter {
+	return &namedEventWriter{
+		events: writer,
+		name:   name,
+	}
+}
+
+// namedEventWriter writes each chunk written to it as an event with the same
+// name.
+type namedEventWriter struct {
+	events *eventWriter
+	name   string
+}
+
+func (writer *namedEventWriter) Write(p []byte) (int, error) {
+	err := writer.events.write(writer.name, p)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,56 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/api/resourceserver"
+	"github.com/concourse/atc/resource"
+)
+
+type FakeCacheClearer struct {
+	ClearStub        func(resource.CacheIdentifier) (int, error)
+	clearMutex       sync.RWMutex
+	clearArgsForCall []struct {
+		arg1 resource.CacheIdentifier
+	}
+	clearReturns struct {
+		result1 int
+		result2 error
+	}
+}
+
+func (fake *FakeCacheClearer) Clear(arg1 resource.CacheIdentifier) (int, error) {
+	fake.clearMutex.Lock()
+	fake.clearArgsForCall = append(fake.clearArgsForCall, struct {
+		arg1 resource.CacheIdentifier
+	}{arg1})
+	fake.clearMutex.Unlock()
+	if fake.ClearStub != nil {
+		return fake.ClearStub(arg1)
+	} else {
+		return fake.clearReturns.result1, fake.clearReturns.result2
+	}
+}
+
+func (fake *FakeCacheClearer) ClearCallCount() int {
+	fake.clearMutex.RLock()
+	defer fake.clearMutex.RUnlock()
+	return len(fake.clearArgsForCall)
+}
+
+func (fake *FakeCacheClearer) ClearArgsForCall(i int) resource.CacheIdentifier {
+	fake.clearMutex.RLock()
+	defer fake.clearMutex.RUnlock()
+	return fake.clearArgsForCall[i].arg1
+}
+
+func (fake *FakeCacheClearer) ClearReturns(result1 int, result2 error) {
+	fake.ClearStub = nil
+	fake.clearReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+var _ resourceserver.CacheClearer = new(FakeCacheClearer)
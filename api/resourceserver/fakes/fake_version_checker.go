@@ -0,0 +1,112 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"io"
+	"sync"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/resourceserver"
+	"github.com/pivotal-golang/lager"
+)
+
+type FakeVersionChecker struct {
+	VersionExistsStub        func(logger lager.Logger, resourceName string, version atc.Version) (bool, error)
+	versionExistsMutex       sync.RWMutex
+	versionExistsArgsForCall []struct {
+		logger       lager.Logger
+		resourceName string
+		version      atc.Version
+	}
+	versionExistsReturns struct {
+		result1 bool
+		result2 error
+	}
+	DebugCheckStub        func(logger lager.Logger, resourceName string, from atc.Version, stdout io.Writer, stderr io.Writer) ([]atc.Version, error)
+	debugCheckMutex       sync.RWMutex
+	debugCheckArgsForCall []struct {
+		logger       lager.Logger
+		resourceName string
+		from         atc.Version
+		stdout       io.Writer
+		stderr       io.Writer
+	}
+	debugCheckReturns struct {
+		result1 []atc.Version
+		result2 error
+	}
+}
+
+func (fake *FakeVersionChecker) VersionExists(logger lager.Logger, resourceName string, version atc.Version) (bool, error) {
+	fake.versionExistsMutex.Lock()
+	fake.versionExistsArgsForCall = append(fake.versionExistsArgsForCall, struct {
+		logger       lager.Logger
+		resourceName string
+		version      atc.Version
+	}{logger, resourceName, version})
+	fake.versionExistsMutex.Unlock()
+	if fake.VersionExistsStub != nil {
+		return fake.VersionExistsStub(logger, resourceName, version)
+	} else {
+		return fake.versionExistsReturns.result1, fake.versionExistsReturns.result2
+	}
+}
+
+func (fake *FakeVersionChecker) VersionExistsCallCount() int {
+	fake.versionExistsMutex.RLock()
+	defer fake.versionExistsMutex.RUnlock()
+	return len(fake.versionExistsArgsForCall)
+}
+
+func (fake *FakeVersionChecker) VersionExistsArgsForCall(i int) (lager.Logger, string, atc.Version) {
+	fake.versionExistsMutex.RLock()
+	defer fake.versionExistsMutex.RUnlock()
+	return fake.versionExistsArgsForCall[i].logger, fake.versionExistsArgsForCall[i].resourceName, fake.versionExistsArgsForCall[i].version
+}
+
+func (fake *FakeVersionChecker) VersionExistsReturns(result1 bool, result2 error) {
+	fake.VersionExistsStub = nil
+	fake.versionExistsReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVersionChecker) DebugCheck(logger lager.Logger, resourceName string, from atc.Version, stdout io.Writer, stderr io.Writer) ([]atc.Version, error) {
+	fake.debugCheckMutex.Lock()
+	fake.debugCheckArgsForCall = append(fake.debugCheckArgsForCall, struct {
+		logger       lager.Logger
+		resourceName string
+		from         atc.Version
+		stdout       io.Writer
+		stderr       io.Writer
+	}{logger, resourceName, from, stdout, stderr})
+	fake.debugCheckMutex.Unlock()
+	if fake.DebugCheckStub != nil {
+		return fake.DebugCheckStub(logger, resourceName, from, stdout, stderr)
+	} else {
+		return fake.debugCheckReturns.result1, fake.debugCheckReturns.result2
+	}
+}
+
+func (fake *FakeVersionChecker) DebugCheckCallCount() int {
+	fake.debugCheckMutex.RLock()
+	defer fake.debugCheckMutex.RUnlock()
+	return len(fake.debugCheckArgsForCall)
+}
+
+func (fake *FakeVersionChecker) DebugCheckArgsForCall(i int) (lager.Logger, string, atc.Version, io.Writer, io.Writer) {
+	fake.debugCheckMutex.RLock()
+	defer fake.debugCheckMutex.RUnlock()
+	return fake.debugCheckArgsForCall[i].logger, fake.debugCheckArgsForCall[i].resourceName, fake.debugCheckArgsForCall[i].from, fake.debugCheckArgsForCall[i].stdout, fake.debugCheckArgsForCall[i].stderr
+}
+
+func (fake *FakeVersionChecker) DebugCheckReturns(result1 []atc.Version, result2 error) {
+	fake.DebugCheckStub = nil
+	fake.debugCheckReturns = struct {
+		result1 []atc.Version
+		result2 error
+	}{result1, result2}
+}
+
+var _ resourceserver.VersionChecker = new(FakeVersionChecker)
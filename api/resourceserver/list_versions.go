@@ -0,0 +1,73 @@
+package resourceserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+	"github.com/tedsuo/rata"
+)
+
+// ListResourceVersions responds with every version of the resource, newest
+// first.
+//
+// The response is tagged with a checksum of the resource's versions and
+// whether each is enabled, so that clients polling for changes can skip
+// loading the history when nothing has changed since they last asked.
+func (s *Server) ListResourceVersions(pipelineDB db.PipelineDB) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resourceName := rata.Param(r, "resource_name")
+
+		config, _, err := pipelineDB.GetConfig()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_, found := config.Resources.Lookup(resourceName)
+		if !found {
+			apierror.Write(w, http.StatusNotFound, "resource_not_found", fmt.Sprintf("resource '%s' not found", resourceName))
+			return
+		}
+
+		dbResource, err := pipelineDB.GetResource(resourceName)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		checksum, err := pipelineDB.GetResourceVersionsChecksum(dbResource.ID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		etag := fmt.Sprintf(`"%s"`, checksum)
+		w.Header().Set("ETag", etag)
+
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		history, err := pipelineDB.GetResourceHistory(resourceName)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		versions := []atc.ResourceVersion{}
+		for _, vh := range history {
+			versions = append(versions, present.ResourceVersion(vh.VersionedResource))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(versions)
+	})
+}
@@ -1,8 +1,10 @@
 package resourceserver
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/db"
 	"github.com/tedsuo/rata"
 )
@@ -11,7 +13,19 @@ func (s *Server) PauseResource(pipelineDB db.PipelineDB) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resourceName := rata.Param(r, "resource_name")
 
-		err := pipelineDB.PauseResource(resourceName)
+		config, _, err := pipelineDB.GetConfig()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_, found := config.Resources.Lookup(resourceName)
+		if !found {
+			apierror.Write(w, http.StatusNotFound, "resource_not_found", fmt.Sprintf("resource '%s' not found", resourceName))
+			return
+		}
+
+		err = pipelineDB.PauseResource(resourceName)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
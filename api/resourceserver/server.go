@@ -1,23 +1,49 @@
 package resourceserver
 
 import (
+	"io"
+
 	"github.com/pivotal-golang/lager"
 
+	"github.com/concourse/atc"
 	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/resource"
 )
 
+//go:generate counterfeiter . CacheClearer
+
+type CacheClearer interface {
+	Clear(resource.CacheIdentifier) (int, error)
+}
+
+//go:generate counterfeiter . VersionChecker
+
+type VersionChecker interface {
+	VersionExists(logger lager.Logger, resourceName string, version atc.Version) (bool, error)
+	DebugCheck(logger lager.Logger, resourceName string, from atc.Version, stdout io.Writer, stderr io.Writer) ([]atc.Version, error)
+}
+
+type VersionCheckerFactory func(db.PipelineDB) VersionChecker
+
 type Server struct {
 	logger lager.Logger
 
-	validator auth.Validator
+	validator             auth.Validator
+	cacheClearer          CacheClearer
+	versionCheckerFactory VersionCheckerFactory
 }
 
 func NewServer(
 	logger lager.Logger,
 	validator auth.Validator,
+	cacheClearer CacheClearer,
+	versionCheckerFactory VersionCheckerFactory,
 ) *Server {
 	return &Server{
-		logger:    logger,
-		validator: validator,
+		logger:                logger,
+		validator:             validator,
+		cacheClearer:          cacheClearer,
+		versionCheckerFactory: versionCheckerFactory,
 	}
 }
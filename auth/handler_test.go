@@ -146,6 +146,65 @@ var _ = Describe("BasicAuthHandler", func() {
 	})
 })
 
+var _ = Describe("TokenHandler", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		server = httptest.NewServer(auth.Handler{
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}),
+			Validator: auth.TokenValidator{Token: "some-token"},
+		})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	request := func(method string, authorization string) int {
+		request, err := http.NewRequest(method, server.URL, nil)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		if authorization != "" {
+			request.Header.Set("Authorization", authorization)
+		}
+
+		response, err := http.DefaultClient.Do(request)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		response.Body.Close()
+
+		return response.StatusCode
+	}
+
+	It("requires the token, whatever the method", func() {
+		Ω(request("GET", "")).Should(Equal(http.StatusUnauthorized))
+		Ω(request("POST", "")).Should(Equal(http.StatusUnauthorized))
+		Ω(request("PUT", "Bearer wrong-token")).Should(Equal(http.StatusUnauthorized))
+		Ω(request("DELETE", header("username", "some-token"))).Should(Equal(http.StatusUnauthorized))
+
+		Ω(request("GET", "Bearer some-token")).Should(Equal(http.StatusOK))
+		Ω(request("POST", "Bearer some-token")).Should(Equal(http.StatusOK))
+		Ω(request("DELETE", "Bearer some-token")).Should(Equal(http.StatusOK))
+	})
+
+	Context("when the token is empty", func() {
+		BeforeEach(func() {
+			server.Close()
+
+			server = httptest.NewServer(auth.Handler{
+				Handler:   http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+				Validator: auth.TokenValidator{},
+			})
+		})
+
+		It("authenticates nothing", func() {
+			Ω(request("POST", "Bearer ")).Should(Equal(http.StatusUnauthorized))
+		})
+	})
+})
+
 var _ = Describe("ExtractUsernameAndPassword", func() {
 	Context("When the string starts with 'Basic '", func() {
 		Context("When the rest of the string is two non-empty strings separated by a colon, base64-encoded", func() {
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"crypto/subtle"
 	"encoding/base64"
 	"errors"
 	"net/http"
@@ -20,6 +21,22 @@ type NoopValidator struct{}
 
 func (NoopValidator) IsAuthenticated(*http.Request) bool { return true }
 
+// TokenValidator authenticates requests that present the token as a bearer
+// token in their 'Authorization' header.
+type TokenValidator struct {
+	Token string
+}
+
+func (validator TokenValidator) IsAuthenticated(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+
+	if validator.Token == "" || !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(auth[7:]), []byte(validator.Token)) == 1
+}
+
 type BasicAuthHashedValidator struct {
 	Username       string
 	HashedPassword string
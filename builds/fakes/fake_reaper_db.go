@@ -0,0 +1,126 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/builds"
+	"github.com/concourse/atc/db"
+)
+
+type FakeReaperDB struct {
+	GetPipelineNameStub        func() string
+	getPipelineNameMutex       sync.RWMutex
+	getPipelineNameArgsForCall []struct{}
+	getPipelineNameReturns     struct {
+		result1 string
+	}
+	GetConfigStub        func() (atc.Config, db.ConfigVersion, error)
+	getConfigMutex       sync.RWMutex
+	getConfigArgsForCall []struct{}
+	getConfigReturns     struct {
+		result1 atc.Config
+		result2 db.ConfigVersion
+		result3 error
+	}
+	ReapJobBuildsStub        func(job string, keep int, finishedBefore time.Time) ([]int, error)
+	reapJobBuildsMutex       sync.RWMutex
+	reapJobBuildsArgsForCall []struct {
+		job            string
+		keep           int
+		finishedBefore time.Time
+	}
+	reapJobBuildsReturns struct {
+		result1 []int
+		result2 error
+	}
+}
+
+func (fake *FakeReaperDB) GetPipelineName() string {
+	fake.getPipelineNameMutex.Lock()
+	fake.getPipelineNameArgsForCall = append(fake.getPipelineNameArgsForCall, struct{}{})
+	fake.getPipelineNameMutex.Unlock()
+	if fake.GetPipelineNameStub != nil {
+		return fake.GetPipelineNameStub()
+	} else {
+		return fake.getPipelineNameReturns.result1
+	}
+}
+
+func (fake *FakeReaperDB) GetPipelineNameCallCount() int {
+	fake.getPipelineNameMutex.RLock()
+	defer fake.getPipelineNameMutex.RUnlock()
+	return len(fake.getPipelineNameArgsForCall)
+}
+
+func (fake *FakeReaperDB) GetPipelineNameReturns(result1 string) {
+	fake.GetPipelineNameStub = nil
+	fake.getPipelineNameReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeReaperDB) GetConfig() (atc.Config, db.ConfigVersion, error) {
+	fake.getConfigMutex.Lock()
+	fake.getConfigArgsForCall = append(fake.getConfigArgsForCall, struct{}{})
+	fake.getConfigMutex.Unlock()
+	if fake.GetConfigStub != nil {
+		return fake.GetConfigStub()
+	} else {
+		return fake.getConfigReturns.result1, fake.getConfigReturns.result2, fake.getConfigReturns.result3
+	}
+}
+
+func (fake *FakeReaperDB) GetConfigCallCount() int {
+	fake.getConfigMutex.RLock()
+	defer fake.getConfigMutex.RUnlock()
+	return len(fake.getConfigArgsForCall)
+}
+
+func (fake *FakeReaperDB) GetConfigReturns(result1 atc.Config, result2 db.ConfigVersion, result3 error) {
+	fake.GetConfigStub = nil
+	fake.getConfigReturns = struct {
+		result1 atc.Config
+		result2 db.ConfigVersion
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeReaperDB) ReapJobBuilds(job string, keep int, finishedBefore time.Time) ([]int, error) {
+	fake.reapJobBuildsMutex.Lock()
+	fake.reapJobBuildsArgsForCall = append(fake.reapJobBuildsArgsForCall, struct {
+		job            string
+		keep           int
+		finishedBefore time.Time
+	}{job, keep, finishedBefore})
+	fake.reapJobBuildsMutex.Unlock()
+	if fake.ReapJobBuildsStub != nil {
+		return fake.ReapJobBuildsStub(job, keep, finishedBefore)
+	} else {
+		return fake.reapJobBuildsReturns.result1, fake.reapJobBuildsReturns.result2
+	}
+}
+
+func (fake *FakeReaperDB) ReapJobBuildsCallCount() int {
+	fake.reapJobBuildsMutex.RLock()
+	defer fake.reapJobBuildsMutex.RUnlock()
+	return len(fake.reapJobBuildsArgsForCall)
+}
+
+func (fake *FakeReaperDB) ReapJobBuildsArgsForCall(i int) (string, int, time.Time) {
+	fake.reapJobBuildsMutex.RLock()
+	defer fake.reapJobBuildsMutex.RUnlock()
+	return fake.reapJobBuildsArgsForCall[i].job, fake.reapJobBuildsArgsForCall[i].keep, fake.reapJobBuildsArgsForCall[i].finishedBefore
+}
+
+func (fake *FakeReaperDB) ReapJobBuildsReturns(result1 []int, result2 error) {
+	fake.ReapJobBuildsStub = nil
+	fake.reapJobBuildsReturns = struct {
+		result1 []int
+		result2 error
+	}{result1, result2}
+}
+
+var _ builds.ReaperDB = new(FakeReaperDB)
@@ -0,0 +1,56 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/builds"
+	"github.com/concourse/atc/db"
+)
+
+type FakeReaperLocker struct {
+	AcquireWriteLockImmediatelyStub        func([]db.NamedLock) (db.Lock, error)
+	acquireWriteLockImmediatelyMutex       sync.RWMutex
+	acquireWriteLockImmediatelyArgsForCall []struct {
+		arg1 []db.NamedLock
+	}
+	acquireWriteLockImmediatelyReturns struct {
+		result1 db.Lock
+		result2 error
+	}
+}
+
+func (fake *FakeReaperLocker) AcquireWriteLockImmediately(arg1 []db.NamedLock) (db.Lock, error) {
+	fake.acquireWriteLockImmediatelyMutex.Lock()
+	fake.acquireWriteLockImmediatelyArgsForCall = append(fake.acquireWriteLockImmediatelyArgsForCall, struct {
+		arg1 []db.NamedLock
+	}{arg1})
+	fake.acquireWriteLockImmediatelyMutex.Unlock()
+	if fake.AcquireWriteLockImmediatelyStub != nil {
+		return fake.AcquireWriteLockImmediatelyStub(arg1)
+	} else {
+		return fake.acquireWriteLockImmediatelyReturns.result1, fake.acquireWriteLockImmediatelyReturns.result2
+	}
+}
+
+func (fake *FakeReaperLocker) AcquireWriteLockImmediatelyCallCount() int {
+	fake.acquireWriteLockImmediatelyMutex.RLock()
+	defer fake.acquireWriteLockImmediatelyMutex.RUnlock()
+	return len(fake.acquireWriteLockImmediatelyArgsForCall)
+}
+
+func (fake *FakeReaperLocker) AcquireWriteLockImmediatelyArgsForCall(i int) []db.NamedLock {
+	fake.acquireWriteLockImmediatelyMutex.RLock()
+	defer fake.acquireWriteLockImmediatelyMutex.RUnlock()
+	return fake.acquireWriteLockImmediatelyArgsForCall[i].arg1
+}
+
+func (fake *FakeReaperLocker) AcquireWriteLockImmediatelyReturns(result1 db.Lock, result2 error) {
+	fake.AcquireWriteLockImmediatelyStub = nil
+	fake.acquireWriteLockImmediatelyReturns = struct {
+		result1 db.Lock
+		result2 error
+	}{result1, result2}
+}
+
+var _ builds.ReaperLocker = new(FakeReaperLocker)
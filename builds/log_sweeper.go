@@ -0,0 +1,86 @@
+package builds
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/concourse/atc/db"
+	"github.com/pivotal-golang/clock"
+	"github.com/pivotal-golang/lager"
+)
+
+//go:generate counterfeiter . LogSweeperDB
+
+type LogSweeperDB interface {
+	GetBuild(buildID int) (db.Build, error)
+}
+
+// LogSweeper periodically deletes the log files of builds that no longer
+// exist. The reaper deletes the files of the builds it reaps itself; this
+// catches the rest, e.g. those of destroyed pipelines.
+type LogSweeper struct {
+	Logger lager.Logger
+	DB     LogSweeperDB
+
+	BuildLogsDir string
+
+	Interval time.Duration
+	Clock    clock.Clock
+}
+
+func (sweeper LogSweeper) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	sweeper.sweep(sweeper.Logger.Session("sweep"))
+
+	ticker := sweeper.Clock.NewTicker(sweeper.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			sweeper.sweep(sweeper.Logger.Session("sweep"))
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+func (sweeper LogSweeper) sweep(logger lager.Logger) {
+	entries, err := ioutil.ReadDir(sweeper.BuildLogsDir)
+	if err != nil {
+		logger.Error("failed-to-read-build-logs-dir", err)
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".log") {
+			continue
+		}
+
+		buildID, err := strconv.Atoi(strings.TrimSuffix(entry.Name(), ".log"))
+		if err != nil {
+			// not a build's log
+			continue
+		}
+
+		_, err = sweeper.DB.GetBuild(buildID)
+		if err == nil {
+			continue
+		}
+
+		if err != db.ErrNoBuild {
+			logger.Error("failed-to-get-build", err, lager.Data{"build": buildID})
+			continue
+		}
+
+		err = os.Remove(filepath.Join(sweeper.BuildLogsDir, entry.Name()))
+		if err != nil && !os.IsNotExist(err) {
+			logger.Error("failed-to-remove-build-log", err, lager.Data{"build": buildID})
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package builds_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/clock/fakeclock"
+	"github.com/pivotal-golang/lager/lagertest"
+	"github.com/tedsuo/ifrit"
+
+	. "github.com/concourse/atc/builds"
+	"github.com/concourse/atc/builds/fakes"
+	"github.com/concourse/atc/db"
+)
+
+var _ = Describe("LogSweeper", func() {
+	var fakeDB *fakes.FakeLogSweeperDB
+	var fakeClock *fakeclock.FakeClock
+	var buildLogsDir string
+	var sweeper LogSweeper
+	var process ifrit.Process
+	var interval = time.Hour
+
+	logFiles := func() []string {
+		entries, err := ioutil.ReadDir(buildLogsDir)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		names := []string{}
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+
+		return names
+	}
+
+	BeforeEach(func() {
+		var err error
+		buildLogsDir, err = ioutil.TempDir("", "build-logs")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		for _, name := range []string{"1.log", "2.log", "3.log", "not-a-build.log"} {
+			err := ioutil.WriteFile(filepath.Join(buildLogsDir, name), []byte("some-output"), 0644)
+			Ω(err).ShouldNot(HaveOccurred())
+		}
+
+		fakeDB = new(fakes.FakeLogSweeperDB)
+		fakeDB.GetBuildStub = func(buildID int) (db.Build, error) {
+			switch buildID {
+			case 1:
+				return db.Build{}, db.ErrNoBuild
+			case 3:
+				return db.Build{}, errors.New("disaster")
+			default:
+				return db.Build{ID: buildID}, nil
+			}
+		}
+
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+
+		sweeper = LogSweeper{
+			Logger: lagertest.NewTestLogger("test"),
+			DB:     fakeDB,
+
+			BuildLogsDir: buildLogsDir,
+
+			Interval: interval,
+			Clock:    fakeClock,
+		}
+	})
+
+	JustBeforeEach(func() {
+		process = ifrit.Invoke(sweeper)
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive())
+
+		os.RemoveAll(buildLogsDir)
+	})
+
+	It("immediately deletes the log files of builds that no longer exist", func() {
+		Eventually(logFiles).Should(Equal([]string{"2.log", "3.log", "not-a-build.log"}))
+	})
+
+	Context("when the interval elapses", func() {
+		JustBeforeEach(func() {
+			Eventually(fakeDB.GetBuildCallCount).Should(Equal(3))
+
+			err := ioutil.WriteFile(filepath.Join(buildLogsDir, "1.log"), []byte("some-output"), 0644)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			fakeClock.Increment(interval)
+		})
+
+		It("sweeps again", func() {
+			Eventually(fakeDB.GetBuildCallCount).Should(Equal(6))
+			Eventually(logFiles).Should(Equal([]string{"2.log", "3.log", "not-a-build.log"}))
+		})
+	})
+})
@@ -0,0 +1,129 @@
+package builds
+
+import (
+	"os"
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/engine"
+	"github.com/pivotal-golang/clock"
+	"github.com/pivotal-golang/lager"
+)
+
+//go:generate counterfeiter . ReaperDB
+
+type ReaperDB interface {
+	GetPipelineName() string
+	GetConfig() (atc.Config, db.ConfigVersion, error)
+	ReapJobBuilds(job string, keep int, finishedBefore time.Time) ([]int, error)
+}
+
+//go:generate counterfeiter . ReaperLocker
+
+type ReaperLocker interface {
+	AcquireWriteLockImmediately([]db.NamedLock) (db.Lock, error)
+}
+
+// Reaper periodically deletes each job's old finished builds, according to
+// the job's builds_to_keep and keep_builds_for, falling back to the defaults
+// for jobs that configure neither.
+//
+// Only one ATC reaps a pipeline at a time; the others skip their turn.
+type Reaper struct {
+	Logger lager.Logger
+	Locker ReaperLocker
+	DB     ReaperDB
+
+	DefaultBuildsToKeep  int
+	DefaultKeepBuildsFor time.Duration
+
+	// if set, the reaped builds' log files are deleted from it too
+	BuildLogsDir string
+
+	Interval time.Duration
+	Clock    clock.Clock
+}
+
+func (reaper Reaper) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	reaper.reap(reaper.Logger.Session("reap"))
+
+	ticker := reaper.Clock.NewTicker(reaper.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			reaper.reap(reaper.Logger.Session("reap"))
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+func (reaper Reaper) reap(logger lager.Logger) {
+	lock, err := reaper.Locker.AcquireWriteLockImmediately([]db.NamedLock{
+		db.BuildReapingLock(reaper.DB.GetPipelineName()),
+	})
+	if err != nil {
+		return
+	}
+
+	defer lock.Release()
+
+	config, _, err := reaper.DB.GetConfig()
+	if err != nil {
+		logger.Error("failed-to-get-config", err)
+		return
+	}
+
+	for _, job := range config.Jobs {
+		keep := reaper.DefaultBuildsToKeep
+		keepFor := reaper.DefaultKeepBuildsFor
+
+		if job.BuildsToKeep != 0 || job.KeepBuildsFor != "" {
+			keep = job.BuildsToKeep
+			keepFor = 0
+
+			if job.KeepBuildsFor != "" {
+				keepFor, err = time.ParseDuration(job.KeepBuildsFor)
+				if err != nil {
+					logger.Error("invalid-keep-builds-for", err, lager.Data{"job": job.Name})
+					continue
+				}
+			}
+		}
+
+		var finishedBefore time.Time
+		if keepFor != 0 {
+			finishedBefore = reaper.Clock.Now().Add(-keepFor)
+		}
+
+		reaped, err := reaper.DB.ReapJobBuilds(job.Name, keep, finishedBefore)
+		if err != nil {
+			logger.Error("failed-to-reap-builds", err, lager.Data{"job": job.Name})
+			continue
+		}
+
+		if len(reaped) > 0 {
+			logger.Info("reaped-builds", lager.Data{"job": job.Name, "count": len(reaped)})
+		}
+
+		reaper.removeBuildLogs(logger, reaped)
+	}
+}
+
+func (reaper Reaper) removeBuildLogs(logger lager.Logger, buildIDs []int) {
+	if reaper.BuildLogsDir == "" {
+		return
+	}
+
+	for _, buildID := range buildIDs {
+		err := os.Remove(engine.BuildLogPath(reaper.BuildLogsDir, buildID))
+		if err != nil && !os.IsNotExist(err) {
+			logger.Error("failed-to-remove-build-log", err, lager.Data{"build": buildID})
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package builds_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/clock/fakeclock"
+	"github.com/pivotal-golang/lager/lagertest"
+	"github.com/tedsuo/ifrit"
+
+	"github.com/concourse/atc"
+	. "github.com/concourse/atc/builds"
+	"github.com/concourse/atc/builds/fakes"
+	"github.com/concourse/atc/db"
+	dbfakes "github.com/concourse/atc/db/fakes"
+)
+
+var _ = Describe("Reaper", func() {
+	var fakeDB *fakes.FakeReaperDB
+	var fakeLocker *fakes.FakeReaperLocker
+	var fakeLock *dbfakes.FakeLock
+	var fakeClock *fakeclock.FakeClock
+	var reaper Reaper
+	var process ifrit.Process
+	var interval = time.Minute
+
+	BeforeEach(func() {
+		fakeDB = new(fakes.FakeReaperDB)
+		fakeDB.GetPipelineNameReturns("some-pipeline")
+
+		fakeLock = new(dbfakes.FakeLock)
+		fakeLocker = new(fakes.FakeReaperLocker)
+		fakeLocker.AcquireWriteLockImmediatelyReturns(fakeLock, nil)
+
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+
+		fakeDB.GetConfigReturns(atc.Config{
+			Jobs: atc.JobConfigs{
+				{Name: "unconfigured-job"},
+				{Name: "counted-job", BuildsToKeep: 5},
+				{Name: "aged-job", KeepBuildsFor: "1h"},
+			},
+		}, 1, nil)
+
+		reaper = Reaper{
+			Logger: lagertest.NewTestLogger("test"),
+			Locker: fakeLocker,
+			DB:     fakeDB,
+
+			DefaultBuildsToKeep:  100,
+			DefaultKeepBuildsFor: 24 * time.Hour,
+
+			Interval: interval,
+			Clock:    fakeClock,
+		}
+	})
+
+	JustBeforeEach(func() {
+		process = ifrit.Invoke(reaper)
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive())
+	})
+
+	It("reaps each job's builds immediately", func() {
+		Eventually(fakeDB.ReapJobBuildsCallCount).Should(Equal(3))
+	})
+
+	It("reaps while holding the pipeline's reaping lock", func() {
+		Eventually(fakeDB.ReapJobBuildsCallCount).Should(Equal(3))
+
+		Ω(fakeLocker.AcquireWriteLockImmediatelyCallCount()).Should(Equal(1))
+		Ω(fakeLocker.AcquireWriteLockImmediatelyArgsForCall(0)).Should(Equal([]db.NamedLock{
+			db.BuildReapingLock("some-pipeline"),
+		}))
+
+		Eventually(fakeLock.ReleaseCallCount).Should(Equal(1))
+	})
+
+	Context("when another ATC is reaping the pipeline", func() {
+		BeforeEach(func() {
+			fakeLocker.AcquireWriteLockImmediatelyReturns(nil, errors.New("lock unavailable"))
+		})
+
+		It("does not reap", func() {
+			Eventually(fakeLocker.AcquireWriteLockImmediatelyCallCount).Should(Equal(1))
+			Consistently(fakeDB.ReapJobBuildsCallCount).Should(BeZero())
+		})
+	})
+
+	Context("with a build logs directory", func() {
+		var buildLogsDir string
+
+		BeforeEach(func() {
+			var err error
+			buildLogsDir, err = ioutil.TempDir("", "build-logs")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			for _, name := range []string{"1.log", "2.log", "3.log"} {
+				err := ioutil.WriteFile(filepath.Join(buildLogsDir, name), []byte("some-output"), 0644)
+				Ω(err).ShouldNot(HaveOccurred())
+			}
+
+			reaper.BuildLogsDir = buildLogsDir
+
+			fakeDB.ReapJobBuildsStub = func(job string, keep int, finishedBefore time.Time) ([]int, error) {
+				if job == "counted-job" {
+					return []int{1, 2, 4}, nil
+				}
+
+				return nil, nil
+			}
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(buildLogsDir)
+		})
+
+		It("deletes the reaped builds' log files", func() {
+			Eventually(fakeDB.ReapJobBuildsCallCount).Should(Equal(3))
+
+			Eventually(func() []string {
+				entries, err := ioutil.ReadDir(buildLogsDir)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				names := []string{}
+				for _, entry := range entries {
+					names = append(names, entry.Name())
+				}
+
+				return names
+			}).Should(Equal([]string{"3.log"}))
+		})
+	})
+
+	It("falls back to the defaults for jobs with no retention of their own", func() {
+		Eventually(fakeDB.ReapJobBuildsCallCount).Should(Equal(3))
+
+		job, keep, finishedBefore := fakeDB.ReapJobBuildsArgsForCall(0)
+		Ω(job).Should(Equal("unconfigured-job"))
+		Ω(keep).Should(Equal(100))
+		Ω(finishedBefore).Should(Equal(time.Unix(123, 0).Add(-24 * time.Hour)))
+	})
+
+	It("uses only the retention configured by the job", func() {
+		Eventually(fakeDB.ReapJobBuildsCallCount).Should(Equal(3))
+
+		job, keep, finishedBefore := fakeDB.ReapJobBuildsArgsForCall(1)
+		Ω(job).Should(Equal("counted-job"))
+		Ω(keep).Should(Equal(5))
+		Ω(finishedBefore).Should(BeZero())
+
+		job, keep, finishedBefore = fakeDB.ReapJobBuildsArgsForCall(2)
+		Ω(job).Should(Equal("aged-job"))
+		Ω(keep).Should(BeZero())
+		Ω(finishedBefore).Should(Equal(time.Unix(123, 0).Add(-time.Hour)))
+	})
+
+	Context("when reaping a job fails", func() {
+		BeforeEach(func() {
+			fakeDB.ReapJobBuildsStub = func(job string, keep int, finishedBefore time.Time) ([]int, error) {
+				if job == "unconfigured-job" {
+					return nil, errors.New("disaster")
+				}
+
+				return nil, nil
+			}
+		})
+
+		It("continues on to the other jobs", func() {
+			Eventually(fakeDB.ReapJobBuildsCallCount).Should(Equal(3))
+		})
+	})
+
+	Context("when the interval elapses", func() {
+		JustBeforeEach(func() {
+			Eventually(fakeDB.ReapJobBuildsCallCount).Should(Equal(3))
+			fakeClock.Increment(interval)
+		})
+
+		It("reaps again", func() {
+			Eventually(fakeDB.ReapJobBuildsCallCount).Should(Equal(6))
+			Consistently(fakeDB.ReapJobBuildsCallCount).Should(Equal(6))
+		})
+	})
+})
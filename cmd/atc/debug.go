@@ -0,0 +1,46 @@
+package main
+
+import (
+	"expvar"
+	"flag"
+	"net/http"
+
+	"github.com/tedsuo/ifrit/grouper"
+	"github.com/tedsuo/ifrit/http_server"
+)
+
+// debugEnabled reports whether pprof should be served. It only is when
+// -debug is given, or in dev mode unless -debug=false is given.
+func debugEnabled(flags *flag.FlagSet, debug bool, dev bool) bool {
+	enabled := dev
+
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "debug" {
+			enabled = debug
+		}
+	})
+
+	return enabled
+}
+
+// debugMembers returns the member serving the debug server on the given
+// address. See debugHandler for what it serves.
+func debugMembers(enabled bool, listenAddr string, handler http.Handler) grouper.Members {
+	return grouper.Members{
+		{"debug", http_server.New(listenAddr, debugHandler(enabled, handler))},
+	}
+}
+
+// debugHandler returns the given handler, which serves pprof as well as
+// expvar, if debugging is enabled. Otherwise it returns a handler serving
+// only expvar's /debug/vars, which is always available for metrics.
+func debugHandler(enabled bool, handler http.Handler) http.Handler {
+	if enabled {
+		return handler
+	}
+
+	vars := http.NewServeMux()
+	vars.Handle("/debug/vars", expvar.Handler())
+
+	return vars
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Debug server", func() {
+	Describe("debugEnabled", func() {
+		var flags *flag.FlagSet
+		var debug *bool
+		var dev *bool
+
+		BeforeEach(func() {
+			flags = flag.NewFlagSet("atc", flag.ContinueOnError)
+			flags.SetOutput(ioutil.Discard)
+
+			debug = flags.Bool("debug", false, "")
+			dev = flags.Bool("dev", false, "")
+		})
+
+		enabled := func(args ...string) bool {
+			Ω(flags.Parse(args)).Should(Succeed())
+			return debugEnabled(flags, *debug, *dev)
+		}
+
+		It("is disabled by default", func() {
+			Ω(enabled()).Should(BeFalse())
+		})
+
+		It("is enabled with -debug", func() {
+			Ω(enabled("-debug")).Should(BeTrue())
+		})
+
+		It("is enabled by default in dev mode", func() {
+			Ω(enabled("-dev")).Should(BeTrue())
+		})
+
+		It("can be disabled explicitly in dev mode", func() {
+			Ω(enabled("-dev", "-debug=false")).Should(BeFalse())
+		})
+	})
+
+	Describe("debugMembers", func() {
+		It("includes the debug member whether or not debugging is enabled", func() {
+			for _, enabled := range []bool{true, false} {
+				members := debugMembers(enabled, "127.0.0.1:8079", http.DefaultServeMux)
+				Ω(members).Should(HaveLen(1))
+				Ω(members[0].Name).Should(Equal("debug"))
+			}
+		})
+	})
+
+	Describe("debugHandler", func() {
+		get := func(handler http.Handler, path string) int {
+			request, err := http.NewRequest("GET", "http://127.0.0.1:8079"+path, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			return recorder.Code
+		}
+
+		It("serves pprof and expvar when enabled", func() {
+			handler := debugHandler(true, http.DefaultServeMux)
+			Ω(get(handler, "/debug/pprof/")).Should(Equal(http.StatusOK))
+			Ω(get(handler, "/debug/vars")).Should(Equal(http.StatusOK))
+		})
+
+		It("serves only expvar when disabled", func() {
+			handler := debugHandler(false, http.DefaultServeMux)
+			Ω(get(handler, "/debug/pprof/")).Should(Equal(http.StatusNotFound))
+			Ω(get(handler, "/debug/vars")).Should(Equal(http.StatusOK))
+		})
+	})
+})
@@ -9,7 +9,8 @@ import (
 	_ "net/http/pprof"
 	"net/url"
 	"os"
-	"strings"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/BurntSushi/migration"
@@ -29,9 +30,14 @@ import (
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/api"
 	"github.com/concourse/atc/api/buildserver"
+	"github.com/concourse/atc/api/configserver"
+	"github.com/concourse/atc/api/jobserver"
+	"github.com/concourse/atc/api/resourceserver"
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/builds"
+	"github.com/concourse/atc/compression"
 	"github.com/concourse/atc/config"
+	"github.com/concourse/atc/credentials"
 	Db "github.com/concourse/atc/db"
 	"github.com/concourse/atc/db/migrations"
 	"github.com/concourse/atc/engine"
@@ -44,11 +50,15 @@ import (
 	"github.com/concourse/atc/worker"
 )
 
-var pipelinePath = flag.String(
-	"pipeline",
-	"",
-	"path to atc pipeline config .yml",
-)
+var pipelinePaths pathsFlag
+
+func init() {
+	flag.Var(
+		&pipelinePaths,
+		"pipeline",
+		"path to atc pipeline config .yml to load as the main pipeline; reloaded on SIGHUP. may be given more than once, or as a comma-separated list, to merge a config split across files",
+	)
+}
 
 var templatesDir = flag.String(
 	"templates",
@@ -62,6 +72,12 @@ var publicDir = flag.String(
 	"path to directory containing public resources (javascript, css, etc.)",
 )
 
+var publicMaxAge = flag.Duration(
+	"publicMaxAge",
+	365*24*time.Hour,
+	"how long browsers may cache public resources requested by their fingerprinted names.",
+)
+
 var gardenNetwork = flag.String(
 	"gardenNetwork",
 	"",
@@ -74,6 +90,24 @@ var gardenAddr = flag.String(
 	"garden API network address (host:port or socket path). leave empty for dynamic registration.",
 )
 
+var artifactCacheDir = flag.String(
+	"artifactCacheDir",
+	"",
+	"directory in which to cache fetched artifacts across builds. leave empty to disable.",
+)
+
+var artifactCacheMaxBytes = flag.Int64(
+	"artifactCacheMaxBytes",
+	10*1024*1024*1024,
+	"maximum number of bytes of artifacts to keep in -artifactCacheDir before removing the least recently used. 0 for no limit.",
+)
+
+var gzipArtifactStreams = flag.Bool(
+	"gzipArtifactStreams",
+	false,
+	"gzip artifacts as they are streamed between steps, decompressing them just before they are streamed into the destination container",
+)
+
 var resourceTypes = flag.String(
 	"resourceTypes",
 	`[
@@ -90,6 +124,12 @@ var resourceTypes = flag.String(
 	"map of resource type to its rootfs",
 )
 
+var customResourceTypes = flag.String(
+	"customResourceTypes",
+	"[]",
+	`resource types whose images are Docker images fetched by a docker-image resource with the given source, e.g. [{"type": "some-type", "image_source": {"repository": "some/some-type-resource"}}]`,
+)
+
 var sqlDriver = flag.String(
 	"sqlDriver",
 	"postgres",
@@ -102,6 +142,18 @@ var sqlDataSource = flag.String(
 	"database/sql data source configuration string",
 )
 
+var dbConnectTimeout = flag.Duration(
+	"dbConnectTimeout",
+	5*time.Minute,
+	"how long to keep retrying to reach the database at startup before exiting (0 retries forever)",
+)
+
+var migrateOnly = flag.Bool(
+	"migrateOnly",
+	false,
+	"run the database migrations and exit",
+)
+
 var webListenAddress = flag.String(
 	"webListenAddress",
 	"0.0.0.0",
@@ -114,22 +166,34 @@ var webListenPort = flag.Int(
 	"port for the web server to listen on",
 )
 
+var webListenSocket = flag.String(
+	"webListenSocket",
+	"",
+	"path to a unix socket for the web server to listen on, instead of -webListenAddress and -webListenPort",
+)
+
 var callbacksURLString = flag.String(
 	"callbacksURL",
 	"http://127.0.0.1:8080",
-	"URL used for callbacks to reach the ATC (excluding basic auth)",
+	"URL used for callbacks to reach the ATC, including scheme and any base path (excluding basic auth)",
+)
+
+var debug = flag.Bool(
+	"debug",
+	false,
+	"also serve pprof on the debug server, which otherwise only serves expvar's /debug/vars; on by default in dev mode",
 )
 
 var debugListenAddress = flag.String(
 	"debugListenAddress",
 	"127.0.0.1",
-	"address for the pprof debugger listen on",
+	"address for the debug server to listen on",
 )
 
 var debugListenPort = flag.Int(
 	"debugListenPort",
 	8079,
-	"port for the pprof debugger to listen on",
+	"port for the debug server to listen on",
 )
 
 var httpUsername = flag.String(
@@ -150,12 +214,102 @@ var httpHashedPassword = flag.String(
 	"bcrypted basic auth password for the server",
 )
 
+var buildLogsDir = flag.String(
+	"buildLogsDir",
+	"",
+	"directory in which to also write each build's output, in a file named after the build's ID. files are deleted along with their builds. leave empty to disable.",
+)
+
+var drainFlushTimeout = flag.Duration(
+	"drainFlushTimeout",
+	10*time.Second,
+	"how long to wait for running builds' buffered output to be saved when shutting down",
+)
+
+var credentialEnvPrefix = flag.String(
+	"credentialEnvPrefix",
+	"",
+	"if set, credentials referenced as ((key)) in task params and resource sources are read from the ATC's environment variables with this prefix, e.g. CONCOURSE_CREDENTIAL_",
+)
+
+var maxResourceOutputSize = flag.Int64(
+	"maxResourceOutputSize",
+	64*1024*1024,
+	"maximum number of bytes a resource's check, in, or out script may print to stdout before it is stopped",
+)
+
+var resourceHTTPProxy = flag.String(
+	"resourceHTTPProxy",
+	"",
+	"proxy for resources' scripts to reach HTTP services through, unless the resource configures its own",
+)
+
+var resourceHTTPSProxy = flag.String(
+	"resourceHTTPSProxy",
+	"",
+	"proxy for resources' scripts to reach HTTPS services through, unless the resource configures its own",
+)
+
+var resourceNoProxy = flag.String(
+	"resourceNoProxy",
+	"",
+	"comma-separated hosts that resources' scripts should reach without a proxy",
+)
+
+var checkContainerGraceTime = flag.Duration(
+	"checkContainerGraceTime",
+	5*time.Minute,
+	"how long garden should keep a resource check container around once the ATC stops heartbeating it",
+)
+
+var buildContainerGraceTime = flag.Duration(
+	"buildContainerGraceTime",
+	1*time.Hour,
+	"how long garden should keep a build's containers around once the ATC stops heartbeating them",
+)
+
 var checkInterval = flag.Duration(
 	"checkInterval",
 	1*time.Minute,
 	"interval on which to poll for new versions of resources",
 )
 
+var buildsToKeep = flag.Int(
+	"buildsToKeep",
+	0,
+	"number of finished builds to keep per job, for jobs that do not configure builds_to_keep or keep_builds_for (0 keeps all)",
+)
+
+var keepBuildsFor = flag.Duration(
+	"keepBuildsFor",
+	0,
+	"how long to keep finished builds per job, for jobs that do not configure builds_to_keep or keep_builds_for (0 keeps them forever)",
+)
+
+var maxConcurrentBuilds = flag.Int(
+	"maxConcurrentBuilds",
+	0,
+	"maximum number of builds this ATC starts and runs at once across all pipelines (0 for no limit); builds beyond it stay pending, and jobs take turns starting theirs. builds run by other ATCs, or resumed after a restart, do not count toward it",
+)
+
+var maxConcurrentChecks = flag.Int(
+	"maxConcurrentChecks",
+	0,
+	"maximum number of resource checks to run at once (0 for no limit)",
+)
+
+var maxConcurrentChecksPerHost = flag.Int(
+	"maxConcurrentChecksPerHost",
+	0,
+	"maximum number of checks to run at once for resources whose source 'uri' shares a host (0 for no limit)",
+)
+
+var maxSystemRetries = flag.Int(
+	"maxSystemRetries",
+	0,
+	"number of times to automatically retry a build that errored due to a system error (e.g. a worker going away)",
+)
+
 var publiclyViewable = flag.Bool(
 	"publiclyViewable",
 	false,
@@ -168,6 +322,12 @@ var dev = flag.Bool(
 	"dev mode; lax security",
 )
 
+var devToken = flag.String(
+	"devToken",
+	"",
+	"in dev mode, require this token as a bearer token for any API call that is not a read. workers registering themselves with the API must then present it too, and builds can no longer be triggered from the web UI",
+)
+
 var noop = flag.Bool(
 	"noop",
 	false,
@@ -181,18 +341,24 @@ var cliDownloadsDir = flag.String(
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(validateCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	flag.Parse()
 
-	if !*dev && (*httpUsername == "" || (*httpHashedPassword == "" && *httpPassword == "")) {
-		fatal(errors.New("must specify -httpUsername and -httpPassword or -httpHashedPassword or turn on dev mode"))
-	}
+	if !*migrateOnly {
+		if !*dev && (*httpUsername == "" || (*httpHashedPassword == "" && *httpPassword == "")) {
+			fatal(errors.New("must specify -httpUsername and -httpPassword or -httpHashedPassword or turn on dev mode"))
+		}
 
-	if _, err := os.Stat(*templatesDir); err != nil {
-		fatal(errors.New("directory specified via -templates does not exist"))
-	}
+		if _, err := os.Stat(*templatesDir); err != nil {
+			fatal(errors.New("directory specified via -templates does not exist"))
+		}
 
-	if _, err := os.Stat(*publicDir); err != nil {
-		fatal(errors.New("directory specified via -public does not exist"))
+		if _, err := os.Stat(*publicDir); err != nil {
+			fatal(errors.New("directory specified via -public does not exist"))
+		}
 	}
 
 	logger := lager.NewLogger("atc")
@@ -205,23 +371,21 @@ func main() {
 	sink := lager.NewReconfigurableSink(lager.NewWriterSink(os.Stdout, lager.DEBUG), logLevel)
 	logger.RegisterSink(sink)
 
-	var err error
-
-	var dbConn Db.Conn
-
-	for {
-		dbConn, err = migration.Open(*sqlDriver, *sqlDataSource, migrations.Migrations)
-		if err != nil {
-			if strings.Contains(err.Error(), " dial ") {
-				logger.Error("failed-to-open-db", err)
-				time.Sleep(5 * time.Second)
-				continue
-			}
-
-			fatal(err)
-		}
+	dbConn, err := Db.Connect(logger.Session("db-connect"), clock.NewClock(), *dbConnectTimeout, func() (Db.Conn, error) {
+		return migration.Open(
+			*sqlDriver,
+			*sqlDataSource,
+			migrations.WithLogging(logger.Session("migrations"), migrations.Migrations),
+		)
+	})
+	if err != nil {
+		fatal(err)
+	}
 
-		break
+	if *migrateOnly {
+		dbConn.Close()
+		logger.Info("migrated")
+		return
 	}
 
 	dbConn = Db.Explain(logger, dbConn, 500*time.Millisecond)
@@ -241,6 +405,14 @@ func main() {
 		logger.Fatal("invalid-resource-types", err)
 	}
 
+	var customResourceTypesNG []resource.CustomResourceType
+	err = json.Unmarshal([]byte(*customResourceTypes), &customResourceTypesNG)
+	if err != nil {
+		logger.Fatal("invalid-custom-resource-types", err)
+	}
+
+	configValidator := configserver.ConfigValidator(config.ValidateConfig)
+
 	var workerClient worker.Client
 	if *gardenAddr != "" {
 		workerClient = worker.NewGardenWorker(
@@ -255,12 +427,54 @@ func main() {
 			"linux",
 			[]string{},
 		)
+
+		// with a single static worker, every type a pipeline uses must be known
+		// up front; refuse configs using any other type rather than failing on
+		// the first check
+		resourceMapping := resource.NewResourceMapping(resourceTypesNG)
+
+		// custom types' images are only known once they are fetched
+		for _, t := range customResourceTypesNG {
+			resourceMapping[resource.ResourceType(t.Type)] = ""
+		}
+		configValidator = validateResourceTypes(config.ValidateConfig, resourceMapping)
+
+		// pipelines saved before the types were known are left running, as
+		// their other resources may still work
+		warnAboutUnknownResourceTypes(logger.Session("validate-resource-types"), db, pipelineDBFactory, resourceMapping)
 	} else {
 		workerClient = worker.NewPool(worker.NewDBWorkerProvider(db, logger))
 	}
 
-	resourceTracker := resource.NewTracker(workerClient)
-	gardenFactory := exec.NewGardenFactory(workerClient, resourceTracker, func() string {
+	resourceProxy := atc.ProxyConfig{
+		HTTP:    *resourceHTTPProxy,
+		HTTPS:   *resourceHTTPSProxy,
+		NoProxy: *resourceNoProxy,
+	}
+
+	// custom types' images are checked for and fetched with the docker-image
+	// type provided by the workers
+	imageTracker := resource.NewTracker(workerClient, nil, *checkContainerGraceTime, *buildContainerGraceTime, *maxResourceOutputSize, resourceProxy)
+	imageFetcher := resource.NewImageFetcher(imageTracker, customResourceTypesNG)
+
+	resourceTracker := resource.NewTracker(workerClient, imageFetcher, *checkContainerGraceTime, *buildContainerGraceTime, *maxResourceOutputSize, resourceProxy)
+	// a cached fetch is only useful for as long as its container is around
+	resourceCache := resource.NewCache(*buildContainerGraceTime, clock.NewClock())
+
+	var artifactCache exec.ArtifactCache
+	if *artifactCacheDir != "" {
+		artifactCache, err = exec.NewDirArtifactCache(*artifactCacheDir, *artifactCacheMaxBytes)
+		if err != nil {
+			logger.Fatal("failed-to-load-artifact-cache", err)
+		}
+	}
+
+	var credentialManager credentials.CredentialManager
+	if *credentialEnvPrefix != "" {
+		credentialManager = credentials.EnvCredentialManager{Prefix: *credentialEnvPrefix}
+	}
+
+	gardenFactory := exec.NewGardenFactory(workerClient, resourceTracker, resourceCache, artifactCache, credentialManager, *buildContainerGraceTime, *gzipArtifactStreams, func() string {
 		guid, err := uuid.NewV4()
 		if err != nil {
 			panic("not enough entropy to generate guid: " + err.Error())
@@ -268,7 +482,8 @@ func main() {
 
 		return guid.String()
 	})
-	execEngine := engine.NewExecEngine(gardenFactory, engine.NewBuildDelegateFactory(db), db)
+	buildDelegateFactory := engine.NewBuildDelegateFactory(db, *buildLogsDir)
+	execEngine := engine.NewExecEngine(gardenFactory, buildDelegateFactory, db, clock.NewClock())
 
 	engine := engine.NewDBEngine(engine.Engines{execEngine}, db, db)
 
@@ -288,16 +503,53 @@ func main() {
 		webValidator = auth.NoopValidator{}
 	}
 
+	writeValidator := webValidator
+	if _, open := webValidator.(auth.NoopValidator); open && *devToken != "" {
+		// reads stay open, but anything else must present the token
+		writeValidator = auth.TokenValidator{Token: *devToken}
+	}
+
 	callbacksURL, err := url.Parse(*callbacksURLString)
 	if err != nil {
 		fatal(err)
 	}
 
+	if callbacksURL.Scheme == "" || callbacksURL.Host == "" {
+		fatal(fmt.Errorf("invalid -callbacksURL %q: must include a scheme and host", *callbacksURLString))
+	}
+
 	drain := make(chan struct{})
 
+	radarSchedulerFactory := pipelines.NewRadarSchedulerFactory(
+		resourceTracker,
+		credentialManager,
+		*checkInterval,
+		*maxSystemRetries,
+		rdr.NewGroupedCheckLimiter(*maxConcurrentChecks, rdr.SourceURIHost, *maxConcurrentChecksPerHost),
+		sched.NewBuildLimiter(*maxConcurrentBuilds, clock.NewClock()),
+		db,
+		engine,
+		db,
+		clock.NewClock(),
+	)
+
+	jobSchedulerFactory := func(pipelineDB Db.PipelineDB) jobserver.BuildScheduler {
+		return radarSchedulerFactory.BuildScheduler(pipelineDB)
+	}
+
+	versionCheckerFactory := func(pipelineDB Db.PipelineDB) resourceserver.VersionChecker {
+		return radarSchedulerFactory.BuildRadar(pipelineDB)
+	}
+
+	fetchCacheClearer := exec.FetchCacheClearer{
+		ResourceCache: resourceCache,
+		ArtifactCache: artifactCache,
+	}
+
 	apiHandler, err := api.NewHandler(
 		logger,            // logger lager.Logger,
-		webValidator,      // validator auth.Validator,
+		webValidator,      // readValidator auth.Validator,
+		writeValidator,    // writeValidator auth.Validator,
 		pipelineDBFactory, // pipelineDBFactory db.PipelineDBFactory,
 
 		configDB, // configDB db.ConfigDB,
@@ -307,9 +559,12 @@ func main() {
 		db, // pipeDB pipes.PipeDB,
 		db, // pipelinesDB db.PipelinesDB,
 
-		config.ValidateConfig,       // configValidator configserver.ConfigValidator,
+		configValidator,             // configValidator configserver.ConfigValidator,
 		callbacksURL.String(),       // peerURL string,
 		buildserver.NewEventHandler, // eventHandlerFactory buildserver.EventHandlerFactory,
+		jobSchedulerFactory,         // schedulerFactory jobserver.SchedulerFactory,
+		fetchCacheClearer,           // cacheClearer resourceserver.CacheClearer,
+		versionCheckerFactory,       // versionCheckerFactory resourceserver.VersionCheckerFactory,
 		drain, // drain <-chan struct{},
 
 		engine,       // engine engine.Engine,
@@ -323,23 +578,17 @@ func main() {
 		fatal(err)
 	}
 
-	radarSchedulerFactory := pipelines.NewRadarSchedulerFactory(
-		resourceTracker,
-		*checkInterval,
-		db,
-		engine,
-		db,
-	)
-
 	webHandler, err := web.NewHandler(
 		logger,
 		webValidator,
+		writeValidator,
 		radarSchedulerFactory,
 		db,
 		pipelineDBFactory,
 		configDB,
 		*templatesDir,
 		*publicDir,
+		*publicMaxAge,
 		engine,
 	)
 	if err != nil {
@@ -354,6 +603,10 @@ func main() {
 
 	httpHandler = webMux
 
+	httpHandler = compression.Handler{
+		Handler: httpHandler,
+	}
+
 	if !*publiclyViewable {
 		httpHandler = auth.Handler{
 			Handler:   httpHandler,
@@ -386,6 +639,7 @@ func main() {
 						radarSchedulerFactory.BuildRadar(pipelineDB),
 						pipelineDB,
 						1*time.Minute,
+						clock.NewClock(),
 					),
 				},
 				{
@@ -401,6 +655,23 @@ func main() {
 						Noop: *noop,
 
 						Interval: 10 * time.Second,
+						Clock:    clock.NewClock(),
+					},
+				},
+				{
+					pipelineDB.ScopedName("reaper"),
+					builds.Reaper{
+						Logger: logger.Session(pipelineDB.ScopedName("reaper")),
+						Locker: db,
+						DB:     pipelineDB,
+
+						DefaultBuildsToKeep:  *buildsToKeep,
+						DefaultKeepBuildsFor: *keepBuildsFor,
+
+						BuildLogsDir: *buildLogsDir,
+
+						Interval: 10 * time.Minute,
+						Clock:    clock.NewClock(),
 					},
 				},
 			})
@@ -413,10 +684,19 @@ func main() {
 		engine,
 	)
 
-	memberGrouper := []grouper.Member{
-		{"web", http_server.New(webListenAddr, httpHandler)},
+	var webServer ifrit.Runner
+	if *webListenSocket != "" {
+		webListenAddr = "unix:" + *webListenSocket
+		webServer = unixSocketServer{
+			path:    *webListenSocket,
+			handler: httpHandler,
+		}
+	} else {
+		webServer = http_server.New(webListenAddr, httpHandler)
+	}
 
-		{"debug", http_server.New(debugListenAddr, http.DefaultServeMux)},
+	memberGrouper := []grouper.Member{
+		{"web", webServer},
 
 		{"drainer", ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
 			close(ready)
@@ -425,6 +705,20 @@ func main() {
 
 			close(drain)
 
+			// save whatever running builds have buffered, but don't let a
+			// struggling database hold up the shutdown
+			flushed := make(chan struct{})
+			go func() {
+				buildDelegateFactory.Drain(logger.Session("drain"))
+				close(flushed)
+			}()
+
+			select {
+			case <-flushed:
+			case <-time.After(*drainFlushTimeout):
+				logger.Info("timed-out-flushing-build-events")
+			}
+
 			return nil
 		})},
 
@@ -439,16 +733,66 @@ func main() {
 			Interval: 10 * time.Second,
 			Clock:    clock.NewClock(),
 		}},
+
+		{"orphan-reaper", worker.OrphanReaper{
+			Logger: logger.Session("orphan-reaper"),
+
+			Client: workerClient,
+			DB:     db,
+
+			BuildGraceTime: *buildContainerGraceTime,
+
+			Clock: clock.NewClock(),
+		}},
+	}
+
+	if *buildLogsDir != "" {
+		memberGrouper = append(memberGrouper, grouper.Member{
+			Name: "build-log-sweeper",
+			Runner: builds.LogSweeper{
+				Logger: logger.Session("build-log-sweeper"),
+				DB:     db,
+
+				BuildLogsDir: *buildLogsDir,
+
+				Interval: time.Hour,
+				Clock:    clock.NewClock(),
+			},
+		})
 	}
 
+	if len(pipelinePaths) > 0 {
+		reload := make(chan os.Signal, 1)
+		signal.Notify(reload, syscall.SIGHUP)
+
+		memberGrouper = append(memberGrouper, grouper.Member{
+			Name: "config-reloader",
+			Runner: pipelines.ConfigReloader{
+				Logger: logger.Session("config-reloader"),
+
+				DB: db,
+
+				Validator: configValidator,
+
+				PipelineName: atc.DefaultPipelineName,
+				ConfigPaths:  pipelinePaths,
+
+				Reload: reload,
+			},
+		})
+	}
+
+	debugging := debugEnabled(flag.CommandLine, *debug, *dev)
+
+	memberGrouper = append(memberGrouper, debugMembers(debugging, debugListenAddr, http.DefaultServeMux)...)
+
 	group := grouper.NewParallel(os.Interrupt, memberGrouper)
 
 	running := ifrit.Envoke(sigmon.New(group))
 
-	logger.Info("listening", lager.Data{
-		"web":   webListenAddr,
-		"debug": debugListenAddr,
-	})
+	listening := lager.Data{"web": webListenAddr, "debug": debugListenAddr}
+
+	logger.Info("listening", listening)
 
 	err = <-running.Wait()
 	if err != nil {
@@ -457,6 +801,38 @@ func main() {
 	}
 }
 
+func validateResourceTypes(validate configserver.ConfigValidator, mapping resource.ResourceMapping) configserver.ConfigValidator {
+	return func(pipelineConfig atc.Config) error {
+		err := validate(pipelineConfig)
+		if err != nil {
+			return err
+		}
+
+		return mapping.Validate(pipelineConfig)
+	}
+}
+
+func warnAboutUnknownResourceTypes(logger lager.Logger, pipelinesDB Db.PipelinesDB, pipelineDBFactory Db.PipelineDBFactory, mapping resource.ResourceMapping) {
+	savedPipelines, err := pipelinesDB.GetAllActivePipelines()
+	if err != nil {
+		logger.Error("failed-to-get-pipelines", err)
+		return
+	}
+
+	for _, pipeline := range savedPipelines {
+		pipelineConfig, _, err := pipelineDBFactory.Build(pipeline).GetConfig()
+		if err != nil {
+			logger.Error("failed-to-get-config", err, lager.Data{"pipeline": pipeline.Name})
+			continue
+		}
+
+		err = mapping.Validate(pipelineConfig)
+		if err != nil {
+			logger.Error("unknown-resource-types", err, lager.Data{"pipeline": pipeline.Name})
+		}
+	}
+}
+
 func fatal(err error) {
 	println(err.Error())
 	os.Exit(1)
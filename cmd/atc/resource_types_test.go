@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/resource"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("validateResourceTypes", func() {
+	var (
+		configErr error
+		mapping   resource.ResourceMapping
+
+		pipelineConfig atc.Config
+	)
+
+	BeforeEach(func() {
+		configErr = nil
+		mapping = resource.NewResourceMapping([]atc.WorkerResourceType{
+			{Type: "git", Image: "some-git-image"},
+		})
+
+		pipelineConfig = atc.Config{
+			Resources: atc.ResourceConfigs{
+				{Name: "some-resource", Type: "git"},
+			},
+		}
+	})
+
+	validate := func() error {
+		return validateResourceTypes(func(atc.Config) error {
+			return configErr
+		}, mapping)(pipelineConfig)
+	}
+
+	It("accepts a config whose resource types are all known", func() {
+		Ω(validate()).Should(Succeed())
+	})
+
+	Context("when a resource has an unknown type", func() {
+		BeforeEach(func() {
+			pipelineConfig.Resources = append(pipelineConfig.Resources, atc.ResourceConfig{
+				Name: "some-other-resource",
+				Type: "bogus",
+			})
+		})
+
+		It("rejects the config, naming the type", func() {
+			Ω(validate()).Should(Equal(resource.UnknownResourceTypesError{
+				Types: []string{"bogus"},
+			}))
+		})
+	})
+
+	Context("when the config is otherwise invalid", func() {
+		BeforeEach(func() {
+			configErr = errors.New("nope")
+		})
+
+		It("returns that error", func() {
+			Ω(validate()).Should(Equal(configErr))
+		})
+	})
+})
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+)
+
+// unixSocketServer serves HTTP on a Unix domain socket, removing any stale
+// socket left behind by a previous run before listening and removing its own
+// socket once it exits.
+type unixSocketServer struct {
+	path    string
+	handler http.Handler
+}
+
+func (server unixSocketServer) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	err := removeStaleSocket(server.path)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("unix", server.path)
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(server.path)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- http.Serve(listener, server.handler)
+	}()
+
+	close(ready)
+
+	select {
+	case <-signals:
+		return listener.Close()
+	case err := <-serveErr:
+		return err
+	}
+}
+
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("refusing to remove %s: not a socket", path)
+	}
+
+	return os.Remove(path)
+}
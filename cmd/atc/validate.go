@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/config"
+	"github.com/concourse/atc/resource"
+)
+
+// validateCommand loads and validates a pipeline config without touching the
+// database or starting any servers, returning the exit status.
+func validateCommand(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var pipelines pathsFlag
+	flags.Var(
+		&pipelines,
+		"pipeline",
+		"path to atc pipeline config .yml. may be given more than once, or as a comma-separated list, to merge a config split across files",
+	)
+
+	resourceTypes := flags.String(
+		"resourceTypes",
+		"",
+		"if specified, also check that each resource's type is in this map of resource type to its rootfs",
+	)
+
+	err := flags.Parse(args)
+	if err != nil {
+		return 2
+	}
+
+	if len(pipelines) == 0 {
+		fmt.Fprintln(stderr, "must specify -pipeline")
+		return 2
+	}
+
+	pipelineConfig, err := config.LoadConfigFiles(pipelines...)
+	if err != nil {
+		fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+
+	if *resourceTypes != "" {
+		var types []atc.WorkerResourceType
+		err := json.Unmarshal([]byte(*resourceTypes), &types)
+		if err != nil {
+			fmt.Fprintf(stderr, "invalid -resourceTypes: %s\n", err)
+			return 2
+		}
+
+		err = resource.NewResourceMapping(types).Validate(pipelineConfig)
+		if err != nil {
+			fmt.Fprintln(stderr, err.Error())
+			return 1
+		}
+	}
+
+	fmt.Fprintln(stdout, "pipeline is valid")
+
+	return 0
+}
+
+type pathsFlag []string
+
+func (paths *pathsFlag) String() string {
+	return strings.Join(*paths, ",")
+}
+
+func (paths *pathsFlag) Set(value string) error {
+	for _, path := range strings.Split(value, ",") {
+		if path != "" {
+			*paths = append(*paths, path)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+// Package compression gzips responses for clients that accept it.
+package compression
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// MinSize is the smallest response body worth compressing; anything smaller
+// is sent as-is, as gzip's overhead would outweigh the savings.
+const MinSize = 1400
+
+// Handler gzips the wrapped handler's responses when the client sends
+// Accept-Encoding: gzip.
+//
+// Responses that are already encoded or are of an already-compressed content
+// type, responses smaller than MinSize, and event streams are passed through
+// unchanged, as are websocket upgrades.
+type Handler struct {
+	Handler http.Handler
+}
+
+func (handler Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if isStream(r) {
+		handler.Handler.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept-Encoding")
+
+	if !acceptsGzip(r) {
+		handler.Handler.ServeHTTP(w, r)
+		return
+	}
+
+	writer := &gzipWriter{ResponseWriter: w}
+	defer writer.Close()
+
+	handler.Handler.ServeHTTP(writer, r)
+}
+
+// websocket upgrades and event streams are left alone entirely
+func isStream(r *http.Request) bool {
+	return r.Header.Get("Upgrade") != "" ||
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		if strings.TrimSpace(strings.SplitN(encoding, ";", 2)[0]) == "gzip" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// content types not worth compressing again
+var compressedTypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"application/gzip",
+	"application/x-gzip",
+	"application/zip",
+	"application/octet-stream",
+	"text/event-stream",
+}
+
+func compressible(header http.Header) bool {
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	contentType := header.Get("Content-Type")
+	for _, t := range compressedTypes {
+		if strings.HasPrefix(contentType, t) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// gzipWriter holds back the start of the response until it knows whether it
+// is worth compressing: either MinSize bytes have been written, or the
+// handler has finished or flushed.
+type gzipWriter struct {
+	http.ResponseWriter
+
+	status int
+
+	decided bool
+	buffer  bytes.Buffer
+	gzip    *gzip.Writer
+}
+
+func (writer *gzipWriter) WriteHeader(status int) {
+	if writer.status != 0 {
+		return
+	}
+
+	writer.status = status
+}
+
+func (writer *gzipWriter) Write(data []byte) (int, error) {
+	if writer.status == 0 {
+		writer.WriteHeader(http.StatusOK)
+	}
+
+	if writer.decided {
+		return writer.write(data)
+	}
+
+	if writer.Header().Get("Content-Type") == "" {
+		writer.Header().Set("Content-Type", http.DetectContentType(data))
+	}
+
+	writer.buffer.Write(data)
+
+	if writer.buffer.Len() >= MinSize {
+		err := writer.decide(true)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(data), nil
+}
+
+func (writer *gzipWriter) Flush() {
+	if !writer.decided {
+		if writer.status == 0 {
+			writer.WriteHeader(http.StatusOK)
+		}
+
+		// the handler is streaming; don't hold anything back from it
+		writer.decide(false)
+	}
+
+	if writer.gzip != nil {
+		writer.gzip.Flush()
+	}
+
+	if flusher, ok := writer.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (writer *gzipWriter) CloseNotify() <-chan bool {
+	return writer.ResponseWriter.(http.CloseNotifier).CloseNotify()
+}
+
+func (writer *gzipWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if !writer.decided {
+		writer.decided = true
+
+		if writer.status != 0 {
+			writer.ResponseWriter.WriteHeader(writer.status)
+		}
+	}
+
+	return writer.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+func (writer *gzipWriter) Close() error {
+	if !writer.decided {
+		if writer.status == 0 {
+			// nothing was written; leave it to the server
+			return nil
+		}
+
+		err := writer.decide(false)
+		if err != nil {
+			return err
+		}
+	}
+
+	if writer.gzip != nil {
+		return writer.gzip.Close()
+	}
+
+	return nil
+}
+
+func (writer *gzipWriter) decide(large bool) error {
+	writer.decided = true
+
+	header := writer.Header()
+
+	if large && compressible(header) {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+
+		writer.gzip = gzip.NewWriter(writer.ResponseWriter)
+	}
+
+	writer.ResponseWriter.WriteHeader(writer.status)
+
+	_, err := writer.write(writer.buffer.Bytes())
+	writer.buffer.Reset()
+
+	return err
+}
+
+func (writer *gzipWriter) write(data []byte) (int, error) {
+	if writer.gzip != nil {
+		return writer.gzip.Write(data)
+	}
+
+	return writer.ResponseWriter.Write(data)
+}
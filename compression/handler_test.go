@@ -0,0 +1,172 @@
+package compression_test
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/compression"
+)
+
+var _ = Describe("Handler", func() {
+	var largePage = "<html>" + strings.Repeat("<p>hello</p>", 1000) + "</html>"
+
+	var (
+		responseBody string
+		contentType  string
+
+		server *httptest.Server
+		client *http.Client
+
+		request  *http.Request
+		response *http.Response
+	)
+
+	BeforeEach(func() {
+		responseBody = largePage
+		contentType = "text/html; charset=utf-8"
+
+		server = httptest.NewServer(compression.Handler{
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", contentType)
+				fmt.Fprint(w, responseBody)
+			}),
+		})
+
+		client = &http.Client{
+			Transport: &http.Transport{
+				// so that responses are seen as they were sent
+				DisableCompression: true,
+			},
+		}
+
+		var err error
+		request, err = http.NewRequest("GET", server.URL, nil)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		response, err = client.Do(request)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	readGzipped := func() string {
+		reader, err := gzip.NewReader(response.Body)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		body, err := ioutil.ReadAll(reader)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		return string(body)
+	}
+
+	readPlain := func() string {
+		body, err := ioutil.ReadAll(response.Body)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		return string(body)
+	}
+
+	Context("when the client accepts gzip", func() {
+		BeforeEach(func() {
+			request.Header.Set("Accept-Encoding", "deflate, gzip;q=1.0")
+		})
+
+		It("gzips a large response", func() {
+			Ω(response.StatusCode).Should(Equal(http.StatusOK))
+			Ω(response.Header.Get("Content-Encoding")).Should(Equal("gzip"))
+			Ω(response.Header.Get("Vary")).Should(Equal("Accept-Encoding"))
+			Ω(response.Header.Get("Content-Type")).Should(Equal("text/html; charset=utf-8"))
+
+			Ω(readGzipped()).Should(Equal(largePage))
+		})
+
+		Context("when the response is small", func() {
+			BeforeEach(func() {
+				responseBody = "<html>hi</html>"
+			})
+
+			It("sends it plain", func() {
+				Ω(response.Header.Get("Content-Encoding")).Should(BeEmpty())
+				Ω(readPlain()).Should(Equal("<html>hi</html>"))
+			})
+		})
+
+		Context("when the response is already compressed", func() {
+			BeforeEach(func() {
+				contentType = "application/gzip"
+			})
+
+			It("sends it as-is", func() {
+				Ω(response.Header.Get("Content-Encoding")).Should(BeEmpty())
+				Ω(readPlain()).Should(Equal(largePage))
+			})
+		})
+
+		Context("when the request is for an event stream", func() {
+			BeforeEach(func() {
+				request.Header.Set("Accept", "text/event-stream")
+			})
+
+			It("leaves the response untouched", func() {
+				Ω(response.Header.Get("Content-Encoding")).Should(BeEmpty())
+				Ω(response.Header.Get("Vary")).Should(BeEmpty())
+				Ω(readPlain()).Should(Equal(largePage))
+			})
+		})
+
+		Context("when the request is a websocket upgrade", func() {
+			BeforeEach(func() {
+				request.Header.Set("Connection", "Upgrade")
+				request.Header.Set("Upgrade", "websocket")
+			})
+
+			It("leaves the response untouched", func() {
+				Ω(response.Header.Get("Content-Encoding")).Should(BeEmpty())
+				Ω(response.Header.Get("Vary")).Should(BeEmpty())
+				Ω(readPlain()).Should(Equal(largePage))
+			})
+		})
+	})
+
+	Context("when the client does not accept gzip", func() {
+		It("sends the response plain", func() {
+			Ω(response.StatusCode).Should(Equal(http.StatusOK))
+			Ω(response.Header.Get("Content-Encoding")).Should(BeEmpty())
+			Ω(response.Header.Get("Vary")).Should(Equal("Accept-Encoding"))
+
+			Ω(readPlain()).Should(Equal(largePage))
+		})
+	})
+
+	Context("when the handler streams its response", func() {
+		BeforeEach(func() {
+			request.Header.Set("Accept-Encoding", "gzip")
+
+			server.Config.Handler = compression.Handler{
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "text/plain")
+					fmt.Fprint(w, "first")
+					w.(http.Flusher).Flush()
+					fmt.Fprint(w, largePage)
+				}),
+			}
+		})
+
+		It("sends it plain, as it is written", func() {
+			Ω(response.Header.Get("Content-Encoding")).Should(BeEmpty())
+			Ω(readPlain()).Should(Equal("first" + largePage))
+		})
+	})
+})
@@ -1,6 +1,9 @@
 package atc
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 const ConfigVersionHeader = "X-Concourse-Config-Version"
 const DefaultPipelineName = "main"
@@ -39,6 +42,35 @@ type ResourceConfig struct {
 
 	Type   string `yaml:"type" json:"type" mapstructure:"type"`
 	Source Source `yaml:"source" json:"source" mapstructure:"source"`
+
+	// overrides the proxy configured for all resources, if set
+	Proxy *ProxyConfig `yaml:"proxy,omitempty" json:"proxy,omitempty" mapstructure:"proxy"`
+}
+
+// ProxyConfig is the proxy that a resource's scripts reach external services
+// through, given to them as the usual environment variables.
+type ProxyConfig struct {
+	HTTP    string `yaml:"http_proxy,omitempty" json:"http_proxy,omitempty" mapstructure:"http_proxy"`
+	HTTPS   string `yaml:"https_proxy,omitempty" json:"https_proxy,omitempty" mapstructure:"https_proxy"`
+	NoProxy string `yaml:"no_proxy,omitempty" json:"no_proxy,omitempty" mapstructure:"no_proxy"`
+}
+
+// Env returns the proxy as environment variables, in both the upper and
+// lower case forms since tools differ in which they respect.
+func (config ProxyConfig) Env() []string {
+	var env []string
+	env = appendEnv(env, "http_proxy", config.HTTP)
+	env = appendEnv(env, "https_proxy", config.HTTPS)
+	env = appendEnv(env, "no_proxy", config.NoProxy)
+	return env
+}
+
+func appendEnv(env []string, name string, value string) []string {
+	if value == "" {
+		return env
+	}
+
+	return append(env, name+"="+value, strings.ToUpper(name)+"="+value)
 }
 
 type JobConfig struct {
@@ -47,6 +79,13 @@ type JobConfig struct {
 	Serial       bool     `yaml:"serial,omitempty" json:"serial,omitempty" mapstructure:"serial"`
 	SerialGroups []string `yaml:"serial_groups,omitempty" json:"serial_groups,omitempty" mapstructure:"serial_groups"`
 
+	// old finished builds are reaped once they are beyond both of these, if set
+	BuildsToKeep  int    `yaml:"builds_to_keep,omitempty" json:"builds_to_keep,omitempty" mapstructure:"builds_to_keep"`
+	KeepBuildsFor string `yaml:"keep_builds_for,omitempty" json:"keep_builds_for,omitempty" mapstructure:"keep_builds_for"`
+
+	// builds running for longer than this are stopped, and fail as timed out
+	BuildTimeout string `yaml:"build_timeout,omitempty" json:"build_timeout,omitempty" mapstructure:"build_timeout"`
+
 	Privileged     bool        `yaml:"privileged,omitempty" json:"privileged,omitempty" mapstructure:"privileged"`
 	TaskConfigPath string      `yaml:"build,omitempty" json:"build,omitempty" mapstructure:"build"`
 	TaskConfig     *TaskConfig `yaml:"config,omitempty" json:"config,omitempty" mapstructure:"config"`
@@ -282,16 +321,27 @@ func (c *Condition) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return fmt.Errorf("invalid condition: %s", err)
 	}
 
-	switch Condition(str) {
-	case ConditionSuccess, ConditionFailure:
-		*c = Condition(str)
-	default:
-		return fmt.Errorf("unknown condition: %s (must be success/failure)", str)
+	condition := Condition(str)
+	if err := condition.Validate(); err != nil {
+		return err
 	}
 
+	*c = condition
+
 	return nil
 }
 
+// Validate returns an error if the condition is not one of the known
+// conditions.
+func (c Condition) Validate() error {
+	switch c {
+	case ConditionSuccess, ConditionFailure:
+		return nil
+	default:
+		return fmt.Errorf("unknown condition: %s (must be success/failure)", string(c))
+	}
+}
+
 type ResourceConfigs []ResourceConfig
 
 func (resources ResourceConfigs) Lookup(name string) (ResourceConfig, bool) {
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/concourse/atc"
+	"gopkg.in/yaml.v2"
+)
+
+// LoadConfigFile reads and validates the pipeline config at the given path.
+// Malformed YAML is reported the same as an invalid config, so callers only
+// need to check a single error.
+func LoadConfigFile(path string) (atc.Config, error) {
+	return LoadConfigFiles(path)
+}
+
+// LoadConfigFiles reads a pipeline config split across the given paths,
+// merging them with MergeConfigs and validating the result.
+func LoadConfigFiles(paths ...string) (atc.Config, error) {
+	files := make([]ConfigFile, 0, len(paths))
+
+	for _, path := range paths {
+		payload, err := ioutil.ReadFile(path)
+		if err != nil {
+			return atc.Config{}, err
+		}
+
+		var config atc.Config
+		err = yaml.Unmarshal(payload, &config)
+		if err != nil {
+			return atc.Config{}, fmt.Errorf("malformed config in %s: %s", path, err)
+		}
+
+		files = append(files, ConfigFile{
+			Path:   path,
+			Config: config,
+		})
+	}
+
+	config, err := MergeConfigs(files)
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	err = ValidateConfig(config)
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	return config, nil
+}
@@ -0,0 +1,188 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/concourse/atc/config"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LoadConfigFile", func() {
+	var (
+		tmpdir     string
+		configPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpdir, err = ioutil.TempDir("", "load-config")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		configPath = filepath.Join(tmpdir, "pipeline.yml")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpdir)
+	})
+
+	writeConfig := func(contents string) {
+		err := ioutil.WriteFile(configPath, []byte(contents), 0644)
+		Ω(err).ShouldNot(HaveOccurred())
+	}
+
+	Context("when the config is valid", func() {
+		BeforeEach(func() {
+			writeConfig(`---
+resources:
+- name: some-resource
+  type: git
+  source: {uri: "git://some-resource"}
+
+jobs:
+- name: some-job
+  plan:
+  - get: some-resource
+`)
+		})
+
+		It("returns the config", func() {
+			config, err := LoadConfigFile(configPath)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(config.Resources).Should(HaveLen(1))
+			Ω(config.Resources[0].Name).Should(Equal("some-resource"))
+
+			Ω(config.Jobs).Should(HaveLen(1))
+			Ω(config.Jobs[0].Name).Should(Equal("some-job"))
+		})
+	})
+
+	Context("when the config is invalid", func() {
+		BeforeEach(func() {
+			writeConfig(`---
+jobs:
+- name: some-job
+  plan:
+  - get: some-resource
+`)
+		})
+
+		It("returns the validation error", func() {
+			_, err := LoadConfigFile(configPath)
+			Ω(err).Should(BeAssignableToTypeOf(InvalidConfigError{}))
+			Ω(err.Error()).Should(ContainSubstring("jobs.some-job.plan[0].get.some-resource refers to a resource that does not exist"))
+		})
+	})
+
+	Context("when the config is not valid YAML", func() {
+		BeforeEach(func() {
+			writeConfig("jobs: [")
+		})
+
+		It("returns an error", func() {
+			_, err := LoadConfigFile(configPath)
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("malformed config"))
+		})
+	})
+
+	Context("when the file does not exist", func() {
+		It("returns an error", func() {
+			_, err := LoadConfigFile(filepath.Join(tmpdir, "bogus.yml"))
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("LoadConfigFiles", func() {
+	var (
+		tmpdir string
+
+		resourcesPath string
+		jobsPath      string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpdir, err = ioutil.TempDir("", "load-config")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		resourcesPath = filepath.Join(tmpdir, "resources.yml")
+		jobsPath = filepath.Join(tmpdir, "jobs.yml")
+
+		err = ioutil.WriteFile(resourcesPath, []byte(`---
+resources:
+- name: some-resource
+  type: git
+  source: {uri: "git://some-resource"}
+`), 0644)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpdir)
+	})
+
+	writeJobs := func(contents string) {
+		err := ioutil.WriteFile(jobsPath, []byte(contents), 0644)
+		Ω(err).ShouldNot(HaveOccurred())
+	}
+
+	Context("when the files merge into a valid config", func() {
+		BeforeEach(func() {
+			writeJobs(`---
+jobs:
+- name: some-job
+  plan:
+  - get: some-resource
+`)
+		})
+
+		It("returns the merged config", func() {
+			config, err := LoadConfigFiles(resourcesPath, jobsPath)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(config.Resources).Should(HaveLen(1))
+			Ω(config.Resources[0].Name).Should(Equal("some-resource"))
+
+			Ω(config.Jobs).Should(HaveLen(1))
+			Ω(config.Jobs[0].Name).Should(Equal("some-job"))
+		})
+	})
+
+	Context("when the merged config is invalid", func() {
+		BeforeEach(func() {
+			writeJobs(`---
+jobs:
+- name: some-job
+  plan:
+  - get: some-other-resource
+`)
+		})
+
+		It("returns the validation error", func() {
+			_, err := LoadConfigFiles(resourcesPath, jobsPath)
+			Ω(err).Should(BeAssignableToTypeOf(InvalidConfigError{}))
+		})
+	})
+
+	Context("when the files define the same resource", func() {
+		BeforeEach(func() {
+			writeJobs(`---
+resources:
+- name: some-resource
+  type: git
+`)
+		})
+
+		It("returns an error", func() {
+			_, err := LoadConfigFiles(resourcesPath, jobsPath)
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("resource 'some-resource' is defined in both"))
+		})
+	})
+})
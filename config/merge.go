@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/concourse/atc"
+)
+
+// ConfigFile is one part of a pipeline config split across files.
+type ConfigFile struct {
+	Path   string
+	Config atc.Config
+}
+
+// MergeConfigs combines the parts of a pipeline config into one, appending
+// their groups, resources and jobs in order.
+//
+// It is an error for two files to define a group, resource or job with the
+// same name. Duplicates within a single file are left to validation.
+func MergeConfigs(files []ConfigFile) (atc.Config, error) {
+	var merged atc.Config
+
+	groupPaths := map[string]string{}
+	resourcePaths := map[string]string{}
+	jobPaths := map[string]string{}
+
+	for _, file := range files {
+		for _, group := range file.Config.Groups {
+			err := checkDuplicate("group", group.Name, file.Path, groupPaths)
+			if err != nil {
+				return atc.Config{}, err
+			}
+
+			merged.Groups = append(merged.Groups, group)
+		}
+
+		for _, resource := range file.Config.Resources {
+			err := checkDuplicate("resource", resource.Name, file.Path, resourcePaths)
+			if err != nil {
+				return atc.Config{}, err
+			}
+
+			merged.Resources = append(merged.Resources, resource)
+		}
+
+		for _, job := range file.Config.Jobs {
+			err := checkDuplicate("job", job.Name, file.Path, jobPaths)
+			if err != nil {
+				return atc.Config{}, err
+			}
+
+			merged.Jobs = append(merged.Jobs, job)
+		}
+	}
+
+	return merged, nil
+}
+
+func checkDuplicate(kind string, name string, path string, seen map[string]string) error {
+	otherPath, found := seen[name]
+	if found && otherPath != path {
+		return fmt.Errorf("%s '%s' is defined in both %s and %s", kind, name, otherPath, path)
+	}
+
+	seen[name] = path
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package config_test
+
+import (
+	"github.com/concourse/atc"
+	. "github.com/concourse/atc/config"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MergeConfigs", func() {
+	var resourcesFile ConfigFile
+	var jobsFile ConfigFile
+
+	BeforeEach(func() {
+		resourcesFile = ConfigFile{
+			Path: "resources.yml",
+			Config: atc.Config{
+				Resources: atc.ResourceConfigs{
+					{Name: "some-resource", Type: "git"},
+					{Name: "some-other-resource", Type: "git"},
+				},
+			},
+		}
+
+		jobsFile = ConfigFile{
+			Path: "jobs.yml",
+			Config: atc.Config{
+				Groups: atc.GroupConfigs{
+					{Name: "some-group", Jobs: []string{"some-job"}},
+				},
+				Jobs: atc.JobConfigs{
+					{Name: "some-job"},
+					{Name: "some-other-job"},
+				},
+			},
+		}
+	})
+
+	It("appends each file's groups, resources, and jobs in order", func() {
+		merged, err := MergeConfigs([]ConfigFile{resourcesFile, jobsFile})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(merged).Should(Equal(atc.Config{
+			Groups: atc.GroupConfigs{
+				{Name: "some-group", Jobs: []string{"some-job"}},
+			},
+			Resources: atc.ResourceConfigs{
+				{Name: "some-resource", Type: "git"},
+				{Name: "some-other-resource", Type: "git"},
+			},
+			Jobs: atc.JobConfigs{
+				{Name: "some-job"},
+				{Name: "some-other-job"},
+			},
+		}))
+	})
+
+	Context("when two files define a job with the same name", func() {
+		BeforeEach(func() {
+			resourcesFile.Config.Jobs = atc.JobConfigs{
+				{Name: "some-other-job"},
+			}
+		})
+
+		It("returns an error naming the job and both files", func() {
+			_, err := MergeConfigs([]ConfigFile{resourcesFile, jobsFile})
+			Ω(err).Should(MatchError("job 'some-other-job' is defined in both resources.yml and jobs.yml"))
+		})
+	})
+
+	Context("when two files define a resource with the same name", func() {
+		BeforeEach(func() {
+			jobsFile.Config.Resources = atc.ResourceConfigs{
+				{Name: "some-resource", Type: "s3"},
+			}
+		})
+
+		It("returns an error naming the resource and both files", func() {
+			_, err := MergeConfigs([]ConfigFile{resourcesFile, jobsFile})
+			Ω(err).Should(MatchError("resource 'some-resource' is defined in both resources.yml and jobs.yml"))
+		})
+	})
+
+	Context("when a single file defines a job twice", func() {
+		BeforeEach(func() {
+			jobsFile.Config.Jobs = append(jobsFile.Config.Jobs, atc.JobConfig{Name: "some-job"})
+		})
+
+		It("leaves it to validation", func() {
+			merged, err := MergeConfigs([]ConfigFile{resourcesFile, jobsFile})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(merged.Jobs).Should(HaveLen(3))
+		})
+	})
+})
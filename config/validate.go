@@ -149,6 +149,26 @@ func validateJobs(c atc.Config) error {
 			errorMessages = append(errorMessages, identifier+" has both a plan and inputs/outputs/build config specified")
 		}
 
+		if job.BuildsToKeep < 0 {
+			errorMessages = append(errorMessages, identifier+" has a negative builds_to_keep")
+		}
+
+		if job.KeepBuildsFor != "" {
+			_, err := time.ParseDuration(job.KeepBuildsFor)
+			if err != nil {
+				errorMessages = append(errorMessages, identifier+fmt.Sprintf(" has a keep_builds_for that could not be parsed ('%s')", job.KeepBuildsFor))
+			}
+		}
+
+		if job.BuildTimeout != "" {
+			timeout, err := time.ParseDuration(job.BuildTimeout)
+			if err != nil {
+				errorMessages = append(errorMessages, identifier+fmt.Sprintf(" has a build_timeout that could not be parsed ('%s')", job.BuildTimeout))
+			} else if timeout <= 0 {
+				errorMessages = append(errorMessages, identifier+fmt.Sprintf(" has a build_timeout that is not positive ('%s')", job.BuildTimeout))
+			}
+		}
+
 		errorMessages = append(errorMessages, validateConditionals(identifier+".plan", job.Plan)...)
 		errorMessages = append(errorMessages, validatePlan(c, identifier+".plan", atc.PlanConfig{Do: &job.Plan})...)
 		errorMessages = append(errorMessages, validateInputOutputConfig(c, job, identifier)...)
@@ -408,6 +428,10 @@ func validatePlan(c atc.Config, identifier string, plan atc.PlanConfig) []string
 		errorMessages = append(errorMessages, validatePlan(c, subIdentifier, *plan.Try)...)
 	}
 
+	if plan.Conditions != nil {
+		errorMessages = append(errorMessages, validateConditions(identifier+".conditions", *plan.Conditions)...)
+	}
+
 	if plan.Ensure != nil {
 		subIdentifier := fmt.Sprintf("%s.ensure", identifier)
 		errorMessages = append(errorMessages, validatePlan(c, subIdentifier, *plan.Ensure)...)
@@ -526,6 +550,8 @@ func validateInputOutputConfig(c atc.Config, job atc.JobConfig, identifier strin
 	for i, output := range job.OutputConfigs {
 		outputIdentifier := fmt.Sprintf("%s.outputs[%d]", identifier, i)
 
+		errorMessages = append(errorMessages, validateConditions(outputIdentifier+".perform_on", output.RawPerformOn)...)
+
 		if output.Resource == "" {
 			errorMessages = append(errorMessages,
 				outputIdentifier+" has no resource")
@@ -546,6 +572,24 @@ func validateInputOutputConfig(c atc.Config, job atc.JobConfig, identifier strin
 	return errorMessages
 }
 
+func validateConditions(identifier string, conditions []atc.Condition) []string {
+	errorMessages := []string{}
+
+	for _, condition := range conditions {
+		if condition.Validate() != nil {
+			errorMessages = append(errorMessages,
+				fmt.Sprintf(
+					"%s has an unknown condition ('%s')",
+					identifier,
+					condition,
+				),
+			)
+		}
+	}
+
+	return errorMessages
+}
+
 func compositeErr(errorMessages []string) error {
 	if len(errorMessages) == 0 {
 		return nil
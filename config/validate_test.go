@@ -209,6 +209,62 @@ var _ = Describe("ValidateConfig", func() {
 			})
 		})
 
+		Context("when a job has a negative builds_to_keep", func() {
+			BeforeEach(func() {
+				job.BuildsToKeep = -1
+				config.Jobs = append(config.Jobs, job)
+			})
+
+			It("returns an error", func() {
+				Ω(validateErr).Should(HaveOccurred())
+				Ω(validateErr.Error()).Should(ContainSubstring(
+					"jobs.some-other-job has a negative builds_to_keep",
+				))
+			})
+		})
+
+		Context("when a job's keep_builds_for is not a duration", func() {
+			BeforeEach(func() {
+				job.KeepBuildsFor = "forever"
+				config.Jobs = append(config.Jobs, job)
+			})
+
+			It("returns an error", func() {
+				Ω(validateErr).Should(HaveOccurred())
+				Ω(validateErr.Error()).Should(ContainSubstring(
+					"jobs.some-other-job has a keep_builds_for that could not be parsed ('forever')",
+				))
+			})
+		})
+
+		Context("when a job's build_timeout is not a duration", func() {
+			BeforeEach(func() {
+				job.BuildTimeout = "a while"
+				config.Jobs = append(config.Jobs, job)
+			})
+
+			It("returns an error", func() {
+				Ω(validateErr).Should(HaveOccurred())
+				Ω(validateErr.Error()).Should(ContainSubstring(
+					"jobs.some-other-job has a build_timeout that could not be parsed ('a while')",
+				))
+			})
+		})
+
+		Context("when a job's build_timeout is not positive", func() {
+			BeforeEach(func() {
+				job.BuildTimeout = "0s"
+				config.Jobs = append(config.Jobs, job)
+			})
+
+			It("returns an error", func() {
+				Ω(validateErr).Should(HaveOccurred())
+				Ω(validateErr.Error()).Should(ContainSubstring(
+					"jobs.some-other-job has a build_timeout that is not positive ('0s')",
+				))
+			})
+		})
+
 		Context("when a job has no config and no config path", func() {
 			BeforeEach(func() {
 				job.TaskConfig = nil
@@ -302,6 +358,23 @@ var _ = Describe("ValidateConfig", func() {
 			})
 		})
 
+		Context("when a job's output is performed on a bogus condition", func() {
+			BeforeEach(func() {
+				job.OutputConfigs = append(job.OutputConfigs, atc.JobOutputConfig{
+					Resource:     "some-resource",
+					RawPerformOn: []atc.Condition{"success", "bogus"},
+				})
+				config.Jobs = append(config.Jobs, job)
+			})
+
+			It("returns an error", func() {
+				Ω(validateErr).Should(HaveOccurred())
+				Ω(validateErr.Error()).Should(ContainSubstring(
+					"jobs.some-other-job.outputs[0].perform_on has an unknown condition ('bogus')",
+				))
+			})
+		})
+
 		Describe("plans", func() {
 			BeforeEach(func() {
 				// clear out old-style configuration
@@ -311,6 +384,41 @@ var _ = Describe("ValidateConfig", func() {
 				job.OutputConfigs = nil
 			})
 
+			Context("when a plan step has a bogus condition", func() {
+				BeforeEach(func() {
+					job.Plan = append(job.Plan, atc.PlanConfig{
+						Conditions:     &atc.Conditions{"bogus"},
+						Task:           "some-task",
+						TaskConfigPath: "task.yml",
+					})
+
+					config.Jobs = append(config.Jobs, job)
+				})
+
+				It("returns an error pointing at the step", func() {
+					Ω(validateErr).Should(HaveOccurred())
+					Ω(validateErr.Error()).Should(ContainSubstring(
+						"jobs.some-other-job.plan[0].conditions has an unknown condition ('bogus')",
+					))
+				})
+			})
+
+			Context("when a plan step has valid conditions", func() {
+				BeforeEach(func() {
+					job.Plan = append(job.Plan, atc.PlanConfig{
+						Conditions:     &atc.Conditions{atc.ConditionSuccess, atc.ConditionFailure},
+						Task:           "some-task",
+						TaskConfigPath: "task.yml",
+					})
+
+					config.Jobs = append(config.Jobs, job)
+				})
+
+				It("does not return an error", func() {
+					Ω(validateErr).ShouldNot(HaveOccurred())
+				})
+			})
+
 			Context("when a plan contains conditionals and hooks", func() {
 				BeforeEach(func() {
 					job.Plan = append(job.Plan, atc.PlanConfig{
@@ -0,0 +1,99 @@
+package atc
+
+// WithDefaults returns the config with every default that is otherwise applied
+// implicitly made explicit, so that it reads exactly as the scheduler and
+// radar act on it:
+//
+// * serial jobs without serial groups are in a serial group of their own
+// * steps that don't name their resource use the resource named by the step
+// * legacy inputs without a name are named after their resource
+// * legacy outputs without conditions are performed on success
+//
+// The config it is called on is left as-is.
+func (config Config) WithDefaults() Config {
+	if config.Jobs != nil {
+		jobs := make(JobConfigs, len(config.Jobs))
+		for i, job := range config.Jobs {
+			jobs[i] = job.withDefaults()
+		}
+
+		config.Jobs = jobs
+	}
+
+	return config
+}
+
+func (config JobConfig) withDefaults() JobConfig {
+	if config.IsSerial() {
+		config.SerialGroups = config.GetSerialGroups()
+	}
+
+	if config.InputConfigs != nil {
+		inputs := make([]JobInputConfig, len(config.InputConfigs))
+		for i, input := range config.InputConfigs {
+			input.RawName = input.Name()
+			inputs[i] = input
+		}
+
+		config.InputConfigs = inputs
+	}
+
+	if config.OutputConfigs != nil {
+		outputs := make([]JobOutputConfig, len(config.OutputConfigs))
+		for i, output := range config.OutputConfigs {
+			output.RawPerformOn = output.PerformOn()
+			outputs[i] = output
+		}
+
+		config.OutputConfigs = outputs
+	}
+
+	config.Plan = config.Plan.withDefaults()
+
+	return config
+}
+
+func (plan PlanSequence) withDefaults() PlanSequence {
+	if plan == nil {
+		return nil
+	}
+
+	steps := make(PlanSequence, len(plan))
+	for i, step := range plan {
+		steps[i] = step.withDefaults()
+	}
+
+	return steps
+}
+
+func (config PlanConfig) withDefaults() PlanConfig {
+	if config.Get != "" || config.Put != "" {
+		config.Resource = config.ResourceName()
+	}
+
+	if config.Do != nil {
+		do := config.Do.withDefaults()
+		config.Do = &do
+	}
+
+	if config.Aggregate != nil {
+		aggregate := config.Aggregate.withDefaults()
+		config.Aggregate = &aggregate
+	}
+
+	config.Failure = hookWithDefaults(config.Failure)
+	config.Ensure = hookWithDefaults(config.Ensure)
+	config.Success = hookWithDefaults(config.Success)
+	config.Try = hookWithDefaults(config.Try)
+
+	return config
+}
+
+func hookWithDefaults(hook *PlanConfig) *PlanConfig {
+	if hook == nil {
+		return nil
+	}
+
+	withDefaults := hook.withDefaults()
+	return &withDefaults
+}
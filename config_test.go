@@ -56,6 +56,16 @@ var _ = Describe("Config", func() {
 				Ω(jobConfig.GetSerialGroups()).Should(Equal([]string{"some-job"}))
 			})
 
+			It("returns the SerialGroups if the job is also serial", func() {
+				jobConfig := JobConfig{
+					Name:         "some-job",
+					Serial:       true,
+					SerialGroups: []string{"shared"},
+				}
+
+				Ω(jobConfig.GetSerialGroups()).Should(Equal([]string{"shared"}))
+			})
+
 			It("returns an empty slice of strings if there are no groups and it is not serial", func() {
 				jobConfig := JobConfig{
 					Name:   "some-job",
@@ -94,6 +104,129 @@ var _ = Describe("Config", func() {
 		})
 	})
 
+	Describe("ProxyConfig", func() {
+		It("has no env if no proxy is configured", func() {
+			Ω(ProxyConfig{}.Env()).Should(BeEmpty())
+		})
+
+		It("gives each configured proxy in both cases", func() {
+			Ω(ProxyConfig{
+				HTTP:    "http://proxy:3128",
+				NoProxy: "localhost",
+			}.Env()).Should(Equal([]string{
+				"http_proxy=http://proxy:3128",
+				"HTTP_PROXY=http://proxy:3128",
+				"no_proxy=localhost",
+				"NO_PROXY=localhost",
+			}))
+		})
+	})
+
+	Describe("WithDefaults", func() {
+		var config Config
+
+		BeforeEach(func() {
+			config = Config{
+				Resources: ResourceConfigs{
+					{Name: "some-resource", Type: "git"},
+				},
+				Jobs: JobConfigs{
+					{
+						Name:   "some-job",
+						Serial: true,
+						Plan: PlanSequence{
+							{
+								Aggregate: &PlanSequence{
+									{Get: "some-input", Resource: "some-resource", Trigger: true},
+									{Get: "some-resource"},
+								},
+							},
+							{
+								Put: "some-resource",
+								Failure: &PlanConfig{
+									Put: "some-resource",
+								},
+							},
+						},
+					},
+					{
+						Name:         "some-legacy-job",
+						SerialGroups: []string{"some-group"},
+						InputConfigs: []JobInputConfig{
+							{Resource: "some-resource"},
+							{RawName: "some-input", Resource: "some-resource", Trigger: true},
+						},
+						OutputConfigs: []JobOutputConfig{
+							{Resource: "some-resource"},
+							{Resource: "some-resource", RawPerformOn: []Condition{"failure"}},
+						},
+					},
+				},
+			}
+		})
+
+		It("materializes every default, preserving explicit values", func() {
+			Ω(config.WithDefaults()).Should(Equal(Config{
+				Resources: ResourceConfigs{
+					{Name: "some-resource", Type: "git"},
+				},
+				Jobs: JobConfigs{
+					{
+						Name:         "some-job",
+						Serial:       true,
+						SerialGroups: []string{"some-job"},
+						Plan: PlanSequence{
+							{
+								Aggregate: &PlanSequence{
+									{Get: "some-input", Resource: "some-resource", Trigger: true},
+									{Get: "some-resource", Resource: "some-resource", Trigger: false},
+								},
+							},
+							{
+								Put:      "some-resource",
+								Resource: "some-resource",
+								Failure: &PlanConfig{
+									Put:      "some-resource",
+									Resource: "some-resource",
+								},
+							},
+						},
+					},
+					{
+						Name:         "some-legacy-job",
+						SerialGroups: []string{"some-group"},
+						InputConfigs: []JobInputConfig{
+							{RawName: "some-resource", Resource: "some-resource", Trigger: false},
+							{RawName: "some-input", Resource: "some-resource", Trigger: true},
+						},
+						OutputConfigs: []JobOutputConfig{
+							{Resource: "some-resource", RawPerformOn: []Condition{"success"}},
+							{Resource: "some-resource", RawPerformOn: []Condition{"failure"}},
+						},
+					},
+				},
+			}))
+		})
+
+		It("does not modify the original config", func() {
+			config.WithDefaults()
+
+			Ω(config.Jobs[0].SerialGroups).Should(BeNil())
+			Ω((*config.Jobs[0].Plan[0].Aggregate)[1].Resource).Should(BeEmpty())
+			Ω(config.Jobs[0].Plan[1].Failure.Resource).Should(BeEmpty())
+			Ω(config.Jobs[1].InputConfigs[0].RawName).Should(BeEmpty())
+			Ω(config.Jobs[1].OutputConfigs[0].RawPerformOn).Should(BeNil())
+		})
+
+		It("leaves jobs without defaults to apply as they were", func() {
+			config = Config{
+				Jobs: JobConfigs{{Name: "some-job"}},
+			}
+
+			Ω(config.WithDefaults()).Should(Equal(config))
+		})
+	})
+
 	Describe("Condition", func() {
 		It("can be unmarshalled from YAML as the string 'success'", func() {
 			var condition Condition
@@ -116,5 +249,44 @@ var _ = Describe("Config", func() {
 			err := yaml.Unmarshal([]byte("bogus"), &condition)
 			Expect(err).To(HaveOccurred())
 		})
+
+		It("fails to unmarshal a config with a bogus condition", func() {
+			var config Config
+			err := yaml.Unmarshal([]byte(`
+jobs:
+- name: some-job
+  plan:
+  - task: some-task
+    file: some/task.yml
+    conditions: [bogus]
+`), &config)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("unmarshals valid combinations of conditions", func() {
+			var config Config
+			err := yaml.Unmarshal([]byte(`
+jobs:
+- name: some-job
+  plan:
+  - task: some-task
+    file: some/task.yml
+    conditions: [success, failure]
+`), &config)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(*config.Jobs[0].Plan[0].Conditions).To(Equal(Conditions{ConditionSuccess, ConditionFailure}))
+		})
+
+		Describe("Validate", func() {
+			It("accepts the known conditions", func() {
+				Expect(ConditionSuccess.Validate()).To(Succeed())
+				Expect(ConditionFailure.Validate()).To(Succeed())
+			})
+
+			It("rejects anything else", func() {
+				Expect(Condition("bogus").Validate()).To(MatchError("unknown condition: bogus (must be success/failure)"))
+			})
+		})
 	})
 })
@@ -0,0 +1,131 @@
+package credentials
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/concourse/atc"
+)
+
+//go:generate counterfeiter . CredentialManager
+
+// CredentialManager looks up secrets by key, so that pipelines can refer to
+// them as ((key)) rather than containing them.
+type CredentialManager interface {
+	Get(key string) (string, error)
+}
+
+// MissingCredentialError is returned when a referenced credential cannot be
+// found.
+type MissingCredentialError struct {
+	Key string
+}
+
+func (err MissingCredentialError) Error() string {
+	return fmt.Sprintf("credential '%s' not found", err.Key)
+}
+
+var referenceRegexp = regexp.MustCompile(`\(\(([-\w./]+)\)\)`)
+
+// Interpolate replaces each ((key)) in the value with the credential it
+// refers to. Values without references are returned as-is, as are all values
+// if the manager is nil.
+func Interpolate(manager CredentialManager, value string) (string, error) {
+	if manager == nil {
+		return value, nil
+	}
+
+	var err error
+
+	interpolated := referenceRegexp.ReplaceAllStringFunc(value, func(reference string) string {
+		if err != nil {
+			return reference
+		}
+
+		key := referenceRegexp.FindStringSubmatch(reference)[1]
+
+		var credential string
+		credential, err = manager.Get(key)
+
+		return credential
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return interpolated, nil
+}
+
+// InterpolateSource returns a copy of the source with every credential
+// reference in its values replaced, including those nested in maps and lists.
+// The source itself is left untouched, so that what is stored and logged
+// never contains credentials.
+func InterpolateSource(manager CredentialManager, source atc.Source) (atc.Source, error) {
+	if manager == nil || source == nil {
+		return source, nil
+	}
+
+	interpolated := make(atc.Source, len(source))
+
+	for key, value := range source {
+		resolved, err := interpolateValue(manager, value)
+		if err != nil {
+			return nil, err
+		}
+
+		interpolated[key] = resolved
+	}
+
+	return interpolated, nil
+}
+
+func interpolateValue(manager CredentialManager, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return Interpolate(manager, v)
+
+	case []interface{}:
+		interpolated := make([]interface{}, len(v))
+		for i, elem := range v {
+			resolved, err := interpolateValue(manager, elem)
+			if err != nil {
+				return nil, err
+			}
+
+			interpolated[i] = resolved
+		}
+
+		return interpolated, nil
+
+	case map[string]interface{}:
+		interpolated := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			resolved, err := interpolateValue(manager, elem)
+			if err != nil {
+				return nil, err
+			}
+
+			interpolated[key] = resolved
+		}
+
+		return interpolated, nil
+
+	// as decoded from YAML
+	case map[interface{}]interface{}:
+		interpolated := make(map[interface{}]interface{}, len(v))
+		for key, elem := range v {
+			resolved, err := interpolateValue(manager, elem)
+			if err != nil {
+				return nil, err
+			}
+
+			interpolated[key] = resolved
+		}
+
+		return interpolated, nil
+
+	default:
+		return value, nil
+	}
+}
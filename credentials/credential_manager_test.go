@@ -0,0 +1,134 @@
+package credentials_test
+
+import (
+	"errors"
+
+	"github.com/concourse/atc"
+	. "github.com/concourse/atc/credentials"
+	"github.com/concourse/atc/credentials/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Interpolate", func() {
+	var fakeManager *fakes.FakeCredentialManager
+
+	BeforeEach(func() {
+		fakeManager = new(fakes.FakeCredentialManager)
+		fakeManager.GetStub = func(key string) (string, error) {
+			switch key {
+			case "some-secret":
+				return "hunter2", nil
+			case "some-other-secret":
+				return "swordfish", nil
+			default:
+				return "", MissingCredentialError{Key: key}
+			}
+		}
+	})
+
+	It("replaces a reference with the credential", func() {
+		Ω(Interpolate(fakeManager, "((some-secret))")).Should(Equal("hunter2"))
+	})
+
+	It("replaces every reference within the value", func() {
+		Ω(Interpolate(fakeManager, "user:((some-secret)) pass:((some-other-secret))")).Should(Equal("user:hunter2 pass:swordfish"))
+	})
+
+	It("leaves values without references alone", func() {
+		Ω(Interpolate(fakeManager, "plain (value)")).Should(Equal("plain (value)"))
+		Ω(fakeManager.GetCallCount()).Should(BeZero())
+	})
+
+	Context("when a referenced credential is missing", func() {
+		It("returns an error naming the missing key", func() {
+			_, err := Interpolate(fakeManager, "((some-secret))-((bogus-secret))")
+			Ω(err).Should(Equal(MissingCredentialError{Key: "bogus-secret"}))
+			Ω(err.Error()).Should(ContainSubstring("bogus-secret"))
+		})
+	})
+
+	Context("when looking up a credential fails", func() {
+		disaster := errors.New("oh no!")
+
+		BeforeEach(func() {
+			fakeManager.GetReturns("", disaster)
+		})
+
+		It("returns the error without looking up any more", func() {
+			_, err := Interpolate(fakeManager, "((some-secret))-((some-other-secret))")
+			Ω(err).Should(Equal(disaster))
+			Ω(fakeManager.GetCallCount()).Should(Equal(1))
+		})
+	})
+
+	Context("when there is no manager", func() {
+		It("leaves references alone", func() {
+			Ω(Interpolate(nil, "((some-secret))")).Should(Equal("((some-secret))"))
+		})
+	})
+})
+
+var _ = Describe("InterpolateSource", func() {
+	var fakeManager *fakes.FakeCredentialManager
+
+	BeforeEach(func() {
+		fakeManager = new(fakes.FakeCredentialManager)
+		fakeManager.GetStub = func(key string) (string, error) {
+			if key == "some-token" {
+				return "some-secret-token", nil
+			}
+
+			return "", MissingCredentialError{Key: key}
+		}
+	})
+
+	It("resolves references nested anywhere in the source, leaving other values alone", func() {
+		source := atc.Source{
+			"uri":   "https://example.com",
+			"token": "((some-token))",
+			"depth": 1,
+			"auth": map[string]interface{}{
+				"tokens": []interface{}{"((some-token))", "plain"},
+			},
+			"yaml": map[interface{}]interface{}{
+				"token": "((some-token))",
+			},
+		}
+
+		Ω(InterpolateSource(fakeManager, source)).Should(Equal(atc.Source{
+			"uri":   "https://example.com",
+			"token": "some-secret-token",
+			"depth": 1,
+			"auth": map[string]interface{}{
+				"tokens": []interface{}{"some-secret-token", "plain"},
+			},
+			"yaml": map[interface{}]interface{}{
+				"token": "some-secret-token",
+			},
+		}))
+	})
+
+	It("does not modify the given source", func() {
+		source := atc.Source{
+			"auth": map[string]interface{}{"token": "((some-token))"},
+		}
+
+		_, err := InterpolateSource(fakeManager, source)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(source).Should(Equal(atc.Source{
+			"auth": map[string]interface{}{"token": "((some-token))"},
+		}))
+	})
+
+	Context("when a nested credential is missing", func() {
+		It("returns an error naming it", func() {
+			_, err := InterpolateSource(fakeManager, atc.Source{
+				"auth": []interface{}{"((bogus-token))"},
+			})
+			Ω(err).Should(Equal(MissingCredentialError{Key: "bogus-token"}))
+		})
+	})
+})
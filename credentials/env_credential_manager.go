@@ -0,0 +1,29 @@
+package credentials
+
+import (
+	"os"
+	"strings"
+)
+
+// EnvCredentialManager looks up credentials in the ATC's environment. The key
+// "some-key.value" is read from the variable <Prefix>SOME_KEY_VALUE.
+//
+// An empty variable is treated as missing.
+type EnvCredentialManager struct {
+	Prefix string
+}
+
+var envNameReplacer = strings.NewReplacer("-", "_", ".", "_", "/", "_")
+
+func (manager EnvCredentialManager) Get(key string) (string, error) {
+	value := os.Getenv(manager.envName(key))
+	if value == "" {
+		return "", MissingCredentialError{Key: key}
+	}
+
+	return value, nil
+}
+
+func (manager EnvCredentialManager) envName(key string) string {
+	return manager.Prefix + strings.ToUpper(envNameReplacer.Replace(key))
+}
@@ -0,0 +1,35 @@
+package credentials_test
+
+import (
+	"os"
+
+	. "github.com/concourse/atc/credentials"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EnvCredentialManager", func() {
+	var manager EnvCredentialManager
+
+	BeforeEach(func() {
+		manager = EnvCredentialManager{Prefix: "TEST_CREDENTIAL_"}
+
+		os.Setenv("TEST_CREDENTIAL_SOME_SECRET_TOKEN", "hunter2")
+	})
+
+	AfterEach(func() {
+		os.Setenv("TEST_CREDENTIAL_SOME_SECRET_TOKEN", "")
+	})
+
+	It("reads the credential from the prefixed, upper-cased environment variable", func() {
+		Ω(manager.Get("some-secret.token")).Should(Equal("hunter2"))
+	})
+
+	Context("when the variable is not set", func() {
+		It("returns an error naming the missing key", func() {
+			_, err := manager.Get("bogus-secret")
+			Ω(err).Should(Equal(MissingCredentialError{Key: "bogus-secret"}))
+		})
+	})
+})
@@ -0,0 +1,31 @@
+package atc
+
+type Dashboard struct {
+	Pipelines []DashboardPipeline `json:"pipelines"`
+}
+
+type DashboardPipeline struct {
+	Name   string `json:"name"`
+	Paused bool   `json:"paused,omitempty"`
+
+	Jobs      []DashboardJob      `json:"jobs"`
+	Resources []DashboardResource `json:"resources"`
+}
+
+type DashboardJob struct {
+	Name   string `json:"name"`
+	Paused bool   `json:"paused,omitempty"`
+
+	// the status of the latest finished build; empty if there is none
+	Status string `json:"status,omitempty"`
+}
+
+type DashboardResource struct {
+	Name   string `json:"name"`
+	Paused bool   `json:"paused,omitempty"`
+
+	Failing bool `json:"failing,omitempty"`
+
+	// unix timestamp of the last successful check
+	LastChecked int64 `json:"last_checked,omitempty"`
+}
@@ -28,6 +28,14 @@ type Build struct {
 
 	StartTime time.Time
 	EndTime   time.Time
+
+	// set when the build errored; see exec.StepErrorCategory
+	ErrorCategory string
+
+	InputsDetermined bool
+
+	// starts at 1; incremented for each automatic retry of the build
+	Attempt int
 }
 
 func (b Build) OneOff() bool {
@@ -55,7 +63,18 @@ type SavedResource struct {
 	ID           int
 	CheckError   error
 	Paused       bool
+	Checking     bool
 	PipelineName string
+
+	// zero if the resource has never been checked successfully, or has never
+	// failed to check, respectively
+	LastChecked      time.Time
+	LastCheckErrored time.Time
+
+	// hash of the source the resource was last checked with successfully;
+	// empty if it has not been recorded
+	SourceHash string
+
 	Resource
 }
 
@@ -63,6 +82,25 @@ func (r SavedResource) FailingToCheck() bool {
 	return r.CheckError != nil
 }
 
+type ResourceCheckResult struct {
+	CheckError error
+	CheckedAt  time.Time
+}
+
+func (r ResourceCheckResult) Succeeded() bool {
+	return r.CheckError == nil
+}
+
+type ResourceStatus struct {
+	SavedResource
+
+	// nil if the resource has no versions yet
+	LatestVersion *SavedVersionedResource
+
+	// nil if the resource has never been checked
+	LastCheck *ResourceCheckResult
+}
+
 type VersionedResource struct {
 	Resource     string
 	Type         string
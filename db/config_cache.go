@@ -0,0 +1,137 @@
+package db
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/concourse/atc"
+)
+
+// configCache holds the most recently fetched config of each pipeline, keyed
+// by pipeline ID, so that it only has to be transferred from the database and
+// decoded again once its version changes.
+//
+// Every caller gets its own deep copy of the cached config, and is free to
+// modify it.
+type configCache struct {
+	configs  map[int]cachedConfig
+	configsL sync.RWMutex
+}
+
+type cachedConfig struct {
+	version ConfigVersion
+	config  atc.Config
+}
+
+func newConfigCache() *configCache {
+	return &configCache{
+		configs: map[int]cachedConfig{},
+	}
+}
+
+// get returns a copy of the pipeline's cached config and its version, which
+// is 0 if nothing is cached; config versions start at 1.
+func (cache *configCache) get(pipelineID int) (atc.Config, ConfigVersion) {
+	if cache == nil {
+		return atc.Config{}, 0
+	}
+
+	cache.configsL.RLock()
+	defer cache.configsL.RUnlock()
+
+	cached, found := cache.configs[pipelineID]
+	if !found {
+		return atc.Config{}, 0
+	}
+
+	return copyConfig(cached.config), cached.version
+}
+
+// put caches the config, keeping a copy so that the caller may go on to
+// modify the one it has.
+func (cache *configCache) put(pipelineID int, version ConfigVersion, config atc.Config) {
+	if cache == nil {
+		return
+	}
+
+	cache.configsL.Lock()
+	defer cache.configsL.Unlock()
+
+	cached, found := cache.configs[pipelineID]
+	if found && cached.version > version {
+		// a newer config was cached concurrently
+		return
+	}
+
+	cache.configs[pipelineID] = cachedConfig{
+		version: version,
+		config:  copyConfig(config),
+	}
+}
+
+func copyConfig(config atc.Config) atc.Config {
+	return deepCopy(reflect.ValueOf(config)).Interface().(atc.Config)
+}
+
+// deepCopy copies everything reachable from the value, so that no map, slice,
+// or pointer is shared with the copy. Nil values stay nil.
+func deepCopy(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+
+		copied := reflect.New(value.Type().Elem())
+		copied.Elem().Set(deepCopy(value.Elem()))
+		return copied
+
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(deepCopy(value.Elem()))
+		return copied
+
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+
+		copied := reflect.MakeMap(value.Type())
+		for _, key := range value.MapKeys() {
+			copied.SetMapIndex(key, deepCopy(value.MapIndex(key)))
+		}
+
+		return copied
+
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(deepCopy(value.Index(i)))
+		}
+
+		return copied
+
+	case reflect.Struct:
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+
+		for i := 0; i < value.NumField(); i++ {
+			if copied.Field(i).CanSet() {
+				copied.Field(i).Set(deepCopy(value.Field(i)))
+			}
+		}
+
+		return copied
+
+	default:
+		return value
+	}
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/pivotal-golang/clock"
+	"github.com/pivotal-golang/lager"
+)
+
+const (
+	initialConnectDelay = 1 * time.Second
+	maxConnectDelay     = 16 * time.Second
+)
+
+// Connect calls open until it succeeds, backing off exponentially (with
+// jitter) while the database cannot be dialed. Any other error is returned
+// immediately. Once more than timeout has passed on the given clock, including
+// time spent in open, the last dial error is returned; a timeout of 0 retries
+// forever.
+func Connect(logger lager.Logger, clock clock.Clock, timeout time.Duration, open func() (Conn, error)) (Conn, error) {
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	started := clock.Now()
+
+	for attempt := uint(0); ; attempt++ {
+		conn, err := open()
+		if err == nil {
+			return conn, nil
+		}
+
+		if !strings.Contains(err.Error(), " dial ") {
+			return nil, err
+		}
+
+		delay := connectDelay(random, attempt)
+		waited := clock.Now().Sub(started)
+
+		if timeout != 0 && waited+delay > timeout {
+			logger.Error("giving-up", err, lager.Data{
+				"attempts": attempt + 1,
+				"waited":   waited.String(),
+			})
+
+			return nil, err
+		}
+
+		logger.Error("failed-to-open-db", err, lager.Data{
+			"attempt": attempt + 1,
+			"delay":   delay.String(),
+		})
+
+		clock.Sleep(delay)
+	}
+}
+
+// connectDelay doubles the delay with each attempt, up to a cap, and then
+// picks a random point in its upper half so that a fleet of ATCs restarting
+// together do not all hit the database in lockstep.
+func connectDelay(random *rand.Rand, attempt uint) time.Duration {
+	delay := maxConnectDelay
+	if attempt < 5 {
+		delay = initialConnectDelay << attempt
+	}
+
+	half := delay / 2
+
+	return half + time.Duration(random.Int63n(int64(half)+1))
+}
@@ -0,0 +1,168 @@
+package db_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/fakes"
+	"github.com/pivotal-golang/clock/fakeclock"
+	"github.com/pivotal-golang/lager/lagertest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// sleepingClock is a fake clock whose Sleep passes the time immediately,
+// recording how long it slept for.
+type sleepingClock struct {
+	*fakeclock.FakeClock
+
+	slept []time.Duration
+}
+
+func (clock *sleepingClock) Sleep(d time.Duration) {
+	clock.slept = append(clock.slept, d)
+	clock.Increment(d)
+}
+
+var _ = Describe("Connect", func() {
+	var (
+		logger    *lagertest.TestLogger
+		fakeClock *sleepingClock
+		timeout   time.Duration
+
+		fakeConn  *fakes.FakeConn
+		openStub  func() (db.Conn, error)
+		openCalls int
+
+		conn       db.Conn
+		connectErr error
+	)
+
+	dialErr := errors.New("dial tcp 127.0.0.1:5432: connection refused")
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("connect")
+		fakeClock = &sleepingClock{FakeClock: fakeclock.NewFakeClock(time.Unix(123, 0))}
+		timeout = time.Minute
+
+		fakeConn = new(fakes.FakeConn)
+		openCalls = 0
+		openStub = func() (db.Conn, error) {
+			return fakeConn, nil
+		}
+	})
+
+	JustBeforeEach(func() {
+		conn, connectErr = db.Connect(logger, fakeClock, timeout, func() (db.Conn, error) {
+			openCalls++
+			return openStub()
+		})
+	})
+
+	Context("when opening succeeds", func() {
+		It("returns the connection without waiting", func() {
+			Ω(connectErr).ShouldNot(HaveOccurred())
+			Ω(conn).Should(Equal(fakeConn))
+
+			Ω(openCalls).Should(Equal(1))
+			Ω(fakeClock.slept).Should(BeEmpty())
+		})
+	})
+
+	Context("when the database cannot be dialed a few times", func() {
+		BeforeEach(func() {
+			openStub = func() (db.Conn, error) {
+				if openCalls <= 3 {
+					return nil, dialErr
+				}
+
+				return fakeConn, nil
+			}
+		})
+
+		It("eventually returns the connection", func() {
+			Ω(connectErr).ShouldNot(HaveOccurred())
+			Ω(conn).Should(Equal(fakeConn))
+			Ω(openCalls).Should(Equal(4))
+		})
+
+		It("backs off exponentially with jitter between attempts", func() {
+			Ω(fakeClock.slept).Should(HaveLen(3))
+
+			Ω(fakeClock.slept[0]).Should(BeNumerically("~", 750*time.Millisecond, 250*time.Millisecond))
+			Ω(fakeClock.slept[1]).Should(BeNumerically("~", 1500*time.Millisecond, 500*time.Millisecond))
+			Ω(fakeClock.slept[2]).Should(BeNumerically("~", 3*time.Second, time.Second))
+		})
+	})
+
+	Context("when the database cannot be dialed for a long time", func() {
+		BeforeEach(func() {
+			openStub = func() (db.Conn, error) {
+				if openCalls <= 20 {
+					return nil, dialErr
+				}
+
+				return fakeConn, nil
+			}
+
+			timeout = 0
+		})
+
+		It("caps the delay", func() {
+			Ω(connectErr).ShouldNot(HaveOccurred())
+
+			for _, delay := range fakeClock.slept {
+				Ω(delay).Should(BeNumerically("<=", 16*time.Second))
+			}
+		})
+
+		Context("and the timeout elapses", func() {
+			BeforeEach(func() {
+				timeout = 30 * time.Second
+			})
+
+			It("gives up with the dial error", func() {
+				Ω(connectErr).Should(Equal(dialErr))
+				Ω(conn).Should(BeNil())
+			})
+
+			It("does not wait longer than the timeout", func() {
+				Ω(fakeClock.Now().Sub(time.Unix(123, 0))).Should(BeNumerically("<=", timeout))
+			})
+		})
+	})
+
+	Context("when each attempt to open takes a while", func() {
+		BeforeEach(func() {
+			openStub = func() (db.Conn, error) {
+				fakeClock.Increment(20 * time.Second)
+				return nil, dialErr
+			}
+
+			timeout = 30 * time.Second
+		})
+
+		It("counts the time spent opening towards the timeout", func() {
+			Ω(connectErr).Should(Equal(dialErr))
+			Ω(openCalls).Should(Equal(2))
+		})
+	})
+
+	Context("when opening fails for a reason other than dialing", func() {
+		disaster := errors.New("pq: password authentication failed")
+
+		BeforeEach(func() {
+			openStub = func() (db.Conn, error) {
+				return nil, disaster
+			}
+		})
+
+		It("returns the error immediately", func() {
+			Ω(connectErr).Should(Equal(disaster))
+			Ω(openCalls).Should(Equal(1))
+			Ω(fakeClock.slept).Should(BeEmpty())
+		})
+	})
+})
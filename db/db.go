@@ -36,6 +36,7 @@ type DB interface {
 
 	StartBuild(buildID int, engineName, engineMetadata string) (bool, error)
 	FinishBuild(buildID int, status Status) error
+	SaveBuildErrorCategory(buildID int, category string) error
 	ErrorBuild(buildID int, cause error) error
 
 	SaveBuildInput(buildID int, input BuildInput) (SavedVersionedResource, error)
@@ -3,6 +3,7 @@ package fakes
 
 import (
 	"sync"
+	"time"
 
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
@@ -96,6 +97,15 @@ type FakePipelineDB struct {
 		result1 int
 		result2 error
 	}
+	GetResourceVersionsChecksumStub        func(resourceID int) (string, error)
+	getResourceVersionsChecksumMutex       sync.RWMutex
+	getResourceVersionsChecksumArgsForCall []struct {
+		resourceID int
+	}
+	getResourceVersionsChecksumReturns struct {
+		result1 string
+		result2 error
+	}
 	PauseResourceStub        func(resourceName string) error
 	pauseResourceMutex       sync.RWMutex
 	pauseResourceArgsForCall []struct {
@@ -121,6 +131,15 @@ type FakePipelineDB struct {
 	saveResourceVersionsReturns struct {
 		result1 error
 	}
+	SaveResourceVersionsForNewSourceStub        func(atc.ResourceConfig, []atc.Version) error
+	saveResourceVersionsForNewSourceMutex       sync.RWMutex
+	saveResourceVersionsForNewSourceArgsForCall []struct {
+		arg1 atc.ResourceConfig
+		arg2 []atc.Version
+	}
+	saveResourceVersionsForNewSourceReturns struct {
+		result1 error
+	}
 	GetLatestVersionedResourceStub        func(resource db.SavedResource) (db.SavedVersionedResource, error)
 	getLatestVersionedResourceMutex       sync.RWMutex
 	getLatestVersionedResourceArgsForCall []struct {
@@ -130,6 +149,16 @@ type FakePipelineDB struct {
 		result1 db.SavedVersionedResource
 		result2 error
 	}
+	GetVersionedResourceStub        func(versionedResourceID int) (db.SavedVersionedResource, bool, error)
+	getVersionedResourceMutex       sync.RWMutex
+	getVersionedResourceArgsForCall []struct {
+		versionedResourceID int
+	}
+	getVersionedResourceReturns struct {
+		result1 db.SavedVersionedResource
+		result2 bool
+		result3 error
+	}
 	EnableVersionedResourceStub        func(resourceID int) error
 	enableVersionedResourceMutex       sync.RWMutex
 	enableVersionedResourceArgsForCall []struct {
@@ -146,6 +175,14 @@ type FakePipelineDB struct {
 	disableVersionedResourceReturns struct {
 		result1 error
 	}
+	DeleteVersionedResourceStub        func(versionedResourceID int) error
+	deleteVersionedResourceMutex       sync.RWMutex
+	deleteVersionedResourceArgsForCall []struct {
+		versionedResourceID int
+	}
+	deleteVersionedResourceReturns struct {
+		result1 error
+	}
 	SetResourceCheckErrorStub        func(resource db.SavedResource, err error) error
 	setResourceCheckErrorMutex       sync.RWMutex
 	setResourceCheckErrorArgsForCall []struct {
@@ -155,6 +192,76 @@ type FakePipelineDB struct {
 	setResourceCheckErrorReturns struct {
 		result1 error
 	}
+	SetResourceCheckingStub        func(resource db.SavedResource, ttl time.Duration) error
+	setResourceCheckingMutex       sync.RWMutex
+	setResourceCheckingArgsForCall []struct {
+		resource db.SavedResource
+		ttl      time.Duration
+	}
+	setResourceCheckingReturns struct {
+		result1 error
+	}
+	ClearResourceCheckingStub        func(resource db.SavedResource) error
+	clearResourceCheckingMutex       sync.RWMutex
+	clearResourceCheckingArgsForCall []struct {
+		resource db.SavedResource
+	}
+	clearResourceCheckingReturns struct {
+		result1 error
+	}
+	UpdateResourceLastCheckedStub        func(resource db.SavedResource, checkedAt time.Time) error
+	updateResourceLastCheckedMutex       sync.RWMutex
+	updateResourceLastCheckedArgsForCall []struct {
+		resource  db.SavedResource
+		checkedAt time.Time
+	}
+	updateResourceLastCheckedReturns struct {
+		result1 error
+	}
+	UpdateResourceLastCheckErroredStub        func(resource db.SavedResource, erroredAt time.Time) error
+	updateResourceLastCheckErroredMutex       sync.RWMutex
+	updateResourceLastCheckErroredArgsForCall []struct {
+		resource  db.SavedResource
+		erroredAt time.Time
+	}
+	updateResourceLastCheckErroredReturns struct {
+		result1 error
+	}
+	UpdateResourceSourceHashStub        func(resource db.SavedResource, sourceHash string) error
+	updateResourceSourceHashMutex       sync.RWMutex
+	updateResourceSourceHashArgsForCall []struct {
+		resource   db.SavedResource
+		sourceHash string
+	}
+	updateResourceSourceHashReturns struct {
+		result1 error
+	}
+	SaveResourceCheckResultStub        func(resource db.SavedResource, err error) error
+	saveResourceCheckResultMutex       sync.RWMutex
+	saveResourceCheckResultArgsForCall []struct {
+		resource db.SavedResource
+		err      error
+	}
+	saveResourceCheckResultReturns struct {
+		result1 error
+	}
+	GetResourceCheckHistoryStub        func(resource db.SavedResource, limit int) ([]db.ResourceCheckResult, error)
+	getResourceCheckHistoryMutex       sync.RWMutex
+	getResourceCheckHistoryArgsForCall []struct {
+		resource db.SavedResource
+		limit    int
+	}
+	getResourceCheckHistoryReturns struct {
+		result1 []db.ResourceCheckResult
+		result2 error
+	}
+	GetResourcesWithStatusStub        func() ([]db.ResourceStatus, error)
+	getResourcesWithStatusMutex       sync.RWMutex
+	getResourcesWithStatusArgsForCall []struct{}
+	getResourcesWithStatusReturns     struct {
+		result1 []db.ResourceStatus
+		result2 error
+	}
 	GetJobStub        func(job string) (db.SavedJob, error)
 	getJobMutex       sync.RWMutex
 	getJobArgsForCall []struct {
@@ -164,6 +271,13 @@ type FakePipelineDB struct {
 		result1 db.SavedJob
 		result2 error
 	}
+	GetJobsWithStatusStub        func() ([]db.JobStatus, error)
+	getJobsWithStatusMutex       sync.RWMutex
+	getJobsWithStatusArgsForCall []struct{}
+	getJobsWithStatusReturns     struct {
+		result1 []db.JobStatus
+		result2 error
+	}
 	PauseJobStub        func(job string) error
 	pauseJobMutex       sync.RWMutex
 	pauseJobArgsForCall []struct {
@@ -190,6 +304,27 @@ type FakePipelineDB struct {
 		result2 *db.Build
 		result3 error
 	}
+	GetLatestFinishedBuildStub        func(job string) (db.Build, bool, error)
+	getLatestFinishedBuildMutex       sync.RWMutex
+	getLatestFinishedBuildArgsForCall []struct {
+		job string
+	}
+	getLatestFinishedBuildReturns struct {
+		result1 db.Build
+		result2 bool
+		result3 error
+	}
+	ReapJobBuildsStub        func(job string, keep int, finishedBefore time.Time) ([]int, error)
+	reapJobBuildsMutex       sync.RWMutex
+	reapJobBuildsArgsForCall []struct {
+		job            string
+		keep           int
+		finishedBefore time.Time
+	}
+	reapJobBuildsReturns struct {
+		result1 []int
+		result2 error
+	}
 	GetAllJobBuildsStub        func(job string) ([]db.Build, error)
 	getAllJobBuildsMutex       sync.RWMutex
 	getAllJobBuildsArgsForCall []struct {
@@ -218,6 +353,16 @@ type FakePipelineDB struct {
 		result1 db.Build
 		result2 error
 	}
+	CreateJobBuildWithPinnedInputsStub        func(job string, inputs []db.BuildInput) (db.Build, error)
+	createJobBuildWithPinnedInputsMutex       sync.RWMutex
+	createJobBuildWithPinnedInputsArgsForCall []struct {
+		job    string
+		inputs []db.BuildInput
+	}
+	createJobBuildWithPinnedInputsReturns struct {
+		result1 db.Build
+		result2 error
+	}
 	CreateJobBuildForCandidateInputsStub        func(job string) (db.Build, bool, error)
 	createJobBuildForCandidateInputsMutex       sync.RWMutex
 	createJobBuildForCandidateInputsArgsForCall []struct {
@@ -228,6 +373,16 @@ type FakePipelineDB struct {
 		result2 bool
 		result3 error
 	}
+	CreateJobBuildRetryStub        func(build db.Build) (db.Build, bool, error)
+	createJobBuildRetryMutex       sync.RWMutex
+	createJobBuildRetryArgsForCall []struct {
+		build db.Build
+	}
+	createJobBuildRetryReturns struct {
+		result1 db.Build
+		result2 bool
+		result3 error
+	}
 	UseInputsForBuildStub        func(buildID int, inputs []db.BuildInput) error
 	useInputsForBuildMutex       sync.RWMutex
 	useInputsForBuildArgsForCall []struct {
@@ -266,6 +421,15 @@ type FakePipelineDB struct {
 		result1 db.Build
 		result2 error
 	}
+	GetPendingJobBuildsStub        func(job string) ([]db.Build, error)
+	getPendingJobBuildsMutex       sync.RWMutex
+	getPendingJobBuildsArgsForCall []struct {
+		job string
+	}
+	getPendingJobBuildsReturns struct {
+		result1 []db.Build
+		result2 error
+	}
 	GetCurrentBuildStub        func(job string) (db.Build, error)
 	getCurrentBuildMutex       sync.RWMutex
 	getCurrentBuildArgsForCall []struct {
@@ -336,6 +500,24 @@ type FakePipelineDB struct {
 		result2 []db.BuildOutput
 		result3 error
 	}
+	GetUnavailableBuildInputsStub        func(buildID int) ([]string, error)
+	getUnavailableBuildInputsMutex       sync.RWMutex
+	getUnavailableBuildInputsArgsForCall []struct {
+		buildID int
+	}
+	getUnavailableBuildInputsReturns struct {
+		result1 []string
+		result2 error
+	}
+	GetPinnedBuildInputsStub        func(buildID int) ([]db.BuildInput, error)
+	getPinnedBuildInputsMutex       sync.RWMutex
+	getPinnedBuildInputsArgsForCall []struct {
+		buildID int
+	}
+	getPinnedBuildInputsReturns struct {
+		result1 []db.BuildInput
+		result2 error
+	}
 }
 
 func (fake *FakePipelineDB) GetPipelineName() string {
@@ -653,6 +835,39 @@ func (fake *FakePipelineDB) GetResourceHistoryMaxIDReturns(result1 int, result2
 	}{result1, result2}
 }
 
+func (fake *FakePipelineDB) GetResourceVersionsChecksum(resourceID int) (string, error) {
+	fake.getResourceVersionsChecksumMutex.Lock()
+	fake.getResourceVersionsChecksumArgsForCall = append(fake.getResourceVersionsChecksumArgsForCall, struct {
+		resourceID int
+	}{resourceID})
+	fake.getResourceVersionsChecksumMutex.Unlock()
+	if fake.GetResourceVersionsChecksumStub != nil {
+		return fake.GetResourceVersionsChecksumStub(resourceID)
+	} else {
+		return fake.getResourceVersionsChecksumReturns.result1, fake.getResourceVersionsChecksumReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetResourceVersionsChecksumCallCount() int {
+	fake.getResourceVersionsChecksumMutex.RLock()
+	defer fake.getResourceVersionsChecksumMutex.RUnlock()
+	return len(fake.getResourceVersionsChecksumArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetResourceVersionsChecksumArgsForCall(i int) int {
+	fake.getResourceVersionsChecksumMutex.RLock()
+	defer fake.getResourceVersionsChecksumMutex.RUnlock()
+	return fake.getResourceVersionsChecksumArgsForCall[i].resourceID
+}
+
+func (fake *FakePipelineDB) GetResourceVersionsChecksumReturns(result1 string, result2 error) {
+	fake.GetResourceVersionsChecksumStub = nil
+	fake.getResourceVersionsChecksumReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePipelineDB) PauseResource(resourceName string) error {
 	fake.pauseResourceMutex.Lock()
 	fake.pauseResourceArgsForCall = append(fake.pauseResourceArgsForCall, struct {
@@ -750,6 +965,39 @@ func (fake *FakePipelineDB) SaveResourceVersionsReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakePipelineDB) SaveResourceVersionsForNewSource(arg1 atc.ResourceConfig, arg2 []atc.Version) error {
+	fake.saveResourceVersionsForNewSourceMutex.Lock()
+	fake.saveResourceVersionsForNewSourceArgsForCall = append(fake.saveResourceVersionsForNewSourceArgsForCall, struct {
+		arg1 atc.ResourceConfig
+		arg2 []atc.Version
+	}{arg1, arg2})
+	fake.saveResourceVersionsForNewSourceMutex.Unlock()
+	if fake.SaveResourceVersionsForNewSourceStub != nil {
+		return fake.SaveResourceVersionsForNewSourceStub(arg1, arg2)
+	} else {
+		return fake.saveResourceVersionsForNewSourceReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) SaveResourceVersionsForNewSourceCallCount() int {
+	fake.saveResourceVersionsForNewSourceMutex.RLock()
+	defer fake.saveResourceVersionsForNewSourceMutex.RUnlock()
+	return len(fake.saveResourceVersionsForNewSourceArgsForCall)
+}
+
+func (fake *FakePipelineDB) SaveResourceVersionsForNewSourceArgsForCall(i int) (atc.ResourceConfig, []atc.Version) {
+	fake.saveResourceVersionsForNewSourceMutex.RLock()
+	defer fake.saveResourceVersionsForNewSourceMutex.RUnlock()
+	return fake.saveResourceVersionsForNewSourceArgsForCall[i].arg1, fake.saveResourceVersionsForNewSourceArgsForCall[i].arg2
+}
+
+func (fake *FakePipelineDB) SaveResourceVersionsForNewSourceReturns(result1 error) {
+	fake.SaveResourceVersionsForNewSourceStub = nil
+	fake.saveResourceVersionsForNewSourceReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePipelineDB) GetLatestVersionedResource(resource db.SavedResource) (db.SavedVersionedResource, error) {
 	fake.getLatestVersionedResourceMutex.Lock()
 	fake.getLatestVersionedResourceArgsForCall = append(fake.getLatestVersionedResourceArgsForCall, struct {
@@ -783,6 +1031,40 @@ func (fake *FakePipelineDB) GetLatestVersionedResourceReturns(result1 db.SavedVe
 	}{result1, result2}
 }
 
+func (fake *FakePipelineDB) GetVersionedResource(versionedResourceID int) (db.SavedVersionedResource, bool, error) {
+	fake.getVersionedResourceMutex.Lock()
+	fake.getVersionedResourceArgsForCall = append(fake.getVersionedResourceArgsForCall, struct {
+		versionedResourceID int
+	}{versionedResourceID})
+	fake.getVersionedResourceMutex.Unlock()
+	if fake.GetVersionedResourceStub != nil {
+		return fake.GetVersionedResourceStub(versionedResourceID)
+	} else {
+		return fake.getVersionedResourceReturns.result1, fake.getVersionedResourceReturns.result2, fake.getVersionedResourceReturns.result3
+	}
+}
+
+func (fake *FakePipelineDB) GetVersionedResourceCallCount() int {
+	fake.getVersionedResourceMutex.RLock()
+	defer fake.getVersionedResourceMutex.RUnlock()
+	return len(fake.getVersionedResourceArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetVersionedResourceArgsForCall(i int) int {
+	fake.getVersionedResourceMutex.RLock()
+	defer fake.getVersionedResourceMutex.RUnlock()
+	return fake.getVersionedResourceArgsForCall[i].versionedResourceID
+}
+
+func (fake *FakePipelineDB) GetVersionedResourceReturns(result1 db.SavedVersionedResource, result2 bool, result3 error) {
+	fake.GetVersionedResourceStub = nil
+	fake.getVersionedResourceReturns = struct {
+		result1 db.SavedVersionedResource
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakePipelineDB) EnableVersionedResource(resourceID int) error {
 	fake.enableVersionedResourceMutex.Lock()
 	fake.enableVersionedResourceArgsForCall = append(fake.enableVersionedResourceArgsForCall, struct {
@@ -847,6 +1129,38 @@ func (fake *FakePipelineDB) DisableVersionedResourceReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakePipelineDB) DeleteVersionedResource(versionedResourceID int) error {
+	fake.deleteVersionedResourceMutex.Lock()
+	fake.deleteVersionedResourceArgsForCall = append(fake.deleteVersionedResourceArgsForCall, struct {
+		versionedResourceID int
+	}{versionedResourceID})
+	fake.deleteVersionedResourceMutex.Unlock()
+	if fake.DeleteVersionedResourceStub != nil {
+		return fake.DeleteVersionedResourceStub(versionedResourceID)
+	} else {
+		return fake.deleteVersionedResourceReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) DeleteVersionedResourceCallCount() int {
+	fake.deleteVersionedResourceMutex.RLock()
+	defer fake.deleteVersionedResourceMutex.RUnlock()
+	return len(fake.deleteVersionedResourceArgsForCall)
+}
+
+func (fake *FakePipelineDB) DeleteVersionedResourceArgsForCall(i int) int {
+	fake.deleteVersionedResourceMutex.RLock()
+	defer fake.deleteVersionedResourceMutex.RUnlock()
+	return fake.deleteVersionedResourceArgsForCall[i].versionedResourceID
+}
+
+func (fake *FakePipelineDB) DeleteVersionedResourceReturns(result1 error) {
+	fake.DeleteVersionedResourceStub = nil
+	fake.deleteVersionedResourceReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePipelineDB) SetResourceCheckError(resource db.SavedResource, err error) error {
 	fake.setResourceCheckErrorMutex.Lock()
 	fake.setResourceCheckErrorArgsForCall = append(fake.setResourceCheckErrorArgsForCall, struct {
@@ -880,6 +1194,262 @@ func (fake *FakePipelineDB) SetResourceCheckErrorReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakePipelineDB) SetResourceChecking(resource db.SavedResource, ttl time.Duration) error {
+	fake.setResourceCheckingMutex.Lock()
+	fake.setResourceCheckingArgsForCall = append(fake.setResourceCheckingArgsForCall, struct {
+		resource db.SavedResource
+		ttl      time.Duration
+	}{resource, ttl})
+	fake.setResourceCheckingMutex.Unlock()
+	if fake.SetResourceCheckingStub != nil {
+		return fake.SetResourceCheckingStub(resource, ttl)
+	} else {
+		return fake.setResourceCheckingReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) SetResourceCheckingCallCount() int {
+	fake.setResourceCheckingMutex.RLock()
+	defer fake.setResourceCheckingMutex.RUnlock()
+	return len(fake.setResourceCheckingArgsForCall)
+}
+
+func (fake *FakePipelineDB) SetResourceCheckingArgsForCall(i int) (db.SavedResource, time.Duration) {
+	fake.setResourceCheckingMutex.RLock()
+	defer fake.setResourceCheckingMutex.RUnlock()
+	return fake.setResourceCheckingArgsForCall[i].resource, fake.setResourceCheckingArgsForCall[i].ttl
+}
+
+func (fake *FakePipelineDB) SetResourceCheckingReturns(result1 error) {
+	fake.SetResourceCheckingStub = nil
+	fake.setResourceCheckingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipelineDB) ClearResourceChecking(resource db.SavedResource) error {
+	fake.clearResourceCheckingMutex.Lock()
+	fake.clearResourceCheckingArgsForCall = append(fake.clearResourceCheckingArgsForCall, struct {
+		resource db.SavedResource
+	}{resource})
+	fake.clearResourceCheckingMutex.Unlock()
+	if fake.ClearResourceCheckingStub != nil {
+		return fake.ClearResourceCheckingStub(resource)
+	} else {
+		return fake.clearResourceCheckingReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) ClearResourceCheckingCallCount() int {
+	fake.clearResourceCheckingMutex.RLock()
+	defer fake.clearResourceCheckingMutex.RUnlock()
+	return len(fake.clearResourceCheckingArgsForCall)
+}
+
+func (fake *FakePipelineDB) ClearResourceCheckingArgsForCall(i int) db.SavedResource {
+	fake.clearResourceCheckingMutex.RLock()
+	defer fake.clearResourceCheckingMutex.RUnlock()
+	return fake.clearResourceCheckingArgsForCall[i].resource
+}
+
+func (fake *FakePipelineDB) ClearResourceCheckingReturns(result1 error) {
+	fake.ClearResourceCheckingStub = nil
+	fake.clearResourceCheckingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipelineDB) UpdateResourceLastChecked(resource db.SavedResource, checkedAt time.Time) error {
+	fake.updateResourceLastCheckedMutex.Lock()
+	fake.updateResourceLastCheckedArgsForCall = append(fake.updateResourceLastCheckedArgsForCall, struct {
+		resource  db.SavedResource
+		checkedAt time.Time
+	}{resource, checkedAt})
+	fake.updateResourceLastCheckedMutex.Unlock()
+	if fake.UpdateResourceLastCheckedStub != nil {
+		return fake.UpdateResourceLastCheckedStub(resource, checkedAt)
+	} else {
+		return fake.updateResourceLastCheckedReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) UpdateResourceLastCheckedCallCount() int {
+	fake.updateResourceLastCheckedMutex.RLock()
+	defer fake.updateResourceLastCheckedMutex.RUnlock()
+	return len(fake.updateResourceLastCheckedArgsForCall)
+}
+
+func (fake *FakePipelineDB) UpdateResourceLastCheckedArgsForCall(i int) (db.SavedResource, time.Time) {
+	fake.updateResourceLastCheckedMutex.RLock()
+	defer fake.updateResourceLastCheckedMutex.RUnlock()
+	return fake.updateResourceLastCheckedArgsForCall[i].resource, fake.updateResourceLastCheckedArgsForCall[i].checkedAt
+}
+
+func (fake *FakePipelineDB) UpdateResourceLastCheckedReturns(result1 error) {
+	fake.UpdateResourceLastCheckedStub = nil
+	fake.updateResourceLastCheckedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipelineDB) UpdateResourceLastCheckErrored(resource db.SavedResource, erroredAt time.Time) error {
+	fake.updateResourceLastCheckErroredMutex.Lock()
+	fake.updateResourceLastCheckErroredArgsForCall = append(fake.updateResourceLastCheckErroredArgsForCall, struct {
+		resource  db.SavedResource
+		erroredAt time.Time
+	}{resource, erroredAt})
+	fake.updateResourceLastCheckErroredMutex.Unlock()
+	if fake.UpdateResourceLastCheckErroredStub != nil {
+		return fake.UpdateResourceLastCheckErroredStub(resource, erroredAt)
+	} else {
+		return fake.updateResourceLastCheckErroredReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) UpdateResourceLastCheckErroredCallCount() int {
+	fake.updateResourceLastCheckErroredMutex.RLock()
+	defer fake.updateResourceLastCheckErroredMutex.RUnlock()
+	return len(fake.updateResourceLastCheckErroredArgsForCall)
+}
+
+func (fake *FakePipelineDB) UpdateResourceLastCheckErroredArgsForCall(i int) (db.SavedResource, time.Time) {
+	fake.updateResourceLastCheckErroredMutex.RLock()
+	defer fake.updateResourceLastCheckErroredMutex.RUnlock()
+	return fake.updateResourceLastCheckErroredArgsForCall[i].resource, fake.updateResourceLastCheckErroredArgsForCall[i].erroredAt
+}
+
+func (fake *FakePipelineDB) UpdateResourceLastCheckErroredReturns(result1 error) {
+	fake.UpdateResourceLastCheckErroredStub = nil
+	fake.updateResourceLastCheckErroredReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipelineDB) UpdateResourceSourceHash(resource db.SavedResource, sourceHash string) error {
+	fake.updateResourceSourceHashMutex.Lock()
+	fake.updateResourceSourceHashArgsForCall = append(fake.updateResourceSourceHashArgsForCall, struct {
+		resource   db.SavedResource
+		sourceHash string
+	}{resource, sourceHash})
+	fake.updateResourceSourceHashMutex.Unlock()
+	if fake.UpdateResourceSourceHashStub != nil {
+		return fake.UpdateResourceSourceHashStub(resource, sourceHash)
+	} else {
+		return fake.updateResourceSourceHashReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) UpdateResourceSourceHashCallCount() int {
+	fake.updateResourceSourceHashMutex.RLock()
+	defer fake.updateResourceSourceHashMutex.RUnlock()
+	return len(fake.updateResourceSourceHashArgsForCall)
+}
+
+func (fake *FakePipelineDB) UpdateResourceSourceHashArgsForCall(i int) (db.SavedResource, string) {
+	fake.updateResourceSourceHashMutex.RLock()
+	defer fake.updateResourceSourceHashMutex.RUnlock()
+	return fake.updateResourceSourceHashArgsForCall[i].resource, fake.updateResourceSourceHashArgsForCall[i].sourceHash
+}
+
+func (fake *FakePipelineDB) UpdateResourceSourceHashReturns(result1 error) {
+	fake.UpdateResourceSourceHashStub = nil
+	fake.updateResourceSourceHashReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipelineDB) SaveResourceCheckResult(resource db.SavedResource, err error) error {
+	fake.saveResourceCheckResultMutex.Lock()
+	fake.saveResourceCheckResultArgsForCall = append(fake.saveResourceCheckResultArgsForCall, struct {
+		resource db.SavedResource
+		err      error
+	}{resource, err})
+	fake.saveResourceCheckResultMutex.Unlock()
+	if fake.SaveResourceCheckResultStub != nil {
+		return fake.SaveResourceCheckResultStub(resource, err)
+	} else {
+		return fake.saveResourceCheckResultReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) SaveResourceCheckResultCallCount() int {
+	fake.saveResourceCheckResultMutex.RLock()
+	defer fake.saveResourceCheckResultMutex.RUnlock()
+	return len(fake.saveResourceCheckResultArgsForCall)
+}
+
+func (fake *FakePipelineDB) SaveResourceCheckResultArgsForCall(i int) (db.SavedResource, error) {
+	fake.saveResourceCheckResultMutex.RLock()
+	defer fake.saveResourceCheckResultMutex.RUnlock()
+	return fake.saveResourceCheckResultArgsForCall[i].resource, fake.saveResourceCheckResultArgsForCall[i].err
+}
+
+func (fake *FakePipelineDB) SaveResourceCheckResultReturns(result1 error) {
+	fake.SaveResourceCheckResultStub = nil
+	fake.saveResourceCheckResultReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipelineDB) GetResourceCheckHistory(resource db.SavedResource, limit int) ([]db.ResourceCheckResult, error) {
+	fake.getResourceCheckHistoryMutex.Lock()
+	fake.getResourceCheckHistoryArgsForCall = append(fake.getResourceCheckHistoryArgsForCall, struct {
+		resource db.SavedResource
+		limit    int
+	}{resource, limit})
+	fake.getResourceCheckHistoryMutex.Unlock()
+	if fake.GetResourceCheckHistoryStub != nil {
+		return fake.GetResourceCheckHistoryStub(resource, limit)
+	} else {
+		return fake.getResourceCheckHistoryReturns.result1, fake.getResourceCheckHistoryReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetResourceCheckHistoryCallCount() int {
+	fake.getResourceCheckHistoryMutex.RLock()
+	defer fake.getResourceCheckHistoryMutex.RUnlock()
+	return len(fake.getResourceCheckHistoryArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetResourceCheckHistoryArgsForCall(i int) (db.SavedResource, int) {
+	fake.getResourceCheckHistoryMutex.RLock()
+	defer fake.getResourceCheckHistoryMutex.RUnlock()
+	return fake.getResourceCheckHistoryArgsForCall[i].resource, fake.getResourceCheckHistoryArgsForCall[i].limit
+}
+
+func (fake *FakePipelineDB) GetResourceCheckHistoryReturns(result1 []db.ResourceCheckResult, result2 error) {
+	fake.GetResourceCheckHistoryStub = nil
+	fake.getResourceCheckHistoryReturns = struct {
+		result1 []db.ResourceCheckResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) GetResourcesWithStatus() ([]db.ResourceStatus, error) {
+	fake.getResourcesWithStatusMutex.Lock()
+	fake.getResourcesWithStatusArgsForCall = append(fake.getResourcesWithStatusArgsForCall, struct{}{})
+	fake.getResourcesWithStatusMutex.Unlock()
+	if fake.GetResourcesWithStatusStub != nil {
+		return fake.GetResourcesWithStatusStub()
+	} else {
+		return fake.getResourcesWithStatusReturns.result1, fake.getResourcesWithStatusReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetResourcesWithStatusCallCount() int {
+	fake.getResourcesWithStatusMutex.RLock()
+	defer fake.getResourcesWithStatusMutex.RUnlock()
+	return len(fake.getResourcesWithStatusArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetResourcesWithStatusReturns(result1 []db.ResourceStatus, result2 error) {
+	fake.GetResourcesWithStatusStub = nil
+	fake.getResourcesWithStatusReturns = struct {
+		result1 []db.ResourceStatus
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePipelineDB) GetJob(job string) (db.SavedJob, error) {
 	fake.getJobMutex.Lock()
 	fake.getJobArgsForCall = append(fake.getJobArgsForCall, struct {
@@ -913,6 +1483,31 @@ func (fake *FakePipelineDB) GetJobReturns(result1 db.SavedJob, result2 error) {
 	}{result1, result2}
 }
 
+func (fake *FakePipelineDB) GetJobsWithStatus() ([]db.JobStatus, error) {
+	fake.getJobsWithStatusMutex.Lock()
+	fake.getJobsWithStatusArgsForCall = append(fake.getJobsWithStatusArgsForCall, struct{}{})
+	fake.getJobsWithStatusMutex.Unlock()
+	if fake.GetJobsWithStatusStub != nil {
+		return fake.GetJobsWithStatusStub()
+	} else {
+		return fake.getJobsWithStatusReturns.result1, fake.getJobsWithStatusReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetJobsWithStatusCallCount() int {
+	fake.getJobsWithStatusMutex.RLock()
+	defer fake.getJobsWithStatusMutex.RUnlock()
+	return len(fake.getJobsWithStatusArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetJobsWithStatusReturns(result1 []db.JobStatus, result2 error) {
+	fake.GetJobsWithStatusStub = nil
+	fake.getJobsWithStatusReturns = struct {
+		result1 []db.JobStatus
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePipelineDB) PauseJob(job string) error {
 	fake.pauseJobMutex.Lock()
 	fake.pauseJobArgsForCall = append(fake.pauseJobArgsForCall, struct {
@@ -1011,6 +1606,75 @@ func (fake *FakePipelineDB) GetJobFinishedAndNextBuildReturns(result1 *db.Build,
 	}{result1, result2, result3}
 }
 
+func (fake *FakePipelineDB) GetLatestFinishedBuild(job string) (db.Build, bool, error) {
+	fake.getLatestFinishedBuildMutex.Lock()
+	fake.getLatestFinishedBuildArgsForCall = append(fake.getLatestFinishedBuildArgsForCall, struct {
+		job string
+	}{job})
+	fake.getLatestFinishedBuildMutex.Unlock()
+	if fake.GetLatestFinishedBuildStub != nil {
+		return fake.GetLatestFinishedBuildStub(job)
+	} else {
+		return fake.getLatestFinishedBuildReturns.result1, fake.getLatestFinishedBuildReturns.result2, fake.getLatestFinishedBuildReturns.result3
+	}
+}
+
+func (fake *FakePipelineDB) GetLatestFinishedBuildCallCount() int {
+	fake.getLatestFinishedBuildMutex.RLock()
+	defer fake.getLatestFinishedBuildMutex.RUnlock()
+	return len(fake.getLatestFinishedBuildArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetLatestFinishedBuildArgsForCall(i int) string {
+	fake.getLatestFinishedBuildMutex.RLock()
+	defer fake.getLatestFinishedBuildMutex.RUnlock()
+	return fake.getLatestFinishedBuildArgsForCall[i].job
+}
+
+func (fake *FakePipelineDB) GetLatestFinishedBuildReturns(result1 db.Build, result2 bool, result3 error) {
+	fake.GetLatestFinishedBuildStub = nil
+	fake.getLatestFinishedBuildReturns = struct {
+		result1 db.Build
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePipelineDB) ReapJobBuilds(job string, keep int, finishedBefore time.Time) ([]int, error) {
+	fake.reapJobBuildsMutex.Lock()
+	fake.reapJobBuildsArgsForCall = append(fake.reapJobBuildsArgsForCall, struct {
+		job            string
+		keep           int
+		finishedBefore time.Time
+	}{job, keep, finishedBefore})
+	fake.reapJobBuildsMutex.Unlock()
+	if fake.ReapJobBuildsStub != nil {
+		return fake.ReapJobBuildsStub(job, keep, finishedBefore)
+	} else {
+		return fake.reapJobBuildsReturns.result1, fake.reapJobBuildsReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) ReapJobBuildsCallCount() int {
+	fake.reapJobBuildsMutex.RLock()
+	defer fake.reapJobBuildsMutex.RUnlock()
+	return len(fake.reapJobBuildsArgsForCall)
+}
+
+func (fake *FakePipelineDB) ReapJobBuildsArgsForCall(i int) (string, int, time.Time) {
+	fake.reapJobBuildsMutex.RLock()
+	defer fake.reapJobBuildsMutex.RUnlock()
+	return fake.reapJobBuildsArgsForCall[i].job, fake.reapJobBuildsArgsForCall[i].keep, fake.reapJobBuildsArgsForCall[i].finishedBefore
+}
+
+func (fake *FakePipelineDB) ReapJobBuildsReturns(result1 []int, result2 error) {
+	fake.ReapJobBuildsStub = nil
+	fake.reapJobBuildsReturns = struct {
+		result1 []int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePipelineDB) GetAllJobBuilds(job string) ([]db.Build, error) {
 	fake.getAllJobBuildsMutex.Lock()
 	fake.getAllJobBuildsArgsForCall = append(fake.getAllJobBuildsArgsForCall, struct {
@@ -1111,6 +1775,40 @@ func (fake *FakePipelineDB) CreateJobBuildReturns(result1 db.Build, result2 erro
 	}{result1, result2}
 }
 
+func (fake *FakePipelineDB) CreateJobBuildWithPinnedInputs(job string, inputs []db.BuildInput) (db.Build, error) {
+	fake.createJobBuildWithPinnedInputsMutex.Lock()
+	fake.createJobBuildWithPinnedInputsArgsForCall = append(fake.createJobBuildWithPinnedInputsArgsForCall, struct {
+		job    string
+		inputs []db.BuildInput
+	}{job, inputs})
+	fake.createJobBuildWithPinnedInputsMutex.Unlock()
+	if fake.CreateJobBuildWithPinnedInputsStub != nil {
+		return fake.CreateJobBuildWithPinnedInputsStub(job, inputs)
+	} else {
+		return fake.createJobBuildWithPinnedInputsReturns.result1, fake.createJobBuildWithPinnedInputsReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) CreateJobBuildWithPinnedInputsCallCount() int {
+	fake.createJobBuildWithPinnedInputsMutex.RLock()
+	defer fake.createJobBuildWithPinnedInputsMutex.RUnlock()
+	return len(fake.createJobBuildWithPinnedInputsArgsForCall)
+}
+
+func (fake *FakePipelineDB) CreateJobBuildWithPinnedInputsArgsForCall(i int) (string, []db.BuildInput) {
+	fake.createJobBuildWithPinnedInputsMutex.RLock()
+	defer fake.createJobBuildWithPinnedInputsMutex.RUnlock()
+	return fake.createJobBuildWithPinnedInputsArgsForCall[i].job, fake.createJobBuildWithPinnedInputsArgsForCall[i].inputs
+}
+
+func (fake *FakePipelineDB) CreateJobBuildWithPinnedInputsReturns(result1 db.Build, result2 error) {
+	fake.CreateJobBuildWithPinnedInputsStub = nil
+	fake.createJobBuildWithPinnedInputsReturns = struct {
+		result1 db.Build
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePipelineDB) CreateJobBuildForCandidateInputs(job string) (db.Build, bool, error) {
 	fake.createJobBuildForCandidateInputsMutex.Lock()
 	fake.createJobBuildForCandidateInputsArgsForCall = append(fake.createJobBuildForCandidateInputsArgsForCall, struct {
@@ -1145,6 +1843,40 @@ func (fake *FakePipelineDB) CreateJobBuildForCandidateInputsReturns(result1 db.B
 	}{result1, result2, result3}
 }
 
+func (fake *FakePipelineDB) CreateJobBuildRetry(build db.Build) (db.Build, bool, error) {
+	fake.createJobBuildRetryMutex.Lock()
+	fake.createJobBuildRetryArgsForCall = append(fake.createJobBuildRetryArgsForCall, struct {
+		build db.Build
+	}{build})
+	fake.createJobBuildRetryMutex.Unlock()
+	if fake.CreateJobBuildRetryStub != nil {
+		return fake.CreateJobBuildRetryStub(build)
+	} else {
+		return fake.createJobBuildRetryReturns.result1, fake.createJobBuildRetryReturns.result2, fake.createJobBuildRetryReturns.result3
+	}
+}
+
+func (fake *FakePipelineDB) CreateJobBuildRetryCallCount() int {
+	fake.createJobBuildRetryMutex.RLock()
+	defer fake.createJobBuildRetryMutex.RUnlock()
+	return len(fake.createJobBuildRetryArgsForCall)
+}
+
+func (fake *FakePipelineDB) CreateJobBuildRetryArgsForCall(i int) db.Build {
+	fake.createJobBuildRetryMutex.RLock()
+	defer fake.createJobBuildRetryMutex.RUnlock()
+	return fake.createJobBuildRetryArgsForCall[i].build
+}
+
+func (fake *FakePipelineDB) CreateJobBuildRetryReturns(result1 db.Build, result2 bool, result3 error) {
+	fake.CreateJobBuildRetryStub = nil
+	fake.createJobBuildRetryReturns = struct {
+		result1 db.Build
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakePipelineDB) UseInputsForBuild(buildID int, inputs []db.BuildInput) error {
 	fake.useInputsForBuildMutex.Lock()
 	fake.useInputsForBuildArgsForCall = append(fake.useInputsForBuildArgsForCall, struct {
@@ -1279,6 +2011,39 @@ func (fake *FakePipelineDB) GetNextPendingBuildReturns(result1 db.Build, result2
 	}{result1, result2}
 }
 
+func (fake *FakePipelineDB) GetPendingJobBuilds(job string) ([]db.Build, error) {
+	fake.getPendingJobBuildsMutex.Lock()
+	fake.getPendingJobBuildsArgsForCall = append(fake.getPendingJobBuildsArgsForCall, struct {
+		job string
+	}{job})
+	fake.getPendingJobBuildsMutex.Unlock()
+	if fake.GetPendingJobBuildsStub != nil {
+		return fake.GetPendingJobBuildsStub(job)
+	} else {
+		return fake.getPendingJobBuildsReturns.result1, fake.getPendingJobBuildsReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetPendingJobBuildsCallCount() int {
+	fake.getPendingJobBuildsMutex.RLock()
+	defer fake.getPendingJobBuildsMutex.RUnlock()
+	return len(fake.getPendingJobBuildsArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetPendingJobBuildsArgsForCall(i int) string {
+	fake.getPendingJobBuildsMutex.RLock()
+	defer fake.getPendingJobBuildsMutex.RUnlock()
+	return fake.getPendingJobBuildsArgsForCall[i].job
+}
+
+func (fake *FakePipelineDB) GetPendingJobBuildsReturns(result1 []db.Build, result2 error) {
+	fake.GetPendingJobBuildsStub = nil
+	fake.getPendingJobBuildsReturns = struct {
+		result1 []db.Build
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePipelineDB) GetCurrentBuild(job string) (db.Build, error) {
 	fake.getCurrentBuildMutex.Lock()
 	fake.getCurrentBuildArgsForCall = append(fake.getCurrentBuildArgsForCall, struct {
@@ -1517,4 +2282,70 @@ func (fake *FakePipelineDB) GetBuildResourcesReturns(result1 []db.BuildInput, re
 	}{result1, result2, result3}
 }
 
+func (fake *FakePipelineDB) GetUnavailableBuildInputs(buildID int) ([]string, error) {
+	fake.getUnavailableBuildInputsMutex.Lock()
+	fake.getUnavailableBuildInputsArgsForCall = append(fake.getUnavailableBuildInputsArgsForCall, struct {
+		buildID int
+	}{buildID})
+	fake.getUnavailableBuildInputsMutex.Unlock()
+	if fake.GetUnavailableBuildInputsStub != nil {
+		return fake.GetUnavailableBuildInputsStub(buildID)
+	} else {
+		return fake.getUnavailableBuildInputsReturns.result1, fake.getUnavailableBuildInputsReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetUnavailableBuildInputsCallCount() int {
+	fake.getUnavailableBuildInputsMutex.RLock()
+	defer fake.getUnavailableBuildInputsMutex.RUnlock()
+	return len(fake.getUnavailableBuildInputsArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetUnavailableBuildInputsArgsForCall(i int) int {
+	fake.getUnavailableBuildInputsMutex.RLock()
+	defer fake.getUnavailableBuildInputsMutex.RUnlock()
+	return fake.getUnavailableBuildInputsArgsForCall[i].buildID
+}
+
+func (fake *FakePipelineDB) GetUnavailableBuildInputsReturns(result1 []string, result2 error) {
+	fake.GetUnavailableBuildInputsStub = nil
+	fake.getUnavailableBuildInputsReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) GetPinnedBuildInputs(buildID int) ([]db.BuildInput, error) {
+	fake.getPinnedBuildInputsMutex.Lock()
+	fake.getPinnedBuildInputsArgsForCall = append(fake.getPinnedBuildInputsArgsForCall, struct {
+		buildID int
+	}{buildID})
+	fake.getPinnedBuildInputsMutex.Unlock()
+	if fake.GetPinnedBuildInputsStub != nil {
+		return fake.GetPinnedBuildInputsStub(buildID)
+	} else {
+		return fake.getPinnedBuildInputsReturns.result1, fake.getPinnedBuildInputsReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetPinnedBuildInputsCallCount() int {
+	fake.getPinnedBuildInputsMutex.RLock()
+	defer fake.getPinnedBuildInputsMutex.RUnlock()
+	return len(fake.getPinnedBuildInputsArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetPinnedBuildInputsArgsForCall(i int) int {
+	fake.getPinnedBuildInputsMutex.RLock()
+	defer fake.getPinnedBuildInputsMutex.RUnlock()
+	return fake.getPinnedBuildInputsArgsForCall[i].buildID
+}
+
+func (fake *FakePipelineDB) GetPinnedBuildInputsReturns(result1 []db.BuildInput, result2 error) {
+	fake.GetPinnedBuildInputsStub = nil
+	fake.getPinnedBuildInputsReturns = struct {
+		result1 []db.BuildInput
+		result2 error
+	}{result1, result2}
+}
+
 var _ db.PipelineDB = new(FakePipelineDB)
@@ -150,6 +150,23 @@ func dbSharedBehavior(database *dbSharedBehaviorInput) func() {
 			Ω(err).Should(Equal(db.ErrEndOfBuildEventStream))
 		})
 
+		It("can save the category of a build's error", func() {
+			build, err := database.CreateOneOffBuild()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(build.ErrorCategory).Should(BeEmpty())
+
+			err = database.SaveBuildErrorCategory(build.ID, "system")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			err = database.FinishBuild(build.ID, db.StatusErrored)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			erroredBuild, err := database.GetBuild(build.ID)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(erroredBuild.Status).Should(Equal(db.StatusErrored))
+			Ω(erroredBuild.ErrorCategory).Should(Equal("system"))
+		})
+
 		It("can keep track of workers", func() {
 			Ω(database.Workers()).Should(BeEmpty())
 
@@ -10,3 +10,11 @@ type SavedJob struct {
 	PipelineName string
 	Job
 }
+
+type JobStatus struct {
+	SavedJob
+
+	// the status of the job's most recent build to have finished; empty if
+	// none of its builds have finished yet
+	LatestFinishedStatus Status
+}
@@ -199,6 +199,42 @@ func jobService(database *dbSharedBehaviorInput) func() {
 							})
 						})
 					})
+
+					Context("When the job is serial and also in serial groups", func() {
+						var service db.JobService
+
+						BeforeEach(func() {
+							fakeDB.GetJobReturns(db.SavedJob{
+								Job: db.Job{
+									Name: "some-job",
+								},
+							}, nil)
+
+							var err error
+							service, err = db.NewJobService(atc.JobConfig{
+								Name:         "some-job",
+								Serial:       true,
+								SerialGroups: []string{"shared"},
+							}, fakeDB)
+							Ω(err).ShouldNot(HaveOccurred())
+
+							fakeDB.GetRunningBuildsBySerialGroupReturns([]db.Build{}, nil)
+							fakeDB.GetNextPendingBuildBySerialGroupReturns(db.Build{ID: 1}, nil)
+						})
+
+						It("coordinates through the shared groups, which include the job's own builds", func() {
+							_, _, err := service.CanBuildBeScheduled(db.Build{ID: 1, Status: db.StatusPending})
+							Ω(err).ShouldNot(HaveOccurred())
+
+							jobName, serialGroups := fakeDB.GetRunningBuildsBySerialGroupArgsForCall(0)
+							Ω(jobName).Should(Equal("some-job"))
+							Ω(serialGroups).Should(Equal([]string{"shared"}))
+
+							jobName, serialGroups = fakeDB.GetNextPendingBuildBySerialGroupArgsForCall(0)
+							Ω(jobName).Should(Equal("some-job"))
+							Ω(serialGroups).Should(Equal([]string{"shared"}))
+						})
+					})
 				})
 			})
 		})
@@ -0,0 +1,15 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddErrorCategoryToBuilds(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE builds ADD COLUMN error_category text
+	`)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
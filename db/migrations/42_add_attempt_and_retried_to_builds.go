@@ -0,0 +1,21 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddAttemptAndRetriedToBuilds(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE builds ADD COLUMN attempt integer NOT NULL DEFAULT 1
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		ALTER TABLE builds ADD COLUMN retried bool NOT NULL DEFAULT false
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
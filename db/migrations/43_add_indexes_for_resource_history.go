@@ -0,0 +1,45 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddIndexesForResourceHistory(tx migration.LimitedTx) error {
+	// paginating a resource's history scans its versions by id
+	_, err := tx.Exec(`
+		CREATE INDEX versioned_resources_resource_id_id ON versioned_resources (resource_id, id)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// resource history and passed constraints join versions to builds
+	_, err = tx.Exec(`
+		CREATE INDEX build_inputs_versioned_resource_id_build_id ON build_inputs (versioned_resource_id, build_id)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE INDEX build_outputs_versioned_resource_id_build_id ON build_outputs (versioned_resource_id, build_id)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// and builds back to their versions
+	_, err = tx.Exec(`
+		CREATE INDEX build_inputs_build_id_versioned_resource_id ON build_inputs (build_id, versioned_resource_id)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE INDEX build_outputs_build_id_versioned_resource_id ON build_outputs (build_id, versioned_resource_id)
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
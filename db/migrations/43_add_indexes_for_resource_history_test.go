@@ -0,0 +1,178 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/BurntSushi/migration"
+	. "github.com/concourse/atc/db/migrations"
+	"github.com/concourse/atc/postgresrunner"
+	_ "github.com/lib/pq"
+	"github.com/tedsuo/ifrit"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("43AddIndexesForResourceHistory", func() {
+	var postgresRunner postgresrunner.Runner
+
+	var dbProcess ifrit.Process
+
+	var dbConn *sql.DB
+
+	// explicit type here is important for reflect.ValueOf
+	var migrationToTest migration.Migrator = AddIndexesForResourceHistory
+
+	var precedingMigrations []migration.Migrator
+	var migrationFromSet migration.Migrator
+
+	for _, migration := range Migrations {
+		if reflect.ValueOf(migration) == reflect.ValueOf(migrationToTest) {
+			migrationFromSet = migration
+			break
+		}
+
+		precedingMigrations = append(precedingMigrations, migration)
+	}
+
+	BeforeEach(func() {
+		Ω(migrationFromSet).ShouldNot(BeNil(), "Migration was not added to the list!")
+
+		var err error
+
+		postgresRunner = postgresrunner.Runner{
+			Port: 5433 + GinkgoParallelNode(),
+		}
+
+		dbProcess = ifrit.Envoke(postgresRunner)
+
+		postgresRunner.CreateTestDB()
+
+		dbConn, err = migration.Open("postgres", postgresRunner.DataSourceName(), precedingMigrations)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		err := dbConn.Close()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		postgresRunner.DropTestDB()
+
+		dbProcess.Signal(os.Interrupt)
+		Eventually(dbProcess.Wait(), 10*time.Second).Should(Receive())
+	})
+
+	JustBeforeEach(func() {
+		tx, err := dbConn.Begin()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		err = migrationFromSet(tx)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		err = tx.Commit()
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("when resource history is present", func() {
+		var resourceID int
+
+		BeforeEach(func() {
+			var pipelineID int
+			err := dbConn.QueryRow(`
+				INSERT INTO pipelines (name, config, version)
+				VALUES ('some-pipeline', '{}', nextval('config_version_seq'))
+				RETURNING id
+			`).Scan(&pipelineID)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			err = dbConn.QueryRow(`
+				INSERT INTO resources (name, pipeline_id)
+				VALUES ('some-resource', $1)
+				RETURNING id
+			`, pipelineID).Scan(&resourceID)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			var jobID int
+			err = dbConn.QueryRow(`
+				INSERT INTO jobs (name, pipeline_id)
+				VALUES ('some-job', $1)
+				RETURNING id
+			`, pipelineID).Scan(&jobID)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			for i := 1; i <= 10; i++ {
+				var versionedResourceID int
+				err = dbConn.QueryRow(`
+					INSERT INTO versioned_resources (resource_id, type, version, source, metadata)
+					VALUES ($1, 'some-type', $2, '{}', '[]')
+					RETURNING id
+				`, resourceID, i).Scan(&versionedResourceID)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				var buildID int
+				err = dbConn.QueryRow(`
+					INSERT INTO builds (name, job_id, status)
+					VALUES ($1, $2, 'succeeded')
+					RETURNING id
+				`, i, jobID).Scan(&buildID)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				_, err = dbConn.Exec(`
+					INSERT INTO build_inputs (build_id, versioned_resource_id, name)
+					VALUES ($1, $2, 'some-input')
+				`, buildID, versionedResourceID)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				_, err = dbConn.Exec(`
+					INSERT INTO build_outputs (build_id, versioned_resource_id)
+					VALUES ($1, $2)
+				`, buildID, versionedResourceID)
+				Ω(err).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("adds the indexes", func() {
+			rows, err := dbConn.Query(`
+				SELECT indexname
+				FROM pg_indexes
+				WHERE tablename IN ('versioned_resources', 'build_inputs', 'build_outputs')
+			`)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			defer rows.Close()
+
+			indexes := []string{}
+			for rows.Next() {
+				var index string
+				err := rows.Scan(&index)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				indexes = append(indexes, index)
+			}
+
+			Ω(indexes).Should(ContainElement("versioned_resources_resource_id_id"))
+			Ω(indexes).Should(ContainElement("build_inputs_versioned_resource_id_build_id"))
+			Ω(indexes).Should(ContainElement("build_outputs_versioned_resource_id_build_id"))
+			Ω(indexes).Should(ContainElement("build_inputs_build_id_versioned_resource_id"))
+			Ω(indexes).Should(ContainElement("build_outputs_build_id_versioned_resource_id"))
+		})
+
+		It("leaves the history intact", func() {
+			var maxID, count int
+			err := dbConn.QueryRow(`
+				SELECT COALESCE(MAX(v.id), 0), COUNT(DISTINCT i.build_id)
+				FROM versioned_resources v
+				INNER JOIN build_inputs i ON i.versioned_resource_id = v.id
+				INNER JOIN build_outputs o ON o.versioned_resource_id = v.id
+				WHERE v.resource_id = $1
+			`, resourceID).Scan(&maxID, &count)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(maxID).ShouldNot(BeZero())
+			Ω(count).Should(Equal(10))
+		})
+	})
+})
@@ -0,0 +1,8 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddCheckingUntilToResources(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`ALTER TABLE resources ADD COLUMN checking_until timestamp with time zone NULL`)
+	return err
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateResourceCheckResults(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE resource_check_results (
+			id serial PRIMARY KEY,
+			resource_id integer NOT NULL REFERENCES resources (id) ON DELETE CASCADE,
+			check_error text NULL,
+			checked_at timestamp with time zone NOT NULL DEFAULT now()
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE INDEX resource_check_results_resource_id_id ON resource_check_results (resource_id, id)
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddLastCheckedToResources(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`ALTER TABLE resources ADD COLUMN last_checked timestamp with time zone NULL`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`ALTER TABLE resources ADD COLUMN last_check_errored timestamp with time zone NULL`)
+	return err
+}
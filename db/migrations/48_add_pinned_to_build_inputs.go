@@ -0,0 +1,8 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddPinnedToBuildInputs(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`ALTER TABLE build_inputs ADD COLUMN pinned boolean NOT NULL default false`)
+	return err
+}
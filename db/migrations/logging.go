@@ -0,0 +1,63 @@
+package migrations
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/migration"
+	"github.com/pivotal-golang/lager"
+)
+
+// WithLogging wraps each of the given migrations so that running it logs when
+// it starts and finishes, how long it took, and, should it fail, exactly
+// which migration it was.
+func WithLogging(logger lager.Logger, migrations []migration.Migrator) []migration.Migrator {
+	logged := make([]migration.Migrator, len(migrations))
+
+	for i, migrator := range migrations {
+		logged[i] = loggedMigrator(logger, i+1, migrator)
+	}
+
+	return logged
+}
+
+func loggedMigrator(logger lager.Logger, version int, migrator migration.Migrator) migration.Migrator {
+	return func(tx migration.LimitedTx) error {
+		mLog := logger.Session("migrate", lager.Data{
+			"version": version,
+			"name":    migratorName(migrator),
+		})
+
+		mLog.Info("starting")
+
+		started := time.Now()
+
+		err := migrator(tx)
+		if err != nil {
+			mLog.Error("failed", err, lager.Data{
+				"duration": time.Since(started).String(),
+			})
+
+			return err
+		}
+
+		mLog.Info("finished", lager.Data{
+			"duration": time.Since(started).String(),
+		})
+
+		return nil
+	}
+}
+
+func migratorName(migrator migration.Migrator) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(migrator).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+
+	name := fn.Name()
+
+	return name[strings.LastIndex(name, ".")+1:]
+}
@@ -0,0 +1,107 @@
+package migrations_test
+
+import (
+	"errors"
+
+	"github.com/BurntSushi/migration"
+	"github.com/pivotal-golang/lager"
+	"github.com/pivotal-golang/lager/lagertest"
+
+	. "github.com/concourse/atc/db/migrations"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WithLogging", func() {
+	var (
+		logger *lagertest.TestLogger
+
+		ranFirst  bool
+		ranSecond bool
+		secondErr error
+
+		logged []migration.Migrator
+	)
+
+	firstMigration := func(migration.LimitedTx) error {
+		ranFirst = true
+		return nil
+	}
+
+	secondMigration := func(migration.LimitedTx) error {
+		ranSecond = true
+		return secondErr
+	}
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("migrations")
+
+		ranFirst = false
+		ranSecond = false
+		secondErr = nil
+
+		logged = WithLogging(logger, []migration.Migrator{firstMigration, secondMigration})
+	})
+
+	It("wraps every migration, in order", func() {
+		Ω(logged).Should(HaveLen(2))
+
+		Ω(logged[0](nil)).Should(Succeed())
+		Ω(ranFirst).Should(BeTrue())
+		Ω(ranSecond).Should(BeFalse())
+
+		Ω(logged[1](nil)).Should(Succeed())
+		Ω(ranSecond).Should(BeTrue())
+	})
+
+	It("logs the start and finish of each migration", func() {
+		Ω(logged[1](nil)).Should(Succeed())
+
+		logs := logger.Logs()
+		Ω(logs).Should(HaveLen(2))
+
+		Ω(logs[0].Message).Should(Equal("migrations.migrate.starting"))
+		Ω(logs[0].Data["version"]).Should(BeNumerically("==", 2))
+
+		Ω(logs[1].Message).Should(Equal("migrations.migrate.finished"))
+		Ω(logs[1].Data["version"]).Should(BeNumerically("==", 2))
+		Ω(logs[1].Data).Should(HaveKey("duration"))
+	})
+
+	Context("when a migration fails", func() {
+		disaster := errors.New("nope")
+
+		BeforeEach(func() {
+			secondErr = disaster
+		})
+
+		It("returns the error", func() {
+			Ω(logged[1](nil)).Should(Equal(disaster))
+		})
+
+		It("logs which migration failed", func() {
+			logged[1](nil)
+
+			logs := logger.Logs()
+			Ω(logs).Should(HaveLen(2))
+
+			Ω(logs[1].Message).Should(Equal("migrations.migrate.failed"))
+			Ω(logs[1].LogLevel).Should(Equal(lager.ERROR))
+			Ω(logs[1].Data["version"]).Should(BeNumerically("==", 2))
+			Ω(logs[1].Data["error"]).Should(Equal("nope"))
+		})
+	})
+
+	It("names each migration after its function", func() {
+		logged := WithLogging(logger, []migration.Migrator{SomeNamedMigration})
+
+		Ω(logged[0](nil)).Should(Succeed())
+
+		Ω(logger.Logs()[0].Data["name"]).Should(Equal("SomeNamedMigration"))
+	})
+})
+
+func SomeNamedMigration(migration.LimitedTx) error {
+	return nil
+}
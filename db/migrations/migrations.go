@@ -43,4 +43,12 @@ var Migrations = []migration.Migrator{
 	AddOrderingToPipelines,
 	AddInputsDeterminedToBuilds,
 	AddExplicitToBuildOutputs,
+	AddErrorCategoryToBuilds,
+	AddAttemptAndRetriedToBuilds,
+	AddIndexesForResourceHistory,
+	AddCheckingUntilToResources,
+	CreateResourceCheckResults,
+	AddLastCheckedToResources,
+	AddSourceHashToResources,
+	AddPinnedToBuildInputs,
 }
@@ -18,6 +18,12 @@ func (jobSchedulingLock JobSchedulingLock) Name() string {
 	return "jobScheduling: " + string(jobSchedulingLock)
 }
 
+type BuildReapingLock string
+
+func (buildReapingLock BuildReapingLock) Name() string {
+	return "buildReaping: " + string(buildReapingLock)
+}
+
 type BuildTrackingLock int
 
 func (buildTrackingLock BuildTrackingLock) Name() string {
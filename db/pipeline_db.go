@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/concourse/atc"
 	"github.com/lib/pq"
@@ -30,31 +31,49 @@ type PipelineDB interface {
 	GetResourceHistory(resource string) ([]*VersionHistory, error)
 	GetResourceHistoryCursor(resource string, startingID int, searchUpwards bool, numResults int) ([]*VersionHistory, bool, error)
 	GetResourceHistoryMaxID(resourceID int) (int, error)
+	GetResourceVersionsChecksum(resourceID int) (string, error)
 	PauseResource(resourceName string) error
 	UnpauseResource(resourceName string) error
 
 	SaveResourceVersions(atc.ResourceConfig, []atc.Version) error
+	SaveResourceVersionsForNewSource(atc.ResourceConfig, []atc.Version) error
 	GetLatestVersionedResource(resource SavedResource) (SavedVersionedResource, error)
+	GetVersionedResource(versionedResourceID int) (SavedVersionedResource, bool, error)
 	EnableVersionedResource(resourceID int) error
 	DisableVersionedResource(resourceID int) error
+	DeleteVersionedResource(versionedResourceID int) error
 	SetResourceCheckError(resource SavedResource, err error) error
+	SetResourceChecking(resource SavedResource, ttl time.Duration) error
+	ClearResourceChecking(resource SavedResource) error
+	UpdateResourceLastChecked(resource SavedResource, checkedAt time.Time) error
+	UpdateResourceLastCheckErrored(resource SavedResource, erroredAt time.Time) error
+	UpdateResourceSourceHash(resource SavedResource, sourceHash string) error
+	SaveResourceCheckResult(resource SavedResource, err error) error
+	GetResourceCheckHistory(resource SavedResource, limit int) ([]ResourceCheckResult, error)
+	GetResourcesWithStatus() ([]ResourceStatus, error)
 
 	GetJob(job string) (SavedJob, error)
+	GetJobsWithStatus() ([]JobStatus, error)
 	PauseJob(job string) error
 	UnpauseJob(job string) error
 
 	GetJobFinishedAndNextBuild(job string) (*Build, *Build, error)
+	GetLatestFinishedBuild(job string) (Build, bool, error)
+	ReapJobBuilds(job string, keep int, finishedBefore time.Time) ([]int, error)
 
 	GetAllJobBuilds(job string) ([]Build, error)
 	GetJobBuild(job string, build string) (Build, error)
 	CreateJobBuild(job string) (Build, error)
+	CreateJobBuildWithPinnedInputs(job string, inputs []BuildInput) (Build, error)
 	CreateJobBuildForCandidateInputs(job string) (Build, bool, error)
+	CreateJobBuildRetry(build Build) (Build, bool, error)
 
 	UseInputsForBuild(buildID int, inputs []BuildInput) error
 
 	GetLatestInputVersions(job string, inputs []atc.JobInput) ([]BuildInput, error)
 	GetJobBuildForInputs(job string, inputs []BuildInput) (Build, error)
 	GetNextPendingBuild(job string) (Build, error)
+	GetPendingJobBuilds(job string) ([]Build, error)
 
 	GetCurrentBuild(job string) (Build, error)
 	GetRunningBuildsBySerialGroup(jobName string, serialGrous []string) ([]Build, error)
@@ -64,6 +83,8 @@ type PipelineDB interface {
 	SaveBuildInput(buildID int, input BuildInput) (SavedVersionedResource, error)
 	SaveBuildOutput(buildID int, vr VersionedResource, explicit bool) (SavedVersionedResource, error)
 	GetBuildResources(buildID int) ([]BuildInput, []BuildOutput, error)
+	GetUnavailableBuildInputs(buildID int) ([]string, error)
+	GetPinnedBuildInputs(buildID int) ([]BuildInput, error)
 }
 
 var ErrPipelineNotFound = errors.New("pipeline not found")
@@ -74,6 +95,8 @@ type pipelineDB struct {
 	conn Conn
 	bus  *notificationsBus
 
+	configCache *configCache
+
 	SavedPipeline
 }
 
@@ -198,14 +221,17 @@ func (pdb *pipelineDB) Destroy() error {
 }
 
 func (pdb *pipelineDB) GetConfig() (atc.Config, ConfigVersion, error) {
+	cachedConfig, cachedVersion := pdb.configCache.get(pdb.ID)
+
 	var configBlob []byte
 	var version int
 
+	// the config is only transferred if it is not the cached version
 	err := pdb.conn.QueryRow(`
-			SELECT config, version
-			FROM pipelines
-			WHERE id = $1
-		`, pdb.ID).Scan(&configBlob, &version)
+		SELECT CASE WHEN version = $2 THEN NULL ELSE config END, version
+		FROM pipelines
+		WHERE id = $1
+	`, pdb.ID, int(cachedVersion)).Scan(&configBlob, &version)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return atc.Config{}, 0, ErrPipelineNotFound
@@ -214,12 +240,18 @@ func (pdb *pipelineDB) GetConfig() (atc.Config, ConfigVersion, error) {
 		return atc.Config{}, 0, err
 	}
 
+	if configBlob == nil {
+		return cachedConfig, cachedVersion, nil
+	}
+
 	var config atc.Config
 	err = json.Unmarshal(configBlob, &config)
 	if err != nil {
 		return atc.Config{}, 0, err
 	}
 
+	pdb.configCache.put(pdb.ID, ConfigVersion(version), config)
+
 	return config, ConfigVersion(version), nil
 }
 
@@ -453,16 +485,33 @@ func (pdb *pipelineDB) GetResourceHistoryMaxID(resourceID int) (int, error) {
 	return id, err
 }
 
+// GetResourceVersionsChecksum returns a digest of the resource's versions
+// that changes whenever one is found, enabled, or disabled, or its metadata
+// changes.
+func (pdb *pipelineDB) GetResourceVersionsChecksum(resourceID int) (string, error) {
+	var checksum string
+
+	err := pdb.conn.QueryRow(`
+		SELECT COALESCE(md5(string_agg(id || ':' || enabled || ':' || COALESCE(metadata, ''), ',' ORDER BY id)), '')
+		FROM versioned_resources
+		WHERE resource_id = $1
+	`, resourceID).Scan(&checksum)
+
+	return checksum, err
+}
+
 func (pdb *pipelineDB) getResource(tx *sql.Tx, name string) (SavedResource, error) {
 	var checkErr sql.NullString
+	var lastChecked, lastCheckErrored pq.NullTime
+	var sourceHash sql.NullString
 	var resource SavedResource
 
 	err := tx.QueryRow(`
-			SELECT id, name, check_error, paused
+			SELECT id, name, check_error, paused, COALESCE(checking_until > NOW(), false), last_checked, last_check_errored, source_hash
 			FROM resources
 			WHERE name = $1
 				AND pipeline_id = $2
-		`, name, pdb.ID).Scan(&resource.ID, &resource.Name, &checkErr, &resource.Paused)
+		`, name, pdb.ID).Scan(&resource.ID, &resource.Name, &checkErr, &resource.Paused, &resource.Checking, &lastChecked, &lastCheckErrored, &sourceHash)
 	if err != nil {
 		return SavedResource{}, err
 	}
@@ -471,6 +520,10 @@ func (pdb *pipelineDB) getResource(tx *sql.Tx, name string) (SavedResource, erro
 		resource.CheckError = errors.New(checkErr.String)
 	}
 
+	resource.LastChecked = lastChecked.Time
+	resource.LastCheckErrored = lastCheckErrored.Time
+	resource.SourceHash = sourceHash.String
+
 	resource.PipelineName = pdb.Name
 
 	return resource, nil
@@ -547,6 +600,77 @@ func (pdb *pipelineDB) SaveResourceVersions(config atc.ResourceConfig, versions
 	return nil
 }
 
+// SaveResourceVersionsForNewSource saves the versions found by checking a
+// resource from scratch after its source changed, and records the source as
+// the one the resource was last checked with.
+//
+// Every version that was not found again is disabled, as it came from the old
+// source and may not exist in the new one. A version that was found again
+// keeps its ID, so without this it could be older than versions that only
+// the old source had. Versions that were found again keep whether they are
+// enabled, so a version disabled by hand stays disabled.
+func (pdb *pipelineDB) SaveResourceVersionsForNewSource(config atc.ResourceConfig, versions []atc.Version) error {
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	err = pdb.registerResource(tx, config.Name)
+	if err != nil {
+		return err
+	}
+
+	savedResource, err := pdb.getResource(tx, config.Name)
+	if err != nil {
+		return err
+	}
+
+	params := []interface{}{savedResource.ID}
+	refs := []string{}
+	for _, version := range versions {
+		savedVersion, err := pdb.saveVersionedResource(tx, VersionedResource{
+			Resource: config.Name,
+			Type:     config.Type,
+			Source:   Source(config.Source),
+			Version:  Version(version),
+		})
+		if err != nil {
+			return err
+		}
+
+		params = append(params, savedVersion.ID)
+		refs = append(refs, fmt.Sprintf("$%d", len(params)))
+	}
+
+	notFoundAgain := ""
+	if len(refs) > 0 {
+		notFoundAgain = "AND id NOT IN (" + strings.Join(refs, ",") + ")"
+	}
+
+	_, err = tx.Exec(`
+		UPDATE versioned_resources
+		SET enabled = false
+		WHERE resource_id = $1
+		`+notFoundAgain+`
+	`, params...)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE resources
+		SET source_hash = $2
+		WHERE id = $1
+	`, savedResource.ID, config.Source.Hash())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (pdb *pipelineDB) DisableVersionedResource(resourceID int) error {
 	rows, err := pdb.conn.Exec(`
 		UPDATE versioned_resources
@@ -591,6 +715,118 @@ func (pdb *pipelineDB) EnableVersionedResource(resourceID int) error {
 	return nil
 }
 
+// VersionInUseError is returned when deleting a version that running builds
+// have as an input or output.
+type VersionInUseError struct {
+	VersionedResourceID int
+	Builds              []Build
+}
+
+func (err VersionInUseError) Error() string {
+	names := make([]string, len(err.Builds))
+	for i, build := range err.Builds {
+		names[i] = fmt.Sprintf("%s #%s", build.JobName, build.Name)
+	}
+
+	return fmt.Sprintf("version %d is in use by running builds: %s", err.VersionedResourceID, strings.Join(names, ", "))
+}
+
+// DeleteVersionedResource removes the version entirely, along with the
+// record of which builds it was an input to or an output of. Versions that
+// running builds depend on cannot be deleted; a VersionInUseError listing the
+// builds is returned instead.
+func (pdb *pipelineDB) DeleteVersionedResource(versionedResourceID int) error {
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	// lock the version so that no build can start using it until it's gone
+	var id int
+	err = tx.QueryRow(`
+		SELECT v.id
+		FROM versioned_resources v
+		INNER JOIN resources r ON v.resource_id = r.id
+		WHERE v.id = $1
+			AND r.pipeline_id = $2
+		FOR UPDATE OF v
+	`, versionedResourceID, pdb.ID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nonOneRowAffectedError{0}
+		}
+
+		return err
+	}
+
+	rows, err := tx.Query(`
+		SELECT `+qualifiedBuildColumns+`
+		FROM builds b
+		INNER JOIN jobs j ON b.job_id = j.id
+		INNER JOIN pipelines p ON j.pipeline_id = p.id
+		WHERE b.status IN ('pending', 'started')
+			AND (
+				b.id IN (SELECT build_id FROM build_inputs WHERE versioned_resource_id = $1)
+				OR b.id IN (SELECT build_id FROM build_outputs WHERE versioned_resource_id = $1)
+			)
+		ORDER BY b.id ASC
+	`, versionedResourceID)
+	if err != nil {
+		return err
+	}
+
+	var runningBuilds []Build
+	for rows.Next() {
+		build, err := pdb.scanBuild(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+
+		runningBuilds = append(runningBuilds, build)
+	}
+
+	err = rows.Close()
+	if err != nil {
+		return err
+	}
+
+	if len(runningBuilds) > 0 {
+		return VersionInUseError{
+			VersionedResourceID: versionedResourceID,
+			Builds:              runningBuilds,
+		}
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM build_inputs
+		WHERE versioned_resource_id = $1
+	`, versionedResourceID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM build_outputs
+		WHERE versioned_resource_id = $1
+	`, versionedResourceID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM versioned_resources
+		WHERE id = $1
+	`, versionedResourceID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (pdb *pipelineDB) GetLatestVersionedResource(resource SavedResource) (SavedVersionedResource, error) {
 	var sourceBytes, versionBytes, metadataBytes string
 
@@ -629,6 +865,48 @@ func (pdb *pipelineDB) GetLatestVersionedResource(resource SavedResource) (Saved
 	return svr, nil
 }
 
+func (pdb *pipelineDB) GetVersionedResource(versionedResourceID int) (SavedVersionedResource, bool, error) {
+	var sourceBytes, versionBytes, metadataBytes string
+
+	svr := SavedVersionedResource{
+		VersionedResource: VersionedResource{
+			PipelineName: pdb.Name,
+		},
+	}
+
+	err := pdb.conn.QueryRow(`
+		SELECT v.id, v.enabled, r.name, v.type, v.source, v.version, v.metadata
+		FROM versioned_resources v, resources r
+		WHERE v.id = $1
+		AND r.id = v.resource_id
+		AND r.pipeline_id = $2
+	`, versionedResourceID, pdb.ID).Scan(&svr.ID, &svr.Enabled, &svr.Resource, &svr.Type, &sourceBytes, &versionBytes, &metadataBytes)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return SavedVersionedResource{}, false, nil
+		}
+
+		return SavedVersionedResource{}, false, err
+	}
+
+	err = json.Unmarshal([]byte(sourceBytes), &svr.Source)
+	if err != nil {
+		return SavedVersionedResource{}, false, err
+	}
+
+	err = json.Unmarshal([]byte(versionBytes), &svr.Version)
+	if err != nil {
+		return SavedVersionedResource{}, false, err
+	}
+
+	err = json.Unmarshal([]byte(metadataBytes), &svr.Metadata)
+	if err != nil {
+		return SavedVersionedResource{}, false, err
+	}
+
+	return svr, true, nil
+}
+
 func (pdb *pipelineDB) SetResourceCheckError(resource SavedResource, cause error) error {
 	var err error
 
@@ -649,6 +927,266 @@ func (pdb *pipelineDB) SetResourceCheckError(resource SavedResource, cause error
 	return err
 }
 
+// SetResourceChecking marks the resource as being checked. The mark expires
+// after the given TTL so that it clears even if the checker goes away.
+func (pdb *pipelineDB) SetResourceChecking(resource SavedResource, ttl time.Duration) error {
+	interval := fmt.Sprintf("%d second", int(ttl.Seconds()))
+
+	_, err := pdb.conn.Exec(`
+		UPDATE resources
+		SET checking_until = NOW() + $2::INTERVAL
+		WHERE id = $1
+	`, resource.ID, interval)
+
+	return err
+}
+
+func (pdb *pipelineDB) ClearResourceChecking(resource SavedResource) error {
+	_, err := pdb.conn.Exec(`
+		UPDATE resources
+		SET checking_until = NULL
+		WHERE id = $1
+	`, resource.ID)
+
+	return err
+}
+
+func (pdb *pipelineDB) UpdateResourceLastChecked(resource SavedResource, checkedAt time.Time) error {
+	_, err := pdb.conn.Exec(`
+		UPDATE resources
+		SET last_checked = $2
+		WHERE id = $1
+	`, resource.ID, checkedAt)
+
+	return err
+}
+
+func (pdb *pipelineDB) UpdateResourceSourceHash(resource SavedResource, sourceHash string) error {
+	_, err := pdb.conn.Exec(`
+		UPDATE resources
+		SET source_hash = $2
+		WHERE id = $1
+	`, resource.ID, sourceHash)
+
+	return err
+}
+
+func (pdb *pipelineDB) UpdateResourceLastCheckErrored(resource SavedResource, erroredAt time.Time) error {
+	_, err := pdb.conn.Exec(`
+		UPDATE resources
+		SET last_check_errored = $2
+		WHERE id = $1
+	`, resource.ID, erroredAt)
+
+	return err
+}
+
+// how many check results are kept around per resource
+const resourceCheckHistoryLength = 20
+
+// SaveResourceCheckResult records the outcome of a check, pruning all but the
+// most recent results for the resource.
+func (pdb *pipelineDB) SaveResourceCheckResult(resource SavedResource, cause error) error {
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	var checkErr sql.NullString
+	if cause != nil {
+		checkErr = sql.NullString{String: cause.Error(), Valid: true}
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO resource_check_results (resource_id, check_error)
+		VALUES ($1, $2)
+	`, resource.ID, checkErr)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM resource_check_results
+		WHERE resource_id = $1
+		AND id NOT IN (
+			SELECT id
+			FROM resource_check_results
+			WHERE resource_id = $1
+			ORDER BY id DESC
+			LIMIT $2
+		)
+	`, resource.ID, resourceCheckHistoryLength)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetResourceCheckHistory returns the resource's most recent check results,
+// newest first.
+func (pdb *pipelineDB) GetResourceCheckHistory(resource SavedResource, limit int) ([]ResourceCheckResult, error) {
+	rows, err := pdb.conn.Query(`
+		SELECT check_error, checked_at
+		FROM resource_check_results
+		WHERE resource_id = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`, resource.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	results := []ResourceCheckResult{}
+	for rows.Next() {
+		var checkErr sql.NullString
+		var result ResourceCheckResult
+
+		err := rows.Scan(&checkErr, &result.CheckedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if checkErr.Valid {
+			result.CheckError = errors.New(checkErr.String)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// GetResourcesWithStatus returns every resource in the pipeline's current
+// config along with its latest version and most recent check result.
+// Resources that have been removed from the config are left out, though
+// their rows are kept.
+func (pdb *pipelineDB) GetResourcesWithStatus() ([]ResourceStatus, error) {
+	config, _, err := pdb.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	configured := map[string]bool{}
+	for _, resource := range config.Resources {
+		configured[resource.Name] = true
+	}
+
+	rows, err := pdb.conn.Query(`
+		SELECT r.id, r.name, r.check_error, r.paused, COALESCE(r.checking_until > NOW(), false),
+			r.last_checked, r.last_check_errored,
+			vr.id, vr.enabled, vr.type, vr.source, vr.version, vr.metadata,
+			cr.id, cr.check_error, cr.checked_at
+		FROM resources r
+		LEFT OUTER JOIN versioned_resources vr
+			ON vr.id = (
+				SELECT MAX(id)
+				FROM versioned_resources
+				WHERE resource_id = r.id
+			)
+		LEFT OUTER JOIN resource_check_results cr
+			ON cr.id = (
+				SELECT MAX(id)
+				FROM resource_check_results
+				WHERE resource_id = r.id
+			)
+		WHERE r.pipeline_id = $1
+		ORDER BY r.name ASC
+	`, pdb.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	statuses := []ResourceStatus{}
+	for rows.Next() {
+		var status ResourceStatus
+
+		var resourceCheckErr sql.NullString
+		var lastChecked, lastCheckErrored pq.NullTime
+
+		var versionID sql.NullInt64
+		var versionEnabled sql.NullBool
+		var versionType, sourceBytes, versionBytes, metadataBytes sql.NullString
+
+		var checkID sql.NullInt64
+		var lastCheckErr sql.NullString
+		var checkedAt pq.NullTime
+
+		err := rows.Scan(
+			&status.ID, &status.Name, &resourceCheckErr, &status.Paused, &status.Checking,
+			&lastChecked, &lastCheckErrored,
+			&versionID, &versionEnabled, &versionType, &sourceBytes, &versionBytes, &metadataBytes,
+			&checkID, &lastCheckErr, &checkedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if !configured[status.Name] {
+			continue
+		}
+
+		status.PipelineName = pdb.Name
+
+		if resourceCheckErr.Valid {
+			status.CheckError = errors.New(resourceCheckErr.String)
+		}
+
+		status.LastChecked = lastChecked.Time
+		status.LastCheckErrored = lastCheckErrored.Time
+
+		if versionID.Valid {
+			svr := SavedVersionedResource{
+				ID:      int(versionID.Int64),
+				Enabled: versionEnabled.Bool,
+				VersionedResource: VersionedResource{
+					Resource: status.Name,
+					Type:     versionType.String,
+				},
+			}
+
+			err = json.Unmarshal([]byte(sourceBytes.String), &svr.Source)
+			if err != nil {
+				return nil, err
+			}
+
+			err = json.Unmarshal([]byte(versionBytes.String), &svr.Version)
+			if err != nil {
+				return nil, err
+			}
+
+			err = json.Unmarshal([]byte(metadataBytes.String), &svr.Metadata)
+			if err != nil {
+				return nil, err
+			}
+
+			status.LatestVersion = &svr
+		}
+
+		if checkID.Valid {
+			result := ResourceCheckResult{
+				CheckedAt: checkedAt.Time,
+			}
+
+			if lastCheckErr.Valid {
+				result.CheckError = errors.New(lastCheckErr.String)
+			}
+
+			status.LastCheck = &result
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
 func (pdb *pipelineDB) registerResource(tx *sql.Tx, name string) error {
 	_, err := tx.Exec(`
 		INSERT INTO resources (name, pipeline_id)
@@ -809,77 +1347,178 @@ func (pdb *pipelineDB) CreateJobBuildForCandidateInputs(jobName string) (Build,
 	if err == sql.ErrNoRows {
 		build, err := pdb.createJobBuild(jobName, tx)
 		if err != nil {
-			return Build{}, false, err
+			return Build{}, false, err
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			return Build{}, false, err
+		}
+
+		return build, true, nil
+	}
+
+	err = tx.Commit()
+
+	return Build{}, false, err
+}
+
+func (pdb *pipelineDB) UseInputsForBuild(buildID int, inputs []BuildInput) error {
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	for _, input := range inputs {
+		_, err := pdb.saveBuildInput(tx, buildID, input)
+		if err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.Exec(`
+		UPDATE builds b
+		SET inputs_determined = true
+		WHERE b.id = $1
+	`, buildID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows != 1 {
+		return errors.New("multiple rows affected but expected only one when determining inputs")
+	}
+
+	return tx.Commit()
+}
+
+func (pdb *pipelineDB) CreateJobBuild(jobName string) (Build, error) {
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return Build{}, err
+	}
+
+	defer tx.Rollback()
+
+	build, err := pdb.createJobBuild(jobName, tx)
+	if err != nil {
+		return Build{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return Build{}, err
+	}
+
+	return build, nil
+}
+
+// CreateJobBuildWithPinnedInputs creates a pending build of the job, saving
+// the given inputs as pinned so that they are used in place of the latest
+// versions whenever the build's inputs are determined.
+func (pdb *pipelineDB) CreateJobBuildWithPinnedInputs(jobName string, inputs []BuildInput) (Build, error) {
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return Build{}, err
+	}
+
+	defer tx.Rollback()
+
+	build, err := pdb.createJobBuild(jobName, tx)
+	if err != nil {
+		return Build{}, err
+	}
+
+	for _, input := range inputs {
+		svr, err := pdb.saveVersionedResource(tx, input.VersionedResource)
+		if err != nil {
+			return Build{}, err
 		}
 
-		err = tx.Commit()
+		_, err = tx.Exec(`
+			INSERT INTO build_inputs (build_id, versioned_resource_id, name, pinned)
+			VALUES ($1, $2, $3, true)
+		`, build.ID, svr.ID, input.Name)
 		if err != nil {
-			return Build{}, false, err
+			return Build{}, err
 		}
-
-		return build, true, nil
 	}
 
 	err = tx.Commit()
+	if err != nil {
+		return Build{}, err
+	}
 
-	return Build{}, false, err
+	return build, nil
 }
 
-func (pdb *pipelineDB) UseInputsForBuild(buildID int, inputs []BuildInput) error {
+// CreateJobBuildRetry creates the next attempt of the given build, using the
+// same inputs. It returns false if the build has already been retried.
+func (pdb *pipelineDB) CreateJobBuildRetry(build Build) (Build, bool, error) {
 	tx, err := pdb.conn.Begin()
 	if err != nil {
-		return err
+		return Build{}, false, err
 	}
 
 	defer tx.Rollback()
 
-	for _, input := range inputs {
-		_, err := pdb.saveBuildInput(tx, buildID, input)
-		if err != nil {
-			return err
-		}
-	}
-
 	result, err := tx.Exec(`
-		UPDATE builds b
-		SET inputs_determined = true
-		WHERE b.id = $1
-	`, buildID)
+		UPDATE builds
+		SET retried = true
+		WHERE id = $1
+		AND NOT retried
+	`, build.ID)
 	if err != nil {
-		return err
+		return Build{}, false, err
 	}
 
-	rows, err := result.RowsAffected()
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return Build{}, false, err
 	}
 
-	if rows != 1 {
-		return errors.New("multiple rows affected but expected only one when determining inputs")
+	if rowsAffected == 0 {
+		return Build{}, false, nil
 	}
 
-	return tx.Commit()
-}
-
-func (pdb *pipelineDB) CreateJobBuild(jobName string) (Build, error) {
-	tx, err := pdb.conn.Begin()
+	retry, err := pdb.createJobBuild(build.JobName, tx)
 	if err != nil {
-		return Build{}, err
+		return Build{}, false, err
 	}
 
-	defer tx.Rollback()
+	_, err = tx.Exec(`
+		INSERT INTO build_inputs (build_id, versioned_resource_id, name)
+		SELECT $2, versioned_resource_id, name
+		FROM build_inputs
+		WHERE build_id = $1
+	`, build.ID, retry.ID)
+	if err != nil {
+		return Build{}, false, err
+	}
 
-	build, err := pdb.createJobBuild(jobName, tx)
+	err = tx.QueryRow(`
+		UPDATE builds
+		SET attempt = $2, inputs_determined = true
+		WHERE id = $1
+		RETURNING attempt, inputs_determined
+	`, retry.ID, build.Attempt+1).Scan(&retry.Attempt, &retry.InputsDetermined)
 	if err != nil {
-		return Build{}, err
+		return Build{}, false, err
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		return Build{}, err
+		return Build{}, false, err
 	}
 
-	return build, nil
+	return retry, true, nil
 }
 
 func (pdb *pipelineDB) createJobBuild(jobName string, tx *sql.Tx) (Build, error) {
@@ -1230,6 +1869,88 @@ func (pdb *pipelineDB) GetBuildResources(buildID int) ([]BuildInput, []BuildOutp
 	return inputs, outputs, nil
 }
 
+// GetUnavailableBuildInputs returns the names of the build's inputs whose
+// versions have since been disabled, and so can no longer be used.
+func (pdb *pipelineDB) GetUnavailableBuildInputs(buildID int) ([]string, error) {
+	rows, err := pdb.conn.Query(`
+		SELECT i.name
+		FROM build_inputs i, versioned_resources v
+		WHERE i.build_id = $1
+		AND i.versioned_resource_id = v.id
+		AND NOT v.enabled
+		ORDER BY i.name ASC
+	`, buildID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		err := rows.Scan(&name)
+		if err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// GetPinnedBuildInputs returns the inputs pinned when the build was
+// triggered, ordered by name.
+func (pdb *pipelineDB) GetPinnedBuildInputs(buildID int) ([]BuildInput, error) {
+	rows, err := pdb.conn.Query(`
+		SELECT i.name, r.name, v.type, v.source, v.version, v.metadata
+		FROM build_inputs i, versioned_resources v, resources r
+		WHERE i.build_id = $1
+		AND i.pinned
+		AND i.versioned_resource_id = v.id
+		AND r.id = v.resource_id
+		ORDER BY i.name ASC
+	`, buildID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	inputs := []BuildInput{}
+	for rows.Next() {
+		var input BuildInput
+
+		var source, version, metadata string
+		err := rows.Scan(&input.Name, &input.Resource, &input.Type, &source, &version, &metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal([]byte(source), &input.Source)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal([]byte(version), &input.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal([]byte(metadata), &input.Metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		input.PipelineName = pdb.Name
+
+		inputs = append(inputs, input)
+	}
+
+	return inputs, nil
+}
+
 func (pdb *pipelineDB) updateSerialGroupsForJob(jobName string, serialGroups []string) error {
 	tx, err := pdb.conn.Begin()
 	if err != nil {
@@ -1748,6 +2469,37 @@ func (pdb *pipelineDB) updatePausedJob(job string, pause bool) error {
 	return tx.Commit()
 }
 
+func (pdb *pipelineDB) GetPendingJobBuilds(job string) ([]Build, error) {
+	rows, err := pdb.conn.Query(`
+		SELECT `+qualifiedBuildColumns+`
+		FROM builds b
+		INNER JOIN jobs j ON b.job_id = j.id
+		INNER JOIN pipelines p ON j.pipeline_id = p.id
+		WHERE j.name = $1
+			AND j.pipeline_id = $2
+			AND b.status = 'pending'
+		ORDER BY b.id ASC
+	`, job, pdb.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	bs := []Build{}
+
+	for rows.Next() {
+		build, err := pdb.scanBuild(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		bs = append(bs, build)
+	}
+
+	return bs, nil
+}
+
 func (pdb *pipelineDB) GetAllJobBuilds(job string) ([]Build, error) {
 	rows, err := pdb.conn.Query(`
 		SELECT `+qualifiedBuildColumns+`
@@ -1782,41 +2534,225 @@ func (pdb *pipelineDB) GetJobFinishedAndNextBuild(job string) (*Build, *Build, e
 	var finished *Build
 	var next *Build
 
-	finishedBuild, err := pdb.scanBuild(pdb.conn.QueryRow(`
+	finishedBuild, found, err := pdb.GetLatestFinishedBuild(job)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if found {
+		finished = &finishedBuild
+	}
+
+	nextBuild, err := pdb.scanBuild(pdb.conn.QueryRow(`
 		SELECT `+qualifiedBuildColumns+`
 		FROM builds b
 		INNER JOIN jobs j ON b.job_id = j.id
 		INNER JOIN pipelines p ON j.pipeline_id = p.id
  		WHERE j.name = $1
 		AND j.pipeline_id = $2
-	 	AND b.status NOT IN ('pending', 'started')
-		ORDER BY b.id DESC
+		AND status IN ('pending', 'started')
+		ORDER BY b.id ASC
 		LIMIT 1
 	`, job, pdb.ID))
 	if err == nil {
-		finished = &finishedBuild
+		next = &nextBuild
 	} else if err != nil && err != ErrNoBuild {
 		return nil, nil, err
 	}
 
-	nextBuild, err := pdb.scanBuild(pdb.conn.QueryRow(`
+	return finished, next, nil
+}
+
+// GetLatestFinishedBuild returns the job's most recent build to have reached
+// a terminal status; builds that are still pending or running are ignored.
+func (pdb *pipelineDB) GetLatestFinishedBuild(job string) (Build, bool, error) {
+	build, err := pdb.scanBuild(pdb.conn.QueryRow(`
 		SELECT `+qualifiedBuildColumns+`
 		FROM builds b
 		INNER JOIN jobs j ON b.job_id = j.id
 		INNER JOIN pipelines p ON j.pipeline_id = p.id
- 		WHERE j.name = $1
+		WHERE j.name = $1
 		AND j.pipeline_id = $2
-		AND status IN ('pending', 'started')
-		ORDER BY b.id ASC
+		AND b.status NOT IN ('pending', 'started')
+		ORDER BY b.id DESC
 		LIMIT 1
 	`, job, pdb.ID))
-	if err == nil {
-		next = &nextBuild
-	} else if err != nil && err != ErrNoBuild {
-		return nil, nil, err
+	if err != nil {
+		if err == ErrNoBuild {
+			return Build{}, false, nil
+		}
+
+		return Build{}, false, err
 	}
 
-	return finished, next, nil
+	return build, true, nil
+}
+
+// ReapJobBuilds deletes the job's finished builds that fall outside its
+// retention, along with their events, inputs, and outputs. A build is only
+// deleted if it is older than the newest keep finished builds (when keep is
+// non-zero) and finished before finishedBefore (when it is non-zero). The
+// job's latest successful build is always retained, as later jobs may still
+// depend on its outputs.
+//
+// Versions only pass through a job by way of its builds, so a version that
+// only reaped builds used no longer satisfies later jobs' passed constraints
+// on it; only the versions of retained builds do.
+//
+// It returns the IDs of the builds deleted.
+func (pdb *pipelineDB) ReapJobBuilds(job string, keep int, finishedBefore time.Time) ([]int, error) {
+	if keep == 0 && finishedBefore.IsZero() {
+		return nil, nil
+	}
+
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	savedJob, err := pdb.getJob(tx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := []string{
+		"b.job_id = $1",
+		"b.status NOT IN ('pending', 'started')",
+		`b.id <> COALESCE((
+			SELECT max(id)
+			FROM builds
+			WHERE job_id = $1
+			AND status = 'succeeded'
+		), 0)`,
+	}
+
+	params := []interface{}{savedJob.ID}
+
+	if keep != 0 {
+		params = append(params, keep)
+		conditions = append(conditions, fmt.Sprintf(`b.id NOT IN (
+			SELECT id
+			FROM builds
+			WHERE job_id = $1
+			AND status NOT IN ('pending', 'started')
+			ORDER BY id DESC
+			LIMIT $%d
+		)`, len(params)))
+	}
+
+	if !finishedBefore.IsZero() {
+		params = append(params, finishedBefore)
+		conditions = append(conditions, fmt.Sprintf("b.end_time < $%d", len(params)))
+	}
+
+	rows, err := tx.Query(`
+		SELECT b.id
+		FROM builds b
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY b.id ASC
+	`, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	var buildIDs []int
+
+	for rows.Next() {
+		var buildID int
+		err := rows.Scan(&buildID)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		buildIDs = append(buildIDs, buildID)
+	}
+
+	err = rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	queries := []string{
+		`DELETE FROM build_events WHERE build_id = $1`,
+		`DELETE FROM build_outputs WHERE build_id = $1`,
+		`DELETE FROM build_inputs WHERE build_id = $1`,
+		`DELETE FROM builds WHERE id = $1`,
+	}
+
+	for _, buildID := range buildIDs {
+		for _, query := range queries {
+			_, err := tx.Exec(query, buildID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return buildIDs, nil
+}
+
+// GetJobsWithStatus returns every job in the pipeline's current config along
+// with the status of its latest finished build. Jobs that have been removed
+// from the config are left out, though their rows are kept.
+func (pdb *pipelineDB) GetJobsWithStatus() ([]JobStatus, error) {
+	config, _, err := pdb.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	configured := map[string]bool{}
+	for _, job := range config.Jobs {
+		configured[job.Name] = true
+	}
+
+	rows, err := pdb.conn.Query(`
+		SELECT j.id, j.name, j.paused, b.status
+		FROM jobs j
+		LEFT OUTER JOIN builds b
+			ON b.id = (
+				SELECT MAX(id)
+				FROM builds
+				WHERE job_id = j.id
+				AND status NOT IN ('pending', 'started')
+			)
+		WHERE j.pipeline_id = $1
+		ORDER BY j.name ASC
+	`, pdb.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	statuses := []JobStatus{}
+	for rows.Next() {
+		var status JobStatus
+		var buildStatus sql.NullString
+
+		err := rows.Scan(&status.ID, &status.Name, &status.Paused, &buildStatus)
+		if err != nil {
+			return nil, err
+		}
+
+		if !configured[status.Name] {
+			continue
+		}
+
+		status.PipelineName = pdb.Name
+		status.LatestFinishedStatus = Status(buildStatus.String)
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
 }
 
 func (pdb *pipelineDB) registerJob(tx *sql.Tx, name string) error {
@@ -1870,12 +2806,13 @@ func (pdb *pipelineDB) scanBuild(row scannable) (Build, error) {
 	var name string
 	var jobID int
 	var status string
-	var scheduled bool
-	var engine, engineMetadata, jobName, pipelineName sql.NullString
+	var scheduled, inputsDetermined bool
+	var attempt int
+	var engine, engineMetadata, errorCategory, jobName, pipelineName sql.NullString
 	var startTime pq.NullTime
 	var endTime pq.NullTime
 
-	err := row.Scan(&id, &name, &jobID, &status, &scheduled, &engine, &engineMetadata, &startTime, &endTime, &jobName, &pipelineName)
+	err := row.Scan(&id, &name, &jobID, &status, &scheduled, &engine, &engineMetadata, &startTime, &endTime, &errorCategory, &inputsDetermined, &attempt, &jobName, &pipelineName)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return Build{}, ErrNoBuild
@@ -1898,6 +2835,11 @@ func (pdb *pipelineDB) scanBuild(row scannable) (Build, error) {
 
 		StartTime: startTime.Time,
 		EndTime:   endTime.Time,
+
+		ErrorCategory: errorCategory.String,
+
+		InputsDetermined: inputsDetermined,
+		Attempt:          attempt,
 	}
 
 	if err != nil {
@@ -20,6 +20,8 @@ type pipelineDBFactory struct {
 	conn        Conn
 	bus         *notificationsBus
 	pipelinesDB PipelinesDB
+
+	configCache *configCache
 }
 
 func NewPipelineDBFactory(
@@ -34,6 +36,8 @@ func NewPipelineDBFactory(
 		conn:        sqldbConnection,
 		bus:         bus,
 		pipelinesDB: pipelinesDB,
+
+		configCache: newConfigCache(),
 	}
 }
 
@@ -53,6 +57,8 @@ func (pdbf *pipelineDBFactory) Build(pipeline SavedPipeline) PipelineDB {
 		conn: pdbf.conn,
 		bus:  pdbf.bus,
 
+		configCache: pdbf.configCache,
+
 		SavedPipeline: pipeline,
 	}
 }
@@ -75,6 +81,8 @@ func (pdbf *pipelineDBFactory) BuildDefault() (PipelineDB, error) {
 		conn: pdbf.conn,
 		bus:  pdbf.bus,
 
+		configCache: pdbf.configCache,
+
 		SavedPipeline: orderedPipelines[0],
 	}, nil
 }
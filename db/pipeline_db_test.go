@@ -4,6 +4,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/concourse/atc"
@@ -323,6 +326,58 @@ var _ = Describe("PipelineDB", func() {
 			Ω(otherReturnedConfig).Should(Equal(updatedConfig))
 			Ω(newOtherConfigVersion).ShouldNot(Equal(otherConfigVersion))
 		})
+
+		It("returns the new config after it is saved through another pipeline DB", func() {
+			_, configVersion, err := pipelineDB.GetConfig()
+			Ω(err).ShouldNot(HaveOccurred())
+
+			updatedConfig := config
+			updatedConfig.Groups = append(config.Groups, atc.GroupConfig{
+				Name: "new-group",
+			})
+
+			_, err = sqlDB.SaveConfig("a-pipeline-name", updatedConfig, configVersion, db.PipelineNoChange)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			returnedConfig, newConfigVersion, err := pipelineDB.GetConfig()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(returnedConfig).Should(Equal(updatedConfig))
+			Ω(newConfigVersion).Should(BeNumerically(">", configVersion))
+		})
+
+		It("returns a separate copy of the config to each caller", func() {
+			returnedConfig, _, err := pipelineDB.GetConfig()
+			Ω(err).ShouldNot(HaveOccurred())
+
+			returnedConfig.Jobs[0].Name = "modified"
+			returnedConfig.Jobs[0].TaskConfig.Image = "modified"
+			returnedConfig.Jobs[0].InputConfigs[0].Params["modified"] = true
+			returnedConfig.Jobs[0].SerialGroups[0] = "modified"
+			returnedConfig.Resources[0].Source["modified"] = true
+
+			returnedAgain, _, err := pipelineDB.GetConfig()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(returnedAgain).Should(Equal(config))
+		})
+
+		It("returns a consistent config to concurrent readers", func() {
+			wg := new(sync.WaitGroup)
+
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+
+					returnedConfig, _, err := pipelineDB.GetConfig()
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(returnedConfig).Should(Equal(config))
+				}()
+			}
+
+			wg.Wait()
+		})
 	})
 
 	Context("Resources", func() {
@@ -421,6 +476,43 @@ var _ = Describe("PipelineDB", func() {
 				Ω(pipelineDB.GetLatestVersionedResource(resource)).Should(Equal(enabledVR))
 			})
 
+			It("can look up a version by ID, including whether it is enabled", func() {
+				err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
+					Name:   "some-resource",
+					Type:   "some-type",
+					Source: atc.Source{"some": "source"},
+				}, []atc.Version{{"version": "1"}})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				savedVR, err := pipelineDB.GetLatestVersionedResource(resource)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = pipelineDB.DisableVersionedResource(savedVR.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				foundVR, found, err := pipelineDB.GetVersionedResource(savedVR.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(found).Should(BeTrue())
+
+				Ω(foundVR.ID).Should(Equal(savedVR.ID))
+				Ω(foundVR.Enabled).Should(BeFalse())
+				Ω(foundVR.VersionedResource).Should(Equal(db.VersionedResource{
+					Resource:     "some-resource",
+					Type:         "some-type",
+					Source:       db.Source{"some": "source"},
+					Version:      db.Version{"version": "1"},
+					PipelineName: "a-pipeline-name",
+				}))
+
+				_, found, err = otherPipelineDB.GetVersionedResource(savedVR.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(found).Should(BeFalse())
+
+				_, found, err = pipelineDB.GetVersionedResource(savedVR.ID + 42)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(found).Should(BeFalse())
+			})
+
 			It("prevents the resource version from being eligible as a previous set of inputs", func() {
 				err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
 					Name:   "some-resource",
@@ -595,6 +687,151 @@ var _ = Describe("PipelineDB", func() {
 			})
 		})
 
+		Describe("deleting versioned resources", func() {
+			var resource db.SavedResource
+			var savedVR db.SavedVersionedResource
+
+			BeforeEach(func() {
+				var err error
+				resource, err = pipelineDB.GetResource("some-resource")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = pipelineDB.SaveResourceVersions(atc.ResourceConfig{
+					Name:   "some-resource",
+					Type:   "some-type",
+					Source: atc.Source{"some": "source"},
+				}, []atc.Version{{"version": "1"}})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				savedVR, err = pipelineDB.GetLatestVersionedResource(resource)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("removes the version", func() {
+				err := pipelineDB.DeleteVersionedResource(savedVR.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				_, found, err := pipelineDB.GetVersionedResource(savedVR.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(found).Should(BeFalse())
+
+				history, err := pipelineDB.GetResourceHistory("some-resource")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(history).Should(BeEmpty())
+			})
+
+			It("returns an error if the version is bogus", func() {
+				err := pipelineDB.DeleteVersionedResource(savedVR.ID + 42)
+				Ω(err).Should(HaveOccurred())
+			})
+
+			It("does not delete versions of other pipelines", func() {
+				err := otherPipelineDB.DeleteVersionedResource(savedVR.ID)
+				Ω(err).Should(HaveOccurred())
+
+				_, found, err := pipelineDB.GetVersionedResource(savedVR.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(found).Should(BeTrue())
+			})
+
+			Context("when finished builds used the version", func() {
+				var build db.Build
+
+				BeforeEach(func() {
+					var err error
+					build, err = pipelineDB.CreateJobBuild("some-job")
+					Ω(err).ShouldNot(HaveOccurred())
+
+					_, err = pipelineDB.SaveBuildInput(build.ID, db.BuildInput{
+						Name:              "some-input",
+						VersionedResource: savedVR.VersionedResource,
+					})
+					Ω(err).ShouldNot(HaveOccurred())
+
+					_, err = pipelineDB.SaveBuildOutput(build.ID, savedVR.VersionedResource, true)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					err = sqlDB.FinishBuild(build.ID, db.StatusSucceeded)
+					Ω(err).ShouldNot(HaveOccurred())
+				})
+
+				It("removes the version along with the builds' record of it", func() {
+					err := pipelineDB.DeleteVersionedResource(savedVR.ID)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					_, found, err := pipelineDB.GetVersionedResource(savedVR.ID)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(found).Should(BeFalse())
+
+					inputs, outputs, err := pipelineDB.GetBuildResources(build.ID)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(inputs).Should(BeEmpty())
+					Ω(outputs).Should(BeEmpty())
+				})
+			})
+
+			Context("when a running build used the version", func() {
+				var build db.Build
+
+				BeforeEach(func() {
+					var err error
+					build, err = pipelineDB.CreateJobBuild("some-job")
+					Ω(err).ShouldNot(HaveOccurred())
+
+					_, err = pipelineDB.SaveBuildInput(build.ID, db.BuildInput{
+						Name:              "some-input",
+						VersionedResource: savedVR.VersionedResource,
+					})
+					Ω(err).ShouldNot(HaveOccurred())
+				})
+
+				It("refuses to delete it, listing the build", func() {
+					err := pipelineDB.DeleteVersionedResource(savedVR.ID)
+					Ω(err).Should(BeAssignableToTypeOf(db.VersionInUseError{}))
+
+					inUseErr := err.(db.VersionInUseError)
+					Ω(inUseErr.VersionedResourceID).Should(Equal(savedVR.ID))
+					Ω(inUseErr.Builds).Should(HaveLen(1))
+					Ω(inUseErr.Builds[0].ID).Should(Equal(build.ID))
+
+					Ω(err.Error()).Should(Equal(fmt.Sprintf("version %d is in use by running builds: some-job #%s", savedVR.ID, build.Name)))
+
+					_, found, err := pipelineDB.GetVersionedResource(savedVR.ID)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(found).Should(BeTrue())
+				})
+			})
+
+			Context("when a pending build has the version pinned", func() {
+				var build db.Build
+
+				BeforeEach(func() {
+					var err error
+					build, err = pipelineDB.CreateJobBuildWithPinnedInputs("some-job", []db.BuildInput{
+						{
+							Name:              "some-input",
+							VersionedResource: savedVR.VersionedResource,
+						},
+					})
+					Ω(err).ShouldNot(HaveOccurred())
+				})
+
+				It("refuses to delete it, listing the build", func() {
+					err := pipelineDB.DeleteVersionedResource(savedVR.ID)
+					Ω(err).Should(BeAssignableToTypeOf(db.VersionInUseError{}))
+
+					inUseErr := err.(db.VersionInUseError)
+					Ω(inUseErr.Builds).Should(HaveLen(1))
+					Ω(inUseErr.Builds[0].ID).Should(Equal(build.ID))
+					Ω(inUseErr.Builds[0].Status).Should(Equal(db.StatusPending))
+
+					_, found, err := pipelineDB.GetVersionedResource(savedVR.ID)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(found).Should(BeTrue())
+				})
+			})
+		})
+
 		Describe("saving versioned resources", func() {
 			It("updates the latest versioned resource", func() {
 				err := pipelineDB.SaveResourceVersions(
@@ -655,6 +892,194 @@ var _ = Describe("PipelineDB", func() {
 			Ω(err).Should(Equal(db.ErrNoBuild))
 		})
 
+		It("returns the oldest of several pending builds, regardless of serial groups", func() {
+			build1, err := pipelineDB.CreateJobBuild("some-job")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			build2, err := pipelineDB.CreateJobBuild("some-job")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			_, err = pipelineDB.CreateJobBuild("some-job")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			nextPending, err := pipelineDB.GetNextPendingBuild("some-job")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(nextPending.ID).Should(Equal(build1.ID))
+
+			started, err := sqlDB.StartBuild(build1.ID, "some-engine", "some-metadata")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(started).Should(BeTrue())
+
+			nextPending, err = pipelineDB.GetNextPendingBuild("some-job")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(nextPending.ID).Should(Equal(build2.ID))
+		})
+
+		It("lists only the pending builds of a job, oldest first", func() {
+			build1, err := pipelineDB.CreateJobBuild("some-job")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			build2, err := pipelineDB.CreateJobBuild("some-job")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			build3, err := pipelineDB.CreateJobBuild("some-job")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			started, err := sqlDB.StartBuild(build2.ID, "some-engine", "some-metadata")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(started).Should(BeTrue())
+
+			pending, err := pipelineDB.GetPendingJobBuilds("some-job")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(pending).Should(HaveLen(2))
+			Ω(pending[0].ID).Should(Equal(build1.ID))
+			Ω(pending[1].ID).Should(Equal(build3.ID))
+		})
+
+		Describe("recording when a resource was last checked", func() {
+			var resource db.SavedResource
+
+			BeforeEach(func() {
+				var err error
+				resource, err = pipelineDB.GetResource("resource-name")
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("starts out never having been checked", func() {
+				Ω(resource.LastChecked).Should(BeZero())
+				Ω(resource.LastCheckErrored).Should(BeZero())
+			})
+
+			It("records successful and errored checks separately", func() {
+				checkedAt := time.Now().Add(-time.Minute)
+				erroredAt := time.Now()
+
+				err := pipelineDB.UpdateResourceLastChecked(resource, checkedAt)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				checkedResource, err := pipelineDB.GetResource("resource-name")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(checkedResource.LastChecked).Should(BeTemporally("~", checkedAt, time.Second))
+				Ω(checkedResource.LastCheckErrored).Should(BeZero())
+
+				err = pipelineDB.UpdateResourceLastCheckErrored(resource, erroredAt)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				erroredResource, err := pipelineDB.GetResource("resource-name")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(erroredResource.LastChecked).Should(BeTemporally("~", checkedAt, time.Second))
+				Ω(erroredResource.LastCheckErrored).Should(BeTemporally("~", erroredAt, time.Second))
+			})
+		})
+
+		Describe("recording the source a resource was checked with", func() {
+			It("starts out with no source recorded", func() {
+				resource, err := pipelineDB.GetResource("resource-name")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(resource.SourceHash).Should(BeEmpty())
+			})
+
+			It("records the source's hash", func() {
+				resource, err := pipelineDB.GetResource("resource-name")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = pipelineDB.UpdateResourceSourceHash(resource, "some-hash")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				checkedResource, err := pipelineDB.GetResource("resource-name")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(checkedResource.SourceHash).Should(Equal("some-hash"))
+			})
+
+			Context("when versions are saved for a new source", func() {
+				var (
+					oldConfig atc.ResourceConfig
+					newConfig atc.ResourceConfig
+
+					oldVersionIDs []int
+				)
+
+				BeforeEach(func() {
+					oldConfig = atc.ResourceConfig{
+						Name:   "resource-name",
+						Type:   "some-type",
+						Source: atc.Source{"uri": "old"},
+					}
+
+					newConfig = oldConfig
+					newConfig.Source = atc.Source{"uri": "new"}
+
+					resource, err := pipelineDB.GetResource("resource-name")
+					Ω(err).ShouldNot(HaveOccurred())
+
+					oldVersionIDs = nil
+					for _, version := range []string{"1", "2", "3"} {
+						err := pipelineDB.SaveResourceVersions(oldConfig, []atc.Version{{"version": version}})
+						Ω(err).ShouldNot(HaveOccurred())
+
+						savedVR, err := pipelineDB.GetLatestVersionedResource(resource)
+						Ω(err).ShouldNot(HaveOccurred())
+
+						oldVersionIDs = append(oldVersionIDs, savedVR.ID)
+					}
+
+					err = pipelineDB.DisableVersionedResource(oldVersionIDs[0])
+					Ω(err).ShouldNot(HaveOccurred())
+
+					err = pipelineDB.SaveResourceVersionsForNewSource(newConfig, []atc.Version{
+						{"version": "1"},
+						{"version": "2"},
+						{"version": "4"},
+					})
+					Ω(err).ShouldNot(HaveOccurred())
+				})
+
+				It("records the new source", func() {
+					resource, err := pipelineDB.GetResource("resource-name")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(resource.SourceHash).Should(Equal(newConfig.Source.Hash()))
+				})
+
+				It("keeps the versions found again, with their IDs", func() {
+					refound, found, err := pipelineDB.GetVersionedResource(oldVersionIDs[0])
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(found).Should(BeTrue())
+					Ω(refound.Source).Should(Equal(db.Source{"uri": "new"}))
+				})
+
+				It("leaves a version found again disabled if it had been disabled", func() {
+					refound, found, err := pipelineDB.GetVersionedResource(oldVersionIDs[0])
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(found).Should(BeTrue())
+					Ω(refound.Enabled).Should(BeFalse())
+				})
+
+				It("leaves a version found again enabled if it was enabled", func() {
+					refound, found, err := pipelineDB.GetVersionedResource(oldVersionIDs[1])
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(found).Should(BeTrue())
+					Ω(refound.Enabled).Should(BeTrue())
+				})
+
+				It("disables the versions only the old source had", func() {
+					old, found, err := pipelineDB.GetVersionedResource(oldVersionIDs[2])
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(found).Should(BeTrue())
+					Ω(old.Enabled).Should(BeFalse())
+				})
+
+				It("saves the new versions, enabled", func() {
+					resource, err := pipelineDB.GetResource("resource-name")
+					Ω(err).ShouldNot(HaveOccurred())
+
+					latest, err := pipelineDB.GetLatestVersionedResource(resource)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(latest.Version).Should(Equal(db.Version{"version": "4"}))
+					Ω(latest.Enabled).Should(BeTrue())
+				})
+			})
+		})
+
 		Describe("marking resource checks as errored", func() {
 			var resource db.SavedResource
 
@@ -702,16 +1127,243 @@ var _ = Describe("PipelineDB", func() {
 			})
 		})
 
-		Describe("GetResourceHistoryMaxID", func() {
+		Describe("marking resources as being checked", func() {
+			var resource db.SavedResource
+
 			BeforeEach(func() {
-				for i := 0; i < 10; i++ {
-					err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
-						Name:   "some-resource",
-						Type:   "some-type",
-						Source: atc.Source{"some": "source"},
-					}, []atc.Version{{"version": i}})
+				var err error
+				resource, err = pipelineDB.GetResource("resource-name")
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("is initially not being checked", func() {
+				Ω(resource.Checking).Should(BeFalse())
+			})
+
+			Context("when the resource is marked as being checked", func() {
+				var ttl time.Duration
+
+				BeforeEach(func() {
+					ttl = time.Minute
+				})
+
+				JustBeforeEach(func() {
+					err := pipelineDB.SetResourceChecking(resource, ttl)
 					Ω(err).ShouldNot(HaveOccurred())
-				}
+				})
+
+				It("is being checked", func() {
+					returnedResource, err := pipelineDB.GetResource("resource-name")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(returnedResource.Checking).Should(BeTrue())
+				})
+
+				Context("and then cleared", func() {
+					JustBeforeEach(func() {
+						err := pipelineDB.ClearResourceChecking(resource)
+						Ω(err).ShouldNot(HaveOccurred())
+					})
+
+					It("is no longer being checked", func() {
+						returnedResource, err := pipelineDB.GetResource("resource-name")
+						Ω(err).ShouldNot(HaveOccurred())
+						Ω(returnedResource.Checking).Should(BeFalse())
+					})
+				})
+
+				Context("and the mark expires", func() {
+					BeforeEach(func() {
+						ttl = time.Second
+					})
+
+					It("is no longer being checked", func() {
+						Eventually(func() bool {
+							returnedResource, err := pipelineDB.GetResource("resource-name")
+							Ω(err).ShouldNot(HaveOccurred())
+							return returnedResource.Checking
+						}, 2*ttl).Should(BeFalse())
+					})
+				})
+			})
+		})
+
+		Describe("resource check history", func() {
+			var resource db.SavedResource
+
+			BeforeEach(func() {
+				var err error
+				resource, err = pipelineDB.GetResource("resource-name")
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("is initially empty", func() {
+				history, err := pipelineDB.GetResourceCheckHistory(resource, 10)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(history).Should(BeEmpty())
+			})
+
+			It("accumulates successive results, newest first", func() {
+				err := pipelineDB.SaveResourceCheckResult(resource, nil)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = pipelineDB.SaveResourceCheckResult(resource, errors.New("on fire"))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = pipelineDB.SaveResourceCheckResult(resource, errors.New("still on fire"))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				history, err := pipelineDB.GetResourceCheckHistory(resource, 10)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(history).Should(HaveLen(3))
+
+				Ω(history[0].CheckError).Should(Equal(errors.New("still on fire")))
+				Ω(history[1].CheckError).Should(Equal(errors.New("on fire")))
+				Ω(history[2].CheckError).Should(BeNil())
+				Ω(history[2].Succeeded()).Should(BeTrue())
+
+				Ω(history[0].CheckedAt).ShouldNot(BeZero())
+			})
+
+			It("returns at most the given number of results", func() {
+				for i := 0; i < 5; i++ {
+					err := pipelineDB.SaveResourceCheckResult(resource, fmt.Errorf("failure %d", i))
+					Ω(err).ShouldNot(HaveOccurred())
+				}
+
+				history, err := pipelineDB.GetResourceCheckHistory(resource, 2)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(history).Should(HaveLen(2))
+				Ω(history[0].CheckError).Should(Equal(errors.New("failure 4")))
+				Ω(history[1].CheckError).Should(Equal(errors.New("failure 3")))
+			})
+
+			It("only keeps the most recent results", func() {
+				for i := 0; i < 25; i++ {
+					err := pipelineDB.SaveResourceCheckResult(resource, fmt.Errorf("failure %d", i))
+					Ω(err).ShouldNot(HaveOccurred())
+				}
+
+				history, err := pipelineDB.GetResourceCheckHistory(resource, 100)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(history).Should(HaveLen(20))
+				Ω(history[0].CheckError).Should(Equal(errors.New("failure 24")))
+				Ω(history[19].CheckError).Should(Equal(errors.New("failure 5")))
+			})
+		})
+
+		Describe("GetResourcesWithStatus", func() {
+			BeforeEach(func() {
+				withResources, version, err := pipelineDB.GetConfig()
+				Ω(err).ShouldNot(HaveOccurred())
+
+				withResources.Resources = append(withResources.Resources,
+					atc.ResourceConfig{Name: "resource-a", Type: "some-type"},
+					atc.ResourceConfig{Name: "resource-b", Type: "some-type"},
+					atc.ResourceConfig{Name: "resource-c", Type: "some-type"},
+				)
+
+				_, err = sqlDB.SaveConfig("a-pipeline-name", withResources, version, db.PipelineNoChange)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				versionedConfig := atc.ResourceConfig{
+					Name:   "resource-a",
+					Type:   "some-type",
+					Source: atc.Source{"some": "source"},
+				}
+
+				err = pipelineDB.SaveResourceVersions(versionedConfig, []atc.Version{
+					{"version": "1"},
+					{"version": "2"},
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				resourceA, err := pipelineDB.GetResource("resource-a")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = pipelineDB.SaveResourceCheckResult(resourceA, errors.New("on fire"))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = pipelineDB.SaveResourceCheckResult(resourceA, nil)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				resourceB, err := pipelineDB.GetResource("resource-b")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = pipelineDB.SetResourceCheckError(resourceB, errors.New("still on fire"))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = pipelineDB.SaveResourceCheckResult(resourceB, errors.New("still on fire"))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = pipelineDB.PauseResource("resource-c")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				_, err = otherPipelineDB.GetResource("resource-d")
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("returns each of the pipeline's resources with its latest version and last check", func() {
+				statuses, err := pipelineDB.GetResourcesWithStatus()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(statuses).Should(HaveLen(3))
+
+				By("including the latest version and a successful last check")
+				Ω(statuses[0].Name).Should(Equal("resource-a"))
+				Ω(statuses[0].PipelineName).Should(Equal("a-pipeline-name"))
+				Ω(statuses[0].CheckError).Should(BeNil())
+				Ω(statuses[0].LatestVersion).ShouldNot(BeNil())
+				Ω(statuses[0].LatestVersion.Enabled).Should(BeTrue())
+				Ω(statuses[0].LatestVersion.VersionedResource).Should(Equal(db.VersionedResource{
+					Resource: "resource-a",
+					Type:     "some-type",
+					Source:   db.Source{"some": "source"},
+					Version:  db.Version{"version": "2"},
+				}))
+				Ω(statuses[0].LastCheck).ShouldNot(BeNil())
+				Ω(statuses[0].LastCheck.Succeeded()).Should(BeTrue())
+				Ω(statuses[0].LastCheck.CheckedAt).ShouldNot(BeZero())
+
+				By("including a failed last check for a resource without versions")
+				Ω(statuses[1].Name).Should(Equal("resource-b"))
+				Ω(statuses[1].CheckError).Should(Equal(errors.New("still on fire")))
+				Ω(statuses[1].LatestVersion).Should(BeNil())
+				Ω(statuses[1].LastCheck).ShouldNot(BeNil())
+				Ω(statuses[1].LastCheck.CheckError).Should(Equal(errors.New("still on fire")))
+
+				By("including a resource that has never been checked")
+				Ω(statuses[2].Name).Should(Equal("resource-c"))
+				Ω(statuses[2].Paused).Should(BeTrue())
+				Ω(statuses[2].LatestVersion).Should(BeNil())
+				Ω(statuses[2].LastCheck).Should(BeNil())
+			})
+
+			It("leaves out resources that have been removed from the config", func() {
+				current, version, err := pipelineDB.GetConfig()
+				Ω(err).ShouldNot(HaveOccurred())
+
+				current.Resources = current.Resources[:len(current.Resources)-1]
+
+				_, err = sqlDB.SaveConfig("a-pipeline-name", current, version, db.PipelineNoChange)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				statuses, err := pipelineDB.GetResourcesWithStatus()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(statuses).Should(HaveLen(2))
+				Ω(statuses[0].Name).Should(Equal("resource-a"))
+				Ω(statuses[1].Name).Should(Equal("resource-b"))
+			})
+		})
+
+		Describe("GetResourceHistoryMaxID", func() {
+			BeforeEach(func() {
+				for i := 0; i < 10; i++ {
+					err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
+						Name:   "some-resource",
+						Type:   "some-type",
+						Source: atc.Source{"some": "source"},
+					}, []atc.Version{{"version": i}})
+					Ω(err).ShouldNot(HaveOccurred())
+				}
 
 				for i := 0; i < 5; i++ {
 					err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
@@ -740,143 +1392,600 @@ var _ = Describe("PipelineDB", func() {
 				})
 			})
 
-			Context("when the resource exists and has versions", func() {
-				It("gets the max version id for the given resource", func() {
-					savedResource, err := pipelineDB.GetResource("some-resource")
-					Ω(err).ShouldNot(HaveOccurred())
-					maxID, err := pipelineDB.GetResourceHistoryMaxID(savedResource.ID)
-					Ω(err).ShouldNot(HaveOccurred())
-					Ω(maxID).Should(Equal(10))
+			Context("when the resource exists and has versions", func() {
+				It("gets the max version id for the given resource", func() {
+					savedResource, err := pipelineDB.GetResource("some-resource")
+					Ω(err).ShouldNot(HaveOccurred())
+					maxID, err := pipelineDB.GetResourceHistoryMaxID(savedResource.ID)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(maxID).Should(Equal(10))
+
+					savedResource, err = pipelineDB.GetResource("other-resource")
+					Ω(err).ShouldNot(HaveOccurred())
+					maxID, err = pipelineDB.GetResourceHistoryMaxID(savedResource.ID)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(maxID).Should(Equal(15))
+				})
+			})
+		})
+
+		Describe("GetResourceVersionsChecksum", func() {
+			var savedResource db.SavedResource
+
+			config := atc.ResourceConfig{
+				Name:   "some-resource",
+				Type:   "some-type",
+				Source: atc.Source{"some": "source"},
+			}
+
+			BeforeEach(func() {
+				err := pipelineDB.SaveResourceVersions(config, []atc.Version{{"version": "1"}, {"version": "2"}})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				savedResource, err = pipelineDB.GetResource("some-resource")
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("is the same until the versions change", func() {
+				checksum, err := pipelineDB.GetResourceVersionsChecksum(savedResource.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(checksum).ShouldNot(BeEmpty())
+
+				Ω(pipelineDB.GetResourceVersionsChecksum(savedResource.ID)).Should(Equal(checksum))
+			})
+
+			It("changes when a version is found", func() {
+				checksum, err := pipelineDB.GetResourceVersionsChecksum(savedResource.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = pipelineDB.SaveResourceVersions(config, []atc.Version{{"version": "3"}})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(pipelineDB.GetResourceVersionsChecksum(savedResource.ID)).ShouldNot(Equal(checksum))
+			})
+
+			It("changes when a version is disabled or enabled, even if it is not the latest", func() {
+				checksum, err := pipelineDB.GetResourceVersionsChecksum(savedResource.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				latest, err := pipelineDB.GetLatestVersionedResource(savedResource)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = pipelineDB.DisableVersionedResource(latest.ID - 1)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				disabledChecksum, err := pipelineDB.GetResourceVersionsChecksum(savedResource.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(disabledChecksum).ShouldNot(Equal(checksum))
+
+				err = pipelineDB.EnableVersionedResource(latest.ID - 1)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(pipelineDB.GetResourceVersionsChecksum(savedResource.ID)).Should(Equal(checksum))
+			})
+
+			It("changes when only a version's metadata changes", func() {
+				checksum, err := pipelineDB.GetResourceVersionsChecksum(savedResource.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				build, err := pipelineDB.CreateJobBuild("some-job")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				_, err = pipelineDB.SaveBuildOutput(build.ID, db.VersionedResource{
+					PipelineName: "a-pipeline-name",
+					Resource:     "some-resource",
+					Type:         "some-type",
+					Source:       db.Source{"some": "source"},
+					Version:      db.Version{"version": "1"},
+					Metadata: []db.MetadataField{
+						{Name: "some", Value: "metadata"},
+					},
+				}, false)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(pipelineDB.GetResourceVersionsChecksum(savedResource.ID)).ShouldNot(Equal(checksum))
+			})
+
+			It("is empty for a resource without versions", func() {
+				otherResource, err := pipelineDB.GetResource("some-other-resource")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(pipelineDB.GetResourceVersionsChecksum(otherResource.ID)).Should(BeEmpty())
+			})
+		})
+
+		Describe("GetResourceHistoryCursor", func() {
+			BeforeEach(func() {
+				for i := 1; i <= 20; i++ {
+					err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
+						Name:   "some-resource",
+						Type:   "some-type",
+						Source: atc.Source{"some": "source"},
+					}, []atc.Version{{"version": i}})
+					Ω(err).ShouldNot(HaveOccurred())
+				}
+				for i := 1; i <= 10; i++ {
+					err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
+						Name:   "other-resource",
+						Type:   "some-type",
+						Source: atc.Source{"some": "source"},
+					}, []atc.Version{{"version": i}})
+					Ω(err).ShouldNot(HaveOccurred())
+				}
+				for i := 21; i <= 30; i++ {
+					err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
+						Name:   "some-resource",
+						Type:   "some-type",
+						Source: atc.Source{"some": "source"},
+					}, []atc.Version{{"version": i}})
+					Ω(err).ShouldNot(HaveOccurred())
+				}
+
+				err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
+					Name:   "another-resource",
+					Type:   "some-type",
+					Source: atc.Source{"some": "source"},
+				}, []atc.Version{})
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			Context("when greaterThanStartingID is false", func() {
+				greaterThanStartingID := false
+
+				Context("when numResults is greater than the number of versions to return", func() {
+					It("should return a slice of the VersionHistorys in descending order from the startingID", func() {
+						versionHistories, hasNext, err := pipelineDB.GetResourceHistoryCursor("some-resource", 35, greaterThanStartingID, 50)
+						Ω(err).ShouldNot(HaveOccurred())
+						Ω(hasNext).Should(BeFalse())
+						numResults := len(versionHistories)
+						Ω(numResults).Should(Equal(25))
+						firstID := versionHistories[0].VersionedResource.ID
+						Ω(firstID).Should(Equal(35))
+						Ω(firstID).Should(BeNumerically(">", versionHistories[numResults-1].VersionedResource.ID))
+					})
+				})
+
+				Context("when numResults is less than the number of versions to return", func() {
+					It("should return a limited slice of the VersionHistories", func() {
+						versionHistories, hasNext, err := pipelineDB.GetResourceHistoryCursor("some-resource", 30, greaterThanStartingID, 17)
+						Ω(err).ShouldNot(HaveOccurred())
+						Ω(len(versionHistories)).Should(Equal(17))
+						Ω(hasNext).Should(BeTrue())
+					})
+				})
+
+				Context("when numResults is 0", func() {
+					It("returns all the rows", func() {
+						versionHistories, hasNext, err := pipelineDB.GetResourceHistoryCursor("some-resource", 100, greaterThanStartingID, 0)
+						Ω(err).ShouldNot(HaveOccurred())
+						Ω(len(versionHistories)).Should(Equal(30))
+						Ω(hasNext).Should(BeFalse())
+					})
+				})
+			})
+
+			Context("when greaterThanStartingID is true", func() {
+				greaterThanStartingID := true
+
+				Context("when numResults is greater than the number of versions to return", func() {
+					It("should return a slice of the VersionHistorys in descending order from that are greater then or equal to the startingID", func() {
+						versionHistories, hasNext, err := pipelineDB.GetResourceHistoryCursor("some-resource", 15, greaterThanStartingID, 7)
+						Ω(err).ShouldNot(HaveOccurred())
+						Ω(hasNext).Should(BeTrue())
+
+						numResults := len(versionHistories)
+						Ω(numResults).Should(Equal(7))
+
+						lastID := versionHistories[numResults-1].VersionedResource.ID
+						Ω(lastID).Should(Equal(15))
+
+						firstID := versionHistories[0].VersionedResource.ID
+						Ω(firstID).Should(Equal(31))
+
+						Ω(firstID).Should(BeNumerically(">", lastID))
+					})
+				})
+
+				Context("when numResults is 0", func() {
+					It("returns all the rows", func() {
+						versionHistories, hasNext, err := pipelineDB.GetResourceHistoryCursor("some-resource", 0, greaterThanStartingID, 0)
+						Ω(err).ShouldNot(HaveOccurred())
+						Ω(len(versionHistories)).Should(Equal(30))
+						Ω(hasNext).Should(BeFalse())
+					})
+				})
+			})
+		})
+	})
+
+	Context("Jobs", func() {
+		Describe("CreateJobBuild", func() {
+			var build db.Build
+
+			BeforeEach(func() {
+				var err error
+				build, err = pipelineDB.CreateJobBuild("some-job")
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("sets the properties of a build for a given job", func() {
+				Ω(build.ID).ShouldNot(BeZero())
+				Ω(build.JobID).ShouldNot(BeZero())
+				Ω(build.Name).Should(Equal("1"))
+				Ω(build.Status).Should(Equal(db.StatusPending))
+				Ω(build.Scheduled).Should(BeFalse())
+			})
+
+			It("numbers builds created concurrently without gaps", func() {
+				wg := new(sync.WaitGroup)
+
+				names := make(chan string, 10)
+
+				for i := 0; i < 10; i++ {
+					wg.Add(1)
+
+					go func() {
+						defer GinkgoRecover()
+						defer wg.Done()
+
+						build, err := pipelineDB.CreateJobBuild("some-job")
+						Ω(err).ShouldNot(HaveOccurred())
+
+						names <- build.Name
+					}()
+				}
+
+				wg.Wait()
+				close(names)
+
+				numbers := []int{}
+				for name := range names {
+					number, err := strconv.Atoi(name)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					numbers = append(numbers, number)
+				}
+
+				sort.Ints(numbers)
+
+				Ω(numbers).Should(Equal([]int{2, 3, 4, 5, 6, 7, 8, 9, 10, 11}))
+			})
+
+			It("numbers each job's builds independently", func() {
+				otherBuild, err := pipelineDB.CreateJobBuild("some-other-job")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(otherBuild.Name).Should(Equal("1"))
+
+				nextBuild, err := pipelineDB.CreateJobBuild("some-job")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(nextBuild.Name).Should(Equal("2"))
+			})
+		})
+
+		Describe("GetJobBuild", func() {
+			var (
+				firstBuild  db.Build
+				secondBuild db.Build
+			)
+
+			BeforeEach(func() {
+				var err error
+				firstBuild, err = pipelineDB.CreateJobBuild("some-job")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				secondBuild, err = pipelineDB.CreateJobBuild("some-job")
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("names the job's builds sequentially", func() {
+				Ω(firstBuild.Name).Should(Equal("1"))
+				Ω(secondBuild.Name).Should(Equal("2"))
+			})
+
+			It("looks up a build by its job and name", func() {
+				build, err := pipelineDB.GetJobBuild("some-job", "2")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(build.ID).Should(Equal(secondBuild.ID))
+				Ω(build.JobName).Should(Equal("some-job"))
+				Ω(build.PipelineName).Should(Equal("a-pipeline-name"))
+			})
+
+			It("does not find builds of other jobs by the same name", func() {
+				otherBuild, err := pipelineDB.CreateJobBuild("some-other-job")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(otherBuild.Name).Should(Equal("1"))
+
+				build, err := pipelineDB.GetJobBuild("some-job", "1")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(build.ID).Should(Equal(firstBuild.ID))
+			})
+
+			It("returns ErrNoBuild when the build does not exist", func() {
+				_, err := pipelineDB.GetJobBuild("some-job", "42")
+				Ω(err).Should(Equal(db.ErrNoBuild))
+			})
+		})
+
+		Describe("CreateJobBuildRetry", func() {
+			var (
+				build  db.Build
+				inputs []db.BuildInput
+			)
+
+			BeforeEach(func() {
+				var err error
+				build, err = pipelineDB.CreateJobBuild("some-job")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				inputs = []db.BuildInput{
+					{
+						Name: "some-input",
+						VersionedResource: db.VersionedResource{
+							PipelineName: "a-pipeline-name",
+							Resource:     "some-resource",
+							Type:         "some-type",
+							Source:       db.Source{"some": "source"},
+							Version:      db.Version{"ver": "1"},
+							Metadata:     []db.MetadataField{},
+						},
+					},
+				}
+
+				err = pipelineDB.UseInputsForBuild(build.ID, inputs)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = sqlDB.SaveBuildErrorCategory(build.ID, "system")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = sqlDB.FinishBuild(build.ID, db.StatusErrored)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				build, err = sqlDB.GetBuild(build.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("creates the next attempt of the build with the same inputs", func() {
+				Ω(build.Attempt).Should(Equal(1))
+
+				retry, created, err := pipelineDB.CreateJobBuildRetry(build)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(created).Should(BeTrue())
+
+				Ω(retry.ID).ShouldNot(Equal(build.ID))
+				Ω(retry.Name).Should(Equal("2"))
+				Ω(retry.JobName).Should(Equal("some-job"))
+				Ω(retry.Status).Should(Equal(db.StatusPending))
+				Ω(retry.Attempt).Should(Equal(2))
+				Ω(retry.InputsDetermined).Should(BeTrue())
+
+				retryInputs, _, err := pipelineDB.GetBuildResources(retry.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(retryInputs).Should(HaveLen(1))
+				Ω(retryInputs[0].Name).Should(Equal("some-input"))
+				Ω(retryInputs[0].VersionedResource.Version).Should(Equal(db.Version{"ver": "1"}))
+
+				gotRetry, err := sqlDB.GetBuild(retry.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(gotRetry).Should(Equal(retry))
+			})
+
+			It("only retries a build once", func() {
+				_, created, err := pipelineDB.CreateJobBuildRetry(build)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(created).Should(BeTrue())
+
+				_, created, err = pipelineDB.CreateJobBuildRetry(build)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(created).Should(BeFalse())
+			})
+		})
+
+		Describe("GetJobsWithStatus", func() {
+			It("returns the pipeline's jobs with the status of their latest finished build", func() {
+				statuses, err := pipelineDB.GetJobsWithStatus()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(statuses).Should(HaveLen(1))
+				Ω(statuses[0].Name).Should(Equal("some-job"))
+				Ω(statuses[0].PipelineName).Should(Equal("a-pipeline-name"))
+				Ω(statuses[0].Paused).Should(BeFalse())
+				Ω(statuses[0].LatestFinishedStatus).Should(BeEmpty())
+
+				finished, err := pipelineDB.CreateJobBuild("some-job")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = sqlDB.FinishBuild(finished.ID, db.StatusFailed)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				_, err = pipelineDB.CreateJobBuild("some-job")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = pipelineDB.PauseJob("some-job")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				statuses, err = pipelineDB.GetJobsWithStatus()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(statuses).Should(HaveLen(1))
+				Ω(statuses[0].Paused).Should(BeTrue())
+				Ω(statuses[0].LatestFinishedStatus).Should(Equal(db.StatusFailed))
+			})
+
+			It("leaves out jobs that have been removed from the config", func() {
+				_, err := pipelineDB.GetJob("some-job")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				current, version, err := pipelineDB.GetConfig()
+				Ω(err).ShouldNot(HaveOccurred())
+
+				current.Jobs = atc.JobConfigs{{Name: "some-new-job"}}
 
-					savedResource, err = pipelineDB.GetResource("other-resource")
-					Ω(err).ShouldNot(HaveOccurred())
-					maxID, err = pipelineDB.GetResourceHistoryMaxID(savedResource.ID)
-					Ω(err).ShouldNot(HaveOccurred())
-					Ω(maxID).Should(Equal(15))
-				})
+				_, err = sqlDB.SaveConfig("a-pipeline-name", current, version, db.PipelineNoChange)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				_, err = pipelineDB.GetJob("some-new-job")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				statuses, err := pipelineDB.GetJobsWithStatus()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(statuses).Should(HaveLen(1))
+				Ω(statuses[0].Name).Should(Equal("some-new-job"))
 			})
 		})
 
-		Describe("GetResourceHistoryCursor", func() {
-			BeforeEach(func() {
-				for i := 1; i <= 20; i++ {
-					err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
-						Name:   "some-resource",
-						Type:   "some-type",
-						Source: atc.Source{"some": "source"},
-					}, []atc.Version{{"version": i}})
-					Ω(err).ShouldNot(HaveOccurred())
+		Describe("ReapJobBuilds", func() {
+			var builds []db.Build
+
+			buildNames := func() []string {
+				remaining, err := pipelineDB.GetAllJobBuilds("some-job")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				names := []string{}
+				for _, build := range remaining {
+					names = append(names, build.Name)
 				}
-				for i := 1; i <= 10; i++ {
-					err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
-						Name:   "other-resource",
-						Type:   "some-type",
-						Source: atc.Source{"some": "source"},
-					}, []atc.Version{{"version": i}})
+
+				return names
+			}
+
+			BeforeEach(func() {
+				builds = []db.Build{}
+
+				for i := 0; i < 8; i++ {
+					build, err := pipelineDB.CreateJobBuild("some-job")
 					Ω(err).ShouldNot(HaveOccurred())
+
+					builds = append(builds, build)
 				}
-				for i := 21; i <= 30; i++ {
-					err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
-						Name:   "some-resource",
-						Type:   "some-type",
-						Source: atc.Source{"some": "source"},
-					}, []atc.Version{{"version": i}})
+
+				_, err := pipelineDB.SaveBuildInput(builds[0].ID, db.BuildInput{
+					Name: "some-input",
+					VersionedResource: db.VersionedResource{
+						PipelineName: "a-pipeline-name",
+						Resource:     "some-resource",
+						Type:         "some-type",
+						Version:      db.Version{"ver": "1"},
+					},
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				_, err = pipelineDB.SaveBuildOutput(builds[0].ID, db.VersionedResource{
+					PipelineName: "a-pipeline-name",
+					Resource:     "some-resource",
+					Type:         "some-type",
+					Version:      db.Version{"ver": "2"},
+				}, true)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = sqlDB.SaveBuildEvent(builds[0].ID, event.StartTask{})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				for i, build := range builds {
+					status := db.StatusFailed
+					if i == 1 {
+						status = db.StatusSucceeded
+					}
+
+					err := sqlDB.FinishBuild(build.ID, status)
 					Ω(err).ShouldNot(HaveOccurred())
 				}
+			})
 
-				err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
-					Name:   "another-resource",
-					Type:   "some-type",
-					Source: atc.Source{"some": "source"},
-				}, []atc.Version{})
+			It("deletes the oldest builds beyond the number to keep, retaining the latest success", func() {
+				reaped, err := pipelineDB.ReapJobBuilds("some-job", 5, time.Time{})
 				Ω(err).ShouldNot(HaveOccurred())
+				Ω(reaped).Should(Equal([]int{builds[0].ID, builds[2].ID}))
+
+				Ω(buildNames()).Should(Equal([]string{"8", "7", "6", "5", "4", "2"}))
 			})
 
-			Context("when greaterThanStartingID is false", func() {
-				greaterThanStartingID := false
+			It("deletes the reaped builds' inputs, outputs, and events", func() {
+				_, err := pipelineDB.ReapJobBuilds("some-job", 5, time.Time{})
+				Ω(err).ShouldNot(HaveOccurred())
 
-				Context("when numResults is greater than the number of versions to return", func() {
-					It("should return a slice of the VersionHistorys in descending order from the startingID", func() {
-						versionHistories, hasNext, err := pipelineDB.GetResourceHistoryCursor("some-resource", 35, greaterThanStartingID, 50)
-						Ω(err).ShouldNot(HaveOccurred())
-						Ω(hasNext).Should(BeFalse())
-						numResults := len(versionHistories)
-						Ω(numResults).Should(Equal(25))
-						firstID := versionHistories[0].VersionedResource.ID
-						Ω(firstID).Should(Equal(35))
-						Ω(firstID).Should(BeNumerically(">", versionHistories[numResults-1].VersionedResource.ID))
-					})
-				})
+				_, err = sqlDB.GetBuild(builds[0].ID)
+				Ω(err).Should(Equal(db.ErrNoBuild))
 
-				Context("when numResults is less than the number of versions to return", func() {
-					It("should return a limited slice of the VersionHistories", func() {
-						versionHistories, hasNext, err := pipelineDB.GetResourceHistoryCursor("some-resource", 30, greaterThanStartingID, 17)
-						Ω(err).ShouldNot(HaveOccurred())
-						Ω(len(versionHistories)).Should(Equal(17))
-						Ω(hasNext).Should(BeTrue())
-					})
-				})
+				inputs, outputs, err := pipelineDB.GetBuildResources(builds[0].ID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(inputs).Should(BeEmpty())
+				Ω(outputs).Should(BeEmpty())
 
-				Context("when numResults is 0", func() {
-					It("returns all the rows", func() {
-						versionHistories, hasNext, err := pipelineDB.GetResourceHistoryCursor("some-resource", 100, greaterThanStartingID, 0)
-						Ω(err).ShouldNot(HaveOccurred())
-						Ω(len(versionHistories)).Should(Equal(30))
-						Ω(hasNext).Should(BeFalse())
-					})
-				})
+				var events int
+				err = dbConn.QueryRow(`
+					SELECT COUNT(*)
+					FROM build_events
+					WHERE build_id = $1
+				`, builds[0].ID).Scan(&events)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(events).Should(BeZero())
 			})
 
-			Context("when greaterThanStartingID is true", func() {
-				greaterThanStartingID := true
+			It("does not affect other jobs' builds", func() {
+				otherBuild, err := pipelineDB.CreateJobBuild("some-other-job")
+				Ω(err).ShouldNot(HaveOccurred())
 
-				Context("when numResults is greater than the number of versions to return", func() {
-					It("should return a slice of the VersionHistorys in descending order from that are greater then or equal to the startingID", func() {
-						versionHistories, hasNext, err := pipelineDB.GetResourceHistoryCursor("some-resource", 15, greaterThanStartingID, 7)
-						Ω(err).ShouldNot(HaveOccurred())
-						Ω(hasNext).Should(BeTrue())
+				err = sqlDB.FinishBuild(otherBuild.ID, db.StatusFailed)
+				Ω(err).ShouldNot(HaveOccurred())
 
-						numResults := len(versionHistories)
-						Ω(numResults).Should(Equal(7))
+				_, err = pipelineDB.ReapJobBuilds("some-job", 1, time.Time{})
+				Ω(err).ShouldNot(HaveOccurred())
 
-						lastID := versionHistories[numResults-1].VersionedResource.ID
-						Ω(lastID).Should(Equal(15))
+				_, err = sqlDB.GetBuild(otherBuild.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
 
-						firstID := versionHistories[0].VersionedResource.ID
-						Ω(firstID).Should(Equal(31))
+			Context("when builds are still pending", func() {
+				It("does not delete them", func() {
+					pending, err := pipelineDB.CreateJobBuild("some-job")
+					Ω(err).ShouldNot(HaveOccurred())
 
-						Ω(firstID).Should(BeNumerically(">", lastID))
-					})
+					for i := 0; i < 2; i++ {
+						_, err := pipelineDB.CreateJobBuild("some-job")
+						Ω(err).ShouldNot(HaveOccurred())
+					}
+
+					_, err = pipelineDB.ReapJobBuilds("some-job", 1, time.Time{})
+					Ω(err).ShouldNot(HaveOccurred())
+
+					_, err = sqlDB.GetBuild(pending.ID)
+					Ω(err).ShouldNot(HaveOccurred())
 				})
 
-				Context("when numResults is 0", func() {
-					It("returns all the rows", func() {
-						versionHistories, hasNext, err := pipelineDB.GetResourceHistoryCursor("some-resource", 0, greaterThanStartingID, 0)
+				It("does not count them among the builds to keep", func() {
+					for i := 0; i < 2; i++ {
+						_, err := pipelineDB.CreateJobBuild("some-job")
 						Ω(err).ShouldNot(HaveOccurred())
-						Ω(len(versionHistories)).Should(Equal(30))
-						Ω(hasNext).Should(BeFalse())
-					})
+					}
+
+					reaped, err := pipelineDB.ReapJobBuilds("some-job", 5, time.Time{})
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(reaped).Should(HaveLen(2))
+
+					Ω(buildNames()).Should(Equal([]string{"10", "9", "8", "7", "6", "5", "4", "2"}))
 				})
 			})
-		})
-	})
 
-	Context("Jobs", func() {
-		Describe("CreateJobBuild", func() {
-			var build db.Build
+			Context("when only builds finished before a time are to be deleted", func() {
+				It("keeps builds that finished after it", func() {
+					reaped, err := pipelineDB.ReapJobBuilds("some-job", 5, time.Now().Add(-time.Hour))
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(reaped).Should(BeEmpty())
 
-			BeforeEach(func() {
-				var err error
-				build, err = pipelineDB.CreateJobBuild("some-job")
-				Ω(err).ShouldNot(HaveOccurred())
+					reaped, err = pipelineDB.ReapJobBuilds("some-job", 0, time.Now().Add(time.Hour))
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(reaped).Should(HaveLen(7))
+
+					Ω(buildNames()).Should(Equal([]string{"2"}))
+				})
 			})
 
-			It("sets the properties of a build for a given job", func() {
-				Ω(build.ID).ShouldNot(BeZero())
-				Ω(build.JobID).ShouldNot(BeZero())
-				Ω(build.Name).Should(Equal("1"))
-				Ω(build.Status).Should(Equal(db.StatusPending))
-				Ω(build.Scheduled).Should(BeFalse())
+			Context("when no retention is given", func() {
+				It("deletes nothing", func() {
+					reaped, err := pipelineDB.ReapJobBuilds("some-job", 0, time.Time{})
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(reaped).Should(BeEmpty())
+
+					Ω(buildNames()).Should(HaveLen(8))
+				})
 			})
 		})
 
@@ -1021,6 +2130,21 @@ var _ = Describe("PipelineDB", func() {
 				Ω(created).Should(BeTrue())
 			})
 
+			It("moves the build from not yet determined to determined", func() {
+				build, created, err := pipelineDB.CreateJobBuildForCandidateInputs("some-job")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(created).Should(BeTrue())
+				Ω(build.InputsDetermined).Should(BeFalse())
+
+				err = pipelineDB.UseInputsForBuild(build.ID, inputs)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				determinedBuild, err := sqlDB.GetBuild(build.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(determinedBuild.InputsDetermined).Should(BeTrue())
+				Ω(determinedBuild.Status).Should(Equal(db.StatusPending))
+			})
+
 			It("saves all the build inputs", func() {
 				build, created, err := pipelineDB.CreateJobBuildForCandidateInputs("some-job")
 				Ω(err).ShouldNot(HaveOccurred())
@@ -1034,7 +2158,8 @@ var _ = Describe("PipelineDB", func() {
 					input2,
 				})
 				Ω(err).ShouldNot(HaveOccurred())
-				Ω(foundBuild).Should(Equal(build))
+				Ω(foundBuild.ID).Should(Equal(build.ID))
+				Ω(foundBuild.InputsDetermined).Should(BeTrue())
 			})
 		})
 
@@ -1217,6 +2342,117 @@ var _ = Describe("PipelineDB", func() {
 			})
 		})
 
+		Describe("GetUnavailableBuildInputs", func() {
+			It("returns the inputs whose versions have been disabled", func() {
+				build, err := pipelineDB.CreateJobBuild("some-job")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				savedVR, err := pipelineDB.SaveBuildInput(build.ID, db.BuildInput{
+					Name: "some-input",
+					VersionedResource: db.VersionedResource{
+						PipelineName: "a-pipeline-name",
+						Resource:     "some-resource",
+						Type:         "some-type",
+						Version:      db.Version{"ver": "1"},
+					},
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				_, err = pipelineDB.SaveBuildInput(build.ID, db.BuildInput{
+					Name: "some-other-input",
+					VersionedResource: db.VersionedResource{
+						PipelineName: "a-pipeline-name",
+						Resource:     "some-other-resource",
+						Type:         "some-type",
+						Version:      db.Version{"ver": "2"},
+					},
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				unavailable, err := pipelineDB.GetUnavailableBuildInputs(build.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(unavailable).Should(BeEmpty())
+
+				err = pipelineDB.DisableVersionedResource(savedVR.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				unavailable, err = pipelineDB.GetUnavailableBuildInputs(build.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(unavailable).Should(Equal([]string{"some-input"}))
+			})
+		})
+
+		Describe("CreateJobBuildWithPinnedInputs", func() {
+			pinnedVR := db.VersionedResource{
+				PipelineName: "a-pipeline-name",
+				Resource:     "some-resource",
+				Type:         "some-type",
+				Source:       db.Source{"some": "source"},
+				Version:      db.Version{"ver": "1"},
+				Metadata:     []db.MetadataField{},
+			}
+
+			It("creates a pending build whose inputs are not yet determined", func() {
+				build, err := pipelineDB.CreateJobBuildWithPinnedInputs("some-job", []db.BuildInput{
+					{Name: "some-input", VersionedResource: pinnedVR},
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(build.Status).Should(Equal(db.StatusPending))
+				Ω(build.InputsDetermined).Should(BeFalse())
+
+				pending, err := pipelineDB.GetNextPendingBuild("some-job")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(pending.ID).Should(Equal(build.ID))
+			})
+
+			It("saves the pinned inputs with the build", func() {
+				build, err := pipelineDB.CreateJobBuildWithPinnedInputs("some-job", []db.BuildInput{
+					{Name: "some-input", VersionedResource: pinnedVR},
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				pinned, err := pipelineDB.GetPinnedBuildInputs(build.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(pinned).Should(Equal([]db.BuildInput{
+					{Name: "some-input", VersionedResource: pinnedVR},
+				}))
+			})
+
+			It("keeps them pinned once the build's inputs are determined", func() {
+				build, err := pipelineDB.CreateJobBuildWithPinnedInputs("some-job", []db.BuildInput{
+					{Name: "some-input", VersionedResource: pinnedVR},
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				otherVR := pinnedVR
+				otherVR.Resource = "some-other-resource"
+
+				err = pipelineDB.UseInputsForBuild(build.ID, []db.BuildInput{
+					{Name: "some-input", VersionedResource: pinnedVR},
+					{Name: "some-other-input", VersionedResource: otherVR},
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				inputs, _, err := pipelineDB.GetBuildResources(build.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(inputs).Should(HaveLen(2))
+
+				pinned, err := pipelineDB.GetPinnedBuildInputs(build.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(pinned).Should(HaveLen(1))
+				Ω(pinned[0].Name).Should(Equal("some-input"))
+			})
+
+			It("pins nothing for builds created without pinned inputs", func() {
+				build, err := pipelineDB.CreateJobBuild("some-job")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				pinned, err := pipelineDB.GetPinnedBuildInputs(build.ID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(pinned).Should(BeEmpty())
+			})
+		})
+
 		Describe("saving inputs, implicit outputs, and explicit outputs", func() {
 			vr1 := db.VersionedResource{
 				PipelineName: "a-pipeline-name",
@@ -2520,5 +3756,52 @@ var _ = Describe("PipelineDB", func() {
 			Ω(next.ID).Should(Equal(anotherRunningBuild.ID))
 			Ω(finished.ID).Should(Equal(nextBuild.ID))
 		})
+
+		Describe("GetLatestFinishedBuild", func() {
+			Context("when the job only has a running build", func() {
+				BeforeEach(func() {
+					runningBuild, err := pipelineDB.CreateJobBuild("some-job")
+					Ω(err).ShouldNot(HaveOccurred())
+
+					started, err := sqlDB.StartBuild(runningBuild.ID, "some-engine", "meta")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(started).Should(BeTrue())
+				})
+
+				It("does not find a build", func() {
+					_, found, err := pipelineDB.GetLatestFinishedBuild("some-job")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(found).Should(BeFalse())
+				})
+			})
+
+			Context("when the job has a finished build followed by a running one", func() {
+				var finishedBuild db.Build
+
+				BeforeEach(func() {
+					var err error
+					finishedBuild, err = pipelineDB.CreateJobBuild("some-job")
+					Ω(err).ShouldNot(HaveOccurred())
+
+					err = sqlDB.FinishBuild(finishedBuild.ID, db.StatusFailed)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					runningBuild, err := pipelineDB.CreateJobBuild("some-job")
+					Ω(err).ShouldNot(HaveOccurred())
+
+					started, err := sqlDB.StartBuild(runningBuild.ID, "some-engine", "meta")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(started).Should(BeTrue())
+				})
+
+				It("returns the finished build", func() {
+					build, found, err := pipelineDB.GetLatestFinishedBuild("some-job")
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(found).Should(BeTrue())
+					Ω(build.ID).Should(Equal(finishedBuild.ID))
+					Ω(build.Status).Should(Equal(db.StatusFailed))
+				})
+			})
+		})
 	})
 })
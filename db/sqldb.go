@@ -21,8 +21,8 @@ type SQLDB struct {
 	bus  *notificationsBus
 }
 
-const buildColumns = "id, name, job_id, status, scheduled, engine, engine_metadata, start_time, end_time"
-const qualifiedBuildColumns = "b.id, b.name, b.job_id, b.status, b.scheduled, b.engine, b.engine_metadata, b.start_time, b.end_time, j.name as job_name, p.name as pipeline_name"
+const buildColumns = "id, name, job_id, status, scheduled, engine, engine_metadata, start_time, end_time, error_category, inputs_determined, attempt"
+const qualifiedBuildColumns = "b.id, b.name, b.job_id, b.status, b.scheduled, b.engine, b.engine_metadata, b.start_time, b.end_time, b.error_category, b.inputs_determined, b.attempt, j.name as job_name, p.name as pipeline_name"
 
 func NewSQL(
 	logger lager.Logger,
@@ -516,6 +516,28 @@ func (db *SQLDB) FinishBuild(buildID int, status Status) error {
 	return nil
 }
 
+func (db *SQLDB) SaveBuildErrorCategory(buildID int, category string) error {
+	result, err := db.conn.Exec(`
+		UPDATE builds
+		SET error_category = $2
+		WHERE id = $1
+	`, buildID, category)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected != 1 {
+		return nonOneRowAffectedError{rowsAffected}
+	}
+
+	return nil
+}
+
 func (db *SQLDB) ErrorBuild(buildID int, cause error) error {
 	err := db.SaveBuildEvent(buildID, event.Error{
 		Message: cause.Error(),
@@ -954,12 +976,13 @@ func scanBuild(row scannable) (Build, error) {
 	var name string
 	var jobID sql.NullInt64
 	var status string
-	var scheduled bool
-	var engine, engineMetadata, jobName, pipelineName sql.NullString
+	var scheduled, inputsDetermined bool
+	var attempt int
+	var engine, engineMetadata, errorCategory, jobName, pipelineName sql.NullString
 	var startTime pq.NullTime
 	var endTime pq.NullTime
 
-	err := row.Scan(&id, &name, &jobID, &status, &scheduled, &engine, &engineMetadata, &startTime, &endTime, &jobName, &pipelineName)
+	err := row.Scan(&id, &name, &jobID, &status, &scheduled, &engine, &engineMetadata, &startTime, &endTime, &errorCategory, &inputsDetermined, &attempt, &jobName, &pipelineName)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return Build{}, ErrNoBuild
@@ -979,6 +1002,11 @@ func scanBuild(row scannable) (Build, error) {
 
 		StartTime: startTime.Time,
 		EndTime:   endTime.Time,
+
+		ErrorCategory: errorCategory.String,
+
+		InputsDetermined: inputsDetermined,
+		Attempt:          attempt,
 	}
 
 	if jobID.Valid {
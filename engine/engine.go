@@ -25,6 +25,7 @@ type EngineDB interface {
 	SaveBuildEvent(buildID int, event atc.Event) error
 
 	FinishBuild(buildID int, status db.Status) error
+	SaveBuildErrorCategory(buildID int, category string) error
 
 	SaveBuildEngineMetadata(buildID int, metadata string) error
 
@@ -12,6 +12,7 @@ import (
 	"github.com/concourse/atc/event"
 	"github.com/concourse/atc/exec"
 	"github.com/concourse/atc/worker"
+	"github.com/pivotal-golang/clock"
 	"github.com/pivotal-golang/lager"
 	"github.com/tedsuo/ifrit"
 )
@@ -24,13 +25,15 @@ type execEngine struct {
 	factory         exec.Factory
 	delegateFactory BuildDelegateFactory
 	db              EngineDB
+	clock           clock.Clock
 }
 
-func NewExecEngine(factory exec.Factory, delegateFactory BuildDelegateFactory, db EngineDB) Engine {
+func NewExecEngine(factory exec.Factory, delegateFactory BuildDelegateFactory, db EngineDB, clock clock.Clock) Engine {
 	return &execEngine{
 		factory:         factory,
 		delegateFactory: delegateFactory,
 		db:              db,
+		clock:           clock,
 	}
 }
 
@@ -43,6 +46,7 @@ func (engine *execEngine) CreateBuild(model db.Build, plan atc.Plan) (Build, err
 		buildID:  model.ID,
 		db:       engine.db,
 		factory:  engine.factory,
+		clock:    engine.clock,
 		delegate: engine.delegateFactory.Delegate(model.ID),
 		metadata: execMetadata{
 			Plan: plan,
@@ -63,6 +67,7 @@ func (engine *execEngine) LookupBuild(model db.Build) (Build, error) {
 		buildID:  model.ID,
 		db:       engine.db,
 		factory:  engine.factory,
+		clock:    engine.clock,
 		delegate: engine.delegateFactory.Delegate(model.ID),
 		metadata: metadata,
 
@@ -75,6 +80,7 @@ type execBuild struct {
 	db      EngineDB
 
 	factory  exec.Factory
+	clock    clock.Clock
 	delegate BuildDelegate
 
 	signals chan os.Signal
@@ -97,10 +103,13 @@ func (build *execBuild) Abort() error {
 }
 
 func (build *execBuild) Resume(logger lager.Logger) {
+	build.delegate.Start()
+
 	stepFactory := build.buildStepFactory(logger, build.metadata.Plan)
-	source := stepFactory.Using(&exec.NoopStep{}, exec.NewSourceRepository())
+	repo := exec.NewSourceRepository()
+	source := stepFactory.Using(&exec.NoopStep{}, repo)
 
-	defer source.Release()
+	defer build.release(logger.Session("release"), source, repo)
 
 	process := ifrit.Background(source)
 
@@ -134,6 +143,22 @@ func (build *execBuild) Resume(logger lager.Logger) {
 	}
 }
 
+// release cleans up after the build once it has finished. The sources in the
+// repository are released first; releasing the root step then takes care of
+// the steps that never registered a source, i.e. ones that failed. Steps are
+// backed by containers that only release once, so the overlap is harmless.
+func (build *execBuild) release(logger lager.Logger, source exec.Step, repo *exec.SourceRepository) {
+	err := repo.Cleanup()
+	if err != nil {
+		logger.Error("failed-to-release-sources", err)
+	}
+
+	err = source.Release()
+	if err != nil {
+		logger.Error("failed-to-release-steps", err)
+	}
+}
+
 func (build *execBuild) buildStepFactory(logger lager.Logger, plan atc.Plan) exec.StepFactory {
 	if plan.Aggregate != nil {
 
@@ -152,7 +177,7 @@ func (build *execBuild) buildStepFactory(logger lager.Logger, plan atc.Plan) exe
 
 	if plan.Timeout != nil {
 		step := build.buildStepFactory(logger, plan.Timeout.Step)
-		return exec.Timeout(step, plan.Timeout.Duration)
+		return exec.Timeout(step, plan.Timeout.Duration, build.clock)
 	}
 
 	if plan.Try != nil {
@@ -202,12 +227,12 @@ func (build *execBuild) buildStepFactory(logger lager.Logger, plan atc.Plan) exe
 
 		var configSource exec.TaskConfigSource
 		if plan.Task.Config != nil && plan.Task.ConfigPath != "" {
-			configSource = exec.MergedConfigSource{
+			configSource = exec.ValidatingConfigSource{exec.MergedConfigSource{
 				A: exec.FileConfigSource{plan.Task.ConfigPath},
 				B: exec.StaticConfigSource{*plan.Task.Config},
-			}
+			}}
 		} else if plan.Task.Config != nil {
-			configSource = exec.StaticConfigSource{*plan.Task.Config}
+			configSource = exec.ValidatingConfigSource{exec.StaticConfigSource{*plan.Task.Config}}
 		} else if plan.Task.ConfigPath != "" {
 			configSource = exec.FileConfigSource{plan.Task.ConfigPath}
 		} else {
@@ -231,7 +256,8 @@ func (build *execBuild) buildStepFactory(logger lager.Logger, plan atc.Plan) exe
 
 	if plan.Get != nil {
 		logger = logger.Session("get", lager.Data{
-			"name": plan.Get.Name,
+			"name":   plan.Get.Name,
+			"source": plan.Get.Source.Redacted(),
 		})
 
 		var location event.OriginLocation
@@ -247,6 +273,7 @@ func (build *execBuild) buildStepFactory(logger lager.Logger, plan atc.Plan) exe
 				Name:   plan.Get.Resource,
 				Type:   plan.Get.Type,
 				Source: plan.Get.Source,
+				Proxy:  plan.Get.Proxy,
 			},
 			plan.Get.Params,
 			plan.Get.Tags,
@@ -256,7 +283,8 @@ func (build *execBuild) buildStepFactory(logger lager.Logger, plan atc.Plan) exe
 
 	if plan.Put != nil {
 		logger = logger.Session("put", lager.Data{
-			"name": plan.Put.Name,
+			"name":   plan.Put.Name,
+			"source": plan.Put.Source.Redacted(),
 		})
 
 		var location event.OriginLocation
@@ -271,6 +299,7 @@ func (build *execBuild) buildStepFactory(logger lager.Logger, plan atc.Plan) exe
 				Name:   plan.Put.Resource,
 				Type:   plan.Put.Type,
 				Source: plan.Put.Source,
+				Proxy:  plan.Put.Proxy,
 			},
 			plan.Put.Tags,
 			plan.Put.Params,
@@ -279,7 +308,8 @@ func (build *execBuild) buildStepFactory(logger lager.Logger, plan atc.Plan) exe
 
 	if plan.DependentGet != nil {
 		logger = logger.Session("get", lager.Data{
-			"name": plan.DependentGet.Name,
+			"name":   plan.DependentGet.Name,
+			"source": plan.DependentGet.Source.Redacted(),
 		})
 
 		var location event.OriginLocation
@@ -296,6 +326,7 @@ func (build *execBuild) buildStepFactory(logger lager.Logger, plan atc.Plan) exe
 				Name:   getPlan.Resource,
 				Type:   getPlan.Type,
 				Source: getPlan.Source,
+				Proxy:  getPlan.Proxy,
 			},
 			getPlan.Tags,
 			getPlan.Params,
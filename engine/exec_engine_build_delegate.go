@@ -1,7 +1,10 @@
 package engine
 
 import (
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -26,6 +29,9 @@ type BuildDelegate interface {
 	ExecutionDelegate(lager.Logger, atc.TaskPlan, event.OriginLocation) exec.TaskDelegate
 	OutputDelegate(lager.Logger, atc.PutPlan, event.OriginLocation) exec.PutDelegate
 
+	// Start marks the build as running on this ATC until it finishes, so
+	// that it is drained if the ATC shuts down first.
+	Start()
 	Finish(lager.Logger, error, exec.Success, bool)
 }
 
@@ -33,18 +39,77 @@ type BuildDelegate interface {
 
 type BuildDelegateFactory interface {
 	Delegate(buildID int) BuildDelegate
+
+	// Drain flushes any output buffered by the delegates of builds that have
+	// started but not yet finished, and marks each of them as interrupted by
+	// the ATC shutting down.
+	Drain(lager.Logger)
 }
 
+// DrainMessage is saved as a drain event for each running build when the
+// ATC drains, explaining the gap in its output until it is resumed.
+const DrainMessage = "ATC shutting down; output may be missing until the build resumes"
+
 type buildDelegateFactory struct {
 	db EngineDB
+
+	buildLogsDir string
+
+	running  map[*delegate]struct{}
+	runningL sync.Mutex
 }
 
-func NewBuildDelegateFactory(db EngineDB) BuildDelegateFactory {
-	return buildDelegateFactory{db}
+// NewBuildDelegateFactory returns a factory for delegates saving their
+// builds' events to the database. If buildLogsDir is set, each build's
+// output is also appended to a file named after the build's ID in it.
+func NewBuildDelegateFactory(db EngineDB, buildLogsDir string) BuildDelegateFactory {
+	return &buildDelegateFactory{
+		db:           db,
+		buildLogsDir: buildLogsDir,
+		running:      map[*delegate]struct{}{},
+	}
 }
 
-func (factory buildDelegateFactory) Delegate(buildID int) BuildDelegate {
-	return newBuildDelegate(factory.db, buildID)
+// BuildLogPath returns the path of the file in buildLogsDir that the build's
+// output is written to.
+func BuildLogPath(buildLogsDir string, buildID int) string {
+	return filepath.Join(buildLogsDir, fmt.Sprintf("%d.log", buildID))
+}
+
+func (factory *buildDelegateFactory) Delegate(buildID int) BuildDelegate {
+	delegate := newBuildDelegate(factory.db, buildID)
+	delegate.buildLogsDir = factory.buildLogsDir
+
+	// delegates are also created to abort builds, which may be running
+	// elsewhere or not at all; only track the ones actually run here
+	delegate.started = func() {
+		factory.runningL.Lock()
+		factory.running[delegate] = struct{}{}
+		factory.runningL.Unlock()
+	}
+
+	delegate.finished = func() {
+		factory.runningL.Lock()
+		delete(factory.running, delegate)
+		factory.runningL.Unlock()
+	}
+
+	return delegate
+}
+
+func (factory *buildDelegateFactory) Drain(logger lager.Logger) {
+	factory.runningL.Lock()
+
+	running := make([]*delegate, 0, len(factory.running))
+	for delegate := range factory.running {
+		running = append(running, delegate)
+	}
+
+	factory.runningL.Unlock()
+
+	for _, delegate := range running {
+		delegate.drain(logger.Session("drain", lager.Data{"build": delegate.buildID}))
+	}
 }
 
 type delegate struct {
@@ -54,16 +119,30 @@ type delegate struct {
 
 	implicitOutputs map[string]implicitOutput
 
+	// category of the first error reported by a step
+	errorCategory exec.StepErrorCategory
+
+	eventWriters []*dbEventWriter
+
+	buildLogsDir string
+	buildLog     *os.File
+
+	started  func()
+	finished func()
+
 	lock sync.Mutex
 }
 
-func newBuildDelegate(db EngineDB, buildID int) BuildDelegate {
+func newBuildDelegate(db EngineDB, buildID int) *delegate {
 	return &delegate{
 		db: db,
 
 		buildID: buildID,
 
 		implicitOutputs: make(map[string]implicitOutput),
+
+		started:  func() {},
+		finished: func() {},
 	}
 }
 
@@ -94,12 +173,17 @@ func (delegate *delegate) ExecutionDelegate(logger lager.Logger, plan atc.TaskPl
 	}
 }
 
+func (delegate *delegate) Start() {
+	delegate.started()
+}
+
 func (delegate *delegate) Finish(logger lager.Logger, err error, succeeded exec.Success, aborted bool) {
 	if aborted {
 		delegate.saveStatus(logger, atc.StatusAborted)
 
 		logger.Info("aborted")
 	} else if err != nil && !strings.Contains(err.Error(), exec.ErrStepTimedOut.Error()) {
+		delegate.saveErrorCategory(logger, delegate.categorize(err))
 		delegate.saveStatus(logger, atc.StatusErrored)
 
 		logger.Error("errored", err)
@@ -122,6 +206,27 @@ func (delegate *delegate) Finish(logger lager.Logger, err error, succeeded exec.
 			logger.Info("failed")
 		}
 	}
+
+	delegate.closeBuildLog(logger)
+
+	delegate.finished()
+}
+
+func (delegate *delegate) drain(logger lager.Logger) {
+	delegate.lock.Lock()
+	writers := delegate.eventWriters
+	delegate.lock.Unlock()
+
+	for _, writer := range writers {
+		writer.flush()
+	}
+
+	err := delegate.db.SaveBuildEvent(delegate.buildID, event.Drain{
+		Message: DrainMessage,
+	})
+	if err != nil {
+		logger.Error("failed-to-save-drain-event", err)
+	}
 }
 
 func (delegate *delegate) registerImplicitOutput(resource string, output implicitOutput) {
@@ -136,6 +241,27 @@ func (delegate *delegate) unregisterImplicitOutput(resource string) {
 	delegate.lock.Unlock()
 }
 
+// categorize prefers the category of the first step error, as the error the
+// build finishes with may only be a summary of it (i.e. from an aggregate).
+func (delegate *delegate) categorize(err error) exec.StepErrorCategory {
+	delegate.lock.Lock()
+	defer delegate.lock.Unlock()
+
+	if delegate.errorCategory != "" {
+		return delegate.errorCategory
+	}
+
+	return exec.CategorizeError(err)
+}
+
+func (delegate *delegate) recordErrorCategory(category exec.StepErrorCategory) {
+	delegate.lock.Lock()
+	if delegate.errorCategory == "" {
+		delegate.errorCategory = category
+	}
+	delegate.lock.Unlock()
+}
+
 func (delegate *delegate) saveInitialize(logger lager.Logger, taskConfig atc.TaskConfig, origin event.Origin) {
 	err := delegate.db.SaveBuildEvent(delegate.buildID, event.InitializeTask{
 		TaskConfig: event.ShadowTaskConfig(taskConfig),
@@ -174,10 +300,21 @@ func (delegate *delegate) saveStatus(logger lager.Logger, status atc.BuildStatus
 	}
 }
 
+func (delegate *delegate) saveErrorCategory(logger lager.Logger, category exec.StepErrorCategory) {
+	err := delegate.db.SaveBuildErrorCategory(delegate.buildID, string(category))
+	if err != nil {
+		logger.Error("failed-to-save-error-category", err)
+	}
+}
+
 func (delegate *delegate) saveErr(logger lager.Logger, errVal error, origin event.Origin) {
+	category := exec.CategorizeError(errVal)
+	delegate.recordErrorCategory(category)
+
 	err := delegate.db.SaveBuildEvent(delegate.buildID, event.Error{
-		Message: errVal.Error(),
-		Origin:  origin,
+		Message:  errVal.Error(),
+		Category: string(category),
+		Origin:   origin,
 	})
 	if err != nil {
 		logger.Error("failed-to-save-error-event", err)
@@ -281,12 +418,65 @@ func (delegate *delegate) saveImplicitOutput(logger lager.Logger, plan atc.GetPl
 	logger.Info("saved", lager.Data{"resource": plan.Resource})
 }
 
-func (delegate *delegate) eventWriter(origin event.Origin) io.Writer {
-	return &dbEventWriter{
+func (delegate *delegate) eventWriter(logger lager.Logger, origin event.Origin) io.Writer {
+	writer := &dbEventWriter{
+		logger:  logger,
 		db:      delegate.db,
 		buildID: delegate.buildID,
 		origin:  origin,
 	}
+
+	delegate.lock.Lock()
+	defer delegate.lock.Unlock()
+
+	delegate.eventWriters = append(delegate.eventWriters, writer)
+
+	if delegate.buildLogsDir == "" {
+		return writer
+	}
+
+	if delegate.buildLog == nil {
+		buildLog, err := os.OpenFile(
+			BuildLogPath(delegate.buildLogsDir, delegate.buildID),
+			os.O_WRONLY|os.O_APPEND|os.O_CREATE,
+			0644,
+		)
+		if err != nil {
+			// the events are what matter; carry on without the file
+			return writer
+		}
+
+		delegate.buildLog = buildLog
+	}
+
+	return io.MultiWriter(writer, buildLogWriter{delegate.buildLog})
+}
+
+func (delegate *delegate) closeBuildLog(logger lager.Logger) {
+	delegate.lock.Lock()
+	defer delegate.lock.Unlock()
+
+	if delegate.buildLog == nil {
+		return
+	}
+
+	err := delegate.buildLog.Close()
+	if err != nil {
+		logger.Error("failed-to-close-build-log", err)
+	}
+
+	delegate.buildLog = nil
+}
+
+// buildLogWriter appends to a build's log file, ignoring failures so that
+// they never interrupt the build's output.
+type buildLogWriter struct {
+	file *os.File
+}
+
+func (writer buildLogWriter) Write(data []byte) (int, error) {
+	writer.file.Write(data)
+	return len(data), nil
 }
 
 type inputDelegate struct {
@@ -322,7 +512,7 @@ func (input *inputDelegate) Failed(err error) {
 }
 
 func (input *inputDelegate) Stdout() io.Writer {
-	return input.delegate.eventWriter(event.Origin{
+	return input.delegate.eventWriter(input.logger, event.Origin{
 		Type:     event.OriginTypeGet,
 		Name:     input.plan.Name,
 		Source:   event.OriginSourceStdout,
@@ -331,7 +521,7 @@ func (input *inputDelegate) Stdout() io.Writer {
 }
 
 func (input *inputDelegate) Stderr() io.Writer {
-	return input.delegate.eventWriter(event.Origin{
+	return input.delegate.eventWriter(input.logger, event.Origin{
 		Type:     event.OriginTypeGet,
 		Name:     input.plan.Name,
 		Source:   event.OriginSourceStderr,
@@ -371,7 +561,7 @@ func (output *outputDelegate) Failed(err error) {
 }
 
 func (output *outputDelegate) Stdout() io.Writer {
-	return output.delegate.eventWriter(event.Origin{
+	return output.delegate.eventWriter(output.logger, event.Origin{
 		Type:     event.OriginTypePut,
 		Name:     output.plan.Name,
 		Source:   event.OriginSourceStdout,
@@ -380,7 +570,7 @@ func (output *outputDelegate) Stdout() io.Writer {
 }
 
 func (output *outputDelegate) Stderr() io.Writer {
-	return output.delegate.eventWriter(event.Origin{
+	return output.delegate.eventWriter(output.logger, event.Origin{
 		Type:     event.OriginTypePut,
 		Name:     output.plan.Name,
 		Source:   event.OriginSourceStderr,
@@ -435,7 +625,7 @@ func (execution *executionDelegate) Failed(err error) {
 }
 
 func (execution *executionDelegate) Stdout() io.Writer {
-	return execution.delegate.eventWriter(event.Origin{
+	return execution.delegate.eventWriter(execution.logger, event.Origin{
 		Type:     event.OriginTypeTask,
 		Name:     execution.plan.Name,
 		Source:   event.OriginSourceStdout,
@@ -444,7 +634,7 @@ func (execution *executionDelegate) Stdout() io.Writer {
 }
 
 func (execution *executionDelegate) Stderr() io.Writer {
-	return execution.delegate.eventWriter(event.Origin{
+	return execution.delegate.eventWriter(execution.logger, event.Origin{
 		Type:     event.OriginTypeTask,
 		Name:     execution.plan.Name,
 		Source:   event.OriginSourceStderr,
@@ -453,15 +643,21 @@ func (execution *executionDelegate) Stderr() io.Writer {
 }
 
 type dbEventWriter struct {
+	logger lager.Logger
+
 	buildID int
 	db      EngineDB
 
 	origin event.Origin
 
 	dangling []byte
+	lock     sync.Mutex
 }
 
 func (writer *dbEventWriter) Write(data []byte) (int, error) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
 	text := append(writer.dangling, data...)
 
 	checkEncoding, _ := utf8.DecodeLastRune(text)
@@ -472,14 +668,38 @@ func (writer *dbEventWriter) Write(data []byte) (int, error) {
 
 	writer.dangling = nil
 
-	writer.db.SaveBuildEvent(writer.buildID, event.Log{
+	err := writer.db.SaveBuildEvent(writer.buildID, event.Log{
 		Payload: string(text),
 		Origin:  writer.origin,
 	})
+	if err != nil {
+		writer.logger.Error("failed-to-save-output", err)
+	}
 
 	return len(data), nil
 }
 
+// flush saves any bytes held back waiting for the rest of a multi-byte
+// character, which may never come if the build is going away.
+func (writer *dbEventWriter) flush() {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if len(writer.dangling) == 0 {
+		return
+	}
+
+	err := writer.db.SaveBuildEvent(writer.buildID, event.Log{
+		Payload: string(writer.dangling),
+		Origin:  writer.origin,
+	})
+	if err != nil {
+		writer.logger.Error("failed-to-save-output", err)
+	}
+
+	writer.dangling = nil
+}
+
 func vrFromInput(pipelineName string, got event.FinishGet) db.VersionedResource {
 	metadata := make([]db.MetadataField, len(got.FetchedMetadata))
 	for i, md := range got.FetchedMetadata {
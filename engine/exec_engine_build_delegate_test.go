@@ -3,6 +3,9 @@ package engine_test
 import (
 	"errors"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/concourse/atc"
@@ -33,7 +36,7 @@ var _ = Describe("BuildDelegate", func() {
 
 	BeforeEach(func() {
 		fakeDB = new(fakes.FakeEngineDB)
-		factory = NewBuildDelegateFactory(fakeDB)
+		factory = NewBuildDelegateFactory(fakeDB, "")
 
 		buildID = 42
 		delegate = factory.Delegate(buildID)
@@ -379,7 +382,8 @@ var _ = Describe("BuildDelegate", func() {
 						Name:     "some-input",
 						Location: location,
 					},
-					Message: "nope",
+					Message:  "nope",
+					Category: "system",
 				}))
 			})
 		})
@@ -409,6 +413,19 @@ var _ = Describe("BuildDelegate", func() {
 					Payload: "some stdout",
 				}))
 			})
+
+			Context("when saving the event fails", func() {
+				BeforeEach(func() {
+					fakeDB.SaveBuildEventReturns(errors.New("nope"))
+				})
+
+				It("logs the failure without interrupting the output", func() {
+					_, err := writer.Write([]byte("some stdout"))
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(logger.LogMessages()).Should(ContainElement("test.failed-to-save-output"))
+				})
+			})
 		})
 
 		Describe("Stderr", func() {
@@ -579,6 +596,31 @@ var _ = Describe("BuildDelegate", func() {
 							Ω(buildID).Should(Equal(42))
 							Ω(savedStatus).Should(Equal(db.StatusErrored))
 						})
+
+						It("saves the category of the error", func() {
+							delegate.Finish(logger, finishErr, succeeded, aborted)
+
+							Ω(fakeDB.SaveBuildErrorCategoryCallCount()).Should(Equal(1))
+
+							buildID, category := fakeDB.SaveBuildErrorCategoryArgsForCall(0)
+							Ω(buildID).Should(Equal(42))
+							Ω(category).Should(Equal("system"))
+						})
+
+						Context("when a step failed with a user error", func() {
+							BeforeEach(func() {
+								executionDelegate.Failed(exec.MissingInputsError{Inputs: []string{"some-input"}})
+							})
+
+							It("saves the category of the step's error", func() {
+								delegate.Finish(logger, finishErr, succeeded, aborted)
+
+								Ω(fakeDB.SaveBuildErrorCategoryCallCount()).Should(Equal(1))
+
+								_, category := fakeDB.SaveBuildErrorCategoryArgsForCall(0)
+								Ω(category).Should(Equal("user"))
+							})
+						})
 					})
 				})
 			})
@@ -620,7 +662,8 @@ var _ = Describe("BuildDelegate", func() {
 				buildID, savedEvent := fakeDB.SaveBuildEventArgsForCall(0)
 				Ω(buildID).Should(Equal(42))
 				Ω(savedEvent).Should(Equal(event.Error{
-					Message: "nope",
+					Message:  "nope",
+					Category: "system",
 					Origin: event.Origin{
 						Type:     event.OriginTypeTask,
 						Name:     "some-task",
@@ -902,7 +945,8 @@ var _ = Describe("BuildDelegate", func() {
 						Name:     "some-output-name",
 						Location: location,
 					},
-					Message: "nope",
+					Message:  "nope",
+					Category: "system",
 				}))
 			})
 		})
@@ -1040,4 +1084,146 @@ var _ = Describe("BuildDelegate", func() {
 			})
 		})
 	})
+
+	Describe("draining", func() {
+		var taskPlan atc.TaskPlan
+
+		BeforeEach(func() {
+			taskPlan = atc.TaskPlan{Name: "some-task"}
+
+			delegate.Start()
+		})
+
+		Context("with output held back waiting for the rest of a character", func() {
+			BeforeEach(func() {
+				stdout := delegate.ExecutionDelegate(logger, taskPlan, location).Stdout()
+
+				_, err := stdout.Write([]byte("\xe2\x98"))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(fakeDB.SaveBuildEventCallCount()).Should(BeZero())
+			})
+
+			It("saves the held back output, then marks the build as interrupted by the drain", func() {
+				factory.Drain(logger)
+
+				Ω(fakeDB.SaveBuildEventCallCount()).Should(Equal(2))
+
+				savedBuildID, savedEvent := fakeDB.SaveBuildEventArgsForCall(0)
+				Ω(savedBuildID).Should(Equal(buildID))
+				Ω(savedEvent).Should(Equal(event.Log{
+					Origin: event.Origin{
+						Type:     event.OriginTypeTask,
+						Name:     "some-task",
+						Source:   event.OriginSourceStdout,
+						Location: location,
+					},
+					Payload: "\xe2\x98",
+				}))
+
+				savedBuildID, savedEvent = fakeDB.SaveBuildEventArgsForCall(1)
+				Ω(savedBuildID).Should(Equal(buildID))
+				Ω(savedEvent).Should(Equal(event.Drain{
+					Message: DrainMessage,
+				}))
+			})
+		})
+
+		Context("with a build that has already finished", func() {
+			BeforeEach(func() {
+				delegate.Finish(logger, nil, false, false)
+			})
+
+			It("leaves it alone", func() {
+				factory.Drain(logger)
+
+				Ω(fakeDB.SaveBuildEventCallCount()).Should(BeZero())
+			})
+		})
+
+		Context("with a build that was never started here", func() {
+			It("leaves it alone", func() {
+				// e.g. one looked up only to be aborted
+				factory.Delegate(43)
+
+				factory.Drain(logger)
+
+				for i := 0; i < fakeDB.SaveBuildEventCallCount(); i++ {
+					savedBuildID, _ := fakeDB.SaveBuildEventArgsForCall(i)
+					Ω(savedBuildID).ShouldNot(Equal(43))
+				}
+			})
+		})
+	})
+
+	Describe("with a build logs directory", func() {
+		var (
+			buildLogsDir string
+			taskPlan     atc.TaskPlan
+		)
+
+		BeforeEach(func() {
+			var err error
+			buildLogsDir, err = ioutil.TempDir("", "build-logs")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			factory = NewBuildDelegateFactory(fakeDB, buildLogsDir)
+			delegate = factory.Delegate(buildID)
+
+			taskPlan = atc.TaskPlan{Name: "some-task"}
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(buildLogsDir)
+		})
+
+		It("saves output both as events and to a file named after the build", func() {
+			executionDelegate := delegate.ExecutionDelegate(logger, taskPlan, location)
+
+			_, err := executionDelegate.Stdout().Write([]byte("some stdout\n"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			_, err = executionDelegate.Stderr().Write([]byte("some stderr\n"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(fakeDB.SaveBuildEventCallCount()).Should(Equal(2))
+
+			_, savedEvent := fakeDB.SaveBuildEventArgsForCall(0)
+			Ω(savedEvent.(event.Log).Payload).Should(Equal("some stdout\n"))
+
+			_, savedEvent = fakeDB.SaveBuildEventArgsForCall(1)
+			Ω(savedEvent.(event.Log).Payload).Should(Equal("some stderr\n"))
+
+			contents, err := ioutil.ReadFile(filepath.Join(buildLogsDir, "42.log"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(contents)).Should(Equal("some stdout\nsome stderr\n"))
+		})
+
+		It("appends to the file when the build's delegate is recreated, e.g. after a restart", func() {
+			_, err := delegate.ExecutionDelegate(logger, taskPlan, location).Stdout().Write([]byte("before\n"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			resumed := factory.Delegate(buildID)
+
+			_, err = resumed.ExecutionDelegate(logger, taskPlan, location).Stdout().Write([]byte("after\n"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			contents, err := ioutil.ReadFile(filepath.Join(buildLogsDir, "42.log"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(contents)).Should(Equal("before\nafter\n"))
+		})
+
+		Context("when the file cannot be created", func() {
+			BeforeEach(func() {
+				os.RemoveAll(buildLogsDir)
+			})
+
+			It("still saves the output as events", func() {
+				_, err := delegate.ExecutionDelegate(logger, taskPlan, location).Stdout().Write([]byte("some stdout"))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(fakeDB.SaveBuildEventCallCount()).Should(Equal(1))
+			})
+		})
+	})
 })
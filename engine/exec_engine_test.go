@@ -13,6 +13,7 @@ import (
 	"github.com/concourse/atc/worker"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/clock"
 	"github.com/pivotal-golang/lager/lagertest"
 )
 
@@ -30,7 +31,7 @@ var _ = Describe("ExecEngine", func() {
 		fakeDelegateFactory = new(fakes.FakeBuildDelegateFactory)
 		fakeDB = new(fakes.FakeEngineDB)
 
-		execEngine = engine.NewExecEngine(fakeFactory, fakeDelegateFactory, fakeDB)
+		execEngine = engine.NewExecEngine(fakeFactory, fakeDelegateFactory, fakeDB, clock.NewClock())
 	})
 
 	Describe("Resume", func() {
@@ -738,6 +739,98 @@ var _ = Describe("ExecEngine", func() {
 			})
 		})
 	})
+
+	Describe("LookupBuild", func() {
+		var (
+			fakeDelegate          *fakes.FakeBuildDelegate
+			fakeExecutionDelegate *execfakes.FakeTaskDelegate
+
+			taskStepFactory *execfakes.FakeStepFactory
+			taskStep        *execfakes.FakeStep
+
+			plan atc.Plan
+		)
+
+		BeforeEach(func() {
+			fakeDelegate = new(fakes.FakeBuildDelegate)
+			fakeDelegateFactory.DelegateReturns(fakeDelegate)
+
+			fakeExecutionDelegate = new(execfakes.FakeTaskDelegate)
+			fakeDelegate.ExecutionDelegateReturns(fakeExecutionDelegate)
+
+			taskStepFactory = new(execfakes.FakeStepFactory)
+			taskStep = new(execfakes.FakeStep)
+			taskStep.ResultStub = successResult(false)
+			taskStepFactory.UsingReturns(taskStep)
+			fakeFactory.TaskReturns(taskStepFactory)
+
+			plan = atc.Plan{
+				Location: &atc.Location{ID: 7},
+				Task: &atc.TaskPlan{
+					Name:   "some-task",
+					Config: &atc.TaskConfig{},
+				},
+			}
+		})
+
+		Context("with the metadata of a build created before a restart", func() {
+			var lookedUpBuild engine.Build
+
+			BeforeEach(func() {
+				createdBuild, err := execEngine.CreateBuild(db.Build{ID: 42}, plan)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				lookedUpBuild, err = execEngine.LookupBuild(db.Build{
+					ID:             42,
+					EngineMetadata: createdBuild.Metadata(),
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("runs the task with the same container identifier, so that its container is reattached to", func() {
+				lookedUpBuild.Resume(lagertest.NewTestLogger("test"))
+
+				Ω(fakeFactory.TaskCallCount()).Should(Equal(1))
+
+				_, workerID, _, _, _, _ := fakeFactory.TaskArgsForCall(0)
+				Ω(workerID).Should(Equal(worker.Identifier{
+					BuildID:      42,
+					Type:         worker.ContainerTypeTask,
+					Name:         "some-task",
+					StepLocation: 7,
+				}))
+			})
+
+			It("does not start the build until it is resumed", func() {
+				Ω(fakeDelegate.StartCallCount()).Should(BeZero())
+
+				lookedUpBuild.Resume(lagertest.NewTestLogger("test"))
+
+				Ω(fakeDelegate.StartCallCount()).Should(Equal(1))
+			})
+
+			It("finishes the build with the reattached step's result", func() {
+				lookedUpBuild.Resume(lagertest.NewTestLogger("test"))
+
+				Ω(fakeDelegate.FinishCallCount()).Should(Equal(1))
+
+				_, err, succeeded, aborted := fakeDelegate.FinishArgsForCall(0)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(succeeded).Should(Equal(exec.Success(false)))
+				Ω(aborted).Should(BeFalse())
+			})
+		})
+
+		Context("with malformed metadata", func() {
+			It("returns an error", func() {
+				_, err := execEngine.LookupBuild(db.Build{
+					ID:             42,
+					EngineMetadata: "{not json",
+				})
+				Ω(err).Should(HaveOccurred())
+			})
+		})
+	})
 })
 
 func successResult(result exec.Success) func(dest interface{}) bool {
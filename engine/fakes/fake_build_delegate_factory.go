@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/concourse/atc/engine"
+	"github.com/pivotal-golang/lager"
 )
 
 type FakeBuildDelegateFactory struct {
@@ -16,6 +17,11 @@ type FakeBuildDelegateFactory struct {
 	delegateReturns struct {
 		result1 engine.BuildDelegate
 	}
+	DrainStub        func(lager.Logger)
+	drainMutex       sync.RWMutex
+	drainArgsForCall []struct {
+		arg1 lager.Logger
+	}
 }
 
 func (fake *FakeBuildDelegateFactory) Delegate(buildID int) engine.BuildDelegate {
@@ -50,4 +56,27 @@ func (fake *FakeBuildDelegateFactory) DelegateReturns(result1 engine.BuildDelega
 	}{result1}
 }
 
+func (fake *FakeBuildDelegateFactory) Drain(arg1 lager.Logger) {
+	fake.drainMutex.Lock()
+	fake.drainArgsForCall = append(fake.drainArgsForCall, struct {
+		arg1 lager.Logger
+	}{arg1})
+	fake.drainMutex.Unlock()
+	if fake.DrainStub != nil {
+		fake.DrainStub(arg1)
+	}
+}
+
+func (fake *FakeBuildDelegateFactory) DrainCallCount() int {
+	fake.drainMutex.RLock()
+	defer fake.drainMutex.RUnlock()
+	return len(fake.drainArgsForCall)
+}
+
+func (fake *FakeBuildDelegateFactory) DrainArgsForCall(i int) lager.Logger {
+	fake.drainMutex.RLock()
+	defer fake.drainMutex.RUnlock()
+	return fake.drainArgsForCall[i].arg1
+}
+
 var _ engine.BuildDelegateFactory = new(FakeBuildDelegateFactory)
@@ -28,6 +28,15 @@ type FakeEngineDB struct {
 	finishBuildReturns struct {
 		result1 error
 	}
+	SaveBuildErrorCategoryStub        func(buildID int, category string) error
+	saveBuildErrorCategoryMutex       sync.RWMutex
+	saveBuildErrorCategoryArgsForCall []struct {
+		buildID  int
+		category string
+	}
+	saveBuildErrorCategoryReturns struct {
+		result1 error
+	}
 	SaveBuildEngineMetadataStub        func(buildID int, metadata string) error
 	saveBuildEngineMetadataMutex       sync.RWMutex
 	saveBuildEngineMetadataArgsForCall []struct {
@@ -126,6 +135,39 @@ func (fake *FakeEngineDB) FinishBuildReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeEngineDB) SaveBuildErrorCategory(buildID int, category string) error {
+	fake.saveBuildErrorCategoryMutex.Lock()
+	fake.saveBuildErrorCategoryArgsForCall = append(fake.saveBuildErrorCategoryArgsForCall, struct {
+		buildID  int
+		category string
+	}{buildID, category})
+	fake.saveBuildErrorCategoryMutex.Unlock()
+	if fake.SaveBuildErrorCategoryStub != nil {
+		return fake.SaveBuildErrorCategoryStub(buildID, category)
+	} else {
+		return fake.saveBuildErrorCategoryReturns.result1
+	}
+}
+
+func (fake *FakeEngineDB) SaveBuildErrorCategoryCallCount() int {
+	fake.saveBuildErrorCategoryMutex.RLock()
+	defer fake.saveBuildErrorCategoryMutex.RUnlock()
+	return len(fake.saveBuildErrorCategoryArgsForCall)
+}
+
+func (fake *FakeEngineDB) SaveBuildErrorCategoryArgsForCall(i int) (int, string) {
+	fake.saveBuildErrorCategoryMutex.RLock()
+	defer fake.saveBuildErrorCategoryMutex.RUnlock()
+	return fake.saveBuildErrorCategoryArgsForCall[i].buildID, fake.saveBuildErrorCategoryArgsForCall[i].category
+}
+
+func (fake *FakeEngineDB) SaveBuildErrorCategoryReturns(result1 error) {
+	fake.SaveBuildErrorCategoryStub = nil
+	fake.saveBuildErrorCategoryReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeEngineDB) SaveBuildEngineMetadata(buildID int, metadata string) error {
 	fake.saveBuildEngineMetadataMutex.Lock()
 	fake.saveBuildEngineMetadataArgsForCall = append(fake.saveBuildEngineMetadataArgsForCall, struct {
@@ -0,0 +1,7 @@
+package atc
+
+// ErrorResponse is the body of every error response from the API.
+type ErrorResponse struct {
+	Message string `json:"error"`
+	Code    string `json:"code"`
+}
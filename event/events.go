@@ -3,13 +3,21 @@ package event
 import "github.com/concourse/atc"
 
 type Error struct {
-	Message string `json:"message"`
-	Origin  Origin `json:"origin,omitempty"`
+	Message  string `json:"message"`
+	Category string `json:"category,omitempty"`
+	Origin   Origin `json:"origin,omitempty"`
 }
 
 func (Error) EventType() atc.EventType  { return EventTypeError }
 func (Error) Version() atc.EventVersion { return "2.0" }
 
+type Drain struct {
+	Message string `json:"message"`
+}
+
+func (Drain) EventType() atc.EventType  { return EventTypeDrain }
+func (Drain) Version() atc.EventVersion { return "1.0" }
+
 type FinishTask struct {
 	Time       int64  `json:"time"`
 	ExitStatus int    `json:"exit_status"`
@@ -101,6 +109,35 @@ type Origin struct {
 	Hook     string         `json:"hook"`
 }
 
+// OriginOf returns the origin of the step that emitted the event. Events
+// about the build as a whole, such as status changes, have no origin.
+func OriginOf(ev atc.Event) (Origin, bool) {
+	var origin Origin
+
+	switch e := ev.(type) {
+	case Log:
+		origin = e.Origin
+	case InitializeTask:
+		origin = e.Origin
+	case StartTask:
+		origin = e.Origin
+	case FinishTask:
+		origin = e.Origin
+	case FinishGet:
+		origin = e.Origin
+	case FinishPut:
+		origin = e.Origin
+	case Error:
+		origin = e.Origin
+	}
+
+	if origin == (Origin{}) {
+		return Origin{}, false
+	}
+
+	return origin, true
+}
+
 type OriginType string
 
 const (
@@ -28,4 +28,8 @@ const (
 
 	// error occurred
 	EventTypeError atc.EventType = "error"
+
+	// the ATC running the build shut down; output may be missing until the
+	// build is resumed
+	EventTypeDrain atc.EventType = "drain"
 )
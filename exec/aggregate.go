@@ -71,11 +71,8 @@ dance:
 	return nil
 }
 
-func (source aggregateStep) Release() {
-
-	for _, src := range source {
-		src.Release()
-	}
+func (source aggregateStep) Release() error {
+	return releaseAll(source...)
 }
 
 func (source aggregateStep) Result(x interface{}) bool {
@@ -139,6 +139,29 @@ var _ = Describe("Aggregate", func() {
 			Ω(err.Error()).Should(ContainSubstring("nope A"))
 			Ω(err.Error()).Should(ContainSubstring("nope B"))
 		})
+
+		Context("when a later step fails first", func() {
+			BeforeEach(func() {
+				bFailed := make(chan struct{})
+
+				outStepA.RunStub = func(signals <-chan os.Signal, ready chan<- struct{}) error {
+					<-bFailed
+					return disasterA
+				}
+
+				outStepB.RunStub = func(signals <-chan os.Signal, ready chan<- struct{}) error {
+					defer close(bFailed)
+					return disasterB
+				}
+			})
+
+			It("still lists the errors in the order of the steps", func() {
+				var err error
+				Eventually(process.Wait()).Should(Receive(&err))
+
+				Ω(err).Should(MatchError("sources failed:\nnope A\nnope B"))
+			})
+		})
 	})
 
 	Describe("releasing", func() {
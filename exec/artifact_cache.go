@@ -0,0 +1,389 @@
+package exec
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/concourse/atc/resource"
+)
+
+//go:generate counterfeiter . ArtifactCache
+
+// ArtifactCache holds the artifact streams of fetches, so that identical
+// artifacts can be streamed again without going back to the container they
+// were fetched into.
+type ArtifactCache interface {
+	Get(resource.CacheIdentifier) (io.ReadCloser, bool)
+	Put(resource.CacheIdentifier, io.Reader) error
+
+	// Remove removes the artifacts of every fetch of the identifier's
+	// version, whatever its params, returning how many there were.
+	Remove(resource.CacheIdentifier) (int, error)
+}
+
+const artifactTempPrefix = "put-"
+
+const (
+	// artifacts, named by the digest of their contents
+	artifactsDirName = "artifacts"
+
+	// one file per fetch, named by the fetch and containing the digest of its
+	// artifact
+	fetchesDirName = "fetches"
+)
+
+type dirArtifactCache struct {
+	artifactsDir string
+	fetchesDir   string
+
+	maxBytes int64
+
+	// artifacts by digest, most recently used first
+	artifacts *list.List
+	byDigest  map[string]*list.Element
+	size      int64
+
+	// the digest of each fetch's artifact, by fetch
+	fetches map[string]string
+
+	lock sync.Mutex
+}
+
+type cachedArtifact struct {
+	digest string
+	size   int64
+}
+
+// NewDirArtifactCache returns an ArtifactCache that keeps artifacts as files
+// in the given directory, addressed by the digest of their contents; fetches
+// that produced identical artifacts share a single file. Once the artifacts
+// take up more than maxBytes, the least recently used ones are removed,
+// along with the fetches of them; 0 means no limit.
+//
+// Artifacts left in the directory by a previous run are kept, oldest first
+// in line for removal.
+func NewDirArtifactCache(dir string, maxBytes int64) (ArtifactCache, error) {
+	cache := &dirArtifactCache{
+		artifactsDir: filepath.Join(dir, artifactsDirName),
+		fetchesDir:   filepath.Join(dir, fetchesDirName),
+
+		maxBytes: maxBytes,
+
+		artifacts: list.New(),
+		byDigest:  map[string]*list.Element{},
+
+		fetches: map[string]string{},
+	}
+
+	err := cache.load()
+	if err != nil {
+		return nil, err
+	}
+
+	cache.evict()
+
+	return cache, nil
+}
+
+func (cache *dirArtifactCache) load() error {
+	for _, dir := range []string{cache.artifactsDir, cache.fetchesDir} {
+		err := os.MkdirAll(dir, 0755)
+		if err != nil {
+			return err
+		}
+	}
+
+	infos, err := ioutil.ReadDir(cache.artifactsDir)
+	if err != nil {
+		return err
+	}
+
+	sort.Sort(byModTime(infos))
+
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+
+		if strings.HasPrefix(info.Name(), artifactTempPrefix) {
+			// interrupted while being put
+			os.Remove(filepath.Join(cache.artifactsDir, info.Name()))
+			continue
+		}
+
+		cache.add(info.Name(), info.Size())
+	}
+
+	infos, err = ioutil.ReadDir(cache.fetchesDir)
+	if err != nil {
+		return err
+	}
+
+	for _, info := range infos {
+		path := filepath.Join(cache.fetchesDir, info.Name())
+
+		if strings.HasPrefix(info.Name(), artifactTempPrefix) {
+			os.Remove(path)
+			continue
+		}
+
+		digest, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if _, found := cache.byDigest[string(digest)]; !found {
+			os.Remove(path)
+			continue
+		}
+
+		cache.fetches[info.Name()] = string(digest)
+	}
+
+	return nil
+}
+
+func (cache *dirArtifactCache) Get(identifier resource.CacheIdentifier) (io.ReadCloser, bool) {
+	name := fetchName(identifier)
+
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	digest, found := cache.fetches[name]
+	if !found {
+		return nil, false
+	}
+
+	element, found := cache.byDigest[digest]
+	if !found {
+		cache.forgetFetch(name)
+		return nil, false
+	}
+
+	// an open file remains readable even if it is evicted while streaming
+	file, err := os.Open(filepath.Join(cache.artifactsDir, digest))
+	if err != nil {
+		cache.remove(element)
+		return nil, false
+	}
+
+	cache.artifacts.MoveToFront(element)
+
+	return file, true
+}
+
+// Put streams the artifact to a temporary file first, digesting it on the
+// way, so that a partially written artifact is never visible to Get. If an
+// identical artifact is already stored, the fetch shares it rather than
+// storing it again. An artifact larger than the whole cache is not stored.
+func (cache *dirArtifactCache) Put(identifier resource.CacheIdentifier, stream io.Reader) error {
+	name := fetchName(identifier)
+
+	tmp, err := ioutil.TempFile(cache.artifactsDir, artifactTempPrefix)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(tmp, hash), stream)
+
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if cache.maxBytes > 0 && size > cache.maxBytes {
+		os.Remove(tmp.Name())
+		return nil
+	}
+
+	digest := fmt.Sprintf("%x", hash.Sum(nil))
+
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	if element, found := cache.byDigest[digest]; found {
+		os.Remove(tmp.Name())
+		cache.artifacts.MoveToFront(element)
+	} else {
+		err = os.Rename(tmp.Name(), filepath.Join(cache.artifactsDir, digest))
+		if err != nil {
+			os.Remove(tmp.Name())
+			return err
+		}
+
+		cache.add(digest, size)
+	}
+
+	previousDigest, replacing := cache.fetches[name]
+
+	err = cache.saveFetch(name, digest)
+	if err != nil {
+		return err
+	}
+
+	if replacing && previousDigest != digest && !cache.isShared(previousDigest) {
+		if element, found := cache.byDigest[previousDigest]; found {
+			cache.remove(element)
+		}
+	}
+
+	cache.evict()
+
+	return nil
+}
+
+func (cache *dirArtifactCache) Remove(identifier resource.CacheIdentifier) (int, error) {
+	prefix := identifier.VersionHash() + "-"
+
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	removed := 0
+
+	for name, digest := range cache.fetches {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		err := cache.forgetFetch(name)
+		if err != nil {
+			return removed, err
+		}
+
+		removed++
+
+		if cache.isShared(digest) {
+			continue
+		}
+
+		if element, found := cache.byDigest[digest]; found {
+			err := cache.remove(element)
+			if err != nil {
+				return removed, err
+			}
+		}
+	}
+
+	return removed, nil
+}
+
+// fetchName names a fetch by its version, then its params, so that every
+// fetch of a version can be found by prefix.
+func fetchName(identifier resource.CacheIdentifier) string {
+	return identifier.VersionHash() + "-" + identifier.Params.Hash()
+}
+
+// saveFetch records which artifact a fetch produced, replacing the file
+// atomically so that a crash never leaves a partially written digest.
+func (cache *dirArtifactCache) saveFetch(name string, digest string) error {
+	tmp, err := ioutil.TempFile(cache.fetchesDir, artifactTempPrefix)
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write([]byte(digest))
+
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(cache.fetchesDir, name))
+	}
+
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	cache.fetches[name] = digest
+
+	return nil
+}
+
+func (cache *dirArtifactCache) forgetFetch(name string) error {
+	delete(cache.fetches, name)
+
+	err := os.Remove(filepath.Join(cache.fetchesDir, name))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (cache *dirArtifactCache) isShared(digest string) bool {
+	for _, fetchDigest := range cache.fetches {
+		if fetchDigest == digest {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (cache *dirArtifactCache) add(digest string, size int64) {
+	cache.byDigest[digest] = cache.artifacts.PushFront(cachedArtifact{
+		digest: digest,
+		size:   size,
+	})
+
+	cache.size += size
+}
+
+// remove removes the artifact, and the fetches that produced it.
+func (cache *dirArtifactCache) remove(element *list.Element) error {
+	artifact := element.Value.(cachedArtifact)
+
+	cache.artifacts.Remove(element)
+	delete(cache.byDigest, artifact.digest)
+
+	cache.size -= artifact.size
+
+	for name, digest := range cache.fetches {
+		if digest == artifact.digest {
+			cache.forgetFetch(name)
+		}
+	}
+
+	err := os.Remove(filepath.Join(cache.artifactsDir, artifact.digest))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// evict removes the least recently used artifacts until the cache is within
+// its limit.
+func (cache *dirArtifactCache) evict() {
+	if cache.maxBytes <= 0 {
+		return
+	}
+
+	for cache.size > cache.maxBytes {
+		cache.remove(cache.artifacts.Back())
+	}
+}
+
+type byModTime []os.FileInfo
+
+func (infos byModTime) Len() int           { return len(infos) }
+func (infos byModTime) Swap(i, j int)      { infos[i], infos[j] = infos[j], infos[i] }
+func (infos byModTime) Less(i, j int) bool { return infos[i].ModTime().Before(infos[j].ModTime()) }
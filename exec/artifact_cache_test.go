@@ -0,0 +1,300 @@
+package exec_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/concourse/atc"
+	. "github.com/concourse/atc/exec"
+	"github.com/concourse/atc/resource"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type failingReader struct {
+	err error
+}
+
+func (r failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+var _ = Describe("DirArtifactCache", func() {
+	var (
+		dir      string
+		maxBytes int64
+
+		identifier resource.CacheIdentifier
+
+		cache ArtifactCache
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "artifact-cache")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		maxBytes = 0
+
+		identifier = resource.CacheIdentifier{
+			Type:    "some-type",
+			Source:  atc.Source{"some": "source"},
+			Params:  atc.Params{"some": "params"},
+			Version: atc.Version{"some": "version"},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		cache, err = NewDirArtifactCache(dir, maxBytes)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	identifierFor := func(version string) resource.CacheIdentifier {
+		other := identifier
+		other.Version = atc.Version{"some": version}
+		return other
+	}
+
+	put := func(identifier resource.CacheIdentifier, contents string) {
+		err := cache.Put(identifier, bytes.NewBufferString(contents))
+		Ω(err).ShouldNot(HaveOccurred())
+	}
+
+	get := func(identifier resource.CacheIdentifier) (string, bool) {
+		stream, found := cache.Get(identifier)
+		if !found {
+			return "", false
+		}
+
+		defer stream.Close()
+
+		contents, err := ioutil.ReadAll(stream)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		return string(contents), true
+	}
+
+	It("misses for an artifact that was never put", func() {
+		_, found := cache.Get(identifier)
+		Ω(found).Should(BeFalse())
+	})
+
+	It("returns a stored artifact's bytes on every get", func() {
+		put(identifier, "some-artifact")
+
+		for i := 0; i < 2; i++ {
+			contents, found := get(identifier)
+			Ω(found).Should(BeTrue())
+			Ω(contents).Should(Equal("some-artifact"))
+		}
+	})
+
+	It("keeps the fetches of a version with different params apart", func() {
+		withParams := identifier
+		withParams.Params = atc.Params{"skip_download": true}
+
+		put(identifier, "some-artifact")
+		put(withParams, "some-other-artifact")
+
+		contents, found := get(identifier)
+		Ω(found).Should(BeTrue())
+		Ω(contents).Should(Equal("some-artifact"))
+
+		contents, found = get(withParams)
+		Ω(found).Should(BeTrue())
+		Ω(contents).Should(Equal("some-other-artifact"))
+	})
+
+	It("replaces an artifact that is put again", func() {
+		put(identifier, "some-artifact")
+		put(identifier, "some-newer-artifact")
+
+		contents, found := get(identifier)
+		Ω(found).Should(BeTrue())
+		Ω(contents).Should(Equal("some-newer-artifact"))
+	})
+
+	Describe("addressing artifacts by their contents", func() {
+		storedArtifacts := func() []os.FileInfo {
+			entries, err := ioutil.ReadDir(filepath.Join(dir, "artifacts"))
+			Ω(err).ShouldNot(HaveOccurred())
+			return entries
+		}
+
+		It("stores identical artifacts of different fetches once, and serves both from it", func() {
+			put(identifierFor("a"), "some-artifact")
+			put(identifierFor("b"), "some-artifact")
+
+			Ω(storedArtifacts()).Should(HaveLen(1))
+
+			contents, found := get(identifierFor("a"))
+			Ω(found).Should(BeTrue())
+			Ω(contents).Should(Equal("some-artifact"))
+
+			contents, found = get(identifierFor("b"))
+			Ω(found).Should(BeTrue())
+			Ω(contents).Should(Equal("some-artifact"))
+		})
+
+		It("keeps a shared artifact when one of the fetches is removed", func() {
+			put(identifierFor("a"), "some-artifact")
+			put(identifierFor("b"), "some-artifact")
+
+			_, err := cache.Remove(identifierFor("a"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			_, found := get(identifierFor("a"))
+			Ω(found).Should(BeFalse())
+
+			contents, found := get(identifierFor("b"))
+			Ω(found).Should(BeTrue())
+			Ω(contents).Should(Equal("some-artifact"))
+		})
+
+		It("removes the old artifact when a fetch is put again with different contents", func() {
+			put(identifier, "some-artifact")
+			put(identifier, "some-newer-artifact")
+
+			Ω(storedArtifacts()).Should(HaveLen(1))
+		})
+	})
+
+	Describe("removing an artifact", func() {
+		It("removes every fetch of the version, and misses afterwards", func() {
+			withParams := identifier
+			withParams.Params = atc.Params{"skip_download": true}
+
+			put(identifier, "some-artifact")
+			put(withParams, "some-other-artifact")
+			put(identifierFor("other-version"), "some-unrelated-artifact")
+
+			removed, err := cache.Remove(identifier)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(removed).Should(Equal(2))
+
+			_, found := cache.Get(identifier)
+			Ω(found).Should(BeFalse())
+
+			_, found = cache.Get(withParams)
+			Ω(found).Should(BeFalse())
+
+			_, found = cache.Get(identifierFor("other-version"))
+			Ω(found).Should(BeTrue())
+		})
+
+		It("reports when there was nothing to remove", func() {
+			removed, err := cache.Remove(identifier)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(removed).Should(BeZero())
+		})
+	})
+
+	Context("with a size limit", func() {
+		BeforeEach(func() {
+			maxBytes = 10
+		})
+
+		It("removes the least recently used artifacts to stay within it", func() {
+			put(identifierFor("a"), "aaaa")
+			put(identifierFor("b"), "bbbb")
+
+			_, found := get(identifierFor("a"))
+			Ω(found).Should(BeTrue())
+
+			put(identifierFor("c"), "cccc")
+
+			_, found = get(identifierFor("b"))
+			Ω(found).Should(BeFalse())
+
+			_, found = get(identifierFor("a"))
+			Ω(found).Should(BeTrue())
+
+			_, found = get(identifierFor("c"))
+			Ω(found).Should(BeTrue())
+
+			entries, err := ioutil.ReadDir(filepath.Join(dir, "artifacts"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(entries).Should(HaveLen(2))
+		})
+
+		It("does not store an artifact larger than the limit", func() {
+			put(identifierFor("a"), "aaaa")
+			put(identifier, "some-very-large-artifact")
+
+			_, found := cache.Get(identifier)
+			Ω(found).Should(BeFalse())
+
+			_, found = get(identifierFor("a"))
+			Ω(found).Should(BeTrue())
+		})
+	})
+
+	Context("when the directory has artifacts from a previous run", func() {
+		BeforeEach(func() {
+			previous, err := NewDirArtifactCache(dir, 0)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			err = previous.Put(identifier, bytes.NewBufferString("some-artifact"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			err = ioutil.WriteFile(filepath.Join(dir, "artifacts", "put-interrupted-123"), []byte("some-partial"), 0644)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("serves them", func() {
+			contents, found := get(identifier)
+			Ω(found).Should(BeTrue())
+			Ω(contents).Should(Equal("some-artifact"))
+		})
+
+		It("cleans up artifacts that were being put", func() {
+			entries, err := ioutil.ReadDir(filepath.Join(dir, "artifacts"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			for _, entry := range entries {
+				Ω(strings.HasPrefix(entry.Name(), "put-")).Should(BeFalse())
+			}
+		})
+
+		Context("and they exceed the size limit", func() {
+			BeforeEach(func() {
+				maxBytes = 5
+			})
+
+			It("removes them", func() {
+				_, found := cache.Get(identifier)
+				Ω(found).Should(BeFalse())
+			})
+		})
+	})
+
+	Context("when the stream fails partway through", func() {
+		disaster := errors.New("nope")
+
+		It("returns the error and stores nothing", func() {
+			err := cache.Put(identifier, io.MultiReader(
+				bytes.NewBufferString("some-partial-artifact"),
+				failingReader{disaster},
+			))
+			Ω(err).Should(Equal(disaster))
+
+			_, found := cache.Get(identifier)
+			Ω(found).Should(BeFalse())
+
+			entries, err := ioutil.ReadDir(filepath.Join(dir, "artifacts"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(entries).Should(BeEmpty())
+		})
+	})
+})
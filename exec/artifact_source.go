@@ -17,12 +17,20 @@ func (err FileNotFoundError) Error() string {
 	return fmt.Sprintf("file not found: %s", err.Path)
 }
 
+type FileIsDirectoryError struct {
+	Path string
+}
+
+func (err FileIsDirectoryError) Error() string {
+	return fmt.Sprintf("path is a directory, not a file: %s", err.Path)
+}
+
 //go:generate counterfeiter . Step
 
 type Step interface {
 	ifrit.Runner
 
-	Release()
+	Release() error
 	// Implementers of this method MUST not mutate the given pointer if they
 	// are unable to respond (i.e. returning false from this function).
 	Result(interface{}) bool
@@ -43,6 +51,16 @@ type ArtifactDestination interface {
 	StreamIn(string, io.Reader) error
 }
 
+//go:generate counterfeiter . CompressedArtifactDestination
+
+// CompressedArtifactDestination is an ArtifactDestination that can also take
+// a gzipped tar stream, i.e. because it is on the far side of a slow link.
+// Sources stream to it compressed rather than calling StreamIn.
+type CompressedArtifactDestination interface {
+	ArtifactDestination
+	StreamInCompressed(string, io.Reader) error
+}
+
 type Success bool
 
 type ExitStatus int
@@ -58,7 +76,9 @@ func (NoopStep) Run(<-chan os.Signal, chan<- struct{}) error {
 	return nil
 }
 
-func (NoopStep) Release() {}
+func (NoopStep) Release() error {
+	return nil
+}
 
 func (NoopStep) Result(interface{}) bool {
 	return false
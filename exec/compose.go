@@ -56,14 +56,8 @@ dance:
 	return step.secondStep.Run(signals, ready)
 }
 
-func (step *composed) Release() {
-	if step.firstStep != nil {
-		step.firstStep.Release()
-	}
-
-	if step.secondStep != nil {
-		step.secondStep.Release()
-	}
+func (step *composed) Release() error {
+	return releaseAll(step.firstStep, step.secondStep)
 }
 
 func (step *composed) Result(x interface{}) bool {
@@ -99,3 +99,25 @@ func (configSource MergedConfigSource) FetchConfig(source *SourceRepository) (at
 
 	return aConfig.Merge(bConfig), nil
 }
+
+// ValidatingConfigSource validates the config fetched from the wrapped source
+// so that an invalid config fails before any container is created. Static
+// configs only make sense to validate once they are complete, i.e. after
+// being merged with the config they override.
+type ValidatingConfigSource struct {
+	ConfigSource TaskConfigSource
+}
+
+func (configSource ValidatingConfigSource) FetchConfig(source *SourceRepository) (atc.TaskConfig, error) {
+	config, err := configSource.ConfigSource.FetchConfig(source)
+	if err != nil {
+		return atc.TaskConfig{}, err
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return atc.TaskConfig{}, err
+	}
+
+	return config, nil
+}
@@ -270,4 +270,71 @@ var _ = Describe("ConfigSource", func() {
 			})
 		})
 	})
+
+	Describe("ValidatingConfigSource", func() {
+		var (
+			fakeConfigSource *fakes.FakeTaskConfigSource
+
+			configSource TaskConfigSource
+
+			fetchedConfig atc.TaskConfig
+			fetchErr      error
+		)
+
+		BeforeEach(func() {
+			fakeConfigSource = new(fakes.FakeTaskConfigSource)
+
+			configSource = ValidatingConfigSource{fakeConfigSource}
+		})
+
+		JustBeforeEach(func() {
+			fetchedConfig, fetchErr = configSource.FetchConfig(repo)
+		})
+
+		Context("when the config is valid", func() {
+			BeforeEach(func() {
+				fakeConfigSource.FetchConfigReturns(someConfig, nil)
+			})
+
+			It("fetches via the wrapped source", func() {
+				Ω(fakeConfigSource.FetchConfigCallCount()).Should(Equal(1))
+				Ω(fakeConfigSource.FetchConfigArgsForCall(0)).Should(Equal(repo))
+			})
+
+			It("returns the config", func() {
+				Ω(fetchErr).ShouldNot(HaveOccurred())
+				Ω(fetchedConfig).Should(Equal(someConfig))
+			})
+		})
+
+		Context("when wrapping an inline config that is missing the path to run", func() {
+			BeforeEach(func() {
+				invalidConfig := someConfig
+				invalidConfig.Run = atc.TaskRunConfig{}
+
+				configSource = ValidatingConfigSource{StaticConfigSource{invalidConfig}}
+			})
+
+			It("fails to fetch the config", func() {
+				Ω(fetchErr).Should(MatchError(ContainSubstring("missing path to executable to run")))
+			})
+
+			It("fails with an error that is categorized as the user's", func() {
+				Ω(fetchErr).Should(BeAssignableToTypeOf(atc.InvalidTaskConfigError{}))
+				Ω(CategorizeError(fetchErr)).Should(Equal(StepErrorCategoryUser))
+			})
+		})
+
+		Context("when fetching via the wrapped source fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeConfigSource.FetchConfigReturns(atc.TaskConfig{}, disaster)
+			})
+
+			It("returns the error", func() {
+				Ω(fetchErr).Should(Equal(disaster))
+			})
+		})
+	})
 })
@@ -27,6 +27,7 @@ var identifier = worker.Identifier{
 var _ = Describe("GardenFactory", func() {
 	var (
 		fakeTracker      *rfakes.FakeTracker
+		fakeCache        *rfakes.FakeCache
 		fakeWorkerClient *wfakes.FakeClient
 
 		factory Factory
@@ -43,9 +44,10 @@ var _ = Describe("GardenFactory", func() {
 
 	BeforeEach(func() {
 		fakeTracker = new(rfakes.FakeTracker)
+		fakeCache = new(rfakes.FakeCache)
 		fakeWorkerClient = new(wfakes.FakeClient)
 
-		factory = NewGardenFactory(fakeWorkerClient, fakeTracker, func() string { return "" })
+		factory = NewGardenFactory(fakeWorkerClient, fakeTracker, fakeCache, nil, nil, 0, false, func() string { return "" })
 
 		stdoutBuf = gbytes.NewBuffer()
 		stderrBuf = gbytes.NewBuffer()
@@ -287,12 +289,20 @@ var _ = Describe("GardenFactory", func() {
 
 					Context("when the resource can stream out", func() {
 						var (
-							streamedOut io.ReadCloser
+							streamedOut *gbytes.Buffer
+							streamedIn  []byte
 						)
 
 						BeforeEach(func() {
-							streamedOut = gbytes.NewBuffer()
+							streamedOut = gbytes.BufferWithBytes([]byte("some-stream"))
 							fakeVersionedSource.StreamOutReturns(streamedOut, nil)
+
+							streamedIn = nil
+							fakeDestination.StreamInStub = func(dst string, src io.Reader) error {
+								var err error
+								streamedIn, err = ioutil.ReadAll(src)
+								return err
+							}
 						})
 
 						It("streams the resource to the destination", func() {
@@ -303,9 +313,9 @@ var _ = Describe("GardenFactory", func() {
 							Ω(fakeVersionedSource.StreamOutArgsForCall(0)).Should(Equal("."))
 
 							Ω(fakeDestination.StreamInCallCount()).Should(Equal(1))
-							dest, src := fakeDestination.StreamInArgsForCall(0)
+							dest, _ := fakeDestination.StreamInArgsForCall(0)
 							Ω(dest).Should(Equal("."))
-							Ω(src).Should(Equal(streamedOut))
+							Ω(string(streamedIn)).Should(Equal("some-stream"))
 						})
 
 						Context("when streaming out of the versioned source fails", func() {
@@ -324,6 +334,7 @@ var _ = Describe("GardenFactory", func() {
 							disaster := errors.New("nope")
 
 							BeforeEach(func() {
+								fakeDestination.StreamInStub = nil
 								fakeDestination.StreamInReturns(disaster)
 							})
 
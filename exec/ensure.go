@@ -31,19 +31,23 @@ func (o ensure) Using(prev Step, repo *SourceRepository) Step {
 	return &o
 }
 
+// Run runs the step and then always runs the ensure hook, even if the step
+// errored or was interrupted. The step's error takes precedence over the
+// hook's.
 func (o *ensure) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
 	stepRunErr := o.step.Run(signals, ready)
 
-	if stepRunErr != nil {
-		return stepRunErr
-	}
-
 	// The contract of the Result method is such that it does not change the value
 	// of the provided pointer if it is not able to respond.
 	// Therefore there is no need to check the return value here.
 	o.ensure = o.ensureFactory.Using(o.step, o.repo)
-	err := o.ensure.Run(signals, make(chan struct{})) // TODO test
-	return err
+	ensureRunErr := o.ensure.Run(signals, make(chan struct{}))
+
+	if stepRunErr != nil {
+		return stepRunErr
+	}
+
+	return ensureRunErr
 }
 
 func (o *ensure) Result(x interface{}) bool {
@@ -68,11 +72,6 @@ func (o *ensure) Result(x interface{}) bool {
 		return false
 	}
 }
-func (o *ensure) Release() {
-	if o.step != nil {
-		o.step.Release()
-	}
-	if o.ensure != nil {
-		o.ensure.Release()
-	}
+func (o *ensure) Release() error {
+	return releaseAll(o.step, o.ensure)
 }
@@ -82,14 +82,32 @@ var _ = Describe("Ensure Step", func() {
 		Eventually(process.Wait()).Should(Receive(noError()))
 	})
 
-	It("does not run the ensure hook if the step errors", func() {
+	It("runs the ensure hook if the step errors, returning the step's error", func() {
 		step.RunReturns(errors.New("disaster"))
 
 		process := ifrit.Background(ensureStep)
 
 		Eventually(step.RunCallCount).Should(Equal(1))
 		Eventually(process.Wait()).Should(Receive(errorMatching("disaster")))
-		Ω(hook.RunCallCount()).Should(Equal(0))
+		Ω(hook.RunCallCount()).Should(Equal(1))
+	})
+
+	It("returns the step's error if both the step and the hook error", func() {
+		step.RunReturns(errors.New("disaster"))
+		hook.RunReturns(errors.New("hook disaster"))
+
+		process := ifrit.Background(ensureStep)
+
+		Eventually(process.Wait()).Should(Receive(errorMatching("disaster")))
+		Ω(hook.RunCallCount()).Should(Equal(1))
+	})
+
+	It("returns the hook's error if only the hook errors", func() {
+		hook.RunReturns(errors.New("hook disaster"))
+
+		process := ifrit.Background(ensureStep)
+
+		Eventually(process.Wait()).Should(Receive(errorMatching("hook disaster")))
 	})
 
 	It("propagates signals to the first step when first step is running", func() {
@@ -106,7 +124,7 @@ var _ = Describe("Ensure Step", func() {
 
 		Eventually(step.RunCallCount).Should(Equal(1))
 		Eventually(process.Wait()).Should(Receive(errorMatching("interrupted")))
-		Ω(hook.RunCallCount()).Should(Equal(0))
+		Ω(hook.RunCallCount()).Should(Equal(1))
 	})
 
 	It("propagates signals to the hook when the hook is running", func() {
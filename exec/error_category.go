@@ -0,0 +1,53 @@
+package exec
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/credentials"
+	"github.com/concourse/atc/resource"
+	"github.com/concourse/atc/worker"
+)
+
+type StepErrorCategory string
+
+const (
+	// the pipeline or its resources did something wrong; running again won't help
+	StepErrorCategoryUser StepErrorCategory = "user"
+
+	// something went wrong in the infrastructure, i.e. a worker went away
+	StepErrorCategorySystem StepErrorCategory = "system"
+
+	// the step was interrupted, i.e. the build was aborted
+	StepErrorCategoryInterrupted StepErrorCategory = "interrupted"
+)
+
+// CategorizeError determines who is to blame for a step's error. Errors that
+// aren't known to be caused by the user are assumed to be system errors.
+func CategorizeError(err error) StepErrorCategory {
+	switch err {
+	case ErrInterrupted, resource.ErrAborted:
+		return StepErrorCategoryInterrupted
+	case ErrStepTimedOut:
+		return StepErrorCategoryUser
+	}
+
+	switch err.(type) {
+	case worker.WorkerGoneError:
+		// the build may well succeed on another worker
+		return StepErrorCategorySystem
+
+	case atc.InvalidTaskConfigError,
+		credentials.MissingCredentialError,
+		resource.ErrResourceScriptFailed,
+		resource.ErrResourceOutputMalformed,
+		resource.ErrResourceOutputTooLarge,
+		MissingInputsError,
+		FileNotFoundError,
+		FileIsDirectoryError,
+		UnknownArtifactSourceError,
+		UnspecifiedArtifactSourceError,
+		worker.NoCompatibleWorkersError:
+		return StepErrorCategoryUser
+	}
+
+	return StepErrorCategorySystem
+}
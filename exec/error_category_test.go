@@ -0,0 +1,99 @@
+package exec_test
+
+import (
+	"errors"
+	"net"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/credentials"
+	. "github.com/concourse/atc/exec"
+	"github.com/concourse/atc/resource"
+	"github.com/concourse/atc/worker"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CategorizeError", func() {
+	It("categorizes a resource script exiting nonzero as a user error", func() {
+		Ω(CategorizeError(resource.ErrResourceScriptFailed{
+			Path:       "/opt/resource/in",
+			ExitStatus: 1,
+		})).Should(Equal(StepErrorCategoryUser))
+	})
+
+	It("categorizes a resource script printing malformed output as a user error", func() {
+		Ω(CategorizeError(resource.ErrResourceOutputMalformed{
+			Path:   "/opt/resource/check",
+			Reason: "unexpected trailing data after JSON value",
+		})).Should(Equal(StepErrorCategoryUser))
+	})
+
+	It("categorizes a resource script printing too much output as a user error", func() {
+		Ω(CategorizeError(resource.ErrResourceOutputTooLarge{
+			Path:  "/opt/resource/check",
+			Limit: 1024,
+		})).Should(Equal(StepErrorCategoryUser))
+	})
+
+	It("categorizes a resource script's output being cut off as a system error", func() {
+		Ω(CategorizeError(resource.ErrResourceOutputTruncated{
+			Path:   "/opt/resource/check",
+			Stdout: `[{"ref":"a`,
+		})).Should(Equal(StepErrorCategorySystem))
+	})
+
+	It("categorizes a missing credential as a user error", func() {
+		Ω(CategorizeError(credentials.MissingCredentialError{Key: "some-secret"})).Should(Equal(StepErrorCategoryUser))
+	})
+
+	It("categorizes missing inputs as a user error", func() {
+		Ω(CategorizeError(MissingInputsError{Inputs: []string{"some-input"}})).Should(Equal(StepErrorCategoryUser))
+	})
+
+	It("categorizes an invalid task config as a user error", func() {
+		Ω(CategorizeError(atc.InvalidTaskConfigError{
+			Errors: []string{"missing 'platform'"},
+		})).Should(Equal(StepErrorCategoryUser))
+	})
+
+	It("categorizes a missing config file as a user error", func() {
+		Ω(CategorizeError(FileNotFoundError{Path: "some/config.yml"})).Should(Equal(StepErrorCategoryUser))
+	})
+
+	It("categorizes a config path that is a directory as a user error", func() {
+		Ω(CategorizeError(FileIsDirectoryError{Path: "some/config"})).Should(Equal(StepErrorCategoryUser))
+	})
+
+	It("categorizes timing out as a user error", func() {
+		Ω(CategorizeError(ErrStepTimedOut)).Should(Equal(StepErrorCategoryUser))
+	})
+
+	It("categorizes having no compatible workers as a user error", func() {
+		Ω(CategorizeError(worker.NoCompatibleWorkersError{})).Should(Equal(StepErrorCategoryUser))
+	})
+
+	It("categorizes losing the worker as a system error", func() {
+		Ω(CategorizeError(worker.WorkerGoneError{
+			Handle: "some-handle",
+			Err:    errors.New("unexpected EOF"),
+		})).Should(Equal(StepErrorCategorySystem))
+	})
+
+	It("categorizes failing to dial garden as a system error", func() {
+		Ω(CategorizeError(&net.OpError{
+			Op:  "dial",
+			Net: "tcp",
+			Err: errors.New("connection refused"),
+		})).Should(Equal(StepErrorCategorySystem))
+	})
+
+	It("categorizes unknown errors as system errors", func() {
+		Ω(CategorizeError(errors.New("oh no"))).Should(Equal(StepErrorCategorySystem))
+	})
+
+	It("categorizes interruption as interrupted", func() {
+		Ω(CategorizeError(ErrInterrupted)).Should(Equal(StepErrorCategoryInterrupted))
+		Ω(CategorizeError(resource.ErrAborted)).Should(Equal(StepErrorCategoryInterrupted))
+	})
+})
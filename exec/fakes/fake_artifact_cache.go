@@ -0,0 +1,141 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"io"
+	"sync"
+
+	"github.com/concourse/atc/exec"
+	"github.com/concourse/atc/resource"
+)
+
+type FakeArtifactCache struct {
+	GetStub        func(resource.CacheIdentifier) (io.ReadCloser, bool)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		arg1 resource.CacheIdentifier
+	}
+	getReturns struct {
+		result1 io.ReadCloser
+		result2 bool
+	}
+	PutStub        func(resource.CacheIdentifier, io.Reader) error
+	putMutex       sync.RWMutex
+	putArgsForCall []struct {
+		arg1 resource.CacheIdentifier
+		arg2 io.Reader
+	}
+	putReturns struct {
+		result1 error
+	}
+	RemoveStub        func(resource.CacheIdentifier) (int, error)
+	removeMutex       sync.RWMutex
+	removeArgsForCall []struct {
+		arg1 resource.CacheIdentifier
+	}
+	removeReturns struct {
+		result1 int
+		result2 error
+	}
+}
+
+func (fake *FakeArtifactCache) Get(arg1 resource.CacheIdentifier) (io.ReadCloser, bool) {
+	fake.getMutex.Lock()
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		arg1 resource.CacheIdentifier
+	}{arg1})
+	fake.getMutex.Unlock()
+	if fake.GetStub != nil {
+		return fake.GetStub(arg1)
+	} else {
+		return fake.getReturns.result1, fake.getReturns.result2
+	}
+}
+
+func (fake *FakeArtifactCache) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakeArtifactCache) GetArgsForCall(i int) resource.CacheIdentifier {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return fake.getArgsForCall[i].arg1
+}
+
+func (fake *FakeArtifactCache) GetReturns(result1 io.ReadCloser, result2 bool) {
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 io.ReadCloser
+		result2 bool
+	}{result1, result2}
+}
+
+func (fake *FakeArtifactCache) Put(arg1 resource.CacheIdentifier, arg2 io.Reader) error {
+	fake.putMutex.Lock()
+	fake.putArgsForCall = append(fake.putArgsForCall, struct {
+		arg1 resource.CacheIdentifier
+		arg2 io.Reader
+	}{arg1, arg2})
+	fake.putMutex.Unlock()
+	if fake.PutStub != nil {
+		return fake.PutStub(arg1, arg2)
+	} else {
+		return fake.putReturns.result1
+	}
+}
+
+func (fake *FakeArtifactCache) PutCallCount() int {
+	fake.putMutex.RLock()
+	defer fake.putMutex.RUnlock()
+	return len(fake.putArgsForCall)
+}
+
+func (fake *FakeArtifactCache) PutArgsForCall(i int) (resource.CacheIdentifier, io.Reader) {
+	fake.putMutex.RLock()
+	defer fake.putMutex.RUnlock()
+	return fake.putArgsForCall[i].arg1, fake.putArgsForCall[i].arg2
+}
+
+func (fake *FakeArtifactCache) PutReturns(result1 error) {
+	fake.PutStub = nil
+	fake.putReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeArtifactCache) Remove(arg1 resource.CacheIdentifier) (int, error) {
+	fake.removeMutex.Lock()
+	fake.removeArgsForCall = append(fake.removeArgsForCall, struct {
+		arg1 resource.CacheIdentifier
+	}{arg1})
+	fake.removeMutex.Unlock()
+	if fake.RemoveStub != nil {
+		return fake.RemoveStub(arg1)
+	} else {
+		return fake.removeReturns.result1, fake.removeReturns.result2
+	}
+}
+
+func (fake *FakeArtifactCache) RemoveCallCount() int {
+	fake.removeMutex.RLock()
+	defer fake.removeMutex.RUnlock()
+	return len(fake.removeArgsForCall)
+}
+
+func (fake *FakeArtifactCache) RemoveArgsForCall(i int) resource.CacheIdentifier {
+	fake.removeMutex.RLock()
+	defer fake.removeMutex.RUnlock()
+	return fake.removeArgsForCall[i].arg1
+}
+
+func (fake *FakeArtifactCache) RemoveReturns(result1 int, result2 error) {
+	fake.RemoveStub = nil
+	fake.removeReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+var _ exec.ArtifactCache = new(FakeArtifactCache)
@@ -0,0 +1,98 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"io"
+	"sync"
+
+	"github.com/concourse/atc/exec"
+)
+
+type FakeCompressedArtifactDestination struct {
+	StreamInStub        func(string, io.Reader) error
+	streamInMutex       sync.RWMutex
+	streamInArgsForCall []struct {
+		arg1 string
+		arg2 io.Reader
+	}
+	streamInReturns struct {
+		result1 error
+	}
+	StreamInCompressedStub        func(string, io.Reader) error
+	streamInCompressedMutex       sync.RWMutex
+	streamInCompressedArgsForCall []struct {
+		arg1 string
+		arg2 io.Reader
+	}
+	streamInCompressedReturns struct {
+		result1 error
+	}
+}
+
+func (fake *FakeCompressedArtifactDestination) StreamIn(arg1 string, arg2 io.Reader) error {
+	fake.streamInMutex.Lock()
+	fake.streamInArgsForCall = append(fake.streamInArgsForCall, struct {
+		arg1 string
+		arg2 io.Reader
+	}{arg1, arg2})
+	fake.streamInMutex.Unlock()
+	if fake.StreamInStub != nil {
+		return fake.StreamInStub(arg1, arg2)
+	} else {
+		return fake.streamInReturns.result1
+	}
+}
+
+func (fake *FakeCompressedArtifactDestination) StreamInCallCount() int {
+	fake.streamInMutex.RLock()
+	defer fake.streamInMutex.RUnlock()
+	return len(fake.streamInArgsForCall)
+}
+
+func (fake *FakeCompressedArtifactDestination) StreamInArgsForCall(i int) (string, io.Reader) {
+	fake.streamInMutex.RLock()
+	defer fake.streamInMutex.RUnlock()
+	return fake.streamInArgsForCall[i].arg1, fake.streamInArgsForCall[i].arg2
+}
+
+func (fake *FakeCompressedArtifactDestination) StreamInReturns(result1 error) {
+	fake.StreamInStub = nil
+	fake.streamInReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCompressedArtifactDestination) StreamInCompressed(arg1 string, arg2 io.Reader) error {
+	fake.streamInCompressedMutex.Lock()
+	fake.streamInCompressedArgsForCall = append(fake.streamInCompressedArgsForCall, struct {
+		arg1 string
+		arg2 io.Reader
+	}{arg1, arg2})
+	fake.streamInCompressedMutex.Unlock()
+	if fake.StreamInCompressedStub != nil {
+		return fake.StreamInCompressedStub(arg1, arg2)
+	} else {
+		return fake.streamInCompressedReturns.result1
+	}
+}
+
+func (fake *FakeCompressedArtifactDestination) StreamInCompressedCallCount() int {
+	fake.streamInCompressedMutex.RLock()
+	defer fake.streamInCompressedMutex.RUnlock()
+	return len(fake.streamInCompressedArgsForCall)
+}
+
+func (fake *FakeCompressedArtifactDestination) StreamInCompressedArgsForCall(i int) (string, io.Reader) {
+	fake.streamInCompressedMutex.RLock()
+	defer fake.streamInCompressedMutex.RUnlock()
+	return fake.streamInCompressedArgsForCall[i].arg1, fake.streamInCompressedArgsForCall[i].arg2
+}
+
+func (fake *FakeCompressedArtifactDestination) StreamInCompressedReturns(result1 error) {
+	fake.StreamInCompressedStub = nil
+	fake.streamInCompressedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ exec.CompressedArtifactDestination = new(FakeCompressedArtifactDestination)
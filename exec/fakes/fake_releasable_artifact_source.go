@@ -0,0 +1,126 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"io"
+	"sync"
+
+	"github.com/concourse/atc/exec"
+)
+
+type FakeReleasableArtifactSource struct {
+	StreamToStub        func(exec.ArtifactDestination) error
+	streamToMutex       sync.RWMutex
+	streamToArgsForCall []struct {
+		arg1 exec.ArtifactDestination
+	}
+	streamToReturns struct {
+		result1 error
+	}
+	StreamFileStub        func(path string) (io.ReadCloser, error)
+	streamFileMutex       sync.RWMutex
+	streamFileArgsForCall []struct {
+		path string
+	}
+	streamFileReturns struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	ReleaseStub        func() error
+	releaseMutex       sync.RWMutex
+	releaseArgsForCall []struct{}
+	releaseReturns     struct {
+		result1 error
+	}
+}
+
+func (fake *FakeReleasableArtifactSource) StreamTo(arg1 exec.ArtifactDestination) error {
+	fake.streamToMutex.Lock()
+	fake.streamToArgsForCall = append(fake.streamToArgsForCall, struct {
+		arg1 exec.ArtifactDestination
+	}{arg1})
+	fake.streamToMutex.Unlock()
+	if fake.StreamToStub != nil {
+		return fake.StreamToStub(arg1)
+	} else {
+		return fake.streamToReturns.result1
+	}
+}
+
+func (fake *FakeReleasableArtifactSource) StreamToCallCount() int {
+	fake.streamToMutex.RLock()
+	defer fake.streamToMutex.RUnlock()
+	return len(fake.streamToArgsForCall)
+}
+
+func (fake *FakeReleasableArtifactSource) StreamToArgsForCall(i int) exec.ArtifactDestination {
+	fake.streamToMutex.RLock()
+	defer fake.streamToMutex.RUnlock()
+	return fake.streamToArgsForCall[i].arg1
+}
+
+func (fake *FakeReleasableArtifactSource) StreamToReturns(result1 error) {
+	fake.StreamToStub = nil
+	fake.streamToReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeReleasableArtifactSource) StreamFile(path string) (io.ReadCloser, error) {
+	fake.streamFileMutex.Lock()
+	fake.streamFileArgsForCall = append(fake.streamFileArgsForCall, struct {
+		path string
+	}{path})
+	fake.streamFileMutex.Unlock()
+	if fake.StreamFileStub != nil {
+		return fake.StreamFileStub(path)
+	} else {
+		return fake.streamFileReturns.result1, fake.streamFileReturns.result2
+	}
+}
+
+func (fake *FakeReleasableArtifactSource) StreamFileCallCount() int {
+	fake.streamFileMutex.RLock()
+	defer fake.streamFileMutex.RUnlock()
+	return len(fake.streamFileArgsForCall)
+}
+
+func (fake *FakeReleasableArtifactSource) StreamFileArgsForCall(i int) string {
+	fake.streamFileMutex.RLock()
+	defer fake.streamFileMutex.RUnlock()
+	return fake.streamFileArgsForCall[i].path
+}
+
+func (fake *FakeReleasableArtifactSource) StreamFileReturns(result1 io.ReadCloser, result2 error) {
+	fake.StreamFileStub = nil
+	fake.streamFileReturns = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReleasableArtifactSource) Release() error {
+	fake.releaseMutex.Lock()
+	fake.releaseArgsForCall = append(fake.releaseArgsForCall, struct{}{})
+	fake.releaseMutex.Unlock()
+	if fake.ReleaseStub != nil {
+		return fake.ReleaseStub()
+	} else {
+		return fake.releaseReturns.result1
+	}
+}
+
+func (fake *FakeReleasableArtifactSource) ReleaseCallCount() int {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	return len(fake.releaseArgsForCall)
+}
+
+func (fake *FakeReleasableArtifactSource) ReleaseReturns(result1 error) {
+	fake.ReleaseStub = nil
+	fake.releaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ exec.ReleasableArtifactSource = new(FakeReleasableArtifactSource)
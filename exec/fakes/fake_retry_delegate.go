@@ -0,0 +1,41 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/exec"
+)
+
+type FakeRetryDelegate struct {
+	RetryingStub        func(attempt int)
+	retryingMutex       sync.RWMutex
+	retryingArgsForCall []struct {
+		attempt int
+	}
+}
+
+func (fake *FakeRetryDelegate) Retrying(attempt int) {
+	fake.retryingMutex.Lock()
+	fake.retryingArgsForCall = append(fake.retryingArgsForCall, struct {
+		attempt int
+	}{attempt})
+	fake.retryingMutex.Unlock()
+	if fake.RetryingStub != nil {
+		fake.RetryingStub(attempt)
+	}
+}
+
+func (fake *FakeRetryDelegate) RetryingCallCount() int {
+	fake.retryingMutex.RLock()
+	defer fake.retryingMutex.RUnlock()
+	return len(fake.retryingArgsForCall)
+}
+
+func (fake *FakeRetryDelegate) RetryingArgsForCall(i int) int {
+	fake.retryingMutex.RLock()
+	defer fake.retryingMutex.RUnlock()
+	return fake.retryingArgsForCall[i].attempt
+}
+
+var _ exec.RetryDelegate = new(FakeRetryDelegate)
@@ -18,12 +18,15 @@ type FakeStep struct {
 	runReturns struct {
 		result1 error
 	}
-	ReleaseStub        func()
+	ReleaseStub        func() error
 	releaseMutex       sync.RWMutex
 	releaseArgsForCall []struct{}
-	ResultStub         func(interface{}) bool
-	resultMutex        sync.RWMutex
-	resultArgsForCall  []struct {
+	releaseReturns     struct {
+		result1 error
+	}
+	ResultStub        func(interface{}) bool
+	resultMutex       sync.RWMutex
+	resultArgsForCall []struct {
 		arg1 interface{}
 	}
 	resultReturns struct {
@@ -64,12 +67,14 @@ func (fake *FakeStep) RunReturns(result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStep) Release() {
+func (fake *FakeStep) Release() error {
 	fake.releaseMutex.Lock()
 	fake.releaseArgsForCall = append(fake.releaseArgsForCall, struct{}{})
 	fake.releaseMutex.Unlock()
 	if fake.ReleaseStub != nil {
-		fake.ReleaseStub()
+		return fake.ReleaseStub()
+	} else {
+		return fake.releaseReturns.result1
 	}
 }
 
@@ -79,6 +84,13 @@ func (fake *FakeStep) ReleaseCallCount() int {
 	return len(fake.releaseArgsForCall)
 }
 
+func (fake *FakeStep) ReleaseReturns(result1 error) {
+	fake.ReleaseStub = nil
+	fake.releaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeStep) Result(arg1 interface{}) bool {
 	fake.resultMutex.Lock()
 	fake.resultArgsForCall = append(fake.resultArgsForCall, struct {
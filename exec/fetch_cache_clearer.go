@@ -0,0 +1,28 @@
+package exec
+
+import "github.com/concourse/atc/resource"
+
+// FetchCacheClearer invalidates everything cached for a fetched resource
+// version, whatever params it was fetched with: the fetches themselves, so
+// that the next get runs the resource's script again, and the artifact they
+// produced.
+type FetchCacheClearer struct {
+	ResourceCache resource.Cache
+	ArtifactCache ArtifactCache
+}
+
+// Clear returns how many cache entries were removed.
+func (clearer FetchCacheClearer) Clear(identifier resource.CacheIdentifier) (int, error) {
+	cleared := clearer.ResourceCache.Clear(identifier)
+
+	if clearer.ArtifactCache != nil {
+		removed, err := clearer.ArtifactCache.Remove(identifier)
+		cleared += removed
+
+		if err != nil {
+			return cleared, err
+		}
+	}
+
+	return cleared, nil
+}
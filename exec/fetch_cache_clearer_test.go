@@ -0,0 +1,120 @@
+package exec_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/concourse/atc"
+	. "github.com/concourse/atc/exec"
+	"github.com/concourse/atc/resource"
+	"github.com/pivotal-golang/clock/fakeclock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FetchCacheClearer", func() {
+	var (
+		resourceCache resource.Cache
+		artifactCache ArtifactCache
+		artifactDir   string
+
+		identifier resource.CacheIdentifier
+
+		clearer FetchCacheClearer
+	)
+
+	BeforeEach(func() {
+		var err error
+		artifactDir, err = ioutil.TempDir("", "artifact-cache")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		resourceCache = resource.NewCache(time.Minute, fakeclock.NewFakeClock(time.Unix(123, 0)))
+		artifactCache, err = NewDirArtifactCache(artifactDir, 0)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		identifier = resource.CacheIdentifier{
+			Type:    "some-type",
+			Source:  atc.Source{"some": "source"},
+			Version: atc.Version{"some": "version"},
+		}
+
+		clearer = FetchCacheClearer{
+			ResourceCache: resourceCache,
+			ArtifactCache: artifactCache,
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(artifactDir)
+	})
+
+	Context("when the fetch and its artifact are cached", func() {
+		BeforeEach(func() {
+			resourceCache.Save(identifier, resource.CachedFetch{
+				Version: atc.Version{"some": "version"},
+			})
+
+			err := artifactCache.Put(identifier, bytes.NewBufferString("some-artifact"))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("clears both, so that the next get misses", func() {
+			cleared, err := clearer.Clear(identifier)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(cleared).Should(Equal(2))
+
+			_, found := resourceCache.Lookup(identifier)
+			Ω(found).Should(BeFalse())
+
+			_, found = artifactCache.Get(identifier)
+			Ω(found).Should(BeFalse())
+		})
+	})
+
+	Context("when nothing is cached", func() {
+		It("clears nothing", func() {
+			cleared, err := clearer.Clear(identifier)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(cleared).Should(BeZero())
+		})
+	})
+
+	Context("when the version was fetched with different params", func() {
+		BeforeEach(func() {
+			withParams := identifier
+			withParams.Params = atc.Params{"skip_download": true}
+
+			resourceCache.Save(identifier, resource.CachedFetch{})
+			resourceCache.Save(withParams, resource.CachedFetch{})
+
+			err := artifactCache.Put(identifier, bytes.NewBufferString("some-artifact"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			err = artifactCache.Put(withParams, bytes.NewBufferString("some-other-artifact"))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("clears every fetch of the version, and their artifacts", func() {
+			cleared, err := clearer.Clear(identifier)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(cleared).Should(Equal(4))
+		})
+	})
+
+	Context("without an artifact cache", func() {
+		BeforeEach(func() {
+			clearer.ArtifactCache = nil
+
+			resourceCache.Save(identifier, resource.CachedFetch{})
+		})
+
+		It("clears only the fetch", func() {
+			cleared, err := clearer.Clear(identifier)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(cleared).Should(Equal(1))
+		})
+	})
+})
@@ -3,18 +3,25 @@ package exec
 import (
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/cloudfoundry-incubator/garden"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/credentials"
 	"github.com/concourse/atc/resource"
 	"github.com/concourse/atc/worker"
 )
 
 type gardenFactory struct {
-	workerClient    worker.Client
-	resourceTracker resource.Tracker
-	uuidGenerator   UUIDGenFunc
+	workerClient      worker.Client
+	resourceTracker   resource.Tracker
+	resourceCache     resource.Cache
+	artifactCache     ArtifactCache
+	credentialManager credentials.CredentialManager
+	taskGraceTime     time.Duration
+	compressStreams   bool
+	uuidGenerator     UUIDGenFunc
 }
 
 type UUIDGenFunc func() string
@@ -22,12 +29,22 @@ type UUIDGenFunc func() string
 func NewGardenFactory(
 	workerClient worker.Client,
 	resourceTracker resource.Tracker,
+	resourceCache resource.Cache,
+	artifactCache ArtifactCache,
+	credentialManager credentials.CredentialManager,
+	taskGraceTime time.Duration,
+	compressArtifactStreams bool,
 	uuidGenerator UUIDGenFunc,
 ) Factory {
 	return &gardenFactory{
-		workerClient:    workerClient,
-		resourceTracker: resourceTracker,
-		uuidGenerator:   uuidGenerator,
+		workerClient:      workerClient,
+		resourceTracker:   resourceTracker,
+		resourceCache:     resourceCache,
+		artifactCache:     artifactCache,
+		credentialManager: credentialManager,
+		taskGraceTime:     taskGraceTime,
+		compressStreams:   compressArtifactStreams,
+		uuidGenerator:     uuidGenerator,
 	}
 }
 
@@ -38,6 +55,7 @@ func (factory *gardenFactory) DependentGet(sourceName SourceName, id worker.Iden
 		Session: resource.Session{
 			ID:        id,
 			Ephemeral: false,
+			Proxy:     config.Proxy,
 		},
 
 		Delegate: delegate,
@@ -46,22 +64,36 @@ func (factory *gardenFactory) DependentGet(sourceName SourceName, id worker.Iden
 		Type:    resource.ResourceType(config.Type),
 		Tags:    tags,
 
-		Action: func(r resource.Resource, s ArtifactSource, vi VersionInfo) resource.VersionedSource {
+		Source:            config.Source,
+		CredentialManager: factory.credentialManager,
+
+		Action: func(r resource.Resource, source atc.Source, s ArtifactSource, vi VersionInfo) resource.VersionedSource {
 			return r.Get(resource.IOConfig{
 				Stdout: delegate.Stdout(),
 				Stderr: delegate.Stderr(),
-			}, config.Source, params, vi.Version)
+			}, source, params, vi.Version)
 		},
 	}
 }
 
 func (factory *gardenFactory) Get(sourceName SourceName, id worker.Identifier, delegate GetDelegate, config atc.ResourceConfig, params atc.Params, tags atc.Tags, version atc.Version) StepFactory {
+	var cacheIdentifier *resource.CacheIdentifier
+	if version != nil {
+		cacheIdentifier = &resource.CacheIdentifier{
+			Type:    resource.ResourceType(config.Type),
+			Source:  config.Source,
+			Params:  params,
+			Version: version,
+		}
+	}
+
 	return resourceStep{
 		SourceName: sourceName,
 
 		Session: resource.Session{
 			ID:        id,
 			Ephemeral: false,
+			Proxy:     config.Proxy,
 		},
 
 		Delegate: delegate,
@@ -70,11 +102,18 @@ func (factory *gardenFactory) Get(sourceName SourceName, id worker.Identifier, d
 		Type:    resource.ResourceType(config.Type),
 		Tags:    tags,
 
-		Action: func(r resource.Resource, s ArtifactSource, vi VersionInfo) resource.VersionedSource {
+		Source:            config.Source,
+		CredentialManager: factory.credentialManager,
+
+		Cache:           factory.resourceCache,
+		CacheIdentifier: cacheIdentifier,
+		ArtifactCache:   factory.artifactCache,
+
+		Action: func(r resource.Resource, source atc.Source, s ArtifactSource, vi VersionInfo) resource.VersionedSource {
 			return r.Get(resource.IOConfig{
 				Stdout: delegate.Stdout(),
 				Stderr: delegate.Stderr(),
-			}, config.Source, params, version)
+			}, source, params, version)
 		},
 	}
 }
@@ -82,7 +121,8 @@ func (factory *gardenFactory) Get(sourceName SourceName, id worker.Identifier, d
 func (factory *gardenFactory) Put(id worker.Identifier, delegate PutDelegate, config atc.ResourceConfig, tags atc.Tags, params atc.Params) StepFactory {
 	return resourceStep{
 		Session: resource.Session{
-			ID: id,
+			ID:    id,
+			Proxy: config.Proxy,
 		},
 
 		Delegate: delegate,
@@ -91,11 +131,14 @@ func (factory *gardenFactory) Put(id worker.Identifier, delegate PutDelegate, co
 		Type:    resource.ResourceType(config.Type),
 		Tags:    tags,
 
-		Action: func(r resource.Resource, s ArtifactSource, vi VersionInfo) resource.VersionedSource {
+		Source:            config.Source,
+		CredentialManager: factory.credentialManager,
+
+		Action: func(r resource.Resource, source atc.Source, s ArtifactSource, vi VersionInfo) resource.VersionedSource {
 			return r.Put(resource.IOConfig{
 				Stdout: delegate.Stdout(),
 				Stderr: delegate.Stderr(),
-			}, config.Source, params, resourceSource{s})
+			}, source, params, resourceSource{s, factory.compressStreams})
 		},
 	}
 }
@@ -115,7 +158,10 @@ func (factory *gardenFactory) Task(sourceName SourceName, id worker.Identifier,
 		Privileged:   privileged,
 		ConfigSource: configSource,
 
-		WorkerClient: factory.workerClient,
+		WorkerClient:      factory.workerClient,
+		CredentialManager: factory.credentialManager,
+		GraceTime:         factory.taskGraceTime,
+		CompressStreams:   factory.compressStreams,
 
 		artifactsRoot: artifactsRoot,
 	}
@@ -155,8 +201,10 @@ func (reporter failureReporter) Run(signals <-chan os.Signal, ready chan<- struc
 
 type resourceSource struct {
 	ArtifactSource
+
+	compress bool
 }
 
 func (source resourceSource) StreamTo(dest resource.ArtifactDestination) error {
-	return source.ArtifactSource.StreamTo(resource.ArtifactDestination(dest))
+	return source.ArtifactSource.StreamTo(compressedIfEnabled(dest, source.compress))
 }
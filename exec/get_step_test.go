@@ -2,12 +2,18 @@ package exec_test
 
 import (
 	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"errors"
 	"io"
 	"io/ioutil"
 	"os"
+	"sync/atomic"
+	"time"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/credentials"
+	cfakes "github.com/concourse/atc/credentials/fakes"
 	. "github.com/concourse/atc/exec"
 	"github.com/concourse/atc/exec/fakes"
 	"github.com/concourse/atc/resource"
@@ -17,12 +23,14 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-golang/clock/fakeclock"
 	"github.com/tedsuo/ifrit"
 )
 
 var _ = Describe("GardenFactory", func() {
 	var (
 		fakeTracker      *rfakes.FakeTracker
+		fakeCache        *rfakes.FakeCache
 		fakeWorkerClient *wfakes.FakeClient
 
 		factory Factory
@@ -39,9 +47,10 @@ var _ = Describe("GardenFactory", func() {
 
 	BeforeEach(func() {
 		fakeTracker = new(rfakes.FakeTracker)
+		fakeCache = new(rfakes.FakeCache)
 		fakeWorkerClient = new(wfakes.FakeClient)
 
-		factory = NewGardenFactory(fakeWorkerClient, fakeTracker, func() string { return "" })
+		factory = NewGardenFactory(fakeWorkerClient, fakeTracker, fakeCache, nil, nil, 0, false, func() string { return "" })
 
 		stdoutBuf = gbytes.NewBuffer()
 		stderrBuf = gbytes.NewBuffer()
@@ -125,6 +134,43 @@ var _ = Describe("GardenFactory", func() {
 				Ω(gotVersion).Should(Equal(version))
 			})
 
+			Context("when the source refers to a credential", func() {
+				var fakeCredentialManager *cfakes.FakeCredentialManager
+
+				BeforeEach(func() {
+					fakeCredentialManager = new(cfakes.FakeCredentialManager)
+					factory = NewGardenFactory(fakeWorkerClient, fakeTracker, fakeCache, nil, fakeCredentialManager, 0, false, func() string { return "" })
+
+					resourceConfig.Source = atc.Source{"some": "source", "token": "((some-token))"}
+				})
+
+				Context("when the credential can be found", func() {
+					BeforeEach(func() {
+						fakeCredentialManager.GetReturns("some-secret-token", nil)
+					})
+
+					It("gets the resource with the credential resolved", func() {
+						Ω(fakeResource.GetCallCount()).Should(Equal(1))
+
+						_, gotSource, _, _ := fakeResource.GetArgsForCall(0)
+						Ω(gotSource).Should(Equal(atc.Source{"some": "source", "token": "some-secret-token"}))
+
+						Ω(fakeCredentialManager.GetArgsForCall(0)).Should(Equal("some-token"))
+					})
+				})
+
+				Context("when the credential is missing", func() {
+					BeforeEach(func() {
+						fakeCredentialManager.GetReturns("", credentials.MissingCredentialError{Key: "some-token"})
+					})
+
+					It("exits with the error, without initializing the resource", func() {
+						Eventually(process.Wait()).Should(Receive(Equal(credentials.MissingCredentialError{Key: "some-token"})))
+						Ω(fakeTracker.InitCallCount()).Should(BeZero())
+					})
+				})
+			})
+
 			It("gets the resource with the io config forwarded", func() {
 				Ω(fakeResource.GetCallCount()).Should(Equal(1))
 
@@ -239,6 +285,237 @@ var _ = Describe("GardenFactory", func() {
 				})
 			})
 
+			Describe("caching", func() {
+				It("looks up the fetch by the resource's type, source, params, and version", func() {
+					Eventually(process.Wait()).Should(Receive(BeNil()))
+
+					Ω(fakeCache.LookupCallCount()).Should(Equal(1))
+					Ω(fakeCache.LookupArgsForCall(0)).Should(Equal(resource.CacheIdentifier{
+						Type:    "some-resource-type",
+						Source:  atc.Source{"some": "source"},
+						Params:  atc.Params{"some-param": "some-value"},
+						Version: atc.Version{"some-version": "some-value"},
+					}))
+				})
+
+				Context("when the fetch is not cached", func() {
+					BeforeEach(func() {
+						fakeCache.LookupReturns(resource.CachedFetch{}, false)
+					})
+
+					It("runs the get resource action", func() {
+						Eventually(process.Wait()).Should(Receive(BeNil()))
+
+						Ω(fakeResource.GetCallCount()).Should(Equal(1))
+						Ω(fakeVersionedSource.RunCallCount()).Should(Equal(1))
+					})
+
+					It("records the fetch in the cache", func() {
+						Eventually(process.Wait()).Should(Receive(BeNil()))
+
+						Ω(fakeCache.SaveCallCount()).Should(Equal(1))
+
+						cacheIdentifier, fetch := fakeCache.SaveArgsForCall(0)
+						Ω(cacheIdentifier.Version).Should(Equal(atc.Version{"some-version": "some-value"}))
+						Ω(fetch).Should(Equal(resource.CachedFetch{
+							Session: resource.Session{
+								ID: identifier,
+							},
+							Version:  atc.Version{"some": "version"},
+							Metadata: []atc.MetadataField{{"some", "metadata"}},
+						}))
+					})
+
+					It("gives back its reference on the fetch when released", func() {
+						Eventually(process.Wait()).Should(Receive(BeNil()))
+
+						Ω(fakeCache.ReleaseCallCount()).Should(BeZero())
+
+						step.Release()
+
+						Ω(fakeCache.ReleaseCallCount()).Should(Equal(1))
+
+						savedIdentifier, savedFetch := fakeCache.SaveArgsForCall(0)
+						releasedIdentifier, releasedFetch := fakeCache.ReleaseArgsForCall(0)
+						Ω(releasedIdentifier).Should(Equal(savedIdentifier))
+						Ω(releasedFetch).Should(Equal(savedFetch))
+					})
+
+					Context("when fetching fails", func() {
+						BeforeEach(func() {
+							fakeVersionedSource.RunReturns(errors.New("nope"))
+						})
+
+						It("does not record the fetch", func() {
+							Eventually(process.Wait()).Should(Receive())
+							Ω(fakeCache.SaveCallCount()).Should(BeZero())
+						})
+					})
+				})
+
+				Context("when the fetch is cached", func() {
+					var (
+						cachedSession resource.Session
+
+						fakeCachedResource *rfakes.FakeResource
+						fakeCachedSource   *rfakes.FakeVersionedSource
+					)
+
+					BeforeEach(func() {
+						cachedSession = resource.Session{
+							ID: worker.Identifier{Name: "some-previous-session-id"},
+						}
+
+						fakeCache.LookupReturns(resource.CachedFetch{
+							Session:  cachedSession,
+							Version:  atc.Version{"some": "cached-version"},
+							Metadata: []atc.MetadataField{{"some", "cached-metadata"}},
+						}, true)
+
+						fakeCachedSource = new(rfakes.FakeVersionedSource)
+						fakeCachedSource.VersionReturns(atc.Version{"some": "cached-version"})
+						fakeCachedSource.MetadataReturns([]atc.MetadataField{{"some", "cached-metadata"}})
+
+						fakeCachedResource = new(rfakes.FakeResource)
+						fakeCachedResource.CachedGetReturns(fakeCachedSource)
+					})
+
+					Context("and its container is still around", func() {
+						BeforeEach(func() {
+							fakeTracker.LookupReturns(fakeCachedResource, nil)
+						})
+
+						It("looks up the resource for the cached session", func() {
+							Eventually(process.Wait()).Should(Receive(BeNil()))
+
+							Ω(fakeTracker.LookupCallCount()).Should(Equal(1))
+							session, typ := fakeTracker.LookupArgsForCall(0)
+							Ω(session).Should(Equal(cachedSession))
+							Ω(typ).Should(Equal(resource.ResourceType("some-resource-type")))
+						})
+
+						It("does not run the get resource action", func() {
+							Eventually(process.Wait()).Should(Receive(BeNil()))
+
+							Ω(fakeTracker.InitCallCount()).Should(BeZero())
+							Ω(fakeResource.GetCallCount()).Should(BeZero())
+							Ω(fakeVersionedSource.RunCallCount()).Should(BeZero())
+						})
+
+						It("uses the cached version and metadata", func() {
+							Eventually(process.Wait()).Should(Receive(BeNil()))
+
+							Ω(fakeCachedResource.CachedGetCallCount()).Should(Equal(1))
+							version, metadata := fakeCachedResource.CachedGetArgsForCall(0)
+							Ω(version).Should(Equal(atc.Version{"some": "cached-version"}))
+							Ω(metadata).Should(Equal([]atc.MetadataField{{"some", "cached-metadata"}}))
+						})
+
+						It("completes with the cached version info", func() {
+							Eventually(getDelegate.CompletedCallCount).Should(Equal(1))
+
+							exitStatus, versionInfo := getDelegate.CompletedArgsForCall(0)
+							Ω(exitStatus).Should(Equal(ExitStatus(0)))
+							Ω(versionInfo).Should(Equal(&VersionInfo{
+								Version:  atc.Version{"some": "cached-version"},
+								Metadata: []atc.MetadataField{{"some", "cached-metadata"}},
+							}))
+						})
+
+						It("does not record the fetch again", func() {
+							Eventually(process.Wait()).Should(Receive(BeNil()))
+							Ω(fakeCache.SaveCallCount()).Should(BeZero())
+						})
+
+						It("releases the cached resource", func() {
+							Eventually(process.Wait()).Should(Receive(BeNil()))
+
+							step.Release()
+							Ω(fakeCachedResource.ReleaseCallCount()).Should(Equal(1))
+						})
+
+						It("gives back its reference on the cached fetch when released", func() {
+							Eventually(process.Wait()).Should(Receive(BeNil()))
+
+							Ω(fakeCache.ReleaseCallCount()).Should(BeZero())
+
+							step.Release()
+
+							Ω(fakeCache.ReleaseCallCount()).Should(Equal(1))
+							_, releasedFetch := fakeCache.ReleaseArgsForCall(0)
+							Ω(releasedFetch.Session).Should(Equal(cachedSession))
+						})
+					})
+
+					Context("but its container has gone away", func() {
+						BeforeEach(func() {
+							fakeTracker.LookupReturns(nil, worker.ErrContainerNotFound)
+						})
+
+						It("falls back to running the get resource action", func() {
+							Eventually(process.Wait()).Should(Receive(BeNil()))
+
+							Ω(fakeTracker.InitCallCount()).Should(Equal(1))
+							Ω(fakeVersionedSource.RunCallCount()).Should(Equal(1))
+						})
+
+						It("gives back its reference on the cached fetch", func() {
+							Eventually(process.Wait()).Should(Receive(BeNil()))
+
+							Ω(fakeCache.ReleaseCallCount()).Should(Equal(1))
+							_, releasedFetch := fakeCache.ReleaseArgsForCall(0)
+							Ω(releasedFetch.Session).Should(Equal(cachedSession))
+						})
+
+						It("records the new fetch in the cache", func() {
+							Eventually(process.Wait()).Should(Receive(BeNil()))
+							Ω(fakeCache.SaveCallCount()).Should(Equal(1))
+						})
+					})
+				})
+
+				Context("when a later build gets the same version", func() {
+					var fakeCachedResource *rfakes.FakeResource
+
+					BeforeEach(func() {
+						resourceCache := resource.NewCache(time.Minute, fakeclock.NewFakeClock(time.Unix(123, 0)))
+						factory = NewGardenFactory(fakeWorkerClient, fakeTracker, resourceCache, nil, nil, 0, false, func() string { return "" })
+
+						fakeCachedResource = new(rfakes.FakeResource)
+						fakeCachedResource.CachedGetReturns(new(rfakes.FakeVersionedSource))
+						fakeTracker.LookupReturns(fakeCachedResource, nil)
+					})
+
+					It("reuses the earlier build's fetch once it is done, without running the script again", func() {
+						Eventually(process.Wait()).Should(Receive(BeNil()))
+						Ω(step.Release()).Should(Succeed())
+
+						laterIdentifier := worker.Identifier{Name: "some-later-session-id"}
+						laterStep := factory.Get(sourceName, laterIdentifier, getDelegate, resourceConfig, params, tags, version).Using(inStep, NewSourceRepository())
+						Eventually(ifrit.Invoke(laterStep).Wait()).Should(Receive(BeNil()))
+
+						Ω(fakeResource.GetCallCount()).Should(Equal(1))
+						Ω(fakeCachedResource.CachedGetCallCount()).Should(Equal(1))
+
+						session, _ := fakeTracker.LookupArgsForCall(0)
+						Ω(session.ID).Should(Equal(identifier))
+					})
+				})
+
+				Context("when no version is specified", func() {
+					BeforeEach(func() {
+						version = nil
+					})
+
+					It("does not consult the cache", func() {
+						Eventually(process.Wait()).Should(Receive(BeNil()))
+
+						Ω(fakeCache.LookupCallCount()).Should(BeZero())
+						Ω(fakeCache.SaveCallCount()).Should(BeZero())
+					})
+				})
+			})
+
 			Describe("releasing", func() {
 				It("releases the resource", func() {
 					Ω(fakeResource.ReleaseCallCount()).Should(BeZero())
@@ -268,12 +545,20 @@ var _ = Describe("GardenFactory", func() {
 
 					Context("when the resource can stream out", func() {
 						var (
-							streamedOut io.ReadCloser
+							streamedOut *gbytes.Buffer
+							streamedIn  []byte
 						)
 
 						BeforeEach(func() {
-							streamedOut = gbytes.NewBuffer()
+							streamedOut = gbytes.BufferWithBytes([]byte("some-stream"))
 							fakeVersionedSource.StreamOutReturns(streamedOut, nil)
+
+							streamedIn = nil
+							fakeDestination.StreamInStub = func(dst string, src io.Reader) error {
+								var err error
+								streamedIn, err = ioutil.ReadAll(src)
+								return err
+							}
 						})
 
 						It("streams the resource to the destination", func() {
@@ -284,9 +569,16 @@ var _ = Describe("GardenFactory", func() {
 							Ω(fakeVersionedSource.StreamOutArgsForCall(0)).Should(Equal("."))
 
 							Ω(fakeDestination.StreamInCallCount()).Should(Equal(1))
-							dest, src := fakeDestination.StreamInArgsForCall(0)
+							dest, _ := fakeDestination.StreamInArgsForCall(0)
 							Ω(dest).Should(Equal("."))
-							Ω(src).Should(Equal(streamedOut))
+							Ω(string(streamedIn)).Should(Equal("some-stream"))
+						})
+
+						It("closes the stream once the destination is done with it", func() {
+							err := artifactSource.StreamTo(fakeDestination)
+							Ω(err).ShouldNot(HaveOccurred())
+
+							Ω(streamedOut.Closed()).Should(BeTrue())
 						})
 
 						Context("when streaming out of the versioned source fails", func() {
@@ -301,10 +593,43 @@ var _ = Describe("GardenFactory", func() {
 							})
 						})
 
+						Context("when the destination accepts compressed streams", func() {
+							var compressedDestination *fakes.FakeCompressedArtifactDestination
+
+							BeforeEach(func() {
+								compressedDestination = new(fakes.FakeCompressedArtifactDestination)
+
+								streamedIn = nil
+								compressedDestination.StreamInCompressedStub = func(dst string, src io.Reader) error {
+									gzipReader, err := gzip.NewReader(src)
+									if err != nil {
+										return err
+									}
+
+									streamedIn, err = ioutil.ReadAll(gzipReader)
+									return err
+								}
+							})
+
+							It("streams the resource gzipped, preserving its bytes", func() {
+								err := artifactSource.StreamTo(compressedDestination)
+								Ω(err).ShouldNot(HaveOccurred())
+
+								Ω(compressedDestination.StreamInCompressedCallCount()).Should(Equal(1))
+								dest, _ := compressedDestination.StreamInCompressedArgsForCall(0)
+								Ω(dest).Should(Equal("."))
+								Ω(string(streamedIn)).Should(Equal("some-stream"))
+
+								Ω(compressedDestination.StreamInCallCount()).Should(BeZero())
+								Ω(streamedOut.Closed()).Should(BeTrue())
+							})
+						})
+
 						Context("when streaming in to the destination fails", func() {
 							disaster := errors.New("nope")
 
 							BeforeEach(func() {
+								fakeDestination.StreamInStub = nil
 								fakeDestination.StreamInReturns(disaster)
 							})
 
@@ -314,6 +639,92 @@ var _ = Describe("GardenFactory", func() {
 						})
 					})
 
+					Context("when the resource streams out more than the destination has read", func() {
+						var (
+							bytesWritten  int64
+							writingExited chan struct{}
+						)
+
+						BeforeEach(func() {
+							outReader, outWriter := io.Pipe()
+							fakeVersionedSource.StreamOutReturns(outReader, nil)
+
+							bytesWritten = 0
+							writingExited = make(chan struct{})
+
+							go func() {
+								defer close(writingExited)
+
+								chunk := make([]byte, 1024)
+
+								for {
+									n, err := outWriter.Write(chunk)
+									atomic.AddInt64(&bytesWritten, int64(n))
+
+									if err != nil {
+										return
+									}
+								}
+							}()
+						})
+
+						Context("while the destination is stalled", func() {
+							var release chan struct{}
+
+							BeforeEach(func() {
+								release = make(chan struct{})
+
+								fakeDestination.StreamInStub = func(dst string, src io.Reader) error {
+									src.Read(make([]byte, 1))
+
+									<-release
+
+									return nil
+								}
+							})
+
+							It("reads no further ahead than the stream buffer", func() {
+								streamed := make(chan error, 1)
+
+								go func() {
+									streamed <- artifactSource.StreamTo(fakeDestination)
+								}()
+
+								Consistently(func() int64 {
+									return atomic.LoadInt64(&bytesWritten)
+								}).Should(BeNumerically("<=", StreamBufferSize))
+
+								close(release)
+
+								Eventually(streamed).Should(Receive(BeNil()))
+								Eventually(writingExited).Should(BeClosed())
+							})
+						})
+
+						Context("when the destination gives up partway through", func() {
+							disaster := errors.New("nope")
+
+							BeforeEach(func() {
+								fakeDestination.StreamInStub = func(dst string, src io.Reader) error {
+									src.Read(make([]byte, 1))
+
+									return disaster
+								}
+							})
+
+							It("cancels the stream and returns the error promptly", func() {
+								streamed := make(chan error, 1)
+
+								go func() {
+									streamed <- artifactSource.StreamTo(fakeDestination)
+								}()
+
+								Eventually(streamed).Should(Receive(Equal(disaster)))
+								Eventually(writingExited).Should(BeClosed())
+							})
+						})
+					})
+
 					Context("when the resource cannot stream out", func() {
 						disaster := errors.New("nope")
 
@@ -325,6 +736,138 @@ var _ = Describe("GardenFactory", func() {
 							Ω(artifactSource.StreamTo(fakeDestination)).Should(Equal(disaster))
 						})
 					})
+
+					Context("with an artifact cache", func() {
+						var (
+							fakeArtifactCache *fakes.FakeArtifactCache
+
+							expectedIdentifier resource.CacheIdentifier
+							streamedIn         []byte
+						)
+
+						BeforeEach(func() {
+							fakeArtifactCache = new(fakes.FakeArtifactCache)
+
+							factory = NewGardenFactory(fakeWorkerClient, fakeTracker, fakeCache, fakeArtifactCache, nil, 0, false, func() string { return "" })
+
+							expectedIdentifier = resource.CacheIdentifier{
+								Type:    "some-resource-type",
+								Source:  atc.Source{"some": "source"},
+								Params:  atc.Params{"some-param": "some-value"},
+								Version: atc.Version{"some-version": "some-value"},
+							}
+
+							streamedIn = nil
+							fakeDestination.StreamInStub = func(dst string, src io.Reader) error {
+								var err error
+								streamedIn, err = ioutil.ReadAll(src)
+								return err
+							}
+						})
+
+						Context("when the artifact is cached", func() {
+							BeforeEach(func() {
+								fakeArtifactCache.GetReturns(ioutil.NopCloser(bytes.NewBufferString("some-cached-stream")), true)
+							})
+
+							It("streams the cached artifact without streaming out of the resource", func() {
+								err := artifactSource.StreamTo(fakeDestination)
+								Ω(err).ShouldNot(HaveOccurred())
+
+								Ω(fakeArtifactCache.GetCallCount()).Should(Equal(1))
+								Ω(fakeArtifactCache.GetArgsForCall(0)).Should(Equal(expectedIdentifier))
+
+								Ω(fakeVersionedSource.StreamOutCallCount()).Should(BeZero())
+								Ω(fakeArtifactCache.PutCallCount()).Should(BeZero())
+
+								Ω(string(streamedIn)).Should(Equal("some-cached-stream"))
+							})
+						})
+
+						Context("when the artifact is not cached", func() {
+							var stored map[string][]byte
+
+							BeforeEach(func() {
+								stored = map[string][]byte{}
+
+								fakeArtifactCache.GetStub = func(identifier resource.CacheIdentifier) (io.ReadCloser, bool) {
+									contents, found := stored[identifier.Hash()]
+									if !found {
+										return nil, false
+									}
+
+									return ioutil.NopCloser(bytes.NewBuffer(contents)), true
+								}
+
+								fakeArtifactCache.PutStub = func(identifier resource.CacheIdentifier, stream io.Reader) error {
+									contents, err := ioutil.ReadAll(stream)
+									if err != nil {
+										return err
+									}
+
+									stored[identifier.Hash()] = contents
+									return nil
+								}
+
+								fakeVersionedSource.StreamOutStub = func(string) (io.ReadCloser, error) {
+									return gbytes.BufferWithBytes([]byte("some-stream")), nil
+								}
+							})
+
+							It("stores the artifact under its fetch, and streams it to the destination", func() {
+								err := artifactSource.StreamTo(fakeDestination)
+								Ω(err).ShouldNot(HaveOccurred())
+
+								Ω(fakeVersionedSource.StreamOutCallCount()).Should(Equal(1))
+
+								Ω(fakeArtifactCache.PutCallCount()).Should(Equal(1))
+								identifier, _ := fakeArtifactCache.PutArgsForCall(0)
+								Ω(identifier).Should(Equal(expectedIdentifier))
+								Ω(string(stored[expectedIdentifier.Hash()])).Should(Equal("some-stream"))
+
+								Ω(string(streamedIn)).Should(Equal("some-stream"))
+							})
+
+							It("reuses the stored artifact on the next stream", func() {
+								Ω(artifactSource.StreamTo(fakeDestination)).Should(Succeed())
+								Ω(artifactSource.StreamTo(fakeDestination)).Should(Succeed())
+
+								Ω(fakeVersionedSource.StreamOutCallCount()).Should(Equal(1))
+								Ω(fakeArtifactCache.PutCallCount()).Should(Equal(1))
+
+								Ω(string(streamedIn)).Should(Equal("some-stream"))
+							})
+
+							Context("when the cache does not keep the artifact", func() {
+								BeforeEach(func() {
+									fakeArtifactCache.PutStub = func(identifier resource.CacheIdentifier, stream io.Reader) error {
+										_, err := ioutil.ReadAll(stream)
+										return err
+									}
+								})
+
+								It("streams it out of the resource again", func() {
+									Ω(artifactSource.StreamTo(fakeDestination)).Should(Succeed())
+
+									Ω(fakeVersionedSource.StreamOutCallCount()).Should(Equal(2))
+									Ω(string(streamedIn)).Should(Equal("some-stream"))
+								})
+							})
+
+							Context("when storing the artifact fails", func() {
+								disaster := errors.New("nope")
+
+								BeforeEach(func() {
+									fakeArtifactCache.PutStub = nil
+									fakeArtifactCache.PutReturns(disaster)
+								})
+
+								It("returns the error", func() {
+									Ω(artifactSource.StreamTo(fakeDestination)).Should(Equal(disaster))
+								})
+							})
+						})
+					})
 				})
 
 				Describe("streaming a file out", func() {
@@ -385,6 +928,180 @@ var _ = Describe("GardenFactory", func() {
 								Ω(err).Should(MatchError(FileNotFoundError{Path: "some-path"}))
 							})
 						})
+
+						Context("when the file is preceded by a directory and a symlink", func() {
+							BeforeEach(func() {
+								tarWriter := tar.NewWriter(tarBuffer)
+
+								err := tarWriter.WriteHeader(&tar.Header{
+									Name:     "some-dir/",
+									Typeflag: tar.TypeDir,
+									Mode:     0755,
+								})
+								Ω(err).ShouldNot(HaveOccurred())
+
+								err = tarWriter.WriteHeader(&tar.Header{
+									Name:     "some-link",
+									Typeflag: tar.TypeSymlink,
+									Linkname: "some-dir",
+									Mode:     0777,
+								})
+								Ω(err).ShouldNot(HaveOccurred())
+
+								err = tarWriter.WriteHeader(&tar.Header{
+									Name:     "some-file",
+									Typeflag: tar.TypeReg,
+									Mode:     0644,
+									Size:     int64(len(fileContent)),
+								})
+								Ω(err).ShouldNot(HaveOccurred())
+
+								_, err = tarWriter.Write([]byte(fileContent))
+								Ω(err).ShouldNot(HaveOccurred())
+
+								err = tarWriter.Close()
+								Ω(err).ShouldNot(HaveOccurred())
+							})
+
+							It("streams out the regular file", func() {
+								reader, err := artifactSource.StreamFile("some-path")
+								Ω(err).ShouldNot(HaveOccurred())
+
+								Ω(ioutil.ReadAll(reader)).Should(Equal([]byte(fileContent)))
+							})
+						})
+
+						Context("when the stream has no regular files", func() {
+							BeforeEach(func() {
+								tarWriter := tar.NewWriter(tarBuffer)
+
+								err := tarWriter.WriteHeader(&tar.Header{
+									Name:     "some-link",
+									Typeflag: tar.TypeSymlink,
+									Linkname: "some-dir",
+									Mode:     0777,
+								})
+								Ω(err).ShouldNot(HaveOccurred())
+
+								err = tarWriter.Close()
+								Ω(err).ShouldNot(HaveOccurred())
+							})
+
+							It("returns ErrFileNotFound and closes the stream", func() {
+								_, err := artifactSource.StreamFile("some-path")
+								Ω(err).Should(MatchError(FileNotFoundError{Path: "some-path"}))
+
+								Ω(tarBuffer.Closed()).Should(BeTrue())
+							})
+						})
+
+						Context("when the requested path is a directory", func() {
+							BeforeEach(func() {
+								tarWriter := tar.NewWriter(tarBuffer)
+
+								err := tarWriter.WriteHeader(&tar.Header{
+									Name:     "some-path/",
+									Typeflag: tar.TypeDir,
+									Mode:     0755,
+								})
+								Ω(err).ShouldNot(HaveOccurred())
+
+								err = tarWriter.WriteHeader(&tar.Header{
+									Name:     "some-path/some-file",
+									Typeflag: tar.TypeReg,
+									Mode:     0644,
+									Size:     int64(len(fileContent)),
+								})
+								Ω(err).ShouldNot(HaveOccurred())
+
+								_, err = tarWriter.Write([]byte(fileContent))
+								Ω(err).ShouldNot(HaveOccurred())
+
+								err = tarWriter.Close()
+								Ω(err).ShouldNot(HaveOccurred())
+							})
+
+							It("returns an error saying so", func() {
+								_, err := artifactSource.StreamFile("some/some-path")
+								Ω(err).Should(MatchError(FileIsDirectoryError{Path: "some/some-path"}))
+							})
+						})
+
+						Context("when the file is large", func() {
+							const (
+								chunkSize = 64 * 1024
+								chunks    = 128
+							)
+
+							var (
+								streamOut  *io.PipeReader
+								writtenAll chan struct{}
+							)
+
+							BeforeEach(func() {
+								var streamIn *io.PipeWriter
+								streamOut, streamIn = io.Pipe()
+
+								fakeVersionedSource.StreamOutReturns(streamOut, nil)
+
+								writtenAll = make(chan struct{})
+
+								go func() {
+									defer GinkgoRecover()
+									defer close(writtenAll)
+
+									tarWriter := tar.NewWriter(streamIn)
+
+									err := tarWriter.WriteHeader(&tar.Header{
+										Name: "some-file",
+										Mode: 0644,
+										Size: chunkSize * chunks,
+									})
+									Ω(err).ShouldNot(HaveOccurred())
+
+									chunk := bytes.Repeat([]byte("x"), chunkSize)
+
+									for i := 0; i < chunks; i++ {
+										_, err := tarWriter.Write(chunk)
+										if err != nil {
+											return
+										}
+									}
+
+									tarWriter.Close()
+									streamIn.Close()
+								}()
+							})
+
+							It("streams the file lazily rather than buffering it", func() {
+								reader, err := artifactSource.StreamFile("some-path")
+								Ω(err).ShouldNot(HaveOccurred())
+
+								// the writer blocks on the pipe until we read, so it cannot
+								// have finished unless the file was buffered up front
+								Consistently(writtenAll).ShouldNot(BeClosed())
+
+								n, err := io.Copy(ioutil.Discard, reader)
+								Ω(err).ShouldNot(HaveOccurred())
+								Ω(n).Should(Equal(int64(chunkSize * chunks)))
+
+								Eventually(writtenAll).Should(BeClosed())
+							})
+
+							It("closes the source stream when the reader is closed partway through", func() {
+								reader, err := artifactSource.StreamFile("some-path")
+								Ω(err).ShouldNot(HaveOccurred())
+
+								_, err = reader.Read(make([]byte, chunkSize))
+								Ω(err).ShouldNot(HaveOccurred())
+
+								Ω(reader.Close()).Should(Succeed())
+
+								// the writer is unblocked by the closed pipe rather than
+								// by us reading everything
+								Eventually(writtenAll).Should(BeClosed())
+							})
+						})
 					})
 
 					Context("when the resource cannot stream out", func() {
@@ -0,0 +1,34 @@
+package exec
+
+import (
+	"compress/gzip"
+	"io"
+)
+
+// gunzipDestination wraps a destination that only takes plain tar streams,
+// i.e. a container, so that sources stream to it gzipped. The stream is
+// decompressed just before it is handed to the destination.
+type gunzipDestination struct {
+	ArtifactDestination
+}
+
+func (dest gunzipDestination) StreamInCompressed(dst string, src io.Reader) error {
+	gzipReader, err := gzip.NewReader(src)
+	if err != nil {
+		return err
+	}
+
+	defer gzipReader.Close()
+
+	return dest.ArtifactDestination.StreamIn(dst, gzipReader)
+}
+
+// compressedIfEnabled wraps the destination in a gunzipDestination if
+// artifact streams are to be compressed.
+func compressedIfEnabled(dest ArtifactDestination, compress bool) ArtifactDestination {
+	if !compress {
+		return dest
+	}
+
+	return gunzipDestination{dest}
+}
@@ -68,11 +68,6 @@ func (o *onFailure) Result(x interface{}) bool {
 	}
 }
 
-func (o *onFailure) Release() {
-	if o.step != nil {
-		o.step.Release()
-	}
-	if o.failure != nil {
-		o.failure.Release()
-	}
+func (o *onFailure) Release() error {
+	return releaseAll(o.step, o.failure)
 }
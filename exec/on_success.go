@@ -77,11 +77,6 @@ func (o *onSuccess) Result(x interface{}) bool {
 	}
 }
 
-func (o *onSuccess) Release() {
-	if o.step != nil {
-		o.step.Release()
-	}
-	if o.success != nil {
-		o.success.Release()
-	}
+func (o *onSuccess) Release() error {
+	return releaseAll(o.step, o.success)
 }
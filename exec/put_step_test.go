@@ -21,6 +21,7 @@ import (
 var _ = Describe("GardenFactory", func() {
 	var (
 		fakeTracker      *rfakes.FakeTracker
+		fakeCache        *rfakes.FakeCache
 		fakeWorkerClient *wfakes.FakeClient
 
 		factory Factory
@@ -35,9 +36,10 @@ var _ = Describe("GardenFactory", func() {
 
 	BeforeEach(func() {
 		fakeTracker = new(rfakes.FakeTracker)
+		fakeCache = new(rfakes.FakeCache)
 		fakeWorkerClient = new(wfakes.FakeClient)
 
-		factory = NewGardenFactory(fakeWorkerClient, fakeTracker, func() string { return "" })
+		factory = NewGardenFactory(fakeWorkerClient, fakeTracker, fakeCache, nil, nil, 0, false, func() string { return "" })
 
 		stdoutBuf = gbytes.NewBuffer()
 		stderrBuf = gbytes.NewBuffer()
@@ -4,8 +4,11 @@ import (
 	"archive/tar"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/credentials"
 	"github.com/concourse/atc/resource"
 )
 
@@ -20,7 +23,16 @@ type resourceStep struct {
 	Type    resource.ResourceType
 	Tags    atc.Tags
 
-	Action func(resource.Resource, ArtifactSource, VersionInfo) resource.VersionedSource
+	// the resource's source, with credential references resolved by the
+	// credential manager before it is given to the action
+	Source            atc.Source
+	CredentialManager credentials.CredentialManager
+
+	Cache           resource.Cache
+	CacheIdentifier *resource.CacheIdentifier
+	ArtifactCache   ArtifactCache
+
+	Action func(resource.Resource, atc.Source, ArtifactSource, VersionInfo) resource.VersionedSource
 
 	PreviousStep Step
 	Repository   *SourceRepository
@@ -28,6 +40,9 @@ type resourceStep struct {
 	Resource        resource.Resource
 	VersionedSource resource.VersionedSource
 
+	// the cached fetch this step holds a reference on, if any
+	cachedFetch *resource.CachedFetch
+
 	exitStatus int
 }
 
@@ -42,19 +57,28 @@ func (step resourceStep) Using(prev Step, repo *SourceRepository) Step {
 }
 
 func (ras *resourceStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
-	trackedResource, err := ras.Tracker.Init(ras.Session, ras.Type, ras.Tags)
-	if err != nil {
-		return err
-	}
+	cached := ras.useCachedFetch()
+
+	if !cached {
+		source, err := credentials.InterpolateSource(ras.CredentialManager, ras.Source)
+		if err != nil {
+			return err
+		}
+
+		trackedResource, err := ras.Tracker.Init(ras.Session, ras.Type, ras.Tags)
+		if err != nil {
+			return err
+		}
 
-	var versionInfo VersionInfo
+		var versionInfo VersionInfo
 
-	ras.PreviousStep.Result(&versionInfo)
+		ras.PreviousStep.Result(&versionInfo)
 
-	ras.Resource = trackedResource
-	ras.VersionedSource = ras.Action(trackedResource, ras.Repository, versionInfo)
+		ras.Resource = trackedResource
+		ras.VersionedSource = ras.Action(trackedResource, source, ras.Repository, versionInfo)
+	}
 
-	err = ras.VersionedSource.Run(signals, ready)
+	err := ras.VersionedSource.Run(signals, ready)
 
 	if err, ok := err.(resource.ErrResourceScriptFailed); ok {
 		ras.exitStatus = err.ExitStatus
@@ -70,6 +94,17 @@ func (ras *resourceStep) Run(signals <-chan os.Signal, ready chan<- struct{}) er
 		ras.Repository.RegisterSource(ras.SourceName, ras)
 	}
 
+	if !cached && ras.Cache != nil && ras.CacheIdentifier != nil {
+		fetch := resource.CachedFetch{
+			Session:  ras.Session,
+			Version:  ras.VersionedSource.Version(),
+			Metadata: ras.VersionedSource.Metadata(),
+		}
+
+		ras.Cache.Save(*ras.CacheIdentifier, fetch)
+		ras.cachedFetch = &fetch
+	}
+
 	ras.exitStatus = 0
 	ras.Delegate.Completed(ExitStatus(0), &VersionInfo{
 		Version:  ras.VersionedSource.Version(),
@@ -79,10 +114,42 @@ func (ras *resourceStep) Run(signals <-chan os.Signal, ready chan<- struct{}) er
 	return nil
 }
 
-func (ras *resourceStep) Release() {
+// useCachedFetch reuses the container of an identical previous get, if it is
+// still around, instead of running the resource's in script again.
+func (ras *resourceStep) useCachedFetch() bool {
+	if ras.Cache == nil || ras.CacheIdentifier == nil {
+		return false
+	}
+
+	fetch, found := ras.Cache.Lookup(*ras.CacheIdentifier)
+	if !found {
+		return false
+	}
+
+	cachedResource, err := ras.Tracker.Lookup(fetch.Session, ras.Type)
+	if err != nil {
+		ras.Cache.Release(*ras.CacheIdentifier, fetch)
+		return false
+	}
+
+	ras.Resource = cachedResource
+	ras.VersionedSource = cachedResource.CachedGet(fetch.Version, fetch.Metadata)
+	ras.cachedFetch = &fetch
+
+	return true
+}
+
+func (ras *resourceStep) Release() error {
 	if ras.Resource != nil {
 		ras.Resource.Release()
 	}
+
+	if ras.cachedFetch != nil {
+		ras.Cache.Release(*ras.CacheIdentifier, *ras.cachedFetch)
+		ras.cachedFetch = nil
+	}
+
+	return nil
 }
 
 func (ras *resourceStep) Result(x interface{}) bool {
@@ -107,30 +174,101 @@ type fileReadCloser struct {
 	io.Closer
 }
 
+// streamFirstFile returns a reader for the first regular file in the tar
+// stream, skipping any directory and symlink entries before it. If the stream
+// holds the requested path itself as a directory, it is not a file.
+func streamFirstFile(out io.ReadCloser, path string) (io.ReadCloser, error) {
+	tarReader := tar.NewReader(out)
+
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			out.Close()
+			return nil, FileNotFoundError{Path: path}
+		}
+
+		switch header.Typeflag {
+		case tar.TypeReg, tar.TypeRegA:
+			return fileReadCloser{
+				Reader: tarReader,
+				Closer: out,
+			}, nil
+
+		case tar.TypeDir:
+			if isRequestedPath(header.Name, path) {
+				out.Close()
+				return nil, FileIsDirectoryError{Path: path}
+			}
+		}
+	}
+}
+
+func isRequestedPath(entryName string, path string) bool {
+	entry := strings.Trim(entryName, "/")
+	if entry == "" || entry == "." {
+		return true
+	}
+
+	return entry == filepath.Base(strings.TrimRight(path, "/"))
+}
+
 func (ras *resourceStep) StreamTo(destination ArtifactDestination) error {
+	if ras.ArtifactCache != nil && ras.CacheIdentifier != nil {
+		return ras.streamThroughArtifactCache(destination)
+	}
+
 	out, err := ras.VersionedSource.StreamOut(".")
 	if err != nil {
 		return err
 	}
 
-	return destination.StreamIn(".", out)
+	return streamTo(destination, out)
 }
 
-func (ras *resourceStep) StreamFile(path string) (io.ReadCloser, error) {
-	out, err := ras.VersionedSource.StreamOut(path)
+// streamThroughArtifactCache streams the artifact from the cache, keyed by
+// the fetch: a resource's type, source, version, and params determine what
+// it fetches, so any build getting the same version with the same params can
+// reuse the stream without going back to the container.
+//
+// On a miss the artifact is stored before being streamed, so that the
+// destination always receives a complete artifact. If the cache did not keep
+// it (e.g. it is larger than the cache), it is streamed from the container.
+func (ras *resourceStep) streamThroughArtifactCache(destination ArtifactDestination) error {
+	identifier := *ras.CacheIdentifier
+
+	if cached, found := ras.ArtifactCache.Get(identifier); found {
+		return streamTo(destination, cached)
+	}
+
+	out, err := ras.VersionedSource.StreamOut(".")
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	tarReader := tar.NewReader(out)
+	err = ras.ArtifactCache.Put(identifier, out)
+	out.Close()
+
+	if err != nil {
+		return err
+	}
+
+	if cached, found := ras.ArtifactCache.Get(identifier); found {
+		return streamTo(destination, cached)
+	}
 
-	_, err = tarReader.Next()
+	out, err = ras.VersionedSource.StreamOut(".")
 	if err != nil {
-		return nil, FileNotFoundError{Path: path}
+		return err
+	}
+
+	return streamTo(destination, out)
+}
+
+func (ras *resourceStep) StreamFile(path string) (io.ReadCloser, error) {
+	out, err := ras.VersionedSource.StreamOut(path)
+	if err != nil {
+		return nil, err
 	}
 
-	return fileReadCloser{
-		Reader: tarReader,
-		Closer: out,
-	}, nil
+	return streamFirstFile(out, path)
 }
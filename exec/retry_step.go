@@ -0,0 +1,108 @@
+package exec
+
+import (
+	"os"
+	"time"
+
+	"github.com/pivotal-golang/clock"
+)
+
+//go:generate counterfeiter . RetryDelegate
+
+type RetryDelegate interface {
+	Retrying(attempt int)
+}
+
+type retry struct {
+	step     StepFactory
+	attempts int
+	delay    time.Duration
+	delegate RetryDelegate
+	clock    clock.Clock
+
+	prev Step
+	repo *SourceRepository
+
+	runStep Step
+}
+
+// Retry runs the step up to the given number of attempts, waiting delay
+// between them, until one attempt succeeds. Each attempt uses a fresh step,
+// and the previous attempt's step is released before the next one starts.
+// An attempt that was interrupted or aborted is never retried.
+func Retry(
+	step StepFactory,
+	attempts int,
+	delay time.Duration,
+	delegate RetryDelegate,
+	clock clock.Clock,
+) StepFactory {
+	return retry{
+		step:     step,
+		attempts: attempts,
+		delay:    delay,
+		delegate: delegate,
+		clock:    clock,
+	}
+}
+
+func (rs retry) Using(prev Step, repo *SourceRepository) Step {
+	rs.prev = prev
+	rs.repo = repo
+
+	return &rs
+}
+
+func (rs *retry) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			rs.delegate.Retrying(attempt)
+		}
+
+		rs.runStep = rs.step.Using(rs.prev, rs.repo)
+
+		err := rs.runStep.Run(signals, make(chan struct{}))
+		if err != nil && CategorizeError(err) == StepErrorCategoryInterrupted {
+			// the signal was consumed by the attempt; it must not be retried
+			return err
+		}
+
+		var succeeded Success
+		if err == nil && rs.runStep.Result(&succeeded) && bool(succeeded) {
+			return nil
+		}
+
+		if attempt >= rs.attempts {
+			return err
+		}
+
+		rs.runStep.Release()
+
+		timer := rs.clock.NewTimer(rs.delay)
+
+		select {
+		case <-timer.C():
+		case <-signals:
+			timer.Stop()
+			return ErrInterrupted
+		}
+	}
+}
+
+func (rs *retry) Release() error {
+	if rs.runStep == nil {
+		return nil
+	}
+
+	return rs.runStep.Release()
+}
+
+func (rs *retry) Result(x interface{}) bool {
+	if rs.runStep == nil {
+		return false
+	}
+
+	return rs.runStep.Result(x)
+}
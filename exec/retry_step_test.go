@@ -0,0 +1,240 @@
+package exec_test
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	. "github.com/concourse/atc/exec"
+
+	"github.com/concourse/atc/exec/fakes"
+	"github.com/concourse/atc/resource"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/clock/fakeclock"
+
+	"github.com/tedsuo/ifrit"
+)
+
+var _ = Describe("Retry Step", func() {
+	var (
+		fakeStepFactory *fakes.FakeStepFactory
+		fakeDelegate    *fakes.FakeRetryDelegate
+
+		attemptSteps []*fakes.FakeStep
+
+		previousStep *fakes.FakeStep
+		repo         *SourceRepository
+
+		delay     time.Duration
+		fakeClock *fakeclock.FakeClock
+
+		step Step
+	)
+
+	BeforeEach(func() {
+		fakeStepFactory = new(fakes.FakeStepFactory)
+		fakeDelegate = new(fakes.FakeRetryDelegate)
+
+		attemptSteps = []*fakes.FakeStep{
+			new(fakes.FakeStep),
+			new(fakes.FakeStep),
+			new(fakes.FakeStep),
+		}
+
+		fakeStepFactory.UsingStub = func(Step, *SourceRepository) Step {
+			return attemptSteps[fakeStepFactory.UsingCallCount()-1]
+		}
+
+		previousStep = new(fakes.FakeStep)
+		repo = NewSourceRepository()
+
+		// with no delay, the fake clock lets each retry go ahead immediately
+		delay = 0
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+	})
+
+	JustBeforeEach(func() {
+		step = Retry(fakeStepFactory, 3, delay, fakeDelegate, fakeClock).Using(previousStep, repo)
+	})
+
+	Context("when the first attempt succeeds", func() {
+		BeforeEach(func() {
+			attemptSteps[0].ResultStub = successResult(true)
+		})
+
+		It("does not retry", func() {
+			Ω(step.Run(nil, make(chan struct{}))).Should(Succeed())
+
+			Ω(fakeStepFactory.UsingCallCount()).Should(Equal(1))
+			Ω(fakeDelegate.RetryingCallCount()).Should(BeZero())
+
+			var success Success
+			Ω(step.Result(&success)).Should(BeTrue())
+			Ω(bool(success)).Should(BeTrue())
+		})
+
+		It("uses the previous step and repo for the attempt", func() {
+			Ω(step.Run(nil, make(chan struct{}))).Should(Succeed())
+
+			prev, argsRepo := fakeStepFactory.UsingArgsForCall(0)
+			Ω(prev).Should(Equal(previousStep))
+			Ω(argsRepo).Should(Equal(repo))
+		})
+	})
+
+	Context("when the second attempt succeeds", func() {
+		BeforeEach(func() {
+			attemptSteps[0].ResultStub = successResult(false)
+			attemptSteps[1].ResultStub = successResult(true)
+		})
+
+		It("succeeds with a fresh step", func() {
+			Ω(step.Run(nil, make(chan struct{}))).Should(Succeed())
+
+			Ω(fakeStepFactory.UsingCallCount()).Should(Equal(2))
+			Ω(attemptSteps[0].RunCallCount()).Should(Equal(1))
+			Ω(attemptSteps[1].RunCallCount()).Should(Equal(1))
+			Ω(attemptSteps[2].RunCallCount()).Should(BeZero())
+
+			var success Success
+			Ω(step.Result(&success)).Should(BeTrue())
+			Ω(bool(success)).Should(BeTrue())
+		})
+
+		It("releases the failed attempt before retrying", func() {
+			Ω(step.Run(nil, make(chan struct{}))).Should(Succeed())
+
+			Ω(attemptSteps[0].ReleaseCallCount()).Should(Equal(1))
+			Ω(attemptSteps[1].ReleaseCallCount()).Should(BeZero())
+		})
+
+		It("reports the attempt to the delegate", func() {
+			Ω(step.Run(nil, make(chan struct{}))).Should(Succeed())
+
+			Ω(fakeDelegate.RetryingCallCount()).Should(Equal(1))
+			Ω(fakeDelegate.RetryingArgsForCall(0)).Should(Equal(2))
+		})
+
+		Describe("releasing", func() {
+			It("releases the successful attempt", func() {
+				Ω(step.Run(nil, make(chan struct{}))).Should(Succeed())
+
+				Ω(step.Release()).Should(Succeed())
+				Ω(attemptSteps[1].ReleaseCallCount()).Should(Equal(1))
+			})
+		})
+	})
+
+	Context("when every attempt fails", func() {
+		BeforeEach(func() {
+			for _, attemptStep := range attemptSteps {
+				attemptStep.ResultStub = successResult(false)
+			}
+		})
+
+		It("gives up after the last attempt, without erroring", func() {
+			Ω(step.Run(nil, make(chan struct{}))).Should(Succeed())
+
+			Ω(fakeStepFactory.UsingCallCount()).Should(Equal(3))
+
+			Ω(fakeDelegate.RetryingCallCount()).Should(Equal(2))
+			Ω(fakeDelegate.RetryingArgsForCall(0)).Should(Equal(2))
+			Ω(fakeDelegate.RetryingArgsForCall(1)).Should(Equal(3))
+
+			var success Success
+			Ω(step.Result(&success)).Should(BeTrue())
+			Ω(bool(success)).Should(BeFalse())
+		})
+	})
+
+	Context("when every attempt errors", func() {
+		BeforeEach(func() {
+			for i, attemptStep := range attemptSteps {
+				attemptStep.RunReturns(fmt.Errorf("disaster %d", i+1))
+			}
+		})
+
+		It("returns the last attempt's error", func() {
+			Ω(step.Run(nil, make(chan struct{}))).Should(MatchError("disaster 3"))
+			Ω(fakeStepFactory.UsingCallCount()).Should(Equal(3))
+		})
+	})
+
+	Context("when an attempt is interrupted", func() {
+		BeforeEach(func() {
+			attemptSteps[0].RunReturns(ErrInterrupted)
+		})
+
+		It("does not retry", func() {
+			Ω(step.Run(nil, make(chan struct{}))).Should(Equal(ErrInterrupted))
+			Ω(fakeStepFactory.UsingCallCount()).Should(Equal(1))
+		})
+	})
+
+	Context("when a get attempt is aborted", func() {
+		BeforeEach(func() {
+			// as a get does once its script is killed by the abort
+			attemptSteps[0].RunStub = func(signals <-chan os.Signal, ready chan<- struct{}) error {
+				close(ready)
+				<-signals
+				return resource.ErrAborted
+			}
+		})
+
+		It("does not retry", func() {
+			process := ifrit.Background(step)
+
+			Eventually(attemptSteps[0].RunCallCount).Should(Equal(1))
+
+			process.Signal(os.Kill)
+
+			Eventually(process.Wait()).Should(Receive(Equal(resource.ErrAborted)))
+
+			Ω(fakeStepFactory.UsingCallCount()).Should(Equal(1))
+			Ω(fakeDelegate.RetryingCallCount()).Should(BeZero())
+		})
+	})
+
+	Context("with a delay", func() {
+		BeforeEach(func() {
+			delay = time.Minute
+
+			attemptSteps[0].ResultStub = successResult(false)
+			attemptSteps[1].ResultStub = successResult(true)
+		})
+
+		It("waits the delay before retrying", func() {
+			process := ifrit.Background(step)
+
+			Eventually(attemptSteps[0].ReleaseCallCount).Should(Equal(1))
+			Consistently(fakeStepFactory.UsingCallCount).Should(Equal(1))
+
+			fakeClock.Increment(delay - time.Second)
+			Consistently(fakeStepFactory.UsingCallCount).Should(Equal(1))
+
+			fakeClock.Increment(time.Second)
+			Eventually(fakeStepFactory.UsingCallCount).Should(Equal(2))
+
+			Eventually(process.Wait()).Should(Receive(BeNil()))
+		})
+	})
+
+	Context("when signalled while waiting to retry", func() {
+		BeforeEach(func() {
+			delay = time.Hour
+			attemptSteps[0].ResultStub = successResult(false)
+		})
+
+		It("stops retrying", func() {
+			process := ifrit.Background(step)
+
+			Eventually(attemptSteps[0].ReleaseCallCount).Should(Equal(1))
+
+			process.Signal(os.Interrupt)
+
+			Eventually(process.Wait()).Should(Receive(Equal(ErrInterrupted)))
+			Ω(fakeStepFactory.UsingCallCount()).Should(Equal(1))
+		})
+	})
+})
@@ -1,26 +1,104 @@
 package exec
 
 import (
+	"fmt"
 	"io"
 	"strings"
 	"sync"
 )
 
+//go:generate counterfeiter . ReleasableArtifactSource
+
+// ReleasableArtifactSource is a source backed by something that must be
+// released once the build is done with it, i.e. a container.
+type ReleasableArtifactSource interface {
+	ArtifactSource
+
+	Release() error
+}
+
 type SourceRepository struct {
 	repo  map[SourceName]ArtifactSource
 	repoL sync.RWMutex
+
+	// every source ever registered, including ones whose name was since
+	// registered again, so that none are leaked
+	registered  []ArtifactSource
+	registeredL sync.Mutex
+
+	// guarded by repoL
+	waiters map[SourceName][]sourceWaiter
+}
+
+type sourceWaiter struct {
+	source chan ArtifactSource
+	cancel <-chan struct{}
+
+	// closed once the source has been sent
+	sent chan struct{}
 }
 
 func NewSourceRepository() *SourceRepository {
 	return &SourceRepository{
-		repo: make(map[SourceName]ArtifactSource),
+		repo:    make(map[SourceName]ArtifactSource),
+		waiters: make(map[SourceName][]sourceWaiter),
 	}
 }
 
 func (repo *SourceRepository) RegisterSource(name SourceName, source ArtifactSource) {
 	repo.repoL.Lock()
 	repo.repo[name] = source
+	waiters := repo.waiters[name]
+	delete(repo.waiters, name)
 	repo.repoL.Unlock()
+
+	for _, waiter := range waiters {
+		select {
+		case <-waiter.cancel:
+			// given up on, but not yet forgotten
+		default:
+			waiter.source <- source
+		}
+
+		close(waiter.sent)
+	}
+
+	repo.registeredL.Lock()
+	repo.registered = append(repo.registered, source)
+	repo.registeredL.Unlock()
+}
+
+// Cleanup releases every releasable source that has been registered, each
+// exactly once. Releasing continues past failures; all of the errors are
+// returned together.
+func (repo *SourceRepository) Cleanup() error {
+	repo.registeredL.Lock()
+	registered := repo.registered
+	repo.registered = nil
+	repo.registeredL.Unlock()
+
+	released := map[ReleasableArtifactSource]bool{}
+
+	var errs ReleaseErrors
+	for _, source := range registered {
+		releasable, ok := source.(ReleasableArtifactSource)
+		if !ok || released[releasable] {
+			continue
+		}
+
+		released[releasable] = true
+
+		err := releasable.Release()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
 }
 
 func (repo *SourceRepository) SourceFor(name SourceName) (ArtifactSource, bool) {
@@ -30,6 +108,61 @@ func (repo *SourceRepository) SourceFor(name SourceName) (ArtifactSource, bool)
 	return source, found
 }
 
+// WaitForSource returns a channel that receives the source registered with
+// the given name, as soon as there is one. If a source is already registered
+// it is received immediately.
+//
+// The channel is only ever sent one source. Closing cancel gives up on it:
+// nothing is sent afterwards, and the repository forgets the waiter rather
+// than keeping it until a source with the name is registered, which may never
+// happen.
+func (repo *SourceRepository) WaitForSource(name SourceName, cancel <-chan struct{}) <-chan ArtifactSource {
+	waiter := sourceWaiter{
+		source: make(chan ArtifactSource, 1),
+		cancel: cancel,
+		sent:   make(chan struct{}),
+	}
+
+	repo.repoL.Lock()
+	defer repo.repoL.Unlock()
+
+	source, found := repo.repo[name]
+	if found {
+		waiter.source <- source
+		return waiter.source
+	}
+
+	repo.waiters[name] = append(repo.waiters[name], waiter)
+
+	go func() {
+		select {
+		case <-cancel:
+			repo.removeWaiter(name, waiter)
+		case <-waiter.sent:
+		}
+	}()
+
+	return waiter.source
+}
+
+func (repo *SourceRepository) removeWaiter(name SourceName, waiter sourceWaiter) {
+	repo.repoL.Lock()
+	defer repo.repoL.Unlock()
+
+	var remaining []sourceWaiter
+	for _, w := range repo.waiters[name] {
+		if w.source != waiter.source {
+			remaining = append(remaining, w)
+		}
+	}
+
+	if len(remaining) == 0 {
+		delete(repo.waiters, name)
+	} else {
+		repo.waiters[name] = remaining
+	}
+}
+
 func (repo *SourceRepository) StreamTo(dest ArtifactDestination) error {
 	sources := map[SourceName]ArtifactSource{}
 
@@ -40,7 +173,7 @@ func (repo *SourceRepository) StreamTo(dest ArtifactDestination) error {
 	repo.repoL.RUnlock()
 
 	for name, src := range sources {
-		err := src.StreamTo(subdirectoryDestination{dest, string(name)})
+		err := src.StreamTo(subdirectory(dest, string(name)))
 		if err != nil {
 			return err
 		}
@@ -67,6 +200,39 @@ func (repo *SourceRepository) StreamFile(path string) (io.ReadCloser, error) {
 	return nil, FileNotFoundError{Path: path}
 }
 
+type ReleaseErrors []error
+
+func (errs ReleaseErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	return fmt.Sprintf("failed to release: %s", strings.Join(msgs, "; "))
+}
+
+// releaseAll releases each of the given steps, skipping ones that were never
+// created, and collects any errors.
+func releaseAll(steps ...Step) error {
+	var errs ReleaseErrors
+	for _, step := range steps {
+		if step == nil {
+			continue
+		}
+
+		err := step.Release()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
 type subdirectoryDestination struct {
 	destination  ArtifactDestination
 	subdirectory string
@@ -75,3 +241,24 @@ type subdirectoryDestination struct {
 func (dest subdirectoryDestination) StreamIn(dst string, src io.Reader) error {
 	return dest.destination.StreamIn(dest.subdirectory+"/"+dst, src)
 }
+
+// compressedSubdirectoryDestination is a subdirectoryDestination that keeps
+// accepting compressed streams if the destination it wraps does.
+type compressedSubdirectoryDestination struct {
+	subdirectoryDestination
+	compressed CompressedArtifactDestination
+}
+
+func (dest compressedSubdirectoryDestination) StreamInCompressed(dst string, src io.Reader) error {
+	return dest.compressed.StreamInCompressed(dest.subdirectory+"/"+dst, src)
+}
+
+func subdirectory(dest ArtifactDestination, name string) ArtifactDestination {
+	sub := subdirectoryDestination{dest, name}
+
+	if compressed, ok := dest.(CompressedArtifactDestination); ok {
+		return compressedSubdirectoryDestination{sub, compressed}
+	}
+
+	return sub
+}
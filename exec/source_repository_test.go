@@ -28,6 +28,46 @@ var _ = Describe("SourceRepository", func() {
 		Ω(found).Should(BeFalse())
 	})
 
+	Describe("WaitForSource", func() {
+		It("is notified when a source with the name is registered", func() {
+			waiter := repo.WaitForSource("some-source", nil)
+			otherWaiter := repo.WaitForSource("some-source", nil)
+
+			Consistently(waiter).ShouldNot(Receive())
+
+			repo.RegisterSource("some-other-source", new(fakes.FakeArtifactSource))
+
+			Consistently(waiter).ShouldNot(Receive())
+
+			someSource := new(fakes.FakeArtifactSource)
+			repo.RegisterSource("some-source", someSource)
+
+			Ω(waiter).Should(Receive(Equal(someSource)))
+			Ω(otherWaiter).Should(Receive(Equal(someSource)))
+		})
+
+		It("is notified immediately when the source is already registered", func() {
+			someSource := new(fakes.FakeArtifactSource)
+			repo.RegisterSource("some-source", someSource)
+
+			Ω(repo.WaitForSource("some-source", nil)).Should(Receive(Equal(someSource)))
+		})
+
+		It("can be given up on when the source never arrives", func() {
+			cancel := make(chan struct{})
+			waiter := repo.WaitForSource("some-source", cancel)
+			otherWaiter := repo.WaitForSource("some-source", nil)
+
+			close(cancel)
+
+			someSource := new(fakes.FakeArtifactSource)
+			repo.RegisterSource("some-source", someSource)
+
+			Consistently(waiter).ShouldNot(Receive())
+			Ω(otherWaiter).Should(Receive(Equal(someSource)))
+		})
+	})
+
 	Context("when a source is registered", func() {
 		var firstSource *fakes.FakeArtifactSource
 
@@ -118,6 +158,25 @@ var _ = Describe("SourceRepository", func() {
 					Ω(stream).Should(Equal(someStream))
 				})
 
+				Context("when the destination accepts compressed streams", func() {
+					It("lets the sources stream to it compressed, under subdirectories", func() {
+						compressedDestination := new(fakes.FakeCompressedArtifactDestination)
+
+						Ω(repo.StreamTo(compressedDestination)).Should(Succeed())
+
+						firstDestination, ok := firstSource.StreamToArgsForCall(1).(CompressedArtifactDestination)
+						Ω(ok).Should(BeTrue())
+
+						someStream := new(bytes.Buffer)
+						Ω(firstDestination.StreamInCompressed("foo", someStream)).Should(Succeed())
+
+						Ω(compressedDestination.StreamInCompressedCallCount()).Should(Equal(1))
+						destDir, stream := compressedDestination.StreamInCompressedArgsForCall(0)
+						Ω(destDir).Should(Equal("first-source/foo"))
+						Ω(stream).Should(Equal(someStream))
+					})
+				})
+
 				Context("when the any of the sources fails to stream", func() {
 					disaster := errors.New("nope")
 
@@ -182,4 +241,96 @@ var _ = Describe("SourceRepository", func() {
 			})
 		})
 	})
+
+	Describe("Cleanup", func() {
+		var (
+			firstSource  *fakes.FakeReleasableArtifactSource
+			secondSource *fakes.FakeReleasableArtifactSource
+			thirdSource  *fakes.FakeReleasableArtifactSource
+
+			cleanupErr error
+		)
+
+		BeforeEach(func() {
+			firstSource = new(fakes.FakeReleasableArtifactSource)
+			secondSource = new(fakes.FakeReleasableArtifactSource)
+			thirdSource = new(fakes.FakeReleasableArtifactSource)
+
+			repo.RegisterSource("first-source", firstSource)
+			repo.RegisterSource("second-source", secondSource)
+			repo.RegisterSource("third-source", thirdSource)
+		})
+
+		JustBeforeEach(func() {
+			cleanupErr = repo.Cleanup()
+		})
+
+		It("releases every registered source", func() {
+			Ω(cleanupErr).ShouldNot(HaveOccurred())
+
+			Ω(firstSource.ReleaseCallCount()).Should(Equal(1))
+			Ω(secondSource.ReleaseCallCount()).Should(Equal(1))
+			Ω(thirdSource.ReleaseCallCount()).Should(Equal(1))
+		})
+
+		It("does not release anything when called again", func() {
+			Ω(repo.Cleanup()).Should(Succeed())
+
+			Ω(firstSource.ReleaseCallCount()).Should(Equal(1))
+			Ω(secondSource.ReleaseCallCount()).Should(Equal(1))
+			Ω(thirdSource.ReleaseCallCount()).Should(Equal(1))
+		})
+
+		Context("when one of the sources fails to release", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				secondSource.ReleaseReturns(disaster)
+			})
+
+			It("still releases the rest", func() {
+				Ω(firstSource.ReleaseCallCount()).Should(Equal(1))
+				Ω(secondSource.ReleaseCallCount()).Should(Equal(1))
+				Ω(thirdSource.ReleaseCallCount()).Should(Equal(1))
+			})
+
+			It("returns the error", func() {
+				Ω(cleanupErr).Should(Equal(ReleaseErrors{disaster}))
+			})
+		})
+
+		Context("when a source's name is registered again", func() {
+			var replacementSource *fakes.FakeReleasableArtifactSource
+
+			BeforeEach(func() {
+				replacementSource = new(fakes.FakeReleasableArtifactSource)
+				repo.RegisterSource("first-source", replacementSource)
+			})
+
+			It("releases both the original and the replacement", func() {
+				Ω(firstSource.ReleaseCallCount()).Should(Equal(1))
+				Ω(replacementSource.ReleaseCallCount()).Should(Equal(1))
+			})
+		})
+
+		Context("when a source is registered under multiple names", func() {
+			BeforeEach(func() {
+				repo.RegisterSource("another-name", firstSource)
+			})
+
+			It("only releases it once", func() {
+				Ω(firstSource.ReleaseCallCount()).Should(Equal(1))
+			})
+		})
+
+		Context("when a source cannot be released", func() {
+			BeforeEach(func() {
+				repo.RegisterSource("unreleasable-source", new(fakes.FakeArtifactSource))
+			})
+
+			It("skips it", func() {
+				Ω(cleanupErr).ShouldNot(HaveOccurred())
+			})
+		})
+	})
 })
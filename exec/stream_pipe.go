@@ -0,0 +1,122 @@
+package exec
+
+import (
+	"compress/gzip"
+	"io"
+	"sync"
+)
+
+// StreamBufferSize is how much of a source stream is read ahead of its
+// destination when streaming artifacts between containers.
+const StreamBufferSize = 64 * 1024
+
+// streamPipe copies a source stream to its reader through a buffer of a fixed
+// size, so that at most that much of the stream is held in memory while the
+// destination catches up. Closing the pipe cancels the copy, closing the
+// source and waiting for the copying goroutine to exit.
+type streamPipe struct {
+	source   io.ReadCloser
+	compress bool
+
+	reader *io.PipeReader
+
+	copying   chan struct{}
+	closeOnce sync.Once
+}
+
+func newStreamPipe(source io.ReadCloser, bufferSize int, compress bool) *streamPipe {
+	reader, writer := io.Pipe()
+
+	pipe := &streamPipe{
+		source:   source,
+		compress: compress,
+
+		reader: reader,
+
+		copying: make(chan struct{}),
+	}
+
+	go pipe.copy(writer, bufferSize)
+
+	return pipe
+}
+
+func (pipe *streamPipe) Read(p []byte) (int, error) {
+	return pipe.reader.Read(p)
+}
+
+func (pipe *streamPipe) Close() error {
+	var err error
+
+	pipe.closeOnce.Do(func() {
+		pipe.reader.Close()
+		err = pipe.source.Close()
+	})
+
+	<-pipe.copying
+
+	return err
+}
+
+func (pipe *streamPipe) copy(writer *io.PipeWriter, bufferSize int) {
+	defer close(pipe.copying)
+
+	var out io.Writer = writer
+
+	var gzipWriter *gzip.Writer
+	if pipe.compress {
+		gzipWriter = gzip.NewWriter(writer)
+		out = gzipWriter
+	}
+
+	buf := make([]byte, bufferSize)
+
+	for {
+		n, readErr := pipe.source.Read(buf)
+		if n > 0 {
+			// blocks until the destination has consumed the chunk, or the pipe
+			// has been closed
+			_, err := out.Write(buf[:n])
+			if err != nil {
+				return
+			}
+		}
+
+		if readErr == io.EOF {
+			if gzipWriter != nil {
+				err := gzipWriter.Close()
+				if err != nil {
+					return
+				}
+			}
+
+			writer.Close()
+			return
+		}
+
+		if readErr != nil {
+			writer.CloseWithError(readErr)
+			return
+		}
+	}
+}
+
+// streamTo streams the source to the destination through a bounded pipe,
+// releasing the source once the destination returns, even if it gave up
+// partway through.
+//
+// The stream is gzipped only if the destination accepts compressed streams;
+// otherwise it is passed along as-is.
+func streamTo(destination ArtifactDestination, source io.ReadCloser) error {
+	if compressed, ok := destination.(CompressedArtifactDestination); ok {
+		pipe := newStreamPipe(source, StreamBufferSize, true)
+		defer pipe.Close()
+
+		return compressed.StreamInCompressed(".", pipe)
+	}
+
+	pipe := newStreamPipe(source, StreamBufferSize, false)
+	defer pipe.Close()
+
+	return destination.StreamIn(".", pipe)
+}
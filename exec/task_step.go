@@ -6,17 +6,22 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry-incubator/garden"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/credentials"
 	"github.com/concourse/atc/worker"
 )
 
 const taskProcessPropertyName = "concourse:task-process"
 const taskExitStatusPropertyName = "concourse:exit-status"
+const taskArtifactsRootPropertyName = "concourse:artifacts-root"
 
 var ErrInterrupted = errors.New("interrupted")
 
@@ -39,7 +44,12 @@ type taskStep struct {
 	Tags         atc.Tags
 	ConfigSource TaskConfigSource
 
-	WorkerClient worker.Client
+	WorkerClient      worker.Client
+	CredentialManager credentials.CredentialManager
+	GraceTime         time.Duration
+
+	// gzip inputs on their way into the container
+	CompressStreams bool
 
 	prev Step
 	repo *SourceRepository
@@ -47,6 +57,7 @@ type taskStep struct {
 	container     worker.Container
 	process       garden.Process
 	artifactsRoot string
+	outputs       []atc.TaskOutputConfig
 
 	exitStatus int
 }
@@ -73,6 +84,23 @@ func (step *taskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 	if err == nil {
 		// container already exists; recover session
 
+		// the outputs aren't stored with the container, but the config they
+		// came from can be fetched again, as the steps before this one have
+		// already been recovered
+		config, err := step.ConfigSource.FetchConfig(step.repo)
+		if err != nil {
+			return err
+		}
+
+		step.outputs = config.Outputs
+
+		// containers created before the artifacts root was saved off have no
+		// outputs to recover, so the generated one is as good as any
+		artifactsRoot, err := step.container.Property(taskArtifactsRootPropertyName)
+		if err == nil {
+			step.artifactsRoot = artifactsRoot
+		}
+
 		exitStatusProp, err := step.container.Property(taskExitStatusPropertyName)
 		if err == nil {
 			// process already completed; recover result
@@ -82,6 +110,8 @@ func (step *taskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 				return err
 			}
 
+			step.registerSources()
+
 			return nil
 		}
 
@@ -110,17 +140,33 @@ func (step *taskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 			return err
 		}
 
+		// resolved before anything is created, so that a missing credential
+		// fails fast
+		params, err := step.resolveParams(config.Params)
+		if err != nil {
+			return err
+		}
+
 		tags := step.mergeTags(step.Tags, config.Tags)
 
 		step.Delegate.Initializing(config)
 
+		image := config.Image
+		if config.ImageResource != "" {
+			image, err = step.imageFromSource(SourceName(config.ImageResource))
+			if err != nil {
+				return err
+			}
+		}
+
 		step.container, err = step.WorkerClient.CreateContainer(
 			step.WorkerID,
 			worker.TaskContainerSpec{
 				Platform:   config.Platform,
 				Tags:       tags,
-				Image:      config.Image,
+				Image:      image,
 				Privileged: bool(step.Privileged),
+				GraceTime:  step.GraceTime,
 			},
 		)
 		if err != nil {
@@ -137,16 +183,25 @@ func (step *taskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 			return err
 		}
 
+		step.outputs = config.Outputs
+
+		if len(config.Run.AppendEnv) > 0 {
+			params, err = step.appendEnv(params, config.Run.AppendEnv, processUser(config.Run.User))
+			if err != nil {
+				return err
+			}
+		}
+
 		step.Delegate.Started()
 
 		step.process, err = step.container.Run(garden.ProcessSpec{
 			Path: config.Run.Path,
 			Args: config.Run.Args,
-			Env:  step.envForParams(config.Params),
+			Env:  step.envForParams(params),
 
-			Dir:  step.artifactsRoot,
-			User: "root",
-			TTY:  &garden.TTYSpec{},
+			Dir:  step.processDir(config.Run.Dir),
+			User: processUser(config.Run.User),
+			TTY:  processTTY(config.Run.TTY),
 		}, processIO)
 		if err != nil {
 			return err
@@ -158,6 +213,11 @@ func (step *taskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 		if err != nil {
 			return err
 		}
+
+		err = step.container.SetProperty(taskArtifactsRootPropertyName, step.artifactsRoot)
+		if err != nil {
+			return err
+		}
 	}
 
 	close(ready)
@@ -179,7 +239,7 @@ func (step *taskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 		return ErrInterrupted
 
 	case status := <-waitExitStatus:
-		step.repo.RegisterSource(step.SourceName, step)
+		step.registerSources()
 
 		step.exitStatus = status
 
@@ -199,6 +259,22 @@ func (step *taskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 	}
 }
 
+func (step *taskStep) registerSources() {
+	step.repo.RegisterSource(step.SourceName, step)
+
+	for _, output := range step.outputs {
+		outputPath := output.Path
+		if len(outputPath) == 0 {
+			outputPath = output.Name
+		}
+
+		step.repo.RegisterSource(SourceName(output.Name), containerSource{
+			container: step.container,
+			path:      path.Join(step.artifactsRoot, outputPath),
+		})
+	}
+}
+
 func (step *taskStep) Result(x interface{}) bool {
 	switch v := x.(type) {
 	case *Success:
@@ -214,43 +290,20 @@ func (step *taskStep) Result(x interface{}) bool {
 	}
 }
 
-func (step *taskStep) Release() {
+func (step *taskStep) Release() error {
 	if step.container != nil {
 		step.container.Release()
 	}
+
+	return nil
 }
 
 func (step *taskStep) StreamFile(source string) (io.ReadCloser, error) {
-	out, err := step.container.StreamOut(garden.StreamOutSpec{
-		Path: path.Join(step.artifactsRoot, source),
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	tarReader := tar.NewReader(out)
-
-	_, err = tarReader.Next()
-	if err != nil {
-		return nil, FileNotFoundError{Path: source}
-	}
-
-	return fileReadCloser{
-		Reader: tarReader,
-		Closer: out,
-	}, nil
+	return containerSource{step.container, step.artifactsRoot}.StreamFile(source)
 }
 
 func (step *taskStep) StreamTo(destination ArtifactDestination) error {
-	out, err := step.container.StreamOut(garden.StreamOutSpec{
-		Path: step.artifactsRoot + "/",
-	})
-	if err != nil {
-		return err
-	}
-
-	return destination.StreamIn(".", out)
+	return containerSource{step.container, step.artifactsRoot}.StreamTo(destination)
 }
 
 func (step *taskStep) ensureBuildDirExists(container garden.Container) error {
@@ -272,6 +325,45 @@ func (step *taskStep) ensureBuildDirExists(container garden.Container) error {
 	return nil
 }
 
+// imageFromSource determines the rootfs of a Docker image fetched by an
+// earlier step, from the 'repository' and 'digest' files it wrote. The image
+// is pinned to the digest, so that the task runs with exactly the image that
+// was fetched even if its tag has since moved.
+func (step *taskStep) imageFromSource(name SourceName) (string, error) {
+	source, found := step.repo.SourceFor(name)
+	if !found {
+		return "", UnknownArtifactSourceError{name}
+	}
+
+	repository, err := readArtifactFile(source, "repository")
+	if err != nil {
+		return "", err
+	}
+
+	digest, err := readArtifactFile(source, "digest")
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("docker:///%s@%s", repository, digest), nil
+}
+
+func readArtifactFile(source ArtifactSource, path string) (string, error) {
+	stream, err := source.StreamFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer stream.Close()
+
+	contents, err := ioutil.ReadAll(stream)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(contents)), nil
+}
+
 func (step *taskStep) collectInputs(inputs []atc.TaskInputConfig) error {
 	type inputPair struct {
 		source      ArtifactSource
@@ -284,13 +376,16 @@ func (step *taskStep) collectInputs(inputs []atc.TaskInputConfig) error {
 	for _, input := range inputs {
 		source, found := step.repo.SourceFor(SourceName(input.Name))
 		if !found {
-			missingInputs = append(missingInputs, input.Name)
+			if !input.Optional {
+				missingInputs = append(missingInputs, input.Name)
+			}
+
 			continue
 		}
 
 		inputMappings = append(inputMappings, inputPair{
 			source:      source,
-			destination: newContainerDestination(step.artifactsRoot, step.container, input),
+			destination: compressedIfEnabled(newContainerDestination(step.artifactsRoot, step.container, input), step.CompressStreams),
 		})
 	}
 
@@ -308,6 +403,43 @@ func (step *taskStep) collectInputs(inputs []atc.TaskInputConfig) error {
 	return nil
 }
 
+func (step *taskStep) processDir(dir string) string {
+	if path.IsAbs(dir) {
+		return dir
+	}
+
+	return path.Join(step.artifactsRoot, dir)
+}
+
+func processUser(user string) string {
+	if user == "" {
+		return "root"
+	}
+
+	return user
+}
+
+func processTTY(config *atc.TaskTTYConfig) *garden.TTYSpec {
+	if config == nil {
+		return &garden.TTYSpec{}
+	}
+
+	if config.Disabled {
+		return nil
+	}
+
+	tty := &garden.TTYSpec{}
+
+	if config.Columns != 0 || config.Rows != 0 {
+		tty.WindowSize = &garden.WindowSize{
+			Columns: config.Columns,
+			Rows:    config.Rows,
+		}
+	}
+
+	return tty
+}
+
 func (taskStep) mergeTags(tagsOne []string, tagsTwo []string) []string {
 	var ret []string
 
@@ -328,11 +460,107 @@ func (taskStep) mergeTags(tagsOne []string, tagsTwo []string) []string {
 	return ret
 }
 
+// resolveParams replaces any credential references in the params' values
+// with the credentials they refer to.
+func (step *taskStep) resolveParams(params map[string]string) (map[string]string, error) {
+	if len(params) == 0 {
+		return params, nil
+	}
+
+	resolved := make(map[string]string, len(params))
+
+	for name, value := range params {
+		interpolated, err := credentials.Interpolate(step.CredentialManager, value)
+		if err != nil {
+			return nil, err
+		}
+
+		resolved[name] = interpolated
+	}
+
+	return resolved, nil
+}
+
+// appendEnv returns the params with the given values appended to the
+// variables of the same name. A variable that is not a param is taken from
+// the image's environment, as seen by a process running as the user.
+func (step *taskStep) appendEnv(params map[string]string, appends map[string]string, user string) (map[string]string, error) {
+	imageEnv, err := step.imageEnv(user)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]string, len(params)+len(appends))
+	for name, value := range params {
+		merged[name] = value
+	}
+
+	for name, value := range appends {
+		existing, found := merged[name]
+		if !found {
+			existing, found = imageEnv[name]
+		}
+
+		if found && existing != "" {
+			merged[name] = existing + ":" + value
+		} else {
+			merged[name] = value
+		}
+	}
+
+	return merged, nil
+}
+
+// imageEnv runs 'env' in the container to find the environment that the
+// image gives processes running as the user.
+func (step *taskStep) imageEnv(user string) (map[string]string, error) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+
+	process, err := step.container.Run(garden.ProcessSpec{
+		Path: "env",
+		User: user,
+	}, garden.ProcessIO{
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := process.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 0 {
+		return nil, fmt.Errorf("failed to determine the image's environment: exit status %d\n\nstderr:\n%s", status, stderr.String())
+	}
+
+	env := map[string]string{}
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		segs := strings.SplitN(line, "=", 2)
+		if len(segs) == 2 {
+			env[segs[0]] = segs[1]
+		}
+	}
+
+	return env, nil
+}
+
+// envForParams converts the params to environment variables, sorted by name
+// so that the process's environment is the same from run to run.
 func (taskStep) envForParams(params map[string]string) []string {
-	env := make([]string, 0, len(params))
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
 
-	for k, v := range params {
-		env = append(env, k+"="+v)
+	sort.Strings(names)
+
+	env := make([]string, 0, len(params))
+	for _, name := range names {
+		env = append(env, name+"="+params[name])
 	}
 
 	return env
@@ -363,3 +591,32 @@ func (dest *containerDestination) StreamIn(dst string, src io.Reader) error {
 		TarStream: src,
 	})
 }
+
+// containerSource is an ArtifactSource for a directory within a container.
+type containerSource struct {
+	container garden.Container
+	path      string
+}
+
+func (src containerSource) StreamFile(source string) (io.ReadCloser, error) {
+	out, err := src.container.StreamOut(garden.StreamOutSpec{
+		Path: path.Join(src.path, source),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return streamFirstFile(out, source)
+}
+
+func (src containerSource) StreamTo(destination ArtifactDestination) error {
+	out, err := src.container.StreamOut(garden.StreamOutSpec{
+		Path: src.path + "/",
+	})
+	if err != nil {
+		return err
+	}
+
+	return streamTo(destination, out)
+}
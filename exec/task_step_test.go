@@ -7,10 +7,13 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/cloudfoundry-incubator/garden"
 	gfakes "github.com/cloudfoundry-incubator/garden/fakes"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/credentials"
+	cfakes "github.com/concourse/atc/credentials/fakes"
 	. "github.com/concourse/atc/exec"
 	"github.com/concourse/atc/exec/fakes"
 	rfakes "github.com/concourse/atc/resource/fakes"
@@ -24,8 +27,10 @@ import (
 
 var _ = Describe("GardenFactory", func() {
 	var (
-		fakeTracker      *rfakes.FakeTracker
-		fakeWorkerClient *wfakes.FakeClient
+		fakeTracker           *rfakes.FakeTracker
+		fakeCache             *rfakes.FakeCache
+		fakeWorkerClient      *wfakes.FakeClient
+		fakeCredentialManager *cfakes.FakeCredentialManager
 
 		factory Factory
 
@@ -41,9 +46,19 @@ var _ = Describe("GardenFactory", func() {
 
 	BeforeEach(func() {
 		fakeTracker = new(rfakes.FakeTracker)
+		fakeCache = new(rfakes.FakeCache)
 		fakeWorkerClient = new(wfakes.FakeClient)
+		fakeCredentialManager = new(cfakes.FakeCredentialManager)
 
-		factory = NewGardenFactory(fakeWorkerClient, fakeTracker, func() string {
+		fakeCredentialManager.GetStub = func(key string) (string, error) {
+			if key == "some-secret" {
+				return "hunter2", nil
+			}
+
+			return "", credentials.MissingCredentialError{Key: key}
+		}
+
+		factory = NewGardenFactory(fakeWorkerClient, fakeTracker, fakeCache, nil, fakeCredentialManager, time.Hour, false, func() string {
 			return "a-random-guid"
 		})
 
@@ -157,7 +172,7 @@ var _ = Describe("GardenFactory", func() {
 						Ω(taskSpec.Tags).Should(ConsistOf("config", "step", "tags"))
 						Ω(taskSpec.Image).Should(Equal("some-image"))
 						Ω(taskSpec.Privileged).Should(BeFalse())
-
+						Ω(taskSpec.GraceTime).Should(Equal(time.Hour))
 					})
 
 					It("ensures artifacts root exists by streaming in an empty payload", func() {
@@ -185,6 +200,259 @@ var _ = Describe("GardenFactory", func() {
 						Ω(spec.TTY).Should(Equal(&garden.TTYSpec{}))
 					})
 
+					Context("when there are multiple params", func() {
+						BeforeEach(func() {
+							fetchedConfig.Params = map[string]string{
+								"ZETA":  "last",
+								"ALPHA": "first",
+								"MU":    "middle",
+								"SOME":  "params",
+							}
+
+							configSource.FetchConfigReturns(fetchedConfig, nil)
+						})
+
+						It("runs the process with the params in the environment, sorted by name", func() {
+							Ω(fakeContainer.RunCallCount()).Should(Equal(1))
+
+							spec, _ := fakeContainer.RunArgsForCall(0)
+							Ω(spec.Env).Should(Equal([]string{
+								"ALPHA=first",
+								"MU=middle",
+								"SOME=params",
+								"ZETA=last",
+							}))
+						})
+					})
+
+					Context("when a param shadows a variable in the image's environment", func() {
+						BeforeEach(func() {
+							fetchedConfig.Params = map[string]string{
+								"PATH": "/custom/bin",
+							}
+
+							configSource.FetchConfigReturns(fetchedConfig, nil)
+						})
+
+						It("replaces the variable", func() {
+							Ω(fakeContainer.RunCallCount()).Should(Equal(1))
+
+							spec, _ := fakeContainer.RunArgsForCall(0)
+							Ω(spec.Env).Should(Equal([]string{"PATH=/custom/bin"}))
+						})
+					})
+
+					Context("when the config appends to a variable", func() {
+						var envProcess *gfakes.FakeProcess
+
+						BeforeEach(func() {
+							envProcess = new(gfakes.FakeProcess)
+							envProcess.WaitReturns(0, nil)
+
+							fakeContainer.RunStub = func(spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
+								if spec.Path == "env" {
+									io.Stdout.Write([]byte("PATH=/usr/bin:/bin\nHOME=/root\n"))
+									return envProcess, nil
+								}
+
+								return fakeProcess, nil
+							}
+
+							fetchedConfig.Params = map[string]string{"SOME": "params"}
+							fetchedConfig.Run.AppendEnv = map[string]string{
+								"PATH": "/extra/bin",
+							}
+
+							configSource.FetchConfigReturns(fetchedConfig, nil)
+						})
+
+						It("appends to the variable's value in the image", func() {
+							Ω(fakeContainer.RunCallCount()).Should(Equal(2))
+
+							envSpec, _ := fakeContainer.RunArgsForCall(0)
+							Ω(envSpec.Path).Should(Equal("env"))
+							Ω(envSpec.User).Should(Equal("root"))
+
+							spec, _ := fakeContainer.RunArgsForCall(1)
+							Ω(spec.Env).Should(Equal([]string{
+								"PATH=/usr/bin:/bin:/extra/bin",
+								"SOME=params",
+							}))
+						})
+
+						Context("when a param also sets the variable", func() {
+							BeforeEach(func() {
+								fetchedConfig.Params = map[string]string{"PATH": "/custom/bin"}
+
+								configSource.FetchConfigReturns(fetchedConfig, nil)
+							})
+
+							It("appends to the param's value", func() {
+								Ω(fakeContainer.RunCallCount()).Should(Equal(2))
+
+								spec, _ := fakeContainer.RunArgsForCall(1)
+								Ω(spec.Env).Should(Equal([]string{"PATH=/custom/bin:/extra/bin"}))
+							})
+						})
+
+						Context("when the image does not set the variable", func() {
+							BeforeEach(func() {
+								fetchedConfig.Run.AppendEnv = map[string]string{
+									"LD_LIBRARY_PATH": "/extra/lib",
+								}
+
+								configSource.FetchConfigReturns(fetchedConfig, nil)
+							})
+
+							It("sets it to the appended value", func() {
+								Ω(fakeContainer.RunCallCount()).Should(Equal(2))
+
+								spec, _ := fakeContainer.RunArgsForCall(1)
+								Ω(spec.Env).Should(Equal([]string{
+									"LD_LIBRARY_PATH=/extra/lib",
+									"SOME=params",
+								}))
+							})
+						})
+
+						Context("when determining the image's environment fails", func() {
+							BeforeEach(func() {
+								envProcess.WaitReturns(127, nil)
+							})
+
+							It("exits with an error without running the task", func() {
+								Eventually(process.Wait()).Should(Receive(HaveOccurred()))
+								Ω(fakeContainer.RunCallCount()).Should(Equal(1))
+							})
+						})
+					})
+
+					Context("when the config disables the TTY", func() {
+						BeforeEach(func() {
+							fetchedConfig.Run.TTY = &atc.TaskTTYConfig{Disabled: true}
+
+							configSource.FetchConfigReturns(fetchedConfig, nil)
+						})
+
+						It("runs the process without a TTY", func() {
+							Ω(fakeContainer.RunCallCount()).Should(Equal(1))
+
+							spec, _ := fakeContainer.RunArgsForCall(0)
+							Ω(spec.TTY).Should(BeNil())
+						})
+					})
+
+					Context("when the config specifies the TTY's dimensions", func() {
+						BeforeEach(func() {
+							fetchedConfig.Run.TTY = &atc.TaskTTYConfig{Columns: 132, Rows: 43}
+
+							configSource.FetchConfigReturns(fetchedConfig, nil)
+						})
+
+						It("runs the process with a TTY of that size", func() {
+							Ω(fakeContainer.RunCallCount()).Should(Equal(1))
+
+							spec, _ := fakeContainer.RunArgsForCall(0)
+							Ω(spec.TTY).Should(Equal(&garden.TTYSpec{
+								WindowSize: &garden.WindowSize{
+									Columns: 132,
+									Rows:    43,
+								},
+							}))
+						})
+					})
+
+					Context("when the config specifies a directory and user to run with", func() {
+						BeforeEach(func() {
+							fetchedConfig.Run.Dir = "subdir"
+							fetchedConfig.Run.User = "some-user"
+
+							configSource.FetchConfigReturns(fetchedConfig, nil)
+						})
+
+						It("runs the process in the directory, relative to the build directory", func() {
+							Ω(fakeContainer.RunCallCount()).Should(Equal(1))
+
+							spec, _ := fakeContainer.RunArgsForCall(0)
+							Ω(spec.Dir).Should(Equal("/tmp/build/a-random-guid/subdir"))
+						})
+
+						It("runs the process as the user", func() {
+							Ω(fakeContainer.RunCallCount()).Should(Equal(1))
+
+							spec, _ := fakeContainer.RunArgsForCall(0)
+							Ω(spec.User).Should(Equal("some-user"))
+						})
+
+						Context("when the directory is absolute", func() {
+							BeforeEach(func() {
+								fetchedConfig.Run.Dir = "/some/dir"
+
+								configSource.FetchConfigReturns(fetchedConfig, nil)
+							})
+
+							It("runs the process in it as-is", func() {
+								Ω(fakeContainer.RunCallCount()).Should(Equal(1))
+
+								spec, _ := fakeContainer.RunArgsForCall(0)
+								Ω(spec.Dir).Should(Equal("/some/dir"))
+							})
+						})
+					})
+
+					Context("when a param refers to a credential", func() {
+						BeforeEach(func() {
+							fetchedConfig.Params = map[string]string{
+								"SOME":   "params",
+								"SECRET": "((some-secret))",
+							}
+
+							configSource.FetchConfigReturns(fetchedConfig, nil)
+						})
+
+						It("runs the process with the credential in place of the reference", func() {
+							Ω(fakeContainer.RunCallCount()).Should(Equal(1))
+
+							spec, _ := fakeContainer.RunArgsForCall(0)
+							Ω(spec.Env).Should(ConsistOf("SOME=params", "SECRET=hunter2"))
+
+							Ω(fakeCredentialManager.GetCallCount()).Should(Equal(1))
+							Ω(fakeCredentialManager.GetArgsForCall(0)).Should(Equal("some-secret"))
+						})
+
+						It("does not pass the credential to the delegate", func() {
+							Ω(taskDelegate.InitializingArgsForCall(0).Params).Should(HaveKeyWithValue("SECRET", "((some-secret))"))
+						})
+					})
+
+					Context("when a param refers to a missing credential", func() {
+						BeforeEach(func() {
+							fetchedConfig.Params = map[string]string{
+								"SECRET": "((bogus-secret))",
+							}
+
+							configSource.FetchConfigReturns(fetchedConfig, nil)
+						})
+
+						It("exits with an error naming the missing credential", func() {
+							var err error
+							Eventually(process.Wait()).Should(Receive(&err))
+
+							Ω(err).Should(Equal(credentials.MissingCredentialError{Key: "bogus-secret"}))
+							Ω(err.Error()).Should(ContainSubstring("bogus-secret"))
+						})
+
+						It("does not create a container", func() {
+							Eventually(process.Wait()).Should(Receive())
+							Ω(fakeWorkerClient.CreateContainerCallCount()).Should(BeZero())
+						})
+
+						It("invokes the delegate's Failed callback", func() {
+							Eventually(process.Wait()).Should(Receive())
+							Ω(taskDelegate.FailedCallCount()).Should(Equal(1))
+						})
+					})
+
 					It("directs the process's stdout/stderr to the io config", func() {
 						Ω(fakeContainer.RunCallCount()).Should(Equal(1))
 
@@ -194,13 +462,21 @@ var _ = Describe("GardenFactory", func() {
 					})
 
 					It("saves the process ID as a property", func() {
-						Ω(fakeContainer.SetPropertyCallCount()).Should(Equal(1))
+						Ω(fakeContainer.SetPropertyCallCount()).Should(Equal(2))
 
 						name, value := fakeContainer.SetPropertyArgsForCall(0)
 						Ω(name).Should(Equal("concourse:task-process"))
 						Ω(value).Should(Equal("42"))
 					})
 
+					It("saves the artifacts root as a property, so that outputs can be found after reattaching", func() {
+						Ω(fakeContainer.SetPropertyCallCount()).Should(Equal(2))
+
+						name, value := fakeContainer.SetPropertyArgsForCall(1)
+						Ω(name).Should(Equal("concourse:artifacts-root"))
+						Ω(value).Should(Equal("/tmp/build/a-random-guid"))
+					})
+
 					It("invokes the delegate's Started callback", func() {
 						Ω(taskDelegate.StartedCallCount()).Should(Equal(1))
 					})
@@ -237,6 +513,85 @@ var _ = Describe("GardenFactory", func() {
 						})
 					})
 
+					Context("when the configuration specifies an image resource", func() {
+						var imageSource *fakes.FakeArtifactSource
+
+						BeforeEach(func() {
+							fetchedConfig.ImageResource = "some-image-resource"
+							configSource.FetchConfigReturns(fetchedConfig, nil)
+
+							imageSource = new(fakes.FakeArtifactSource)
+						})
+
+						Context("when the image resource is in the source repository", func() {
+							BeforeEach(func() {
+								repo.RegisterSource("some-image-resource", imageSource)
+							})
+
+							Context("and it has a repository and a digest", func() {
+								BeforeEach(func() {
+									imageSource.StreamFileStub = func(path string) (io.ReadCloser, error) {
+										switch path {
+										case "repository":
+											return ioutil.NopCloser(bytes.NewBufferString("some/repository\n")), nil
+										case "digest":
+											return ioutil.NopCloser(bytes.NewBufferString("sha256:some-digest\n")), nil
+										case "tag":
+											return ioutil.NopCloser(bytes.NewBufferString("some-tag\n")), nil
+										default:
+											return nil, FileNotFoundError{Path: path}
+										}
+									}
+								})
+
+								It("creates the container with the fetched image, pinned by its digest, rather than the config's image", func() {
+									Eventually(process.Wait()).Should(Receive(BeNil()))
+
+									Ω(fakeWorkerClient.CreateContainerCallCount()).Should(Equal(1))
+									_, spec := fakeWorkerClient.CreateContainerArgsForCall(0)
+
+									taskSpec := spec.(worker.TaskContainerSpec)
+									Ω(taskSpec.Image).Should(Equal("docker:///some/repository@sha256:some-digest"))
+								})
+							})
+
+							Context("and it has no digest", func() {
+								BeforeEach(func() {
+									imageSource.StreamFileStub = func(path string) (io.ReadCloser, error) {
+										if path == "repository" {
+											return ioutil.NopCloser(bytes.NewBufferString("some/repository")), nil
+										}
+
+										return nil, FileNotFoundError{Path: path}
+									}
+								})
+
+								It("exits with the error without creating a container", func() {
+									Eventually(process.Wait()).Should(Receive(Equal(FileNotFoundError{Path: "digest"})))
+									Ω(fakeWorkerClient.CreateContainerCallCount()).Should(BeZero())
+								})
+							})
+
+							Context("and it has no repository", func() {
+								BeforeEach(func() {
+									imageSource.StreamFileReturns(nil, FileNotFoundError{Path: "repository"})
+								})
+
+								It("exits with the error without creating a container", func() {
+									Eventually(process.Wait()).Should(Receive(Equal(FileNotFoundError{Path: "repository"})))
+									Ω(fakeWorkerClient.CreateContainerCallCount()).Should(BeZero())
+								})
+							})
+						})
+
+						Context("when the image resource is not in the source repository", func() {
+							It("exits with an error without creating a container", func() {
+								Eventually(process.Wait()).Should(Receive(Equal(UnknownArtifactSourceError{"some-image-resource"})))
+								Ω(fakeWorkerClient.CreateContainerCallCount()).Should(BeZero())
+							})
+						})
+					})
+
 					Context("when the configuration specifies paths for inputs", func() {
 						var inputSource *fakes.FakeArtifactSource
 						var otherInputSource *fakes.FakeArtifactSource
@@ -302,6 +657,48 @@ var _ = Describe("GardenFactory", func() {
 								Eventually(process.Wait()).Should(Receive(BeNil()))
 							})
 
+							It("streams inputs in untouched, preserving file modes and modification times", func() {
+								modTime := time.Unix(1234567890, 0)
+
+								streamIn := new(bytes.Buffer)
+								tarWriter := tar.NewWriter(streamIn)
+
+								err := tarWriter.WriteHeader(&tar.Header{
+									Name:     "some-script",
+									Typeflag: tar.TypeReg,
+									Mode:     0755,
+									ModTime:  modTime,
+									Size:     int64(len("#!/bin/sh")),
+								})
+								Ω(err).ShouldNot(HaveOccurred())
+
+								_, err = tarWriter.Write([]byte("#!/bin/sh"))
+								Ω(err).ShouldNot(HaveOccurred())
+
+								err = tarWriter.Close()
+								Ω(err).ShouldNot(HaveOccurred())
+
+								destination := inputSource.StreamToArgsForCall(0)
+
+								initial := fakeContainer.StreamInCallCount()
+
+								err = destination.StreamIn(".", streamIn)
+								Ω(err).ShouldNot(HaveOccurred())
+
+								spec := fakeContainer.StreamInArgsForCall(initial)
+
+								tarReader := tar.NewReader(spec.TarStream)
+
+								header, err := tarReader.Next()
+								Ω(err).ShouldNot(HaveOccurred())
+
+								Ω(header.Name).Should(Equal("some-script"))
+								Ω(header.FileInfo().Mode().Perm()).Should(Equal(os.FileMode(0755)))
+								Ω(header.ModTime.Equal(modTime)).Should(BeTrue())
+
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+							})
+
 							Context("when streaming the bits in to the container fails", func() {
 								disaster := errors.New("nope")
 
@@ -351,6 +748,134 @@ var _ = Describe("GardenFactory", func() {
 						})
 					})
 
+					Context("when artifact streams are compressed", func() {
+						var streamedIn map[string]string
+
+						BeforeEach(func() {
+							factory = NewGardenFactory(fakeWorkerClient, fakeTracker, fakeCache, nil, fakeCredentialManager, time.Hour, true, func() string {
+								return "a-random-guid"
+							})
+
+							configSource.FetchConfigReturns(atc.TaskConfig{
+								Image: "some-image",
+								Run: atc.TaskRunConfig{
+									Path: "ls",
+								},
+								Inputs: []atc.TaskInputConfig{
+									{Name: "some-input"},
+								},
+							}, nil)
+
+							writeStep := WriteSource("some-input", map[string][]byte{
+								"some-file":       []byte("some-content"),
+								"some-dir/nested": []byte("some-nested-content"),
+							}).Using(nil, repo)
+							Ω(writeStep.Run(nil, make(chan struct{}))).Should(Succeed())
+
+							streamedIn = map[string]string{}
+							fakeContainer.StreamInStub = func(spec garden.StreamInSpec) error {
+								tarReader := tar.NewReader(spec.TarStream)
+
+								for {
+									hdr, err := tarReader.Next()
+									if err == io.EOF {
+										return nil
+									}
+
+									if err != nil {
+										return err
+									}
+
+									content, err := ioutil.ReadAll(tarReader)
+									if err != nil {
+										return err
+									}
+
+									streamedIn[hdr.Name] = string(content)
+								}
+							}
+						})
+
+						It("decompresses the input before streaming it into the container", func() {
+							Eventually(process.Wait()).Should(Receive(BeNil()))
+
+							Ω(fakeContainer.StreamInCallCount()).Should(Equal(1))
+							Ω(fakeContainer.StreamInArgsForCall(0).Path).Should(Equal("/tmp/build/a-random-guid/some-input/."))
+							Ω(streamedIn).Should(Equal(map[string]string{
+								"some-file":       "some-content",
+								"some-dir/nested": "some-nested-content",
+							}))
+						})
+					})
+
+					Context("when the configuration specifies optional inputs", func() {
+						var inputSource *fakes.FakeArtifactSource
+						var optionalInputSource *fakes.FakeArtifactSource
+
+						BeforeEach(func() {
+							inputSource = new(fakes.FakeArtifactSource)
+							optionalInputSource = new(fakes.FakeArtifactSource)
+
+							configSource.FetchConfigReturns(atc.TaskConfig{
+								Image: "some-image",
+								Run: atc.TaskRunConfig{
+									Path: "ls",
+								},
+								Inputs: []atc.TaskInputConfig{
+									{Name: "some-input"},
+									{Name: "some-optional-input", Optional: true},
+								},
+							}, nil)
+						})
+
+						Context("when an optional input is missing", func() {
+							BeforeEach(func() {
+								repo.RegisterSource("some-input", inputSource)
+							})
+
+							It("runs the task with the inputs that are present", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								Ω(inputSource.StreamToCallCount()).Should(Equal(1))
+								Ω(fakeContainer.RunCallCount()).Should(Equal(1))
+							})
+						})
+
+						Context("when the optional input is present", func() {
+							BeforeEach(func() {
+								repo.RegisterSource("some-input", inputSource)
+								repo.RegisterSource("some-optional-input", optionalInputSource)
+							})
+
+							It("streams it in", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								Ω(optionalInputSource.StreamToCallCount()).Should(Equal(1))
+							})
+						})
+
+						Context("when a required input is missing", func() {
+							BeforeEach(func() {
+								repo.RegisterSource("some-optional-input", optionalInputSource)
+							})
+
+							It("exits with failure", func() {
+								var err error
+								Eventually(process.Wait()).Should(Receive(&err))
+								Ω(err).Should(Equal(MissingInputsError{[]string{"some-input"}}))
+								Ω(fakeContainer.RunCallCount()).Should(BeZero())
+							})
+						})
+
+						Context("when both the required and optional inputs are missing", func() {
+							It("exits with failure, listing only the required input", func() {
+								var err error
+								Eventually(process.Wait()).Should(Receive(&err))
+								Ω(err).Should(Equal(MissingInputsError{[]string{"some-input"}}))
+							})
+						})
+					})
+
 					Context("when the process exits 0", func() {
 						BeforeEach(func() {
 							fakeProcess.WaitReturns(0, nil)
@@ -359,9 +884,9 @@ var _ = Describe("GardenFactory", func() {
 						It("saves the exit status property", func() {
 							Eventually(process.Wait()).Should(Receive(BeNil()))
 
-							Ω(fakeContainer.SetPropertyCallCount()).Should(Equal(2))
+							Ω(fakeContainer.SetPropertyCallCount()).Should(Equal(3))
 
-							name, value := fakeContainer.SetPropertyArgsForCall(1)
+							name, value := fakeContainer.SetPropertyArgsForCall(2)
 							Ω(name).Should(Equal("concourse:exit-status"))
 							Ω(value).Should(Equal("0"))
 						})
@@ -382,6 +907,92 @@ var _ = Describe("GardenFactory", func() {
 							Ω(status).Should(Equal(ExitStatus(0)))
 						})
 
+						Context("when the configuration declares outputs", func() {
+							BeforeEach(func() {
+								fetchedConfig.Outputs = []atc.TaskOutputConfig{
+									{Name: "some-output", Path: "some-output-configured-path"},
+									{Name: "some-other-output"},
+								}
+
+								configSource.FetchConfigReturns(fetchedConfig, nil)
+							})
+
+							It("registers a source for each output", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								_, found := repo.SourceFor("some-output")
+								Ω(found).Should(BeTrue())
+
+								_, found = repo.SourceFor("some-other-output")
+								Ω(found).Should(BeTrue())
+							})
+
+							It("streams each output's configured path to destinations", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								streamedOut := gbytes.BufferWithBytes([]byte("some-stream"))
+								fakeContainer.StreamOutReturns(streamedOut, nil)
+
+								var streamedIn []byte
+								fakeDestination := new(fakes.FakeArtifactDestination)
+								fakeDestination.StreamInStub = func(dst string, src io.Reader) error {
+									var err error
+									streamedIn, err = ioutil.ReadAll(src)
+									return err
+								}
+
+								outputSource, _ := repo.SourceFor("some-output")
+								err := outputSource.StreamTo(fakeDestination)
+								Ω(err).ShouldNot(HaveOccurred())
+
+								spec := fakeContainer.StreamOutArgsForCall(0)
+								Ω(spec.Path).Should(Equal("/tmp/build/a-random-guid/some-output-configured-path/"))
+
+								dest, _ := fakeDestination.StreamInArgsForCall(0)
+								Ω(dest).Should(Equal("."))
+								Ω(string(streamedIn)).Should(Equal("some-stream"))
+								Ω(streamedOut.Closed()).Should(BeTrue())
+
+								otherOutputSource, _ := repo.SourceFor("some-other-output")
+								err = otherOutputSource.StreamTo(fakeDestination)
+								Ω(err).ShouldNot(HaveOccurred())
+
+								spec = fakeContainer.StreamOutArgsForCall(1)
+								Ω(spec.Path).Should(Equal("/tmp/build/a-random-guid/some-other-output/"))
+							})
+
+							It("streams files out of each output's configured path", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								tarBuffer := gbytes.NewBuffer()
+								tarWriter := tar.NewWriter(tarBuffer)
+
+								err := tarWriter.WriteHeader(&tar.Header{
+									Name: "some-file",
+									Mode: 0644,
+									Size: int64(len("file-content")),
+								})
+								Ω(err).ShouldNot(HaveOccurred())
+
+								_, err = tarWriter.Write([]byte("file-content"))
+								Ω(err).ShouldNot(HaveOccurred())
+
+								err = tarWriter.Close()
+								Ω(err).ShouldNot(HaveOccurred())
+
+								fakeContainer.StreamOutReturns(tarBuffer, nil)
+
+								outputSource, _ := repo.SourceFor("some-output")
+								reader, err := outputSource.StreamFile("some-path")
+								Ω(err).ShouldNot(HaveOccurred())
+
+								Ω(ioutil.ReadAll(reader)).Should(Equal([]byte("file-content")))
+
+								spec := fakeContainer.StreamOutArgsForCall(0)
+								Ω(spec.Path).Should(Equal("/tmp/build/a-random-guid/some-output-configured-path/some-path"))
+							})
+						})
+
 						Describe("the registered source", func() {
 							var artifactSource ArtifactSource
 
@@ -401,11 +1012,21 @@ var _ = Describe("GardenFactory", func() {
 								})
 
 								Context("when the resource can stream out", func() {
-									var streamedOut io.ReadCloser
+									var (
+										streamedOut *gbytes.Buffer
+										streamedIn  []byte
+									)
 
 									BeforeEach(func() {
-										streamedOut = gbytes.NewBuffer()
+										streamedOut = gbytes.BufferWithBytes([]byte("some-stream"))
 										fakeContainer.StreamOutReturns(streamedOut, nil)
+
+										streamedIn = nil
+										fakeDestination.StreamInStub = func(dst string, src io.Reader) error {
+											var err error
+											streamedIn, err = ioutil.ReadAll(src)
+											return err
+										}
 									})
 
 									It("streams the resource to the destination", func() {
@@ -418,9 +1039,16 @@ var _ = Describe("GardenFactory", func() {
 										Ω(spec.User).Should(Equal("")) // use default
 
 										Ω(fakeDestination.StreamInCallCount()).Should(Equal(1))
-										dest, src := fakeDestination.StreamInArgsForCall(0)
+										dest, _ := fakeDestination.StreamInArgsForCall(0)
 										Ω(dest).Should(Equal("."))
-										Ω(src).Should(Equal(streamedOut))
+										Ω(string(streamedIn)).Should(Equal("some-stream"))
+									})
+
+									It("closes the stream once the destination is done with it", func() {
+										err := artifactSource.StreamTo(fakeDestination)
+										Ω(err).ShouldNot(HaveOccurred())
+
+										Ω(streamedOut.Closed()).Should(BeTrue())
 									})
 
 									Context("when streaming out of the versioned source fails", func() {
@@ -439,6 +1067,7 @@ var _ = Describe("GardenFactory", func() {
 										disaster := errors.New("nope")
 
 										BeforeEach(func() {
+											fakeDestination.StreamInStub = nil
 											fakeDestination.StreamInReturns(disaster)
 										})
 
@@ -591,9 +1220,9 @@ var _ = Describe("GardenFactory", func() {
 						It("saves the exit status property", func() {
 							Eventually(process.Wait()).Should(Receive(BeNil()))
 
-							Ω(fakeContainer.SetPropertyCallCount()).Should(Equal(2))
+							Ω(fakeContainer.SetPropertyCallCount()).Should(Equal(3))
 
-							name, value := fakeContainer.SetPropertyArgsForCall(1)
+							name, value := fakeContainer.SetPropertyArgsForCall(2)
 							Ω(name).Should(Equal("concourse:exit-status"))
 							Ω(value).Should(Equal("1"))
 						})
@@ -677,6 +1306,38 @@ var _ = Describe("GardenFactory", func() {
 						})
 					})
 
+					Context("when the worker goes away while waiting on the process", func() {
+						var workerGone worker.WorkerGoneError
+
+						BeforeEach(func() {
+							workerGone = worker.WorkerGoneError{
+								Handle: "some-handle",
+								Err:    errors.New("unexpected EOF"),
+							}
+
+							fakeProcess.WaitReturns(0, workerGone)
+						})
+
+						It("exits with an error categorized as a system error", func() {
+							var err error
+							Eventually(process.Wait()).Should(Receive(&err))
+
+							Ω(err).Should(Equal(workerGone))
+							Ω(CategorizeError(err)).Should(Equal(StepErrorCategorySystem))
+						})
+
+						It("does not report the task as finished", func() {
+							Eventually(process.Wait()).Should(Receive())
+
+							Ω(taskDelegate.FinishedCallCount()).Should(BeZero())
+
+							for i := 0; i < fakeContainer.SetPropertyCallCount(); i++ {
+								name, _ := fakeContainer.SetPropertyArgsForCall(i)
+								Ω(name).ShouldNot(Equal("concourse:exit-status"))
+							}
+						})
+					})
+
 					Context("when setting the process property fails", func() {
 						disaster := errors.New("nope")
 
@@ -839,6 +1500,61 @@ var _ = Describe("GardenFactory", func() {
 					Eventually(process.Wait()).Should(Receive(BeNil()))
 					Ω(taskDelegate.FinishedCallCount()).Should(BeZero())
 				})
+
+				It("registers itself as a source", func() {
+					Eventually(process.Wait()).Should(Receive(BeNil()))
+
+					_, found := repo.SourceFor(sourceName)
+					Ω(found).Should(BeTrue())
+				})
+
+				Context("when the config has outputs and the artifacts root was saved off", func() {
+					BeforeEach(func() {
+						configSource.FetchConfigReturns(atc.TaskConfig{
+							Outputs: []atc.TaskOutputConfig{
+								{Name: "some-output"},
+							},
+						}, nil)
+
+						fakeContainer.PropertyStub = func(name string) (string, error) {
+							switch name {
+							case "concourse:exit-status":
+								return "123", nil
+							case "concourse:artifacts-root":
+								return "/tmp/build/some-previous-guid", nil
+							default:
+								return "", errors.New("unstubbed property: " + name)
+							}
+						}
+					})
+
+					It("registers each output under the recovered artifacts root", func() {
+						Eventually(process.Wait()).Should(Receive(BeNil()))
+
+						fakeContainer.StreamOutReturns(gbytes.BufferWithBytes([]byte("some-stream")), nil)
+
+						outputSource, found := repo.SourceFor("some-output")
+						Ω(found).Should(BeTrue())
+
+						err := outputSource.StreamTo(new(fakes.FakeArtifactDestination))
+						Ω(err).ShouldNot(HaveOccurred())
+
+						spec := fakeContainer.StreamOutArgsForCall(0)
+						Ω(spec.Path).Should(Equal("/tmp/build/some-previous-guid/some-output/"))
+					})
+				})
+
+				Context("when fetching the config fails", func() {
+					disaster := errors.New("nope")
+
+					BeforeEach(func() {
+						configSource.FetchConfigReturns(atc.TaskConfig{}, disaster)
+					})
+
+					It("exits with the error", func() {
+						Eventually(process.Wait()).Should(Receive(Equal(disaster)))
+					})
+				})
 			})
 
 			Context("when the process id can be found", func() {
@@ -879,6 +1595,24 @@ var _ = Describe("GardenFactory", func() {
 					It("does not invoke the delegate's Started callback", func() {
 						Ω(taskDelegate.StartedCallCount()).Should(BeZero())
 					})
+
+					Context("when the process exits", func() {
+						BeforeEach(func() {
+							fakeProcess.WaitReturns(3, nil)
+						})
+
+						It("reports the reattached process's exit status", func() {
+							Eventually(process.Wait()).Should(Receive(BeNil()))
+
+							var status ExitStatus
+							Ω(step.Result(&status)).Should(BeTrue())
+							Ω(status).Should(Equal(ExitStatus(3)))
+
+							var success Success
+							Ω(step.Result(&success)).Should(BeTrue())
+							Ω(bool(success)).Should(BeFalse())
+						})
+					})
 				})
 
 				Context("when attaching to the process fails", func() {
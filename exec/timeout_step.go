@@ -5,6 +5,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/pivotal-golang/clock"
 	"github.com/tedsuo/ifrit"
 )
 
@@ -12,6 +13,7 @@ type timeout struct {
 	step     StepFactory
 	runStep  Step
 	duration string
+	clock    clock.Clock
 	timedOut bool
 }
 
@@ -20,10 +22,12 @@ var ErrStepTimedOut = errors.New("process-exceeded-timeout-limit")
 func Timeout(
 	step StepFactory,
 	duration string,
+	clock clock.Clock,
 ) StepFactory {
 	return timeout{
 		step:     step,
 		duration: duration,
+		clock:    clock,
 	}
 }
 
@@ -43,7 +47,8 @@ func (ts *timeout) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
 		return err
 	}
 
-	timer := time.NewTimer(parsedDuration)
+	timer := ts.clock.NewTimer(parsedDuration)
+	defer timer.Stop()
 
 	var runErr error
 	var timeoutErr error
@@ -54,7 +59,7 @@ dance:
 		select {
 		case runErr = <-runProcess.Wait():
 			break dance
-		case <-timer.C:
+		case <-timer.C():
 			ts.timedOut = true
 			timeoutErr = ErrStepTimedOut
 			runProcess.Signal(os.Kill)
@@ -74,8 +79,8 @@ dance:
 	return nil
 }
 
-func (ts *timeout) Release() {
-	ts.runStep.Release()
+func (ts *timeout) Release() error {
+	return ts.runStep.Release()
 }
 
 func (ts *timeout) Result(x interface{}) bool {
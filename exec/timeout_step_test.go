@@ -10,6 +10,7 @@ import (
 	"github.com/concourse/atc/exec/fakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/clock/fakeclock"
 
 	"github.com/tedsuo/ifrit"
 )
@@ -27,6 +28,7 @@ var _ = Describe("Timeout Step", func() {
 		process   ifrit.Process
 
 		timeoutDuration string
+		fakeClock       *fakeclock.FakeClock
 	)
 
 	BeforeEach(func() {
@@ -35,11 +37,12 @@ var _ = Describe("Timeout Step", func() {
 		runStep = new(fakes.FakeStep)
 		fakeStepFactoryStep.UsingReturns(runStep)
 
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
 	})
 
 	Context("when the process is invoked with invoke", func() {
 		It("exits successfully", func() {
-			timeout = Timeout(fakeStepFactoryStep, timeoutDuration)
+			timeout = Timeout(fakeStepFactoryStep, timeoutDuration, fakeClock)
 			step = timeout.Using(nil, nil)
 			process = ifrit.Invoke(step)
 
@@ -49,7 +52,7 @@ var _ = Describe("Timeout Step", func() {
 
 	Context("when we pass an invalid duration", func() {
 		It("errors", func() {
-			timeout = Timeout(fakeStepFactoryStep, "nope")
+			timeout = Timeout(fakeStepFactoryStep, "nope", fakeClock)
 			step = timeout.Using(nil, nil)
 			ready := make(chan struct{})
 			err := step.Run(nil, ready)
@@ -59,7 +62,7 @@ var _ = Describe("Timeout Step", func() {
 
 	Context("when the process is invoked with background", func() {
 		JustBeforeEach(func() {
-			timeout = Timeout(fakeStepFactoryStep, timeoutDuration)
+			timeout = Timeout(fakeStepFactoryStep, timeoutDuration, fakeClock)
 			step = timeout.Using(nil, nil)
 			process = ifrit.Background(step)
 		})
@@ -82,9 +85,15 @@ var _ = Describe("Timeout Step", func() {
 
 			It("should interrupt after timeout duration", func() {
 				Eventually(runStep.RunCallCount).Should(Equal(1))
+				Consistently(process.Wait()).ShouldNot(Receive())
+
+				fakeClock.Increment(time.Second - time.Millisecond)
+				Consistently(process.Wait()).ShouldNot(Receive())
+
+				fakeClock.Increment(time.Millisecond)
 
 				var receivedError error
-				Eventually(process.Wait(), 3*time.Second).Should(Receive(&receivedError))
+				Eventually(process.Wait()).Should(Receive(&receivedError))
 				Ω(receivedError).Should(Equal(ErrStepTimedOut))
 			})
 
@@ -125,9 +134,12 @@ var _ = Describe("Timeout Step", func() {
 			Context("result", func() {
 				It("is not successful", func() {
 					Eventually(runStep.RunCallCount).Should(Equal(1))
+					Consistently(process.Wait()).ShouldNot(Receive())
+
+					fakeClock.Increment(time.Second)
 
 					var receivedError error
-					Eventually(process.Wait(), 3*time.Second).Should(Receive(&receivedError))
+					Eventually(process.Wait()).Should(Receive(&receivedError))
 					Ω(receivedError).ShouldNot(BeNil())
 
 					var success Success
@@ -169,4 +181,28 @@ var _ = Describe("Timeout Step", func() {
 			})
 		})
 	})
+
+	Describe("Release", func() {
+		BeforeEach(func() {
+			timeout = Timeout(fakeStepFactoryStep, "1h", fakeClock)
+			step = timeout.Using(nil, nil)
+		})
+
+		It("releases the inner step", func() {
+			Ω(step.Release()).Should(Succeed())
+			Ω(runStep.ReleaseCallCount()).Should(Equal(1))
+		})
+
+		Context("when releasing the inner step fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				runStep.ReleaseReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				Ω(step.Release()).Should(Equal(disaster))
+			})
+		})
+	})
 })
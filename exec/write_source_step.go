@@ -0,0 +1,117 @@
+package exec
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// WriteSource registers a source with the given name whose content is the
+// given files, keyed by their path within the source. No container is
+// involved; the files are held in memory and streamed out as a tar.
+//
+// This is for injecting computed values, e.g. a version number or a
+// generated config, as an artifact for later steps.
+func WriteSource(name SourceName, files map[string][]byte) StepFactory {
+	return writeSource{
+		name:  name,
+		files: files,
+	}
+}
+
+type writeSource struct {
+	name  SourceName
+	files map[string][]byte
+}
+
+func (ws writeSource) Using(prev Step, repo *SourceRepository) Step {
+	return &writeSourceStep{
+		writeSource: ws,
+		repo:        repo,
+	}
+}
+
+type writeSourceStep struct {
+	writeSource
+
+	repo *SourceRepository
+}
+
+func (step *writeSourceStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	step.repo.RegisterSource(step.name, inMemorySource(step.files))
+	close(ready)
+	return nil
+}
+
+func (step *writeSourceStep) Release() error {
+	return nil
+}
+
+func (step *writeSourceStep) Result(x interface{}) bool {
+	switch v := x.(type) {
+	case *Success:
+		*v = true
+		return true
+
+	default:
+		return false
+	}
+}
+
+type inMemorySource map[string][]byte
+
+func (source inMemorySource) StreamTo(dest ArtifactDestination) error {
+	paths := make([]string, 0, len(source))
+	for path := range source {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	buf := new(bytes.Buffer)
+	tarWriter := tar.NewWriter(buf)
+
+	for _, path := range paths {
+		content := source[path]
+
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name: path,
+			Mode: 0644,
+			Size: int64(len(content)),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = tarWriter.Write(content)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return streamTo(dest, ioutil.NopCloser(buf))
+}
+
+func (source inMemorySource) StreamFile(path string) (io.ReadCloser, error) {
+	content, found := source[path]
+	if found {
+		return ioutil.NopCloser(bytes.NewReader(content)), nil
+	}
+
+	for filePath := range source {
+		if strings.HasPrefix(filePath, path+"/") {
+			return nil, FileIsDirectoryError{Path: path}
+		}
+	}
+
+	return nil, FileNotFoundError{Path: path}
+}
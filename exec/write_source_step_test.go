@@ -0,0 +1,141 @@
+package exec_test
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+
+	. "github.com/concourse/atc/exec"
+	"github.com/concourse/atc/exec/fakes"
+	"github.com/tedsuo/ifrit"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteSource", func() {
+	var (
+		inStep *fakes.FakeStep
+		repo   *SourceRepository
+
+		step    Step
+		process ifrit.Process
+	)
+
+	BeforeEach(func() {
+		inStep = new(fakes.FakeStep)
+		repo = NewSourceRepository()
+	})
+
+	JustBeforeEach(func() {
+		step = WriteSource("some-source", map[string][]byte{
+			"version":          []byte("1.2.3"),
+			"config/build.yml": []byte("run: {path: ls}"),
+		}).Using(inStep, repo)
+
+		process = ifrit.Invoke(step)
+	})
+
+	It("succeeds", func() {
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+
+		var success Success
+		Ω(step.Result(&success)).Should(BeTrue())
+		Ω(bool(success)).Should(BeTrue())
+	})
+
+	It("registers the source under the given name", func() {
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+
+		_, found := repo.SourceFor("some-source")
+		Ω(found).Should(BeTrue())
+	})
+
+	Describe("the registered source", func() {
+		var source ArtifactSource
+
+		JustBeforeEach(func() {
+			Eventually(process.Wait()).Should(Receive(BeNil()))
+
+			var found bool
+			source, found = repo.SourceFor("some-source")
+			Ω(found).Should(BeTrue())
+		})
+
+		Describe("streaming a file out", func() {
+			It("streams the file's content", func() {
+				reader, err := source.StreamFile("config/build.yml")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(ioutil.ReadAll(reader)).Should(Equal([]byte("run: {path: ls}")))
+			})
+
+			It("returns FileNotFoundError for files it does not have", func() {
+				_, err := source.StreamFile("bogus")
+				Ω(err).Should(Equal(FileNotFoundError{Path: "bogus"}))
+			})
+
+			It("returns FileIsDirectoryError for directories", func() {
+				_, err := source.StreamFile("config")
+				Ω(err).Should(Equal(FileIsDirectoryError{Path: "config"}))
+			})
+
+			It("can be reached through the repository", func() {
+				reader, err := repo.StreamFile("some-source/version")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(ioutil.ReadAll(reader)).Should(Equal([]byte("1.2.3")))
+			})
+		})
+
+		Describe("streaming to a destination", func() {
+			var fakeDestination *fakes.FakeArtifactDestination
+			var streamed map[string]string
+
+			BeforeEach(func() {
+				fakeDestination = new(fakes.FakeArtifactDestination)
+				streamed = map[string]string{}
+
+				fakeDestination.StreamInStub = func(dst string, src io.Reader) error {
+					tarReader := tar.NewReader(src)
+
+					for {
+						header, err := tarReader.Next()
+						if err == io.EOF {
+							return nil
+						}
+
+						Ω(err).ShouldNot(HaveOccurred())
+
+						content, err := ioutil.ReadAll(tarReader)
+						Ω(err).ShouldNot(HaveOccurred())
+
+						streamed[header.Name] = string(content)
+					}
+				}
+			})
+
+			It("streams each file in as a tar", func() {
+				err := source.StreamTo(fakeDestination)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(fakeDestination.StreamInCallCount()).Should(Equal(1))
+
+				dst, _ := fakeDestination.StreamInArgsForCall(0)
+				Ω(dst).Should(Equal("."))
+
+				Ω(streamed).Should(Equal(map[string]string{
+					"version":          "1.2.3",
+					"config/build.yml": "run: {path: ls}",
+				}))
+			})
+		})
+	})
+
+	Describe("releasing", func() {
+		It("succeeds", func() {
+			Eventually(process.Wait()).Should(Receive(BeNil()))
+			Ω(step.Release()).Should(Succeed())
+		})
+	})
+})
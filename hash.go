@@ -0,0 +1,68 @@
+package atc
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+)
+
+// Hash returns a digest of the version that is the same for equivalent
+// versions, regardless of key order.
+func (version Version) Hash() string {
+	return stableHash(map[string]interface{}(version))
+}
+
+// Hash returns a digest of the source that is the same for equivalent
+// sources, regardless of key order.
+func (source Source) Hash() string {
+	return stableHash(map[string]interface{}(source))
+}
+
+// Hash returns a digest of the params that is the same for equivalent
+// params, regardless of key order.
+func (params Params) Hash() string {
+	return stableHash(map[string]interface{}(params))
+}
+
+func stableHash(value map[string]interface{}) string {
+	// encoding/json sorts map keys, so the encoding is canonical
+	payload, err := json.Marshal(normalizeForHash(value))
+	if err != nil {
+		panic("unserializable value: " + err.Error())
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(payload))
+}
+
+// normalizeForHash converts maps decoded from YAML, which are keyed by
+// interface{}, into string-keyed maps that encoding/json can sort.
+func normalizeForHash(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			normalized[key] = normalizeForHash(val)
+		}
+
+		return normalized
+
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			normalized[fmt.Sprintf("%v", key)] = normalizeForHash(val)
+		}
+
+		return normalized
+
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, val := range v {
+			normalized[i] = normalizeForHash(val)
+		}
+
+		return normalized
+
+	default:
+		return value
+	}
+}
@@ -0,0 +1,91 @@
+package atc_test
+
+import (
+	. "github.com/concourse/atc"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hashing", func() {
+	Describe("Version.Hash", func() {
+		It("is the same regardless of the order entries were added in", func() {
+			a := Version{}
+			a["ref"] = "abc"
+			a["branch"] = "master"
+			a["number"] = 42
+
+			b := Version{}
+			b["number"] = 42
+			b["branch"] = "master"
+			b["ref"] = "abc"
+
+			for i := 0; i < 100; i++ {
+				Ω(a.Hash()).Should(Equal(b.Hash()))
+			}
+		})
+
+		It("differs for different versions", func() {
+			a := Version{"ref": "abc"}
+			b := Version{"ref": "def"}
+
+			Ω(a.Hash()).ShouldNot(Equal(b.Hash()))
+		})
+
+		It("is a SHA-256 hex digest", func() {
+			Ω(Version{"ref": "abc"}.Hash()).Should(MatchRegexp(`^[0-9a-f]{64}$`))
+		})
+	})
+
+	Describe("Source.Hash", func() {
+		It("is the same regardless of the order entries were added in", func() {
+			a := Source{}
+			a["uri"] = "https://example.com"
+			a["branch"] = "master"
+			a["nested"] = map[string]interface{}{"x": 1, "y": 2}
+
+			b := Source{}
+			b["nested"] = map[string]interface{}{"y": 2, "x": 1}
+			b["branch"] = "master"
+			b["uri"] = "https://example.com"
+
+			for i := 0; i < 100; i++ {
+				Ω(a.Hash()).Should(Equal(b.Hash()))
+			}
+		})
+
+		It("differs for different sources", func() {
+			a := Source{"uri": "https://example.com/a"}
+			b := Source{"uri": "https://example.com/b"}
+
+			Ω(a.Hash()).ShouldNot(Equal(b.Hash()))
+		})
+
+		It("is the same for sources decoded from YAML and JSON", func() {
+			fromYAML := Source{
+				"nested": map[interface{}]interface{}{"x": 1},
+				"list":   []interface{}{map[interface{}]interface{}{"y": 2}},
+			}
+
+			fromJSON := Source{
+				"nested": map[string]interface{}{"x": 1},
+				"list":   []interface{}{map[string]interface{}{"y": 2}},
+			}
+
+			Ω(fromYAML.Hash()).Should(Equal(fromJSON.Hash()))
+		})
+	})
+
+	Describe("Params.Hash", func() {
+		It("differs for different params", func() {
+			a := Params{"skip_download": true}
+			b := Params{"skip_download": false}
+
+			Ω(a.Hash()).ShouldNot(Equal(b.Hash()))
+		})
+
+		It("is the same for no params and empty params", func() {
+			Ω(Params(nil).Hash()).Should(Equal(Params{}.Hash()))
+		})
+	})
+})
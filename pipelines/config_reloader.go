@@ -0,0 +1,76 @@
+package pipelines
+
+import (
+	"os"
+
+	"github.com/concourse/atc/api/configserver"
+	"github.com/concourse/atc/config"
+	"github.com/concourse/atc/db"
+	"github.com/pivotal-golang/lager"
+)
+
+// ConfigReloader loads the pipeline config files, merged into one config, into
+// the database on start and again whenever it receives on Reload (e.g. on
+// SIGHUP). Configs that fail to load or validate are logged and ignored,
+// keeping the current config in place. They are validated as they would be
+// if set via the API.
+//
+// The radar and scheduler runners read the config from the database on each
+// tick, so they adopt the new jobs and resources without interrupting any
+// running builds.
+type ConfigReloader struct {
+	Logger lager.Logger
+
+	DB db.ConfigDB
+
+	Validator configserver.ConfigValidator
+
+	PipelineName string
+	ConfigPaths  []string
+
+	Reload <-chan os.Signal
+}
+
+func (reloader ConfigReloader) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	reloader.load(reloader.Logger.Session("load"))
+
+	close(ready)
+
+	for {
+		select {
+		case <-reloader.Reload:
+			reloader.load(reloader.Logger.Session("reload"))
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+func (reloader ConfigReloader) load(logger lager.Logger) {
+	logger.Info("start", lager.Data{"paths": reloader.ConfigPaths})
+	defer logger.Info("done")
+
+	newConfig, err := config.LoadConfigFiles(reloader.ConfigPaths...)
+	if err != nil {
+		logger.Error("failed-to-load-config", err)
+		return
+	}
+
+	err = reloader.Validator(newConfig)
+	if err != nil {
+		logger.Error("invalid-config", err)
+		return
+	}
+
+	_, version, err := reloader.DB.GetConfig(reloader.PipelineName)
+	if err != nil {
+		logger.Error("failed-to-get-current-config", err)
+		return
+	}
+
+	_, err = reloader.DB.SaveConfig(reloader.PipelineName, newConfig, version, db.PipelineNoChange)
+	if err != nil {
+		logger.Error("failed-to-save-config", err)
+		return
+	}
+}
@@ -0,0 +1,186 @@
+package pipelines_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	dbfakes "github.com/concourse/atc/db/fakes"
+	. "github.com/concourse/atc/pipelines"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/lager/lagertest"
+	"github.com/tedsuo/ifrit"
+)
+
+var _ = Describe("ConfigReloader", func() {
+	var fakeDB *dbfakes.FakeConfigDB
+	var validateErr error
+	var validatedConfigs []atc.Config
+	var reload chan os.Signal
+
+	var configDir string
+	var configPath string
+	var configPaths []string
+
+	var process ifrit.Process
+
+	writeConfig := func(contents string) {
+		err := ioutil.WriteFile(configPath, []byte(contents), 0644)
+		Ω(err).ShouldNot(HaveOccurred())
+	}
+
+	savedJobs := func(call int) []string {
+		_, config, _, _ := fakeDB.SaveConfigArgsForCall(call)
+
+		names := []string{}
+		for _, job := range config.Jobs {
+			names = append(names, job.Name)
+		}
+
+		return names
+	}
+
+	BeforeEach(func() {
+		var err error
+		configDir, err = ioutil.TempDir("", "config-reloader")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		configPath = filepath.Join(configDir, "pipeline.yml")
+		configPaths = []string{configPath}
+
+		writeConfig(`
+jobs:
+- name: job-1
+`)
+
+		fakeDB = new(dbfakes.FakeConfigDB)
+		fakeDB.GetConfigReturns(atc.Config{}, 42, nil)
+
+		validateErr = nil
+		validatedConfigs = nil
+
+		reload = make(chan os.Signal, 1)
+	})
+
+	JustBeforeEach(func() {
+		process = ifrit.Invoke(ConfigReloader{
+			Logger: lagertest.NewTestLogger("test"),
+
+			DB: fakeDB,
+
+			Validator: func(config atc.Config) error {
+				validatedConfigs = append(validatedConfigs, config)
+				return validateErr
+			},
+
+			PipelineName: "main",
+			ConfigPaths:  configPaths,
+
+			Reload: reload,
+		})
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive())
+
+		os.RemoveAll(configDir)
+	})
+
+	It("saves the config on start, on top of the current version", func() {
+		Ω(fakeDB.GetConfigCallCount()).Should(Equal(1))
+		Ω(fakeDB.GetConfigArgsForCall(0)).Should(Equal("main"))
+
+		Ω(fakeDB.SaveConfigCallCount()).Should(Equal(1))
+
+		name, _, version, pausedState := fakeDB.SaveConfigArgsForCall(0)
+		Ω(name).Should(Equal("main"))
+		Ω(version).Should(Equal(db.ConfigVersion(42)))
+		Ω(pausedState).Should(Equal(db.PipelineNoChange))
+
+		Ω(savedJobs(0)).Should(Equal([]string{"job-1"}))
+	})
+
+	Context("when the config is split across files", func() {
+		BeforeEach(func() {
+			otherConfigPath := filepath.Join(configDir, "other-pipeline.yml")
+
+			err := ioutil.WriteFile(otherConfigPath, []byte(`
+jobs:
+- name: job-3
+`), 0644)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			configPaths = append(configPaths, otherConfigPath)
+		})
+
+		It("saves them merged into one config", func() {
+			Ω(fakeDB.SaveConfigCallCount()).Should(Equal(1))
+			Ω(savedJobs(0)).Should(Equal([]string{"job-1", "job-3"}))
+		})
+	})
+
+	Context("when told to reload with a valid new config", func() {
+		JustBeforeEach(func() {
+			writeConfig(`
+jobs:
+- name: job-1
+- name: job-2
+`)
+
+			reload <- syscall.SIGHUP
+		})
+
+		It("saves the new set of jobs", func() {
+			Eventually(fakeDB.SaveConfigCallCount).Should(Equal(2))
+			Ω(savedJobs(1)).Should(Equal([]string{"job-1", "job-2"}))
+		})
+	})
+
+	Context("when told to reload with an invalid config", func() {
+		JustBeforeEach(func() {
+			writeConfig(`
+jobs:
+- name: job-1
+- name: job-1
+`)
+
+			reload <- syscall.SIGHUP
+		})
+
+		It("keeps the old config", func() {
+			Eventually(reload).ShouldNot(Receive())
+			Consistently(fakeDB.SaveConfigCallCount).Should(Equal(1))
+		})
+	})
+
+	Context("when the validator rejects the config", func() {
+		BeforeEach(func() {
+			validateErr = errors.New("unknown resource type: bogus")
+		})
+
+		It("validates the loaded config", func() {
+			Ω(validatedConfigs).Should(HaveLen(1))
+			Ω(validatedConfigs[0].Jobs[0].Name).Should(Equal("job-1"))
+		})
+
+		It("does not save it", func() {
+			Ω(fakeDB.SaveConfigCallCount()).Should(BeZero())
+		})
+	})
+
+	Context("when saving the config fails", func() {
+		BeforeEach(func() {
+			fakeDB.SaveConfigReturns(false, errors.New("disaster"))
+		})
+
+		It("keeps running", func() {
+			Consistently(process.Wait()).ShouldNot(Receive())
+		})
+	})
+})
@@ -3,12 +3,14 @@ package pipelines
 import (
 	"time"
 
+	"github.com/concourse/atc/credentials"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/engine"
 	"github.com/concourse/atc/radar"
 	"github.com/concourse/atc/resource"
 	"github.com/concourse/atc/scheduler"
 	"github.com/concourse/atc/scheduler/factory"
+	"github.com/pivotal-golang/clock"
 )
 
 //go:generate counterfeiter . Locker
@@ -28,31 +30,46 @@ type RadarSchedulerFactory interface {
 }
 
 type radarSchedulerFactory struct {
-	tracker  resource.Tracker
-	interval time.Duration
-	locker   Locker
-	engine   engine.Engine
-	db       db.DB
+	tracker           resource.Tracker
+	credentialManager credentials.CredentialManager
+	interval          time.Duration
+	maxSystemRetries  int
+	checkLimiter      *radar.CheckLimiter
+	buildLimiter      *scheduler.BuildLimiter
+	locker            Locker
+	engine            engine.Engine
+	db                db.DB
+	clock             clock.Clock
 }
 
 func NewRadarSchedulerFactory(
 	tracker resource.Tracker,
+	credentialManager credentials.CredentialManager,
 	interval time.Duration,
+	maxSystemRetries int,
+	checkLimiter *radar.CheckLimiter,
+	buildLimiter *scheduler.BuildLimiter,
 	locker Locker,
 	engine engine.Engine,
 	db db.DB,
+	clock clock.Clock,
 ) RadarSchedulerFactory {
 	return &radarSchedulerFactory{
-		tracker:  tracker,
-		interval: interval,
-		locker:   locker,
-		engine:   engine,
-		db:       db,
+		tracker:           tracker,
+		credentialManager: credentialManager,
+		interval:          interval,
+		maxSystemRetries:  maxSystemRetries,
+		checkLimiter:      checkLimiter,
+		buildLimiter:      buildLimiter,
+		locker:            locker,
+		engine:            engine,
+		db:                db,
+		clock:             clock,
 	}
 }
 
 func (rsf *radarSchedulerFactory) BuildRadar(pipelineDB db.PipelineDB) *radar.Radar {
-	return radar.NewRadar(rsf.tracker, rsf.interval, rsf.locker, pipelineDB)
+	return radar.NewRadar(rsf.tracker, rsf.credentialManager, rsf.interval, rsf.locker, pipelineDB, rsf.checkLimiter, rsf.clock)
 }
 
 func (rsf *radarSchedulerFactory) BuildScheduler(pipelineDB db.PipelineDB) *scheduler.Scheduler {
@@ -63,5 +80,10 @@ func (rsf *radarSchedulerFactory) BuildScheduler(pipelineDB db.PipelineDB) *sche
 		Factory:    &factory.BuildFactory{PipelineName: pipelineDB.GetPipelineName()},
 		Engine:     rsf.engine,
 		Scanner:    radar,
+		Locker:     rsf.locker,
+		Limiter:    rsf.buildLimiter,
+		Clock:      rsf.clock,
+
+		MaxSystemRetries: rsf.maxSystemRetries,
 	}
 }
@@ -25,6 +25,8 @@ type DependentGetPlan struct {
 	Tags     Tags   `json:"tags,omitempty"`
 	Source   Source `json:"source"`
 	Timeout  string `json:"timeout,omitempty"`
+
+	Proxy *ProxyConfig `json:"proxy,omitempty"`
 }
 
 type Location struct {
@@ -73,6 +75,8 @@ type GetPlan struct {
 	Version  Version `json:"version,omitempty"`
 	Tags     Tags    `json:"tags,omitempty"`
 	Timeout  string  `json:"timeout,omitempty"`
+
+	Proxy *ProxyConfig `json:"proxy,omitempty"`
 }
 
 type PutPlan struct {
@@ -84,6 +88,8 @@ type PutPlan struct {
 	Params   Params `json:"params,omitempty"`
 	Tags     Tags   `json:"tags,omitempty"`
 	Timeout  string `json:"timeout,omitempty"`
+
+	Proxy *ProxyConfig `json:"proxy,omitempty"`
 }
 
 func (plan DependentGetPlan) GetPlan() GetPlan {
@@ -96,6 +102,7 @@ func (plan DependentGetPlan) GetPlan() GetPlan {
 		Tags:     plan.Tags,
 		Timeout:  plan.Timeout,
 		Params:   plan.Params,
+		Proxy:    plan.Proxy,
 	}
 }
 
@@ -0,0 +1,130 @@
+package radar
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/concourse/atc"
+)
+
+// CheckGroupFunc determines which group a resource's checks count against,
+// e.g. the upstream host. Resources in the empty group are not limited by
+// group.
+type CheckGroupFunc func(atc.ResourceConfig) string
+
+// CheckLimiter bounds how many resource checks run at once, across every
+// pipeline's radar. Checks beyond the limit wait for a slot to free up.
+//
+// Checks may additionally be limited per group, so that resources sharing an
+// upstream (and its rate limits) are not all checked together.
+type CheckLimiter struct {
+	slots chan struct{}
+
+	group       CheckGroupFunc
+	maxPerGroup int
+
+	groupSlots  map[string]chan struct{}
+	groupSlotsL sync.Mutex
+}
+
+// NewCheckLimiter returns a limiter allowing max concurrent checks; 0 means
+// no limit.
+func NewCheckLimiter(max int) *CheckLimiter {
+	return NewGroupedCheckLimiter(max, nil, 0)
+}
+
+// NewGroupedCheckLimiter returns a limiter allowing max concurrent checks,
+// and at most maxPerGroup concurrent checks within each group determined by
+// group. For either, 0 means no limit.
+func NewGroupedCheckLimiter(max int, group CheckGroupFunc, maxPerGroup int) *CheckLimiter {
+	limiter := &CheckLimiter{
+		group:       group,
+		maxPerGroup: maxPerGroup,
+		groupSlots:  map[string]chan struct{}{},
+	}
+
+	if max > 0 {
+		limiter.slots = make(chan struct{}, max)
+	}
+
+	return limiter
+}
+
+func (limiter *CheckLimiter) Acquire(resource atc.ResourceConfig) {
+	if limiter == nil {
+		return
+	}
+
+	// always take the group's slot first, so that checks waiting on a busy
+	// group do not hold up checks for other groups
+	if groupSlots := limiter.slotsForGroup(resource); groupSlots != nil {
+		groupSlots <- struct{}{}
+	}
+
+	if limiter.slots != nil {
+		limiter.slots <- struct{}{}
+	}
+}
+
+func (limiter *CheckLimiter) Release(resource atc.ResourceConfig) {
+	if limiter == nil {
+		return
+	}
+
+	if limiter.slots != nil {
+		<-limiter.slots
+	}
+
+	if groupSlots := limiter.slotsForGroup(resource); groupSlots != nil {
+		<-groupSlots
+	}
+}
+
+func (limiter *CheckLimiter) slotsForGroup(resource atc.ResourceConfig) chan struct{} {
+	if limiter.group == nil || limiter.maxPerGroup <= 0 {
+		return nil
+	}
+
+	group := limiter.group(resource)
+	if group == "" {
+		return nil
+	}
+
+	limiter.groupSlotsL.Lock()
+	defer limiter.groupSlotsL.Unlock()
+
+	slots, found := limiter.groupSlots[group]
+	if !found {
+		slots = make(chan struct{}, limiter.maxPerGroup)
+		limiter.groupSlots[group] = slots
+	}
+
+	return slots
+}
+
+// SourceURIHost groups resources by the host of their source's 'uri', which
+// may be a URL or an scp-style git address (e.g. git@github.com:org/repo).
+func SourceURIHost(resource atc.ResourceConfig) string {
+	uri, ok := resource.Source["uri"].(string)
+	if !ok || uri == "" {
+		return ""
+	}
+
+	if parsed, err := url.Parse(uri); err == nil && parsed.Host != "" {
+		return strings.ToLower(parsed.Host)
+	}
+
+	// scp-style: [user@]host:path
+	colon := strings.Index(uri, ":")
+	if colon == -1 {
+		return ""
+	}
+
+	host := uri[:colon]
+	if at := strings.LastIndex(host, "@"); at != -1 {
+		host = host[at+1:]
+	}
+
+	return strings.ToLower(host)
+}
@@ -0,0 +1,31 @@
+package radar_test
+
+import (
+	"github.com/concourse/atc"
+	. "github.com/concourse/atc/radar"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SourceURIHost", func() {
+	host := func(uri interface{}) string {
+		return SourceURIHost(atc.ResourceConfig{
+			Source: atc.Source{"uri": uri},
+		})
+	}
+
+	It("returns the host of a URL", func() {
+		Ω(host("https://GitHub.com/concourse/atc.git")).Should(Equal("github.com"))
+	})
+
+	It("returns the host of an scp-style git address", func() {
+		Ω(host("git@github.com:concourse/atc.git")).Should(Equal("github.com"))
+	})
+
+	It("returns nothing when there is no uri", func() {
+		Ω(SourceURIHost(atc.ResourceConfig{Source: atc.Source{}})).Should(BeEmpty())
+		Ω(host(42)).Should(BeEmpty())
+		Ω(host("just-a-name")).Should(BeEmpty())
+	})
+})
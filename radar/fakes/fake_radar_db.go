@@ -3,6 +3,7 @@ package fakes
 
 import (
 	"sync"
+	"time"
 
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
@@ -82,6 +83,15 @@ type FakeRadarDB struct {
 	saveResourceVersionsReturns struct {
 		result1 error
 	}
+	SaveResourceVersionsForNewSourceStub        func(atc.ResourceConfig, []atc.Version) error
+	saveResourceVersionsForNewSourceMutex       sync.RWMutex
+	saveResourceVersionsForNewSourceArgsForCall []struct {
+		arg1 atc.ResourceConfig
+		arg2 []atc.Version
+	}
+	saveResourceVersionsForNewSourceReturns struct {
+		result1 error
+	}
 	SetResourceCheckErrorStub        func(resource db.SavedResource, err error) error
 	setResourceCheckErrorMutex       sync.RWMutex
 	setResourceCheckErrorArgsForCall []struct {
@@ -91,6 +101,59 @@ type FakeRadarDB struct {
 	setResourceCheckErrorReturns struct {
 		result1 error
 	}
+	SaveResourceCheckResultStub        func(resource db.SavedResource, err error) error
+	saveResourceCheckResultMutex       sync.RWMutex
+	saveResourceCheckResultArgsForCall []struct {
+		resource db.SavedResource
+		err      error
+	}
+	saveResourceCheckResultReturns struct {
+		result1 error
+	}
+	SetResourceCheckingStub        func(resource db.SavedResource, ttl time.Duration) error
+	setResourceCheckingMutex       sync.RWMutex
+	setResourceCheckingArgsForCall []struct {
+		resource db.SavedResource
+		ttl      time.Duration
+	}
+	setResourceCheckingReturns struct {
+		result1 error
+	}
+	ClearResourceCheckingStub        func(resource db.SavedResource) error
+	clearResourceCheckingMutex       sync.RWMutex
+	clearResourceCheckingArgsForCall []struct {
+		resource db.SavedResource
+	}
+	clearResourceCheckingReturns struct {
+		result1 error
+	}
+	UpdateResourceLastCheckedStub        func(resource db.SavedResource, checkedAt time.Time) error
+	updateResourceLastCheckedMutex       sync.RWMutex
+	updateResourceLastCheckedArgsForCall []struct {
+		resource  db.SavedResource
+		checkedAt time.Time
+	}
+	updateResourceLastCheckedReturns struct {
+		result1 error
+	}
+	UpdateResourceLastCheckErroredStub        func(resource db.SavedResource, erroredAt time.Time) error
+	updateResourceLastCheckErroredMutex       sync.RWMutex
+	updateResourceLastCheckErroredArgsForCall []struct {
+		resource  db.SavedResource
+		erroredAt time.Time
+	}
+	updateResourceLastCheckErroredReturns struct {
+		result1 error
+	}
+	UpdateResourceSourceHashStub        func(resource db.SavedResource, sourceHash string) error
+	updateResourceSourceHashMutex       sync.RWMutex
+	updateResourceSourceHashArgsForCall []struct {
+		resource   db.SavedResource
+		sourceHash string
+	}
+	updateResourceSourceHashReturns struct {
+		result1 error
+	}
 }
 
 func (fake *FakeRadarDB) GetPipelineName() string {
@@ -363,6 +426,39 @@ func (fake *FakeRadarDB) SaveResourceVersionsReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeRadarDB) SaveResourceVersionsForNewSource(arg1 atc.ResourceConfig, arg2 []atc.Version) error {
+	fake.saveResourceVersionsForNewSourceMutex.Lock()
+	fake.saveResourceVersionsForNewSourceArgsForCall = append(fake.saveResourceVersionsForNewSourceArgsForCall, struct {
+		arg1 atc.ResourceConfig
+		arg2 []atc.Version
+	}{arg1, arg2})
+	fake.saveResourceVersionsForNewSourceMutex.Unlock()
+	if fake.SaveResourceVersionsForNewSourceStub != nil {
+		return fake.SaveResourceVersionsForNewSourceStub(arg1, arg2)
+	} else {
+		return fake.saveResourceVersionsForNewSourceReturns.result1
+	}
+}
+
+func (fake *FakeRadarDB) SaveResourceVersionsForNewSourceCallCount() int {
+	fake.saveResourceVersionsForNewSourceMutex.RLock()
+	defer fake.saveResourceVersionsForNewSourceMutex.RUnlock()
+	return len(fake.saveResourceVersionsForNewSourceArgsForCall)
+}
+
+func (fake *FakeRadarDB) SaveResourceVersionsForNewSourceArgsForCall(i int) (atc.ResourceConfig, []atc.Version) {
+	fake.saveResourceVersionsForNewSourceMutex.RLock()
+	defer fake.saveResourceVersionsForNewSourceMutex.RUnlock()
+	return fake.saveResourceVersionsForNewSourceArgsForCall[i].arg1, fake.saveResourceVersionsForNewSourceArgsForCall[i].arg2
+}
+
+func (fake *FakeRadarDB) SaveResourceVersionsForNewSourceReturns(result1 error) {
+	fake.SaveResourceVersionsForNewSourceStub = nil
+	fake.saveResourceVersionsForNewSourceReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRadarDB) SetResourceCheckError(resource db.SavedResource, err error) error {
 	fake.setResourceCheckErrorMutex.Lock()
 	fake.setResourceCheckErrorArgsForCall = append(fake.setResourceCheckErrorArgsForCall, struct {
@@ -396,4 +492,201 @@ func (fake *FakeRadarDB) SetResourceCheckErrorReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeRadarDB) SaveResourceCheckResult(resource db.SavedResource, err error) error {
+	fake.saveResourceCheckResultMutex.Lock()
+	fake.saveResourceCheckResultArgsForCall = append(fake.saveResourceCheckResultArgsForCall, struct {
+		resource db.SavedResource
+		err      error
+	}{resource, err})
+	fake.saveResourceCheckResultMutex.Unlock()
+	if fake.SaveResourceCheckResultStub != nil {
+		return fake.SaveResourceCheckResultStub(resource, err)
+	} else {
+		return fake.saveResourceCheckResultReturns.result1
+	}
+}
+
+func (fake *FakeRadarDB) SaveResourceCheckResultCallCount() int {
+	fake.saveResourceCheckResultMutex.RLock()
+	defer fake.saveResourceCheckResultMutex.RUnlock()
+	return len(fake.saveResourceCheckResultArgsForCall)
+}
+
+func (fake *FakeRadarDB) SaveResourceCheckResultArgsForCall(i int) (db.SavedResource, error) {
+	fake.saveResourceCheckResultMutex.RLock()
+	defer fake.saveResourceCheckResultMutex.RUnlock()
+	return fake.saveResourceCheckResultArgsForCall[i].resource, fake.saveResourceCheckResultArgsForCall[i].err
+}
+
+func (fake *FakeRadarDB) SaveResourceCheckResultReturns(result1 error) {
+	fake.SaveResourceCheckResultStub = nil
+	fake.saveResourceCheckResultReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRadarDB) SetResourceChecking(resource db.SavedResource, ttl time.Duration) error {
+	fake.setResourceCheckingMutex.Lock()
+	fake.setResourceCheckingArgsForCall = append(fake.setResourceCheckingArgsForCall, struct {
+		resource db.SavedResource
+		ttl      time.Duration
+	}{resource, ttl})
+	fake.setResourceCheckingMutex.Unlock()
+	if fake.SetResourceCheckingStub != nil {
+		return fake.SetResourceCheckingStub(resource, ttl)
+	} else {
+		return fake.setResourceCheckingReturns.result1
+	}
+}
+
+func (fake *FakeRadarDB) SetResourceCheckingCallCount() int {
+	fake.setResourceCheckingMutex.RLock()
+	defer fake.setResourceCheckingMutex.RUnlock()
+	return len(fake.setResourceCheckingArgsForCall)
+}
+
+func (fake *FakeRadarDB) SetResourceCheckingArgsForCall(i int) (db.SavedResource, time.Duration) {
+	fake.setResourceCheckingMutex.RLock()
+	defer fake.setResourceCheckingMutex.RUnlock()
+	return fake.setResourceCheckingArgsForCall[i].resource, fake.setResourceCheckingArgsForCall[i].ttl
+}
+
+func (fake *FakeRadarDB) SetResourceCheckingReturns(result1 error) {
+	fake.SetResourceCheckingStub = nil
+	fake.setResourceCheckingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRadarDB) ClearResourceChecking(resource db.SavedResource) error {
+	fake.clearResourceCheckingMutex.Lock()
+	fake.clearResourceCheckingArgsForCall = append(fake.clearResourceCheckingArgsForCall, struct {
+		resource db.SavedResource
+	}{resource})
+	fake.clearResourceCheckingMutex.Unlock()
+	if fake.ClearResourceCheckingStub != nil {
+		return fake.ClearResourceCheckingStub(resource)
+	} else {
+		return fake.clearResourceCheckingReturns.result1
+	}
+}
+
+func (fake *FakeRadarDB) ClearResourceCheckingCallCount() int {
+	fake.clearResourceCheckingMutex.RLock()
+	defer fake.clearResourceCheckingMutex.RUnlock()
+	return len(fake.clearResourceCheckingArgsForCall)
+}
+
+func (fake *FakeRadarDB) ClearResourceCheckingArgsForCall(i int) db.SavedResource {
+	fake.clearResourceCheckingMutex.RLock()
+	defer fake.clearResourceCheckingMutex.RUnlock()
+	return fake.clearResourceCheckingArgsForCall[i].resource
+}
+
+func (fake *FakeRadarDB) ClearResourceCheckingReturns(result1 error) {
+	fake.ClearResourceCheckingStub = nil
+	fake.clearResourceCheckingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRadarDB) UpdateResourceLastChecked(resource db.SavedResource, checkedAt time.Time) error {
+	fake.updateResourceLastCheckedMutex.Lock()
+	fake.updateResourceLastCheckedArgsForCall = append(fake.updateResourceLastCheckedArgsForCall, struct {
+		resource  db.SavedResource
+		checkedAt time.Time
+	}{resource, checkedAt})
+	fake.updateResourceLastCheckedMutex.Unlock()
+	if fake.UpdateResourceLastCheckedStub != nil {
+		return fake.UpdateResourceLastCheckedStub(resource, checkedAt)
+	} else {
+		return fake.updateResourceLastCheckedReturns.result1
+	}
+}
+
+func (fake *FakeRadarDB) UpdateResourceLastCheckedCallCount() int {
+	fake.updateResourceLastCheckedMutex.RLock()
+	defer fake.updateResourceLastCheckedMutex.RUnlock()
+	return len(fake.updateResourceLastCheckedArgsForCall)
+}
+
+func (fake *FakeRadarDB) UpdateResourceLastCheckedArgsForCall(i int) (db.SavedResource, time.Time) {
+	fake.updateResourceLastCheckedMutex.RLock()
+	defer fake.updateResourceLastCheckedMutex.RUnlock()
+	return fake.updateResourceLastCheckedArgsForCall[i].resource, fake.updateResourceLastCheckedArgsForCall[i].checkedAt
+}
+
+func (fake *FakeRadarDB) UpdateResourceLastCheckedReturns(result1 error) {
+	fake.UpdateResourceLastCheckedStub = nil
+	fake.updateResourceLastCheckedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRadarDB) UpdateResourceLastCheckErrored(resource db.SavedResource, erroredAt time.Time) error {
+	fake.updateResourceLastCheckErroredMutex.Lock()
+	fake.updateResourceLastCheckErroredArgsForCall = append(fake.updateResourceLastCheckErroredArgsForCall, struct {
+		resource  db.SavedResource
+		erroredAt time.Time
+	}{resource, erroredAt})
+	fake.updateResourceLastCheckErroredMutex.Unlock()
+	if fake.UpdateResourceLastCheckErroredStub != nil {
+		return fake.UpdateResourceLastCheckErroredStub(resource, erroredAt)
+	} else {
+		return fake.updateResourceLastCheckErroredReturns.result1
+	}
+}
+
+func (fake *FakeRadarDB) UpdateResourceLastCheckErroredCallCount() int {
+	fake.updateResourceLastCheckErroredMutex.RLock()
+	defer fake.updateResourceLastCheckErroredMutex.RUnlock()
+	return len(fake.updateResourceLastCheckErroredArgsForCall)
+}
+
+func (fake *FakeRadarDB) UpdateResourceLastCheckErroredArgsForCall(i int) (db.SavedResource, time.Time) {
+	fake.updateResourceLastCheckErroredMutex.RLock()
+	defer fake.updateResourceLastCheckErroredMutex.RUnlock()
+	return fake.updateResourceLastCheckErroredArgsForCall[i].resource, fake.updateResourceLastCheckErroredArgsForCall[i].erroredAt
+}
+
+func (fake *FakeRadarDB) UpdateResourceLastCheckErroredReturns(result1 error) {
+	fake.UpdateResourceLastCheckErroredStub = nil
+	fake.updateResourceLastCheckErroredReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRadarDB) UpdateResourceSourceHash(resource db.SavedResource, sourceHash string) error {
+	fake.updateResourceSourceHashMutex.Lock()
+	fake.updateResourceSourceHashArgsForCall = append(fake.updateResourceSourceHashArgsForCall, struct {
+		resource   db.SavedResource
+		sourceHash string
+	}{resource, sourceHash})
+	fake.updateResourceSourceHashMutex.Unlock()
+	if fake.UpdateResourceSourceHashStub != nil {
+		return fake.UpdateResourceSourceHashStub(resource, sourceHash)
+	} else {
+		return fake.updateResourceSourceHashReturns.result1
+	}
+}
+
+func (fake *FakeRadarDB) UpdateResourceSourceHashCallCount() int {
+	fake.updateResourceSourceHashMutex.RLock()
+	defer fake.updateResourceSourceHashMutex.RUnlock()
+	return len(fake.updateResourceSourceHashArgsForCall)
+}
+
+func (fake *FakeRadarDB) UpdateResourceSourceHashArgsForCall(i int) (db.SavedResource, string) {
+	fake.updateResourceSourceHashMutex.RLock()
+	defer fake.updateResourceSourceHashMutex.RUnlock()
+	return fake.updateResourceSourceHashArgsForCall[i].resource, fake.updateResourceSourceHashArgsForCall[i].sourceHash
+}
+
+func (fake *FakeRadarDB) UpdateResourceSourceHashReturns(result1 error) {
+	fake.UpdateResourceSourceHashStub = nil
+	fake.updateResourceSourceHashReturns = struct {
+		result1 error
+	}{result1}
+}
+
 var _ radar.RadarDB = new(FakeRadarDB)
@@ -2,13 +2,17 @@ package radar
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"reflect"
 	"time"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/credentials"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/resource"
 	"github.com/concourse/atc/worker"
+	"github.com/pivotal-golang/clock"
 	"github.com/tedsuo/ifrit"
 
 	"github.com/pivotal-golang/lager"
@@ -38,37 +42,61 @@ type RadarDB interface {
 	UnpauseResource(resourceName string) error
 
 	SaveResourceVersions(atc.ResourceConfig, []atc.Version) error
+	SaveResourceVersionsForNewSource(atc.ResourceConfig, []atc.Version) error
 	SetResourceCheckError(resource db.SavedResource, err error) error
+	SaveResourceCheckResult(resource db.SavedResource, err error) error
+	SetResourceChecking(resource db.SavedResource, ttl time.Duration) error
+	ClearResourceChecking(resource db.SavedResource) error
+	UpdateResourceLastChecked(resource db.SavedResource, checkedAt time.Time) error
+	UpdateResourceLastCheckErrored(resource db.SavedResource, erroredAt time.Time) error
+	UpdateResourceSourceHash(resource db.SavedResource, sourceHash string) error
 }
 
+// how long a resource is shown as being checked if the check never finishes,
+// i.e. because the ATC went away mid-check; refreshed while the check runs
+const checkingTTL = 5 * time.Minute
+
 type Radar struct {
 	logger lager.Logger
 
 	tracker resource.Tracker
 
+	credentialManager credentials.CredentialManager
+
 	interval time.Duration
 
 	locker Locker
 	db     RadarDB
+
+	checkLimiter *CheckLimiter
+
+	clock clock.Clock
 }
 
 func NewRadar(
 	tracker resource.Tracker,
+	credentialManager credentials.CredentialManager,
 	interval time.Duration,
 	locker Locker,
 	db RadarDB,
+	checkLimiter *CheckLimiter,
+	clock clock.Clock,
 ) *Radar {
 	return &Radar{
-		tracker:  tracker,
-		interval: interval,
-		locker:   locker,
-		db:       db,
+		tracker:           tracker,
+		credentialManager: credentialManager,
+		interval:          interval,
+		locker:            locker,
+		db:                db,
+		checkLimiter:      checkLimiter,
+		clock:             clock,
 	}
 }
 
 func (radar *Radar) Scanner(logger lager.Logger, resourceName string) ifrit.Runner {
 	return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
-		ticker := time.NewTicker(radar.interval)
+		ticker := radar.clock.NewTicker(radar.interval)
+		defer ticker.Stop()
 
 		close(ready)
 
@@ -77,18 +105,8 @@ func (radar *Radar) Scanner(logger lager.Logger, resourceName string) ifrit.Runn
 			case <-signals:
 				return nil
 
-			case <-ticker.C:
-				lock := radar.checkLock(radar.db.ScopedName(resourceName))
-				resourceCheckingLock, err := radar.locker.AcquireWriteLockImmediately(lock)
-
-				if err != nil {
-					continue
-				}
-
-				err = radar.scan(logger.Session("tick"), resourceName)
-
-				resourceCheckingLock.Release()
-
+			case <-ticker.C():
+				err := radar.scan(logger.Session("tick"), resourceName, false)
 				if err != nil {
 					return err
 				}
@@ -97,18 +115,13 @@ func (radar *Radar) Scanner(logger lager.Logger, resourceName string) ifrit.Runn
 	})
 }
 
+// Scan checks the resource once, as an explicit action. Unlike the periodic
+// checks done by the Scanner, it runs even if the resource is paused.
 func (radar *Radar) Scan(logger lager.Logger, resourceName string) error {
-	lock, err := radar.locker.AcquireWriteLock(radar.checkLock(radar.db.ScopedName(resourceName)))
-	if err != nil {
-		return err
-	}
-
-	defer lock.Release()
-
-	return radar.scan(logger, resourceName)
+	return radar.scan(logger, resourceName, true)
 }
 
-func (radar *Radar) scan(logger lager.Logger, resourceName string) error {
+func (radar *Radar) scan(logger lager.Logger, resourceName string, explicit bool) error {
 	pipelinePaused, err := radar.db.IsPaused()
 	if err != nil {
 		logger.Error("failed-to-check-if-pipeline-paused", err)
@@ -127,6 +140,8 @@ func (radar *Radar) scan(logger lager.Logger, resourceName string) error {
 		return nil
 	}
 
+	config = config.WithDefaults()
+
 	resourceConfig, found := config.Resources.Lookup(resourceName)
 	if !found {
 		logger.Info("resource-removed-from-configuration")
@@ -139,10 +154,44 @@ func (radar *Radar) scan(logger lager.Logger, resourceName string) error {
 		return err
 	}
 
-	if savedResource.Paused {
+	if savedResource.Paused && !explicit {
+		logger.Debug("resource-paused")
 		return nil
 	}
 
+	// the slot is taken before the lock, so that while this check waits for
+	// one, another ATC can check the resource instead
+	radar.checkLimiter.Acquire(resourceConfig)
+	defer radar.checkLimiter.Release(resourceConfig)
+
+	lock := radar.checkLock(radar.db.ScopedName(resourceName))
+
+	var resourceCheckingLock db.Lock
+	if explicit {
+		resourceCheckingLock, err = radar.locker.AcquireWriteLock(lock)
+		if err != nil {
+			return err
+		}
+	} else {
+		resourceCheckingLock, err = radar.locker.AcquireWriteLockImmediately(lock)
+		if err != nil {
+			// being checked elsewhere
+			return nil
+		}
+	}
+
+	defer resourceCheckingLock.Release()
+
+	// read again now that the resource is locked, as it may have been
+	// checked by another ATC in the meantime
+	savedResource, err = radar.db.GetResource(resourceName)
+	if err != nil {
+		return err
+	}
+
+	doneChecking := radar.markChecking(logger, savedResource)
+	defer doneChecking()
+
 	typ := resource.ResourceType(resourceConfig.Type)
 
 	res, err := radar.tracker.Init(checkIdentifier(radar.db.GetPipelineName(), resourceConfig), typ, []string{})
@@ -153,47 +202,210 @@ func (radar *Radar) scan(logger lager.Logger, resourceName string) error {
 
 	defer res.Release()
 
+	// a resource whose source has changed is checked from scratch, as its
+	// latest version may not even exist in the new source; likewise if its
+	// latest version was found with another source
+	sourceHash := resourceConfig.Source.Hash()
+	sourceChanged := savedResource.SourceHash != "" && savedResource.SourceHash != sourceHash
+
 	var from db.Version
-	if vr, err := radar.db.GetLatestVersionedResource(savedResource); err == nil {
-		from = vr.Version
+	if sourceChanged {
+		logger.Info("source-changed")
+	} else if vr, err := radar.db.GetLatestVersionedResource(savedResource); err == nil {
+		if atc.Source(vr.Source).Hash() == sourceHash {
+			from = vr.Version
+		}
 	}
 
 	logger.Debug("checking", lager.Data{
-		"from": from,
+		"from":   from,
+		"source": resourceConfig.Source.Redacted(),
 	})
 
-	newVersions, err := res.Check(resourceConfig.Source, atc.Version(from))
+	// a missing credential is recorded like any other check error
+	var newVersions []atc.Version
+	source, err := credentials.InterpolateSource(radar.credentialManager, resourceConfig.Source)
+	if err == nil {
+		newVersions, err = res.Check(resource.IOConfig{}, source, atc.Version(from))
+	}
+
 	setErr := radar.db.SetResourceCheckError(savedResource, err)
 	if setErr != nil {
 		logger.Error("failed-to-set-check-error", err)
 	}
 
+	saveErr := radar.db.SaveResourceCheckResult(savedResource, err)
+	if saveErr != nil {
+		logger.Error("failed-to-save-check-result", saveErr)
+	}
+
 	if err != nil {
 		logger.Error("failed-to-check", err)
 
+		updateErr := radar.db.UpdateResourceLastCheckErrored(savedResource, radar.clock.Now())
+		if updateErr != nil {
+			logger.Error("failed-to-update-last-check-errored", updateErr)
+		}
+
 		return err
 	}
 
+	updateErr := radar.db.UpdateResourceLastChecked(savedResource, radar.clock.Now())
+	if updateErr != nil {
+		logger.Error("failed-to-update-last-checked", updateErr)
+	}
+
 	if len(newVersions) == 0 {
 		logger.Debug("no-new-versions")
-		return nil
+	} else {
+		logger.Info("versions-found", lager.Data{
+			"versions": newVersions,
+			"total":    len(newVersions),
+		})
 	}
 
-	logger.Info("versions-found", lager.Data{
-		"versions": newVersions,
-		"total":    len(newVersions),
-	})
+	// the new source is recorded along with its versions, so that if they
+	// fail to save the next check is from scratch too
+	if sourceChanged {
+		err = radar.db.SaveResourceVersionsForNewSource(resourceConfig, newVersions)
+	} else if len(newVersions) > 0 {
+		err = radar.db.SaveResourceVersions(resourceConfig, newVersions)
+	}
 
-	err = radar.db.SaveResourceVersions(resourceConfig, newVersions)
 	if err != nil {
 		logger.Error("failed-to-save-versions", err, lager.Data{
 			"versions": newVersions,
 		})
+
+		return nil
+	}
+
+	if savedResource.SourceHash == "" {
+		updateErr := radar.db.UpdateResourceSourceHash(savedResource, sourceHash)
+		if updateErr != nil {
+			logger.Error("failed-to-update-source-hash", updateErr)
+		}
 	}
 
 	return nil
 }
 
+// VersionExists reports whether the resource still has the given version
+// upstream, by running a check from that version and looking for it in the
+// versions returned.
+//
+// Nothing is saved; this doesn't count as checking the resource.
+func (radar *Radar) VersionExists(logger lager.Logger, resourceName string, version atc.Version) (bool, error) {
+	versions, err := radar.checkWithoutSaving(logger, resourceName, version, resource.IOConfig{})
+	if err != nil {
+		return false, err
+	}
+
+	for _, v := range versions {
+		if reflect.DeepEqual(v, version) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// DebugCheck runs a check for the resource from the given version, streaming
+// the check script's stdout and stderr to the given writers as it runs, and
+// returns the versions it found.
+//
+// As with VersionExists, nothing is saved.
+func (radar *Radar) DebugCheck(logger lager.Logger, resourceName string, from atc.Version, stdout io.Writer, stderr io.Writer) ([]atc.Version, error) {
+	return radar.checkWithoutSaving(logger, resourceName, from, resource.IOConfig{
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+}
+
+func (radar *Radar) checkWithoutSaving(logger lager.Logger, resourceName string, from atc.Version, ioConfig resource.IOConfig) ([]atc.Version, error) {
+	config, _, err := radar.db.GetConfig()
+	if err != nil {
+		logger.Error("failed-to-get-config", err)
+		return nil, err
+	}
+
+	config = config.WithDefaults()
+
+	resourceConfig, found := config.Resources.Lookup(resourceName)
+	if !found {
+		return nil, resourceNotConfiguredError{ResourceName: resourceName}
+	}
+
+	typ := resource.ResourceType(resourceConfig.Type)
+
+	radar.checkLimiter.Acquire(resourceConfig)
+	defer radar.checkLimiter.Release(resourceConfig)
+
+	res, err := radar.tracker.Init(checkIdentifier(radar.db.GetPipelineName(), resourceConfig), typ, []string{})
+	if err != nil {
+		logger.Error("failed-to-initialize-new-resource", err)
+		return nil, err
+	}
+
+	defer res.Release()
+
+	source, err := credentials.InterpolateSource(radar.credentialManager, resourceConfig.Source)
+	if err != nil {
+		logger.Error("failed-to-resolve-credentials", err)
+		return nil, err
+	}
+
+	versions, err := res.Check(ioConfig, source, from)
+	if err != nil {
+		logger.Error("failed-to-check", err)
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// markChecking shows the resource as being checked, keeping it so for as long
+// as the check runs, until the returned function is called.
+func (radar *Radar) markChecking(logger lager.Logger, savedResource db.SavedResource) func() {
+	err := radar.db.SetResourceChecking(savedResource, checkingTTL)
+	if err != nil {
+		logger.Error("failed-to-mark-resource-as-checking", err)
+	}
+
+	ticker := radar.clock.NewTicker(checkingTTL / 2)
+
+	done := make(chan struct{})
+	refreshed := make(chan struct{})
+
+	go func() {
+		defer close(refreshed)
+
+		for {
+			select {
+			case <-ticker.C():
+				err := radar.db.SetResourceChecking(savedResource, checkingTTL)
+				if err != nil {
+					logger.Error("failed-to-refresh-resource-checking", err)
+				}
+
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+		<-refreshed
+
+		err := radar.db.ClearResourceChecking(savedResource)
+		if err != nil {
+			logger.Error("failed-to-clear-resource-checking", err)
+		}
+	}
+}
+
 func (radar *Radar) checkLock(resourceName string) []db.NamedLock {
 	return []db.NamedLock{db.ResourceCheckingLock(resourceName)}
 }
@@ -210,5 +422,6 @@ func checkIdentifier(pipelineName string, res atc.ResourceConfig) resource.Sessi
 			CheckSource: res.Source,
 		},
 		Ephemeral: true,
+		Proxy:     res.Proxy,
 	}
 }
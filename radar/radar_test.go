@@ -1,14 +1,21 @@
 package radar_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/credentials"
+	cfakes "github.com/concourse/atc/credentials/fakes"
 	"github.com/concourse/atc/db"
 	dbfakes "github.com/concourse/atc/db/fakes"
 	"github.com/concourse/atc/worker"
+	"github.com/pivotal-golang/clock"
+	"github.com/pivotal-golang/clock/fakeclock"
 	"github.com/pivotal-golang/lager/lagertest"
 	"github.com/tedsuo/ifrit"
 
@@ -22,9 +29,11 @@ import (
 
 var _ = Describe("Radar", func() {
 	var (
-		fakeTracker *rfakes.FakeTracker
-		fakeRadarDB *fakes.FakeRadarDB
-		interval    time.Duration
+		fakeTracker           *rfakes.FakeTracker
+		fakeCredentialManager *cfakes.FakeCredentialManager
+		fakeRadarDB           *fakes.FakeRadarDB
+		interval              time.Duration
+		radarClock            clock.Clock
 
 		radar *Radar
 
@@ -42,11 +51,21 @@ var _ = Describe("Radar", func() {
 	BeforeEach(func() {
 		fakeTracker = new(rfakes.FakeTracker)
 		fakeRadarDB = new(fakes.FakeRadarDB)
+
+		fakeCredentialManager = new(cfakes.FakeCredentialManager)
+		fakeCredentialManager.GetStub = func(key string) (string, error) {
+			if key == "some-token" {
+				return "some-secret-token", nil
+			}
+
+			return "", credentials.MissingCredentialError{Key: key}
+		}
 		locker = new(fakes.FakeLocker)
 		interval = 100 * time.Millisecond
+		radarClock = clock.NewClock()
 
 		fakeRadarDB.GetPipelineNameReturns("some-pipeline-name")
-		radar = NewRadar(fakeTracker, interval, locker, fakeRadarDB)
+		radar = NewRadar(fakeTracker, fakeCredentialManager, interval, locker, fakeRadarDB, NewCheckLimiter(0), radarClock)
 
 		resourceConfig = atc.ResourceConfig{
 			Name:   "some-resource",
@@ -96,7 +115,7 @@ var _ = Describe("Radar", func() {
 
 			times = make(chan time.Time, 100)
 
-			fakeResource.CheckStub = func(atc.Source, atc.Version) ([]atc.Version, error) {
+			fakeResource.CheckStub = func(resource.IOConfig, atc.Source, atc.Version) ([]atc.Version, error) {
 				times <- time.Now()
 				return nil, nil
 			}
@@ -131,14 +150,33 @@ var _ = Describe("Radar", func() {
 			Ω(tags).Should(BeEmpty()) // This allows the check to run on any worker
 		})
 
-		It("checks on a specified interval", func() {
-			var time1 time.Time
-			var time2 time.Time
+		Context("with a fake clock", func() {
+			var fakeClock *fakeclock.FakeClock
+
+			BeforeEach(func() {
+				fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+				radar = NewRadar(fakeTracker, fakeCredentialManager, interval, locker, fakeRadarDB, NewCheckLimiter(0), fakeClock)
+			})
 
-			Eventually(times).Should(Receive(&time1))
-			Eventually(times).Should(Receive(&time2))
+			It("checks each time the interval elapses", func() {
+				Consistently(times).ShouldNot(Receive())
 
-			Ω(time2.Sub(time1)).Should(BeNumerically("~", interval, interval/4))
+				fakeClock.Increment(interval)
+				Eventually(times).Should(Receive())
+				Consistently(times).ShouldNot(Receive())
+
+				fakeClock.Increment(interval)
+				Eventually(times).Should(Receive())
+			})
+
+			It("records the check as of the clock's time", func() {
+				fakeClock.Increment(interval)
+
+				Eventually(fakeRadarDB.UpdateResourceLastCheckedCallCount).Should(Equal(1))
+
+				_, checkedAt := fakeRadarDB.UpdateResourceLastCheckedArgsForCall(0)
+				Ω(checkedAt).Should(Equal(time.Unix(123, 0).Add(interval)))
+			})
 		})
 
 		It("grabs a resource checking lock before checking, releases after done", func() {
@@ -158,11 +196,51 @@ var _ = Describe("Radar", func() {
 			Ω(fakeResource.ReleaseCallCount()).Should(Equal(1))
 		})
 
+		Context("while waiting for a check slot", func() {
+			var limiter *CheckLimiter
+
+			BeforeEach(func() {
+				limiter = NewCheckLimiter(1)
+				limiter.Acquire(resourceConfig)
+
+				radar = NewRadar(fakeTracker, fakeCredentialManager, interval, locker, fakeRadarDB, limiter, radarClock)
+			})
+
+			It("does not hold the resource checking lock", func() {
+				Consistently(locker.AcquireWriteLockImmediatelyCallCount).Should(BeZero())
+
+				limiter.Release(resourceConfig)
+
+				Eventually(times).Should(Receive())
+				Ω(locker.AcquireWriteLockImmediatelyCallCount()).Should(BeNumerically(">=", 1))
+			})
+
+			It("does not mark the resource as being checked until the check starts", func() {
+				Consistently(fakeRadarDB.SetResourceCheckingCallCount).Should(BeZero())
+
+				limiter.Release(resourceConfig)
+
+				Eventually(times).Should(Receive())
+				Ω(fakeRadarDB.SetResourceCheckingCallCount()).Should(BeNumerically(">=", 1))
+			})
+		})
+
+		Context("when the resource checking lock is held elsewhere", func() {
+			BeforeEach(func() {
+				locker.AcquireWriteLockImmediatelyReturns(nil, errors.New("no lock for you"))
+			})
+
+			It("does not check", func() {
+				Eventually(locker.AcquireWriteLockImmediatelyCallCount).Should(BeNumerically(">=", 2))
+				Ω(times).ShouldNot(Receive())
+			})
+		})
+
 		Context("when there is no current version", func() {
 			It("checks from nil", func() {
 				Eventually(times).Should(Receive())
 
-				_, version := fakeResource.CheckArgsForCall(0)
+				_, _, version := fakeResource.CheckArgsForCall(0)
 				Ω(version).Should(BeNil())
 			})
 		})
@@ -173,6 +251,7 @@ var _ = Describe("Radar", func() {
 					db.SavedVersionedResource{
 						ID: 1,
 						VersionedResource: db.VersionedResource{
+							Source: db.Source{"uri": "http://example.com"},
 							Version: db.Version{
 								"version": "1",
 							},
@@ -183,17 +262,20 @@ var _ = Describe("Radar", func() {
 			It("checks from it", func() {
 				Eventually(times).Should(Receive())
 
-				_, version := fakeResource.CheckArgsForCall(0)
+				_, _, version := fakeResource.CheckArgsForCall(0)
 				Ω(version).Should(Equal(atc.Version{"version": "1"}))
 
 				fakeRadarDB.GetLatestVersionedResourceReturns(db.SavedVersionedResource{
-					ID:                2,
-					VersionedResource: db.VersionedResource{Version: db.Version{"version": "2"}},
+					ID: 2,
+					VersionedResource: db.VersionedResource{
+						Source:  db.Source{"uri": "http://example.com"},
+						Version: db.Version{"version": "2"},
+					},
 				}, nil)
 
 				Eventually(times).Should(Receive())
 
-				_, version = fakeResource.CheckArgsForCall(1)
+				_, _, version = fakeResource.CheckArgsForCall(1)
 				Ω(version).Should(Equal(atc.Version{"version": "2"}))
 			})
 		})
@@ -217,7 +299,7 @@ var _ = Describe("Radar", func() {
 				}
 
 				check := 0
-				fakeResource.CheckStub = func(source atc.Source, from atc.Version) ([]atc.Version, error) {
+				fakeResource.CheckStub = func(_ resource.IOConfig, source atc.Source, from atc.Version) ([]atc.Version, error) {
 					defer GinkgoRecover()
 
 					Ω(source).Should(Equal(resourceConfig.Source))
@@ -345,12 +427,12 @@ var _ = Describe("Radar", func() {
 				It("checks using the new config", func() {
 					Eventually(times).Should(Receive())
 
-					source, _ := fakeResource.CheckArgsForCall(0)
+					_, source, _ := fakeResource.CheckArgsForCall(0)
 					Ω(source).Should(Equal(resourceConfig.Source))
 
 					Eventually(times).Should(Receive())
 
-					source, _ = fakeResource.CheckArgsForCall(1)
+					_, source, _ = fakeResource.CheckArgsForCall(1)
 					Ω(source).Should(Equal(atc.Source{"uri": "http://example.com/updated-uri"}))
 				})
 			})
@@ -365,7 +447,7 @@ var _ = Describe("Radar", func() {
 				It("exits", func() {
 					Eventually(times).Should(Receive())
 
-					source, _ := fakeResource.CheckArgsForCall(0)
+					_, source, _ := fakeResource.CheckArgsForCall(0)
 					Ω(source).Should(Equal(resourceConfig.Source))
 
 					Eventually(process.Wait()).Should(Receive())
@@ -377,7 +459,7 @@ var _ = Describe("Radar", func() {
 			BeforeEach(func() {
 				checked := false
 
-				fakeResource.CheckStub = func(atc.Source, atc.Version) ([]atc.Version, error) {
+				fakeResource.CheckStub = func(resource.IOConfig, atc.Source, atc.Version) ([]atc.Version, error) {
 					times <- time.Now()
 
 					if checked {
@@ -406,16 +488,19 @@ var _ = Describe("Radar", func() {
 		var (
 			fakeResource *rfakes.FakeResource
 
+			logger  *lagertest.TestLogger
 			scanErr error
 		)
 
 		BeforeEach(func() {
 			fakeResource = new(rfakes.FakeResource)
 			fakeTracker.InitReturns(fakeResource, nil)
+
+			logger = lagertest.NewTestLogger("test")
 		})
 
 		JustBeforeEach(func() {
-			scanErr = radar.Scan(lagertest.NewTestLogger("test"), "some-resource")
+			scanErr = radar.Scan(logger, "some-resource")
 		})
 
 		It("succeeds", func() {
@@ -452,6 +537,60 @@ var _ = Describe("Radar", func() {
 			Ω(fakeResource.ReleaseCallCount()).Should(Equal(1))
 		})
 
+		It("marks the resource as being checked until the check is done", func() {
+			Ω(fakeRadarDB.SetResourceCheckingCallCount()).Should(Equal(1))
+
+			savedResourceArg, ttl := fakeRadarDB.SetResourceCheckingArgsForCall(0)
+			Ω(savedResourceArg).Should(Equal(savedResource))
+			Ω(ttl).Should(Equal(5 * time.Minute))
+
+			Ω(fakeRadarDB.ClearResourceCheckingCallCount()).Should(Equal(1))
+			Ω(fakeRadarDB.ClearResourceCheckingArgsForCall(0)).Should(Equal(savedResource))
+		})
+
+		Context("while checking", func() {
+			BeforeEach(func() {
+				fakeResource.CheckStub = func(resource.IOConfig, atc.Source, atc.Version) ([]atc.Version, error) {
+					Ω(fakeRadarDB.SetResourceCheckingCallCount()).Should(Equal(1))
+					Ω(fakeRadarDB.ClearResourceCheckingCallCount()).Should(BeZero())
+					return nil, nil
+				}
+			})
+
+			It("has the resource marked as being checked", func() {
+				Ω(fakeResource.CheckCallCount()).Should(Equal(1))
+			})
+		})
+
+		Context("when the check outlasts how long the resource is marked as being checked", func() {
+			BeforeEach(func() {
+				fakeClock := fakeclock.NewFakeClock(time.Unix(123, 0))
+				radar = NewRadar(fakeTracker, fakeCredentialManager, interval, locker, fakeRadarDB, NewCheckLimiter(0), fakeClock)
+
+				fakeResource.CheckStub = func(resource.IOConfig, atc.Source, atc.Version) ([]atc.Version, error) {
+					fakeClock.Increment(3 * time.Minute)
+					Eventually(fakeRadarDB.SetResourceCheckingCallCount).Should(Equal(2))
+
+					fakeClock.Increment(3 * time.Minute)
+					Eventually(fakeRadarDB.SetResourceCheckingCallCount).Should(Equal(3))
+
+					return nil, nil
+				}
+			})
+
+			It("keeps the resource marked while the check runs", func() {
+				Ω(fakeResource.CheckCallCount()).Should(Equal(1))
+
+				for i := 0; i < 3; i++ {
+					savedResourceArg, ttl := fakeRadarDB.SetResourceCheckingArgsForCall(i)
+					Ω(savedResourceArg).Should(Equal(savedResource))
+					Ω(ttl).Should(Equal(5 * time.Minute))
+				}
+
+				Ω(fakeRadarDB.ClearResourceCheckingCallCount()).Should(Equal(1))
+			})
+		})
+
 		It("clears the resource's check error", func() {
 			Ω(fakeRadarDB.SetResourceCheckErrorCallCount()).Should(Equal(1))
 
@@ -460,9 +599,84 @@ var _ = Describe("Radar", func() {
 			Ω(err).Should(BeNil())
 		})
 
+		It("records a successful check result", func() {
+			Ω(fakeRadarDB.SaveResourceCheckResultCallCount()).Should(Equal(1))
+
+			savedResourceArg, err := fakeRadarDB.SaveResourceCheckResultArgsForCall(0)
+			Ω(savedResourceArg).Should(Equal(savedResource))
+			Ω(err).Should(BeNil())
+		})
+
+		It("updates when the resource was last checked, but not when it last errored", func() {
+			Ω(fakeRadarDB.UpdateResourceLastCheckedCallCount()).Should(Equal(1))
+
+			savedResourceArg, checkedAt := fakeRadarDB.UpdateResourceLastCheckedArgsForCall(0)
+			Ω(savedResourceArg).Should(Equal(savedResource))
+			Ω(checkedAt).Should(BeTemporally("~", time.Now(), time.Minute))
+
+			Ω(fakeRadarDB.UpdateResourceLastCheckErroredCallCount()).Should(BeZero())
+		})
+
+		Context("when the source refers to a credential", func() {
+			BeforeEach(func() {
+				resourceConfig.Source = atc.Source{
+					"uri": "http://example.com",
+					"auth": map[string]interface{}{
+						"token": "((some-token))",
+					},
+				}
+
+				fakeRadarDB.GetConfigReturns(atc.Config{
+					Resources: atc.ResourceConfigs{resourceConfig},
+				}, 1, nil)
+			})
+
+			It("resolves the credential before checking", func() {
+				Ω(fakeResource.CheckCallCount()).Should(Equal(1))
+
+				_, source, _ := fakeResource.CheckArgsForCall(0)
+				Ω(source).Should(Equal(atc.Source{
+					"uri": "http://example.com",
+					"auth": map[string]interface{}{
+						"token": "some-secret-token",
+					},
+				}))
+			})
+
+			It("identifies the check container by the unresolved source", func() {
+				session, _, _ := fakeTracker.InitArgsForCall(0)
+				Ω(session.ID.CheckSource).Should(Equal(resourceConfig.Source))
+			})
+
+			It("never logs the credential", func() {
+				logs, err := json.Marshal(logger.Logs())
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(string(logs)).ShouldNot(ContainSubstring("some-secret-token"))
+			})
+
+			Context("when the credential is missing", func() {
+				BeforeEach(func() {
+					fakeCredentialManager.GetReturns("", credentials.MissingCredentialError{Key: "some-token"})
+				})
+
+				It("returns the error without checking", func() {
+					Ω(scanErr).Should(Equal(credentials.MissingCredentialError{Key: "some-token"}))
+					Ω(fakeResource.CheckCallCount()).Should(BeZero())
+				})
+
+				It("records it as the resource's check error", func() {
+					Ω(fakeRadarDB.SetResourceCheckErrorCallCount()).Should(Equal(1))
+
+					_, err := fakeRadarDB.SetResourceCheckErrorArgsForCall(0)
+					Ω(err).Should(Equal(credentials.MissingCredentialError{Key: "some-token"}))
+				})
+			})
+		})
+
 		Context("when there is no current version", func() {
 			It("checks from nil", func() {
-				_, version := fakeResource.CheckArgsForCall(0)
+				_, _, version := fakeResource.CheckArgsForCall(0)
 				Ω(version).Should(BeNil())
 			})
 		})
@@ -473,6 +687,7 @@ var _ = Describe("Radar", func() {
 					db.SavedVersionedResource{
 						ID: 1,
 						VersionedResource: db.VersionedResource{
+							Source: db.Source{"uri": "http://example.com"},
 							Version: db.Version{
 								"version": "1",
 							},
@@ -481,9 +696,101 @@ var _ = Describe("Radar", func() {
 			})
 
 			It("checks from it", func() {
-				_, version := fakeResource.CheckArgsForCall(0)
+				_, _, version := fakeResource.CheckArgsForCall(0)
 				Ω(version).Should(Equal(atc.Version{"version": "1"}))
 			})
+
+			It("records the source, as none was recorded yet", func() {
+				Ω(fakeRadarDB.UpdateResourceSourceHashCallCount()).Should(Equal(1))
+
+				resource, sourceHash := fakeRadarDB.UpdateResourceSourceHashArgsForCall(0)
+				Ω(resource).Should(Equal(savedResource))
+				Ω(sourceHash).Should(Equal(resourceConfig.Source.Hash()))
+			})
+
+			Context("and it was found with a different source", func() {
+				BeforeEach(func() {
+					fakeRadarDB.GetLatestVersionedResourceReturns(
+						db.SavedVersionedResource{
+							ID: 1,
+							VersionedResource: db.VersionedResource{
+								Source:  db.Source{"uri": "http://old.example.com"},
+								Version: db.Version{"version": "1"},
+							},
+						}, nil)
+				})
+
+				It("checks from scratch", func() {
+					_, _, version := fakeResource.CheckArgsForCall(0)
+					Ω(version).Should(BeNil())
+				})
+			})
+
+			Context("and the resource was last checked with the same source", func() {
+				BeforeEach(func() {
+					savedResource.SourceHash = resourceConfig.Source.Hash()
+					fakeRadarDB.GetResourceReturns(savedResource, nil)
+				})
+
+				It("checks from it", func() {
+					_, _, version := fakeResource.CheckArgsForCall(0)
+					Ω(version).Should(Equal(atc.Version{"version": "1"}))
+				})
+
+				It("does not record the source again", func() {
+					Ω(fakeRadarDB.UpdateResourceSourceHashCallCount()).Should(BeZero())
+				})
+			})
+
+			Context("and the resource was last checked with a different source", func() {
+				BeforeEach(func() {
+					savedResource.SourceHash = atc.Source{"uri": "http://old.example.com"}.Hash()
+					fakeRadarDB.GetResourceReturns(savedResource, nil)
+				})
+
+				It("checks from scratch", func() {
+					_, _, version := fakeResource.CheckArgsForCall(0)
+					Ω(version).Should(BeNil())
+					Ω(fakeRadarDB.GetLatestVersionedResourceCallCount()).Should(BeZero())
+				})
+
+				It("saves the versions found for the new source, which records it", func() {
+					Ω(fakeRadarDB.SaveResourceVersionsCallCount()).Should(BeZero())
+					Ω(fakeRadarDB.UpdateResourceSourceHashCallCount()).Should(BeZero())
+
+					Ω(fakeRadarDB.SaveResourceVersionsForNewSourceCallCount()).Should(Equal(1))
+
+					savedConfig, versions := fakeRadarDB.SaveResourceVersionsForNewSourceArgsForCall(0)
+					Ω(savedConfig).Should(Equal(resourceConfig))
+					Ω(versions).Should(BeEmpty())
+				})
+
+				Context("when the check finds versions", func() {
+					BeforeEach(func() {
+						fakeResource.CheckReturns([]atc.Version{{"version": "1"}}, nil)
+					})
+
+					It("saves them for the new source", func() {
+						Ω(fakeRadarDB.SaveResourceVersionsCallCount()).Should(BeZero())
+
+						Ω(fakeRadarDB.SaveResourceVersionsForNewSourceCallCount()).Should(Equal(1))
+
+						_, versions := fakeRadarDB.SaveResourceVersionsForNewSourceArgsForCall(0)
+						Ω(versions).Should(Equal([]atc.Version{{"version": "1"}}))
+					})
+				})
+
+				Context("when the check fails", func() {
+					BeforeEach(func() {
+						fakeResource.CheckReturns(nil, errors.New("nope"))
+					})
+
+					It("does not record the new source, so the next check is from scratch too", func() {
+						Ω(fakeRadarDB.SaveResourceVersionsForNewSourceCallCount()).Should(BeZero())
+						Ω(fakeRadarDB.UpdateResourceSourceHashCallCount()).Should(BeZero())
+					})
+				})
+			})
 		})
 
 		Context("when the check returns versions", func() {
@@ -505,7 +812,7 @@ var _ = Describe("Radar", func() {
 				}
 
 				check := 0
-				fakeResource.CheckStub = func(source atc.Source, from atc.Version) ([]atc.Version, error) {
+				fakeResource.CheckStub = func(_ resource.IOConfig, source atc.Source, from atc.Version) ([]atc.Version, error) {
 					defer GinkgoRecover()
 
 					Ω(source).Should(Equal(resourceConfig.Source))
@@ -533,6 +840,16 @@ var _ = Describe("Radar", func() {
 					{"version": "3"},
 				}))
 			})
+
+			Context("when saving them fails", func() {
+				BeforeEach(func() {
+					fakeRadarDB.SaveResourceVersionsReturns(errors.New("nope"))
+				})
+
+				It("does not record the source", func() {
+					Ω(fakeRadarDB.UpdateResourceSourceHashCallCount()).Should(BeZero())
+				})
+			})
 		})
 
 		Context("when checking fails", func() {
@@ -553,6 +870,319 @@ var _ = Describe("Radar", func() {
 				Ω(savedResourceArg).Should(Equal(savedResource))
 				Ω(err).Should(Equal(disaster))
 			})
+
+			It("records the failed check result", func() {
+				Ω(fakeRadarDB.SaveResourceCheckResultCallCount()).Should(Equal(1))
+
+				savedResourceArg, err := fakeRadarDB.SaveResourceCheckResultArgsForCall(0)
+				Ω(savedResourceArg).Should(Equal(savedResource))
+				Ω(err).Should(Equal(disaster))
+			})
+
+			It("updates when the resource last errored, but not when it was last checked", func() {
+				Ω(fakeRadarDB.UpdateResourceLastCheckErroredCallCount()).Should(Equal(1))
+
+				savedResourceArg, erroredAt := fakeRadarDB.UpdateResourceLastCheckErroredArgsForCall(0)
+				Ω(savedResourceArg).Should(Equal(savedResource))
+				Ω(erroredAt).Should(BeTemporally("~", time.Now(), time.Minute))
+
+				Ω(fakeRadarDB.UpdateResourceLastCheckedCallCount()).Should(BeZero())
+			})
+		})
+
+		Context("when the resource is paused", func() {
+			BeforeEach(func() {
+				fakeRadarDB.GetResourceReturns(db.SavedResource{
+					Resource: db.Resource{
+						Name: "some-resource",
+					},
+					Paused: true,
+				}, nil)
+			})
+
+			It("checks anyway, as the scan was explicitly requested", func() {
+				Ω(scanErr).ShouldNot(HaveOccurred())
+				Ω(fakeResource.CheckCallCount()).Should(Equal(1))
+			})
+		})
+
+		Context("with a limit on concurrent checks", func() {
+			var (
+				inFlight    int
+				maxInFlight int
+				checks      int
+				checksL     sync.Mutex
+			)
+
+			BeforeEach(func() {
+				inFlight = 0
+				maxInFlight = 0
+				checks = 0
+
+				radar = NewRadar(fakeTracker, fakeCredentialManager, interval, locker, fakeRadarDB, NewCheckLimiter(2), radarClock)
+
+				fakeResource.CheckStub = func(resource.IOConfig, atc.Source, atc.Version) ([]atc.Version, error) {
+					checksL.Lock()
+					inFlight++
+					if inFlight > maxInFlight {
+						maxInFlight = inFlight
+					}
+					checksL.Unlock()
+
+					time.Sleep(100 * time.Millisecond)
+
+					checksL.Lock()
+					inFlight--
+					checks++
+					checksL.Unlock()
+
+					return nil, nil
+				}
+			})
+
+			It("runs at most that many checks at once", func() {
+				wg := new(sync.WaitGroup)
+
+				for i := 0; i < 3; i++ {
+					wg.Add(1)
+
+					go func() {
+						defer GinkgoRecover()
+						defer wg.Done()
+
+						err := radar.Scan(lagertest.NewTestLogger("test"), "some-resource")
+						Ω(err).ShouldNot(HaveOccurred())
+					}()
+				}
+
+				wg.Wait()
+
+				checksL.Lock()
+				defer checksL.Unlock()
+
+				// the JustBeforeEach scan plus the three concurrent ones
+				Ω(checks).Should(Equal(4))
+				Ω(maxInFlight).Should(Equal(2))
+			})
+		})
+
+		Context("with a limit on concurrent checks per host", func() {
+			var (
+				inFlight    map[string]int
+				maxInFlight map[string]int
+				checksL     sync.Mutex
+			)
+
+			BeforeEach(func() {
+				inFlight = map[string]int{}
+				maxInFlight = map[string]int{}
+
+				radar = NewRadar(fakeTracker, fakeCredentialManager, interval, locker, fakeRadarDB, NewGroupedCheckLimiter(0, SourceURIHost, 1), radarClock)
+
+				fakeRadarDB.GetConfigReturns(atc.Config{
+					Resources: atc.ResourceConfigs{
+						resourceConfig,
+						{
+							Name:   "some-resource-on-the-same-host",
+							Type:   "git",
+							Source: atc.Source{"uri": "git@example.com:some/repo.git"},
+						},
+						{
+							Name:   "some-resource-on-another-host",
+							Type:   "git",
+							Source: atc.Source{"uri": "https://example.org/some/repo.git"},
+						},
+					},
+				}, 1, nil)
+
+				fakeResource.CheckStub = func(_ resource.IOConfig, source atc.Source, _ atc.Version) ([]atc.Version, error) {
+					uri := source["uri"].(string)
+
+					checksL.Lock()
+					inFlight[uri]++
+					total := 0
+					for _, n := range inFlight {
+						total += n
+					}
+					if total > maxInFlight["total"] {
+						maxInFlight["total"] = total
+					}
+					sameHost := inFlight["http://example.com"] + inFlight["git@example.com:some/repo.git"]
+					if sameHost > maxInFlight["example.com"] {
+						maxInFlight["example.com"] = sameHost
+					}
+					checksL.Unlock()
+
+					time.Sleep(100 * time.Millisecond)
+
+					checksL.Lock()
+					inFlight[uri]--
+					checksL.Unlock()
+
+					return nil, nil
+				}
+			})
+
+			It("does not check resources sharing a host at the same time", func() {
+				wg := new(sync.WaitGroup)
+
+				for _, name := range []string{"some-resource", "some-resource-on-the-same-host", "some-resource-on-another-host"} {
+					wg.Add(1)
+
+					go func(name string) {
+						defer GinkgoRecover()
+						defer wg.Done()
+
+						err := radar.Scan(lagertest.NewTestLogger("test"), name)
+						Ω(err).ShouldNot(HaveOccurred())
+					}(name)
+				}
+
+				wg.Wait()
+
+				checksL.Lock()
+				defer checksL.Unlock()
+
+				Ω(maxInFlight["example.com"]).Should(Equal(1))
+				Ω(maxInFlight["total"]).Should(Equal(2))
+			})
+		})
+	})
+
+	Describe("VersionExists", func() {
+		var (
+			fakeResource *rfakes.FakeResource
+
+			exists    bool
+			existsErr error
+		)
+
+		BeforeEach(func() {
+			fakeResource = new(rfakes.FakeResource)
+			fakeTracker.InitReturns(fakeResource, nil)
+		})
+
+		JustBeforeEach(func() {
+			exists, existsErr = radar.VersionExists(lagertest.NewTestLogger("test"), "some-resource", atc.Version{"version": "1"})
+		})
+
+		It("checks from the version with the resource's config", func() {
+			Ω(fakeTracker.InitCallCount()).Should(Equal(1))
+
+			_, source, version := fakeResource.CheckArgsForCall(0)
+			Ω(source).Should(Equal(resourceConfig.Source))
+			Ω(version).Should(Equal(atc.Version{"version": "1"}))
+		})
+
+		It("releases the resource", func() {
+			Ω(fakeResource.ReleaseCallCount()).Should(Equal(1))
+		})
+
+		It("does not save anything", func() {
+			Ω(fakeRadarDB.SaveResourceVersionsCallCount()).Should(BeZero())
+			Ω(fakeRadarDB.SaveResourceCheckResultCallCount()).Should(BeZero())
+			Ω(fakeRadarDB.UpdateResourceLastCheckedCallCount()).Should(BeZero())
+		})
+
+		Context("when the version is still present upstream", func() {
+			BeforeEach(func() {
+				fakeResource.CheckReturns([]atc.Version{
+					{"version": "1"},
+					{"version": "2"},
+				}, nil)
+			})
+
+			It("returns true", func() {
+				Ω(existsErr).ShouldNot(HaveOccurred())
+				Ω(exists).Should(BeTrue())
+			})
+		})
+
+		Context("when the version has been removed upstream", func() {
+			BeforeEach(func() {
+				fakeResource.CheckReturns([]atc.Version{
+					{"version": "2"},
+				}, nil)
+			})
+
+			It("returns false", func() {
+				Ω(existsErr).ShouldNot(HaveOccurred())
+				Ω(exists).Should(BeFalse())
+			})
+		})
+
+		Context("when the check fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeResource.CheckReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				Ω(existsErr).Should(Equal(disaster))
+			})
+		})
+
+		Context("when the resource is not in the config", func() {
+			BeforeEach(func() {
+				fakeRadarDB.GetConfigReturns(atc.Config{}, 1, nil)
+			})
+
+			It("returns an error without checking", func() {
+				Ω(existsErr).Should(HaveOccurred())
+				Ω(fakeTracker.InitCallCount()).Should(BeZero())
+			})
+		})
+	})
+
+	Describe("DebugCheck", func() {
+		var (
+			fakeResource *rfakes.FakeResource
+
+			stdout *bytes.Buffer
+			stderr *bytes.Buffer
+
+			versions []atc.Version
+			checkErr error
+		)
+
+		BeforeEach(func() {
+			fakeResource = new(rfakes.FakeResource)
+			fakeTracker.InitReturns(fakeResource, nil)
+
+			stdout = new(bytes.Buffer)
+			stderr = new(bytes.Buffer)
+
+			fakeResource.CheckStub = func(ioConfig resource.IOConfig, _ atc.Source, _ atc.Version) ([]atc.Version, error) {
+				ioConfig.Stdout.Write([]byte("some-stdout"))
+				ioConfig.Stderr.Write([]byte("some-stderr"))
+				return []atc.Version{{"version": "2"}}, nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			versions, checkErr = radar.DebugCheck(lagertest.NewTestLogger("test"), "some-resource", atc.Version{"version": "1"}, stdout, stderr)
+		})
+
+		It("checks from the version with the resource's config, streaming stdout and stderr", func() {
+			Ω(checkErr).ShouldNot(HaveOccurred())
+
+			_, source, version := fakeResource.CheckArgsForCall(0)
+			Ω(source).Should(Equal(resourceConfig.Source))
+			Ω(version).Should(Equal(atc.Version{"version": "1"}))
+
+			Ω(stdout.String()).Should(Equal("some-stdout"))
+			Ω(stderr.String()).Should(Equal("some-stderr"))
+		})
+
+		It("returns the versions found", func() {
+			Ω(versions).Should(Equal([]atc.Version{{"version": "2"}}))
+		})
+
+		It("releases the resource without saving anything", func() {
+			Ω(fakeResource.ReleaseCallCount()).Should(Equal(1))
+			Ω(fakeRadarDB.SaveResourceVersionsCallCount()).Should(BeZero())
+			Ω(fakeRadarDB.SaveResourceCheckResultCallCount()).Should(BeZero())
 		})
 	})
 })
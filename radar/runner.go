@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/concourse/atc/db"
+	"github.com/pivotal-golang/clock"
 	"github.com/pivotal-golang/lager"
 	"github.com/tedsuo/ifrit"
 	"github.com/tedsuo/ifrit/grouper"
@@ -34,6 +35,7 @@ type Runner struct {
 	db                db.PipelineDB
 	pipelineDBFactory db.PipelineDBFactory
 	syncInterval      time.Duration
+	clock             clock.Clock
 }
 
 func NewRunner(
@@ -43,6 +45,7 @@ func NewRunner(
 	scannerFactory ScannerFactory,
 	db db.PipelineDB,
 	syncInterval time.Duration,
+	clock clock.Clock,
 ) *Runner {
 	return &Runner{
 		logger:         logger,
@@ -51,6 +54,7 @@ func NewRunner(
 		scannerFactory: scannerFactory,
 		db:             db,
 		syncInterval:   syncInterval,
+		clock:          clock,
 	}
 }
 
@@ -65,7 +69,8 @@ func (runner *Runner) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 	runner.logger.Info("start")
 	defer runner.logger.Info("done")
 
-	ticker := time.NewTicker(runner.syncInterval)
+	ticker := runner.clock.NewTicker(runner.syncInterval)
+	defer ticker.Stop()
 
 	scannersGroup := grouper.NewDynamic(nil, 0, 0)
 
@@ -96,7 +101,7 @@ dance:
 
 			delete(scanning, exited.Member.Name)
 
-		case <-ticker.C:
+		case <-ticker.C():
 			runner.tick(scanning, insertScanner)
 		}
 	}
@@ -111,6 +116,8 @@ func (runner *Runner) tick(scanning map[string]bool, insertScanner chan<- groupe
 		return
 	}
 
+	config = config.WithDefaults()
+
 	for _, resource := range config.Resources {
 		scopedName := runner.db.ScopedName(resource.Name)
 
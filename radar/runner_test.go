@@ -9,6 +9,7 @@ import (
 	dbfakes "github.com/concourse/atc/db/fakes"
 	. "github.com/concourse/atc/radar"
 	"github.com/concourse/atc/radar/fakes"
+	"github.com/pivotal-golang/clock/fakeclock"
 	"github.com/pivotal-golang/lager"
 	"github.com/pivotal-golang/lager/lagertest"
 	"github.com/tedsuo/ifrit"
@@ -25,6 +26,8 @@ var _ = Describe("Runner", func() {
 		scannerFactory *fakes.FakeScannerFactory
 		noop           bool
 		syncInterval   time.Duration
+		fakeClock      *fakeclock.FakeClock
+		logger         *lagertest.TestLogger
 
 		initialConfig atc.Config
 
@@ -36,7 +39,9 @@ var _ = Describe("Runner", func() {
 		scannerFactory = new(fakes.FakeScannerFactory)
 		pipelineDB = new(dbfakes.FakePipelineDB)
 		noop = false
-		syncInterval = 100 * time.Millisecond
+		syncInterval = 10 * time.Second
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+		logger = lagertest.NewTestLogger("test")
 
 		initialConfig = atc.Config{
 			Resources: atc.ResourceConfigs{
@@ -65,12 +70,13 @@ var _ = Describe("Runner", func() {
 
 	JustBeforeEach(func() {
 		process = ginkgomon.Invoke(NewRunner(
-			lagertest.NewTestLogger("test"),
+			logger,
 			noop,
 			locker,
 			scannerFactory,
 			pipelineDB,
 			syncInterval,
+			fakeClock,
 		))
 	})
 
@@ -92,7 +98,7 @@ var _ = Describe("Runner", func() {
 		var updateConfig chan<- atc.Config
 
 		BeforeEach(func() {
-			configs := make(chan atc.Config)
+			configs := make(chan atc.Config, 1)
 			updateConfig = configs
 
 			config := initialConfig
@@ -107,7 +113,7 @@ var _ = Describe("Runner", func() {
 			}
 		})
 
-		It("scans for them eventually", func() {
+		It("scans for them once the interval elapses", func() {
 			Eventually(scannerFactory.ScannerCallCount).Should(Equal(2))
 
 			_, resource := scannerFactory.ScannerArgsForCall(0)
@@ -122,6 +128,9 @@ var _ = Describe("Runner", func() {
 			})
 
 			updateConfig <- newConfig
+			Consistently(scannerFactory.ScannerCallCount).Should(Equal(2))
+
+			fakeClock.Increment(syncInterval)
 
 			Eventually(scannerFactory.ScannerCallCount).Should(Equal(3))
 
@@ -139,9 +148,17 @@ var _ = Describe("Runner", func() {
 			scannerExit = make(chan struct{})
 
 			scannerFactory.ScannerStub = func(lager.Logger, string) ifrit.Runner {
+				// only the first scanners stop; the ones scanning again keep going
+				stops := scannerFactory.ScannerCallCount() <= 2
+
 				return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
 					close(ready)
 
+					if !stops {
+						<-signals
+						return nil
+					}
+
 					select {
 					case <-signals:
 						return nil
@@ -152,7 +169,7 @@ var _ = Describe("Runner", func() {
 			}
 		})
 
-		It("starts scanning again eventually", func() {
+		It("starts scanning again once the interval elapses", func() {
 			Eventually(scannerFactory.ScannerCallCount).Should(Equal(2))
 
 			_, resource := scannerFactory.ScannerArgsForCall(0)
@@ -163,7 +180,22 @@ var _ = Describe("Runner", func() {
 
 			close(scannerExit)
 
-			Eventually(scannerFactory.ScannerCallCount, 10*syncInterval).Should(Equal(4))
+			Eventually(func() int {
+				exited := 0
+				for _, message := range logger.LogMessages() {
+					if message == "test.scanner-exited" {
+						exited++
+					}
+				}
+
+				return exited
+			}).Should(Equal(2))
+
+			Consistently(scannerFactory.ScannerCallCount).Should(Equal(2))
+
+			fakeClock.Increment(syncInterval)
+
+			Eventually(scannerFactory.ScannerCallCount).Should(Equal(4))
 
 			_, resource = scannerFactory.ScannerArgsForCall(2)
 			Ω(resource).Should(Equal("some-resource"))
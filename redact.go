@@ -0,0 +1,35 @@
+package atc
+
+// RedactedValue replaces the values of source keys that may be secret.
+const RedactedValue = "***"
+
+// PublicSourceKeys are the source keys whose values are not considered
+// secret, and so are left in place by Source.Redacted.
+var PublicSourceKeys = map[string]bool{
+	"uri":        true,
+	"branch":     true,
+	"repository": true,
+	"tag":        true,
+	"bucket":     true,
+	"regexp":     true,
+	"paths":      true,
+}
+
+// Redacted returns a copy of the source with the value of every key not in
+// PublicSourceKeys replaced, making it safe to log or show to users.
+func (src Source) Redacted() Source {
+	if src == nil {
+		return nil
+	}
+
+	redacted := make(Source, len(src))
+	for key, value := range src {
+		if PublicSourceKeys[key] {
+			redacted[key] = value
+		} else {
+			redacted[key] = RedactedValue
+		}
+	}
+
+	return redacted
+}
@@ -10,4 +10,25 @@ type Resource struct {
 
 	FailingToCheck bool   `json:"failing_to_check,omitempty"`
 	CheckError     string `json:"check_error,omitempty"`
+
+	Checking bool `json:"checking,omitempty"`
+
+	// unix timestamps of the last successful and the last failed check
+	LastChecked      int64 `json:"last_checked,omitempty"`
+	LastCheckErrored int64 `json:"last_check_errored,omitempty"`
+}
+
+type ResourceVersion struct {
+	ID       int             `json:"id"`
+	Version  Version         `json:"version"`
+	Enabled  bool            `json:"enabled"`
+	Metadata []MetadataField `json:"metadata,omitempty"`
+}
+
+type VersionExists struct {
+	Exists bool `json:"exists"`
+}
+
+type ClearedCache struct {
+	Cleared int `json:"cleared"`
 }
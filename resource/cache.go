@@ -0,0 +1,178 @@
+package resource
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/pivotal-golang/clock"
+)
+
+// CacheIdentifier identifies a fetched resource version. Two gets with the
+// same identifier would produce the same bits.
+type CacheIdentifier struct {
+	Type    ResourceType
+	Source  atc.Source
+	Params  atc.Params
+	Version atc.Version
+}
+
+// Hash identifies the fetch, including the params it was fetched with.
+func (identifier CacheIdentifier) Hash() string {
+	return hashParts(identifier.VersionHash(), identifier.Params.Hash())
+}
+
+// VersionHash identifies the version fetched, regardless of the params it
+// was fetched with, e.g. to clear every fetch of a version.
+func (identifier CacheIdentifier) VersionHash() string {
+	return hashParts(string(identifier.Type), identifier.Source.Hash(), identifier.Version.Hash())
+}
+
+func hashParts(parts ...string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(parts, "\x00"))))
+}
+
+type CachedFetch struct {
+	Session  Session
+	Version  atc.Version
+	Metadata []atc.MetadataField
+}
+
+//go:generate counterfeiter . Cache
+
+// Cache remembers the containers of previous gets so that identical gets can
+// reuse them. Each user of a fetch holds a reference on it. Once none are
+// left, the fetch is only kept for as long as its container is expected to
+// outlive its last user, so that a later build can still reuse it.
+type Cache interface {
+	// Lookup returns the fetch for the identifier, taking a reference on it.
+	Lookup(CacheIdentifier) (CachedFetch, bool)
+
+	// Save records a new fetch for the identifier, replacing any previous one,
+	// with a reference held by the caller.
+	Save(CacheIdentifier, CachedFetch)
+
+	// Release gives back a reference taken by Lookup or Save.
+	Release(CacheIdentifier, CachedFetch)
+
+	// Clear forgets every fetch of the identifier's version, whatever its
+	// params, returning how many there were.
+	Clear(CacheIdentifier) int
+}
+
+type cachedEntry struct {
+	identifier CacheIdentifier
+	fetch      CachedFetch
+	references int
+
+	// when the last reference was given back
+	releasedAt time.Time
+}
+
+type cache struct {
+	ttl   time.Duration
+	clock clock.Clock
+
+	fetches  map[string]*cachedEntry
+	fetchesL sync.Mutex
+}
+
+// NewCache returns a Cache that keeps fetches nobody holds a reference on
+// for the given TTL, i.e. the grace time of the containers they were fetched
+// into.
+func NewCache(ttl time.Duration, clock clock.Clock) Cache {
+	return &cache{
+		ttl:   ttl,
+		clock: clock,
+
+		fetches: map[string]*cachedEntry{},
+	}
+}
+
+func (cache *cache) Lookup(identifier CacheIdentifier) (CachedFetch, bool) {
+	cache.fetchesL.Lock()
+	defer cache.fetchesL.Unlock()
+
+	cache.evictExpired()
+
+	entry, found := cache.fetches[identifier.Hash()]
+	if !found {
+		return CachedFetch{}, false
+	}
+
+	entry.references++
+
+	return entry.fetch, true
+}
+
+func (cache *cache) Save(identifier CacheIdentifier, fetch CachedFetch) {
+	cache.fetchesL.Lock()
+	defer cache.fetchesL.Unlock()
+
+	cache.evictExpired()
+
+	cache.fetches[identifier.Hash()] = &cachedEntry{
+		identifier: identifier,
+		fetch:      fetch,
+		references: 1,
+	}
+}
+
+func (cache *cache) Release(identifier CacheIdentifier, fetch CachedFetch) {
+	hash := identifier.Hash()
+
+	cache.fetchesL.Lock()
+	defer cache.fetchesL.Unlock()
+
+	entry, found := cache.fetches[hash]
+
+	// the fetch may have been cleared or replaced since the reference was taken
+	if !found || !reflect.DeepEqual(entry.fetch.Session, fetch.Session) {
+		return
+	}
+
+	if entry.references == 0 {
+		return
+	}
+
+	entry.references--
+
+	if entry.references == 0 {
+		entry.releasedAt = cache.clock.Now()
+	}
+}
+
+func (cache *cache) Clear(identifier CacheIdentifier) int {
+	versionHash := identifier.VersionHash()
+
+	cache.fetchesL.Lock()
+	defer cache.fetchesL.Unlock()
+
+	cleared := 0
+	for hash, entry := range cache.fetches {
+		if entry.identifier.VersionHash() == versionHash {
+			delete(cache.fetches, hash)
+			cleared++
+		}
+	}
+
+	return cleared
+}
+
+// evictExpired forgets the fetches that nobody has held a reference on for
+// longer than the TTL, as their containers may have been reaped.
+//
+// Must be called with fetchesL held.
+func (cache *cache) evictExpired() {
+	now := cache.clock.Now()
+
+	for hash, entry := range cache.fetches {
+		if entry.references == 0 && now.Sub(entry.releasedAt) >= cache.ttl {
+			delete(cache.fetches, hash)
+		}
+	}
+}
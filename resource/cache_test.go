@@ -0,0 +1,261 @@
+package resource_test
+
+import (
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/worker"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/clock/fakeclock"
+
+	. "github.com/concourse/atc/resource"
+)
+
+var _ = Describe("Cache", func() {
+	var (
+		fakeClock *fakeclock.FakeClock
+
+		cache Cache
+
+		identifier CacheIdentifier
+		fetch      CachedFetch
+	)
+
+	BeforeEach(func() {
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+
+		cache = NewCache(time.Minute, fakeClock)
+
+		identifier = CacheIdentifier{
+			Type:    "some-type",
+			Source:  atc.Source{"some": "source"},
+			Version: atc.Version{"some": "version"},
+		}
+
+		fetch = CachedFetch{
+			Session: Session{
+				ID: worker.Identifier{Name: "some-session"},
+			},
+			Version:  atc.Version{"some": "version"},
+			Metadata: []atc.MetadataField{{"some", "metadata"}},
+		}
+	})
+
+	It("misses when nothing has been saved", func() {
+		_, found := cache.Lookup(identifier)
+		Ω(found).Should(BeFalse())
+	})
+
+	It("hits after a fetch has been saved", func() {
+		cache.Save(identifier, fetch)
+
+		cachedFetch, found := cache.Lookup(identifier)
+		Ω(found).Should(BeTrue())
+		Ω(cachedFetch).Should(Equal(fetch))
+	})
+
+	It("misses when the version differs", func() {
+		cache.Save(identifier, fetch)
+
+		identifier.Version = atc.Version{"some": "other-version"}
+
+		_, found := cache.Lookup(identifier)
+		Ω(found).Should(BeFalse())
+	})
+
+	It("misses when the source differs", func() {
+		cache.Save(identifier, fetch)
+
+		identifier.Source = atc.Source{"some": "other-source"}
+
+		_, found := cache.Lookup(identifier)
+		Ω(found).Should(BeFalse())
+	})
+
+	It("misses when the type differs", func() {
+		cache.Save(identifier, fetch)
+
+		identifier.Type = "some-other-type"
+
+		_, found := cache.Lookup(identifier)
+		Ω(found).Should(BeFalse())
+	})
+
+	It("misses when the params differ", func() {
+		cache.Save(identifier, fetch)
+
+		identifier.Params = atc.Params{"skip_download": true}
+
+		_, found := cache.Lookup(identifier)
+		Ω(found).Should(BeFalse())
+	})
+
+	It("replaces previously saved fetches", func() {
+		cache.Save(identifier, fetch)
+
+		fetch.Session.ID.Name = "some-other-session"
+		cache.Save(identifier, fetch)
+
+		cachedFetch, found := cache.Lookup(identifier)
+		Ω(found).Should(BeTrue())
+		Ω(cachedFetch.Session.ID.Name).Should(Equal("some-other-session"))
+	})
+
+	Describe("Release", func() {
+		It("keeps the fetch for the TTL once every reference is given back", func() {
+			cache.Save(identifier, fetch)
+
+			_, found := cache.Lookup(identifier)
+			Ω(found).Should(BeTrue())
+
+			cache.Release(identifier, fetch)
+			cache.Release(identifier, fetch)
+
+			fakeClock.Increment(time.Minute - time.Second)
+
+			_, found = cache.Lookup(identifier)
+			Ω(found).Should(BeTrue())
+			cache.Release(identifier, fetch)
+
+			fakeClock.Increment(time.Minute)
+
+			_, found = cache.Lookup(identifier)
+			Ω(found).Should(BeFalse())
+		})
+
+		It("lets sequential builds share a fetch", func() {
+			// the first build fetches, and is done with it
+			cache.Save(identifier, fetch)
+			cache.Release(identifier, fetch)
+
+			fakeClock.Increment(time.Second)
+
+			// the second build hits
+			cachedFetch, found := cache.Lookup(identifier)
+			Ω(found).Should(BeTrue())
+			Ω(cachedFetch).Should(Equal(fetch))
+		})
+
+		It("does not expire a fetch that is still referenced", func() {
+			cache.Save(identifier, fetch)
+
+			fakeClock.Increment(time.Hour)
+
+			_, found := cache.Lookup(identifier)
+			Ω(found).Should(BeTrue())
+		})
+
+		It("keeps the fetch while its saver still holds a reference", func() {
+			cache.Save(identifier, fetch)
+
+			_, found := cache.Lookup(identifier)
+			Ω(found).Should(BeTrue())
+
+			cache.Release(identifier, fetch)
+
+			_, found = cache.Lookup(identifier)
+			Ω(found).Should(BeTrue())
+		})
+
+		It("does not affect a fetch that replaced the released one", func() {
+			cache.Save(identifier, fetch)
+
+			replacement := fetch
+			replacement.Session.ID.Name = "some-other-session"
+			cache.Save(identifier, replacement)
+
+			cache.Release(identifier, fetch)
+
+			cachedFetch, found := cache.Lookup(identifier)
+			Ω(found).Should(BeTrue())
+			Ω(cachedFetch).Should(Equal(replacement))
+		})
+	})
+
+	Describe("Clear", func() {
+		It("removes a saved fetch, so that it misses", func() {
+			cache.Save(identifier, fetch)
+
+			Ω(cache.Clear(identifier)).Should(Equal(1))
+
+			_, found := cache.Lookup(identifier)
+			Ω(found).Should(BeFalse())
+		})
+
+		It("removes the fetches of the version with any params", func() {
+			withParams := identifier
+			withParams.Params = atc.Params{"skip_download": true}
+
+			cache.Save(identifier, fetch)
+			cache.Save(withParams, fetch)
+
+			Ω(cache.Clear(identifier)).Should(Equal(2))
+
+			_, found := cache.Lookup(withParams)
+			Ω(found).Should(BeFalse())
+		})
+
+		It("reports when there was nothing to clear", func() {
+			Ω(cache.Clear(identifier)).Should(BeZero())
+		})
+	})
+})
+
+var _ = Describe("CacheIdentifier", func() {
+	Describe("Hash", func() {
+		It("is the same for equivalent identifiers", func() {
+			a := CacheIdentifier{
+				Type:    "some-type",
+				Source:  atc.Source{"a": "1", "b": "2"},
+				Version: atc.Version{"ref": "abc"},
+			}
+
+			b := CacheIdentifier{
+				Type:    "some-type",
+				Source:  atc.Source{"b": "2", "a": "1"},
+				Version: atc.Version{"ref": "abc"},
+			}
+
+			Ω(a.Hash()).Should(Equal(b.Hash()))
+		})
+
+		It("differs when the version differs", func() {
+			a := CacheIdentifier{Type: "some-type", Version: atc.Version{"ref": "abc"}}
+			b := CacheIdentifier{Type: "some-type", Version: atc.Version{"ref": "def"}}
+
+			Ω(a.Hash()).ShouldNot(Equal(b.Hash()))
+		})
+
+		It("differs when the params differ", func() {
+			a := CacheIdentifier{Type: "some-type", Params: atc.Params{"globs": []interface{}{"*.tgz"}}}
+			b := CacheIdentifier{Type: "some-type", Params: atc.Params{"globs": []interface{}{"*.zip"}}}
+
+			Ω(a.Hash()).ShouldNot(Equal(b.Hash()))
+		})
+
+		It("is the same for sources decoded from YAML", func() {
+			fromYAML := CacheIdentifier{
+				Type:   "some-type",
+				Source: atc.Source{"nested": map[interface{}]interface{}{"x": 1}},
+			}
+
+			fromJSON := CacheIdentifier{
+				Type:   "some-type",
+				Source: atc.Source{"nested": map[string]interface{}{"x": 1}},
+			}
+
+			Ω(fromYAML.Hash()).Should(Equal(fromJSON.Hash()))
+		})
+	})
+
+	Describe("VersionHash", func() {
+		It("is the same regardless of params", func() {
+			a := CacheIdentifier{Type: "some-type", Version: atc.Version{"ref": "abc"}}
+			b := CacheIdentifier{Type: "some-type", Version: atc.Version{"ref": "abc"}, Params: atc.Params{"skip_download": true}}
+
+			Ω(a.VersionHash()).Should(Equal(b.VersionHash()))
+			Ω(a.Hash()).ShouldNot(Equal(b.Hash()))
+		})
+	})
+})
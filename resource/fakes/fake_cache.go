@@ -0,0 +1,155 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/resource"
+)
+
+type FakeCache struct {
+	LookupStub        func(resource.CacheIdentifier) (resource.CachedFetch, bool)
+	lookupMutex       sync.RWMutex
+	lookupArgsForCall []struct {
+		arg1 resource.CacheIdentifier
+	}
+	lookupReturns struct {
+		result1 resource.CachedFetch
+		result2 bool
+	}
+	SaveStub        func(resource.CacheIdentifier, resource.CachedFetch)
+	saveMutex       sync.RWMutex
+	saveArgsForCall []struct {
+		arg1 resource.CacheIdentifier
+		arg2 resource.CachedFetch
+	}
+	ReleaseStub        func(resource.CacheIdentifier, resource.CachedFetch)
+	releaseMutex       sync.RWMutex
+	releaseArgsForCall []struct {
+		arg1 resource.CacheIdentifier
+		arg2 resource.CachedFetch
+	}
+	ClearStub        func(resource.CacheIdentifier) int
+	clearMutex       sync.RWMutex
+	clearArgsForCall []struct {
+		arg1 resource.CacheIdentifier
+	}
+	clearReturns struct {
+		result1 int
+	}
+}
+
+func (fake *FakeCache) Lookup(arg1 resource.CacheIdentifier) (resource.CachedFetch, bool) {
+	fake.lookupMutex.Lock()
+	fake.lookupArgsForCall = append(fake.lookupArgsForCall, struct {
+		arg1 resource.CacheIdentifier
+	}{arg1})
+	fake.lookupMutex.Unlock()
+	if fake.LookupStub != nil {
+		return fake.LookupStub(arg1)
+	} else {
+		return fake.lookupReturns.result1, fake.lookupReturns.result2
+	}
+}
+
+func (fake *FakeCache) LookupCallCount() int {
+	fake.lookupMutex.RLock()
+	defer fake.lookupMutex.RUnlock()
+	return len(fake.lookupArgsForCall)
+}
+
+func (fake *FakeCache) LookupArgsForCall(i int) resource.CacheIdentifier {
+	fake.lookupMutex.RLock()
+	defer fake.lookupMutex.RUnlock()
+	return fake.lookupArgsForCall[i].arg1
+}
+
+func (fake *FakeCache) LookupReturns(result1 resource.CachedFetch, result2 bool) {
+	fake.LookupStub = nil
+	fake.lookupReturns = struct {
+		result1 resource.CachedFetch
+		result2 bool
+	}{result1, result2}
+}
+
+func (fake *FakeCache) Save(arg1 resource.CacheIdentifier, arg2 resource.CachedFetch) {
+	fake.saveMutex.Lock()
+	fake.saveArgsForCall = append(fake.saveArgsForCall, struct {
+		arg1 resource.CacheIdentifier
+		arg2 resource.CachedFetch
+	}{arg1, arg2})
+	fake.saveMutex.Unlock()
+	if fake.SaveStub != nil {
+		fake.SaveStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeCache) SaveCallCount() int {
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	return len(fake.saveArgsForCall)
+}
+
+func (fake *FakeCache) SaveArgsForCall(i int) (resource.CacheIdentifier, resource.CachedFetch) {
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	return fake.saveArgsForCall[i].arg1, fake.saveArgsForCall[i].arg2
+}
+
+func (fake *FakeCache) Release(arg1 resource.CacheIdentifier, arg2 resource.CachedFetch) {
+	fake.releaseMutex.Lock()
+	fake.releaseArgsForCall = append(fake.releaseArgsForCall, struct {
+		arg1 resource.CacheIdentifier
+		arg2 resource.CachedFetch
+	}{arg1, arg2})
+	fake.releaseMutex.Unlock()
+	if fake.ReleaseStub != nil {
+		fake.ReleaseStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeCache) ReleaseCallCount() int {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	return len(fake.releaseArgsForCall)
+}
+
+func (fake *FakeCache) ReleaseArgsForCall(i int) (resource.CacheIdentifier, resource.CachedFetch) {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	return fake.releaseArgsForCall[i].arg1, fake.releaseArgsForCall[i].arg2
+}
+
+func (fake *FakeCache) Clear(arg1 resource.CacheIdentifier) int {
+	fake.clearMutex.Lock()
+	fake.clearArgsForCall = append(fake.clearArgsForCall, struct {
+		arg1 resource.CacheIdentifier
+	}{arg1})
+	fake.clearMutex.Unlock()
+	if fake.ClearStub != nil {
+		return fake.ClearStub(arg1)
+	} else {
+		return fake.clearReturns.result1
+	}
+}
+
+func (fake *FakeCache) ClearCallCount() int {
+	fake.clearMutex.RLock()
+	defer fake.clearMutex.RUnlock()
+	return len(fake.clearArgsForCall)
+}
+
+func (fake *FakeCache) ClearArgsForCall(i int) resource.CacheIdentifier {
+	fake.clearMutex.RLock()
+	defer fake.clearMutex.RUnlock()
+	return fake.clearArgsForCall[i].arg1
+}
+
+func (fake *FakeCache) ClearReturns(result1 int) {
+	fake.ClearStub = nil
+	fake.clearReturns = struct {
+		result1 int
+	}{result1}
+}
+
+var _ resource.Cache = new(FakeCache)
@@ -0,0 +1,102 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/resource"
+)
+
+type FakeImageFetcher struct {
+	ImageVersionStub        func(resource.ResourceType) (atc.Version, bool, error)
+	imageVersionMutex       sync.RWMutex
+	imageVersionArgsForCall []struct {
+		arg1 resource.ResourceType
+	}
+	imageVersionReturns struct {
+		result1 atc.Version
+		result2 bool
+		result3 error
+	}
+	FetchImageStub        func(resource.ResourceType, atc.Version) (resource.ContainerImage, error)
+	fetchImageMutex       sync.RWMutex
+	fetchImageArgsForCall []struct {
+		arg1 resource.ResourceType
+		arg2 atc.Version
+	}
+	fetchImageReturns struct {
+		result1 resource.ContainerImage
+		result2 error
+	}
+}
+
+func (fake *FakeImageFetcher) ImageVersion(arg1 resource.ResourceType) (atc.Version, bool, error) {
+	fake.imageVersionMutex.Lock()
+	fake.imageVersionArgsForCall = append(fake.imageVersionArgsForCall, struct {
+		arg1 resource.ResourceType
+	}{arg1})
+	fake.imageVersionMutex.Unlock()
+	if fake.ImageVersionStub != nil {
+		return fake.ImageVersionStub(arg1)
+	} else {
+		return fake.imageVersionReturns.result1, fake.imageVersionReturns.result2, fake.imageVersionReturns.result3
+	}
+}
+
+func (fake *FakeImageFetcher) ImageVersionCallCount() int {
+	fake.imageVersionMutex.RLock()
+	defer fake.imageVersionMutex.RUnlock()
+	return len(fake.imageVersionArgsForCall)
+}
+
+func (fake *FakeImageFetcher) ImageVersionArgsForCall(i int) resource.ResourceType {
+	fake.imageVersionMutex.RLock()
+	defer fake.imageVersionMutex.RUnlock()
+	return fake.imageVersionArgsForCall[i].arg1
+}
+
+func (fake *FakeImageFetcher) ImageVersionReturns(result1 atc.Version, result2 bool, result3 error) {
+	fake.ImageVersionStub = nil
+	fake.imageVersionReturns = struct {
+		result1 atc.Version
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeImageFetcher) FetchImage(arg1 resource.ResourceType, arg2 atc.Version) (resource.ContainerImage, error) {
+	fake.fetchImageMutex.Lock()
+	fake.fetchImageArgsForCall = append(fake.fetchImageArgsForCall, struct {
+		arg1 resource.ResourceType
+		arg2 atc.Version
+	}{arg1, arg2})
+	fake.fetchImageMutex.Unlock()
+	if fake.FetchImageStub != nil {
+		return fake.FetchImageStub(arg1, arg2)
+	} else {
+		return fake.fetchImageReturns.result1, fake.fetchImageReturns.result2
+	}
+}
+
+func (fake *FakeImageFetcher) FetchImageCallCount() int {
+	fake.fetchImageMutex.RLock()
+	defer fake.fetchImageMutex.RUnlock()
+	return len(fake.fetchImageArgsForCall)
+}
+
+func (fake *FakeImageFetcher) FetchImageArgsForCall(i int) (resource.ResourceType, atc.Version) {
+	fake.fetchImageMutex.RLock()
+	defer fake.fetchImageMutex.RUnlock()
+	return fake.fetchImageArgsForCall[i].arg1, fake.fetchImageArgsForCall[i].arg2
+}
+
+func (fake *FakeImageFetcher) FetchImageReturns(result1 resource.ContainerImage, result2 error) {
+	fake.FetchImageStub = nil
+	fake.fetchImageReturns = struct {
+		result1 resource.ContainerImage
+		result2 error
+	}{result1, result2}
+}
+
+var _ resource.ImageFetcher = new(FakeImageFetcher)
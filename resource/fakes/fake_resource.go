@@ -26,6 +26,15 @@ type FakeResource struct {
 	getReturns struct {
 		result1 resource.VersionedSource
 	}
+	CachedGetStub        func(atc.Version, []atc.MetadataField) resource.VersionedSource
+	cachedGetMutex       sync.RWMutex
+	cachedGetArgsForCall []struct {
+		arg1 atc.Version
+		arg2 []atc.MetadataField
+	}
+	cachedGetReturns struct {
+		result1 resource.VersionedSource
+	}
 	PutStub        func(resource.IOConfig, atc.Source, atc.Params, resource.ArtifactSource) resource.VersionedSource
 	putMutex       sync.RWMutex
 	putArgsForCall []struct {
@@ -37,11 +46,12 @@ type FakeResource struct {
 	putReturns struct {
 		result1 resource.VersionedSource
 	}
-	CheckStub        func(atc.Source, atc.Version) ([]atc.Version, error)
+	CheckStub        func(resource.IOConfig, atc.Source, atc.Version) ([]atc.Version, error)
 	checkMutex       sync.RWMutex
 	checkArgsForCall []struct {
-		arg1 atc.Source
-		arg2 atc.Version
+		arg1 resource.IOConfig
+		arg2 atc.Source
+		arg3 atc.Version
 	}
 	checkReturns struct {
 		result1 []atc.Version
@@ -117,6 +127,39 @@ func (fake *FakeResource) GetReturns(result1 resource.VersionedSource) {
 	}{result1}
 }
 
+func (fake *FakeResource) CachedGet(arg1 atc.Version, arg2 []atc.MetadataField) resource.VersionedSource {
+	fake.cachedGetMutex.Lock()
+	fake.cachedGetArgsForCall = append(fake.cachedGetArgsForCall, struct {
+		arg1 atc.Version
+		arg2 []atc.MetadataField
+	}{arg1, arg2})
+	fake.cachedGetMutex.Unlock()
+	if fake.CachedGetStub != nil {
+		return fake.CachedGetStub(arg1, arg2)
+	} else {
+		return fake.cachedGetReturns.result1
+	}
+}
+
+func (fake *FakeResource) CachedGetCallCount() int {
+	fake.cachedGetMutex.RLock()
+	defer fake.cachedGetMutex.RUnlock()
+	return len(fake.cachedGetArgsForCall)
+}
+
+func (fake *FakeResource) CachedGetArgsForCall(i int) (atc.Version, []atc.MetadataField) {
+	fake.cachedGetMutex.RLock()
+	defer fake.cachedGetMutex.RUnlock()
+	return fake.cachedGetArgsForCall[i].arg1, fake.cachedGetArgsForCall[i].arg2
+}
+
+func (fake *FakeResource) CachedGetReturns(result1 resource.VersionedSource) {
+	fake.CachedGetStub = nil
+	fake.cachedGetReturns = struct {
+		result1 resource.VersionedSource
+	}{result1}
+}
+
 func (fake *FakeResource) Put(arg1 resource.IOConfig, arg2 atc.Source, arg3 atc.Params, arg4 resource.ArtifactSource) resource.VersionedSource {
 	fake.putMutex.Lock()
 	fake.putArgsForCall = append(fake.putArgsForCall, struct {
@@ -152,15 +195,16 @@ func (fake *FakeResource) PutReturns(result1 resource.VersionedSource) {
 	}{result1}
 }
 
-func (fake *FakeResource) Check(arg1 atc.Source, arg2 atc.Version) ([]atc.Version, error) {
+func (fake *FakeResource) Check(arg1 resource.IOConfig, arg2 atc.Source, arg3 atc.Version) ([]atc.Version, error) {
 	fake.checkMutex.Lock()
 	fake.checkArgsForCall = append(fake.checkArgsForCall, struct {
-		arg1 atc.Source
-		arg2 atc.Version
-	}{arg1, arg2})
+		arg1 resource.IOConfig
+		arg2 atc.Source
+		arg3 atc.Version
+	}{arg1, arg2, arg3})
 	fake.checkMutex.Unlock()
 	if fake.CheckStub != nil {
-		return fake.CheckStub(arg1, arg2)
+		return fake.CheckStub(arg1, arg2, arg3)
 	} else {
 		return fake.checkReturns.result1, fake.checkReturns.result2
 	}
@@ -172,10 +216,10 @@ func (fake *FakeResource) CheckCallCount() int {
 	return len(fake.checkArgsForCall)
 }
 
-func (fake *FakeResource) CheckArgsForCall(i int) (atc.Source, atc.Version) {
+func (fake *FakeResource) CheckArgsForCall(i int) (resource.IOConfig, atc.Source, atc.Version) {
 	fake.checkMutex.RLock()
 	defer fake.checkMutex.RUnlock()
-	return fake.checkArgsForCall[i].arg1, fake.checkArgsForCall[i].arg2
+	return fake.checkArgsForCall[i].arg1, fake.checkArgsForCall[i].arg2, fake.checkArgsForCall[i].arg3
 }
 
 func (fake *FakeResource) CheckReturns(result1 []atc.Version, result2 error) {
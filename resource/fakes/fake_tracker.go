@@ -20,6 +20,16 @@ type FakeTracker struct {
 		result1 resource.Resource
 		result2 error
 	}
+	LookupStub        func(resource.Session, resource.ResourceType) (resource.Resource, error)
+	lookupMutex       sync.RWMutex
+	lookupArgsForCall []struct {
+		arg1 resource.Session
+		arg2 resource.ResourceType
+	}
+	lookupReturns struct {
+		result1 resource.Resource
+		result2 error
+	}
 }
 
 func (fake *FakeTracker) Init(arg1 resource.Session, arg2 resource.ResourceType, arg3 atc.Tags) (resource.Resource, error) {
@@ -57,4 +67,38 @@ func (fake *FakeTracker) InitReturns(result1 resource.Resource, result2 error) {
 	}{result1, result2}
 }
 
+func (fake *FakeTracker) Lookup(arg1 resource.Session, arg2 resource.ResourceType) (resource.Resource, error) {
+	fake.lookupMutex.Lock()
+	fake.lookupArgsForCall = append(fake.lookupArgsForCall, struct {
+		arg1 resource.Session
+		arg2 resource.ResourceType
+	}{arg1, arg2})
+	fake.lookupMutex.Unlock()
+	if fake.LookupStub != nil {
+		return fake.LookupStub(arg1, arg2)
+	} else {
+		return fake.lookupReturns.result1, fake.lookupReturns.result2
+	}
+}
+
+func (fake *FakeTracker) LookupCallCount() int {
+	fake.lookupMutex.RLock()
+	defer fake.lookupMutex.RUnlock()
+	return len(fake.lookupArgsForCall)
+}
+
+func (fake *FakeTracker) LookupArgsForCall(i int) (resource.Session, resource.ResourceType) {
+	fake.lookupMutex.RLock()
+	defer fake.lookupMutex.RUnlock()
+	return fake.lookupArgsForCall[i].arg1, fake.lookupArgsForCall[i].arg2
+}
+
+func (fake *FakeTracker) LookupReturns(result1 resource.Resource, result2 error) {
+	fake.LookupStub = nil
+	fake.lookupReturns = struct {
+		result1 resource.Resource
+		result2 error
+	}{result1, result2}
+}
+
 var _ resource.Tracker = new(FakeTracker)
@@ -0,0 +1,148 @@
+package resource
+
+import (
+	"archive/tar"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/worker"
+	"github.com/tedsuo/ifrit"
+)
+
+// the type of resource that fetches custom types' images; it must be
+// provided by the workers
+const imageResourceType ResourceType = "docker-image"
+
+// CustomResourceType is a resource type whose image is not provided by the
+// workers, but is a Docker image fetched by a 'docker-image' resource with
+// the given source.
+type CustomResourceType struct {
+	Type        string     `json:"type"`
+	ImageSource atc.Source `json:"image_source"`
+}
+
+// ErrNoImageVersion is returned when checking a custom type's image finds no
+// versions of it.
+type ErrNoImageVersion struct {
+	Type string
+}
+
+func (err ErrNoImageVersion) Error() string {
+	return fmt.Sprintf("no version of the image for resource type '%s' was found", err.Type)
+}
+
+type imageFetcher struct {
+	tracker Tracker
+	types   map[ResourceType]CustomResourceType
+}
+
+// NewImageFetcher returns an ImageFetcher for the given custom types. Each
+// type's image is checked for and fetched by a 'docker-image' resource from
+// the given tracker, and is used pinned to the digest it was fetched at.
+func NewImageFetcher(tracker Tracker, types []CustomResourceType) ImageFetcher {
+	byType := map[ResourceType]CustomResourceType{}
+	for _, t := range types {
+		byType[ResourceType(t.Type)] = t
+	}
+
+	return &imageFetcher{
+		tracker: tracker,
+		types:   byType,
+	}
+}
+
+func (fetcher *imageFetcher) ImageVersion(typ ResourceType) (atc.Version, bool, error) {
+	customType, found := fetcher.types[typ]
+	if !found {
+		return nil, false, nil
+	}
+
+	res, err := fetcher.tracker.Init(fetcher.session(customType, worker.ContainerTypeCheck), imageResourceType, atc.Tags{})
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer res.Release()
+
+	versions, err := res.Check(IOConfig{}, customType.ImageSource, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(versions) == 0 {
+		return nil, false, ErrNoImageVersion{Type: customType.Type}
+	}
+
+	return versions[len(versions)-1], true, nil
+}
+
+func (fetcher *imageFetcher) FetchImage(typ ResourceType, version atc.Version) (ContainerImage, error) {
+	customType, found := fetcher.types[typ]
+	if !found {
+		return "", ErrUnknownResourceType
+	}
+
+	res, err := fetcher.tracker.Init(fetcher.session(customType, worker.ContainerTypeGet), imageResourceType, atc.Tags{})
+	if err != nil {
+		return "", err
+	}
+
+	defer res.Release()
+
+	versionedSource := res.Get(IOConfig{}, customType.ImageSource, atc.Params{}, version)
+
+	err = <-ifrit.Invoke(versionedSource).Wait()
+	if err != nil {
+		return "", err
+	}
+
+	repository, err := readFetchedFile(versionedSource, "repository")
+	if err != nil {
+		return "", err
+	}
+
+	digest, err := readFetchedFile(versionedSource, "digest")
+	if err != nil {
+		return "", err
+	}
+
+	return ContainerImage(fmt.Sprintf("docker:///%s@%s", repository, digest)), nil
+}
+
+func (fetcher *imageFetcher) session(customType CustomResourceType, containerType worker.ContainerType) Session {
+	return Session{
+		ID: worker.Identifier{
+			Name: "image:" + customType.Type,
+			Type: containerType,
+
+			CheckType:   string(imageResourceType),
+			CheckSource: customType.ImageSource,
+		},
+		Ephemeral: true,
+	}
+}
+
+func readFetchedFile(source VersionedSource, path string) (string, error) {
+	out, err := source.StreamOut(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer out.Close()
+
+	tarReader := tar.NewReader(out)
+
+	_, err = tarReader.Next()
+	if err != nil {
+		return "", err
+	}
+
+	contents, err := ioutil.ReadAll(tarReader)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(contents)), nil
+}
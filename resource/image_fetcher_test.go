@@ -0,0 +1,203 @@
+package resource_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/resource/fakes"
+	"github.com/concourse/atc/worker"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/concourse/atc/resource"
+)
+
+var _ = Describe("ImageFetcher", func() {
+	var (
+		fakeTracker         *fakes.FakeTracker
+		fakeImageResource   *fakes.FakeResource
+		fakeVersionedSource *fakes.FakeVersionedSource
+
+		fetcher ImageFetcher
+	)
+
+	imageSource := atc.Source{"repository": "some/custom-resource"}
+
+	BeforeEach(func() {
+		fakeTracker = new(fakes.FakeTracker)
+		fakeImageResource = new(fakes.FakeResource)
+		fakeVersionedSource = new(fakes.FakeVersionedSource)
+
+		fakeTracker.InitReturns(fakeImageResource, nil)
+		fakeImageResource.GetReturns(fakeVersionedSource)
+
+		fetcher = NewImageFetcher(fakeTracker, []CustomResourceType{
+			{Type: "some-custom-type", ImageSource: imageSource},
+		})
+	})
+
+	Describe("ImageVersion", func() {
+		Context("when the type is not a custom type", func() {
+			It("returns false without checking anything", func() {
+				_, found, err := fetcher.ImageVersion("git")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(found).Should(BeFalse())
+
+				Ω(fakeTracker.InitCallCount()).Should(BeZero())
+			})
+		})
+
+		Context("when the type is a custom type", func() {
+			BeforeEach(func() {
+				fakeImageResource.CheckReturns([]atc.Version{
+					{"digest": "sha256:older"},
+					{"digest": "sha256:latest"},
+				}, nil)
+			})
+
+			It("checks for the image with a docker-image resource", func() {
+				_, _, err := fetcher.ImageVersion("some-custom-type")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				session, typ, _ := fakeTracker.InitArgsForCall(0)
+				Ω(typ).Should(Equal(ResourceType("docker-image")))
+				Ω(session.Ephemeral).Should(BeTrue())
+				Ω(session.ID.Type).Should(Equal(worker.ContainerTypeCheck))
+
+				_, source, fromVersion := fakeImageResource.CheckArgsForCall(0)
+				Ω(source).Should(Equal(imageSource))
+				Ω(fromVersion).Should(BeNil())
+			})
+
+			It("returns the latest version", func() {
+				version, found, err := fetcher.ImageVersion("some-custom-type")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(found).Should(BeTrue())
+				Ω(version).Should(Equal(atc.Version{"digest": "sha256:latest"}))
+			})
+
+			It("releases the resource", func() {
+				fetcher.ImageVersion("some-custom-type")
+				Ω(fakeImageResource.ReleaseCallCount()).Should(Equal(1))
+			})
+
+			Context("when no versions are found", func() {
+				BeforeEach(func() {
+					fakeImageResource.CheckReturns([]atc.Version{}, nil)
+				})
+
+				It("returns an error", func() {
+					_, _, err := fetcher.ImageVersion("some-custom-type")
+					Ω(err).Should(Equal(ErrNoImageVersion{Type: "some-custom-type"}))
+				})
+			})
+
+			Context("when checking fails", func() {
+				disaster := errors.New("oh no!")
+
+				BeforeEach(func() {
+					fakeImageResource.CheckReturns(nil, disaster)
+				})
+
+				It("returns the error", func() {
+					_, _, err := fetcher.ImageVersion("some-custom-type")
+					Ω(err).Should(Equal(disaster))
+				})
+			})
+		})
+	})
+
+	Describe("FetchImage", func() {
+		var files map[string]string
+
+		BeforeEach(func() {
+			files = map[string]string{
+				"repository": "some/custom-resource\n",
+				"digest":     "sha256:latest\n",
+			}
+
+			fakeVersionedSource.StreamOutStub = func(path string) (io.ReadCloser, error) {
+				content, found := files[path]
+				if !found {
+					return nil, errors.New("no such file")
+				}
+
+				buf := new(bytes.Buffer)
+
+				tarWriter := tar.NewWriter(buf)
+
+				err := tarWriter.WriteHeader(&tar.Header{
+					Name: path,
+					Mode: 0644,
+					Size: int64(len(content)),
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				_, err = tarWriter.Write([]byte(content))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(tarWriter.Close()).Should(Succeed())
+
+				return ioutil.NopCloser(buf), nil
+			}
+		})
+
+		It("fetches the image's version with a docker-image resource", func() {
+			_, err := fetcher.FetchImage("some-custom-type", atc.Version{"digest": "sha256:latest"})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			session, typ, _ := fakeTracker.InitArgsForCall(0)
+			Ω(typ).Should(Equal(ResourceType("docker-image")))
+			Ω(session.ID.Type).Should(Equal(worker.ContainerTypeGet))
+
+			_, source, params, version := fakeImageResource.GetArgsForCall(0)
+			Ω(source).Should(Equal(imageSource))
+			Ω(params).Should(Equal(atc.Params{}))
+			Ω(version).Should(Equal(atc.Version{"digest": "sha256:latest"}))
+
+			Ω(fakeVersionedSource.RunCallCount()).Should(Equal(1))
+			Ω(fakeImageResource.ReleaseCallCount()).Should(Equal(1))
+		})
+
+		It("returns the fetched image, pinned by its digest", func() {
+			image, err := fetcher.FetchImage("some-custom-type", atc.Version{"digest": "sha256:latest"})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(image).Should(Equal(ContainerImage("docker:///some/custom-resource@sha256:latest")))
+		})
+
+		Context("when the get fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				fakeVersionedSource.RunReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				_, err := fetcher.FetchImage("some-custom-type", atc.Version{"digest": "sha256:latest"})
+				Ω(err).Should(Equal(disaster))
+			})
+		})
+
+		Context("when the fetched image has no digest", func() {
+			BeforeEach(func() {
+				delete(files, "digest")
+			})
+
+			It("returns an error", func() {
+				_, err := fetcher.FetchImage("some-custom-type", atc.Version{"digest": "sha256:latest"})
+				Ω(err).Should(HaveOccurred())
+			})
+		})
+
+		Context("when the type is not a custom type", func() {
+			It("returns ErrUnknownResourceType", func() {
+				_, err := fetcher.FetchImage("git", atc.Version{"digest": "sha256:latest"})
+				Ω(err).Should(Equal(ErrUnknownResourceType))
+			})
+		})
+	})
+})
@@ -0,0 +1,55 @@
+package resource
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/concourse/atc"
+)
+
+// ResourceMapping maps each resource type to the image that provides it.
+type ResourceMapping map[ResourceType]ContainerImage
+
+func NewResourceMapping(types []atc.WorkerResourceType) ResourceMapping {
+	mapping := ResourceMapping{}
+	for _, t := range types {
+		mapping[ResourceType(t.Type)] = ContainerImage(t.Image)
+	}
+
+	return mapping
+}
+
+// UnknownResourceTypesError lists the resource types used by a config that
+// have no image in the mapping.
+type UnknownResourceTypesError struct {
+	Types []string
+}
+
+func (err UnknownResourceTypesError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrUnknownResourceType, strings.Join(err.Types, ", "))
+}
+
+// Validate checks that every resource in the config has a type that resolves
+// to an image, returning an UnknownResourceTypesError naming any that do not.
+func (mapping ResourceMapping) Validate(config atc.Config) error {
+	unknown := map[string]bool{}
+	for _, resource := range config.Resources {
+		if _, found := mapping[ResourceType(resource.Type)]; !found {
+			unknown[resource.Type] = true
+		}
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	types := make([]string, 0, len(unknown))
+	for t := range unknown {
+		types = append(types, t)
+	}
+
+	sort.Strings(types)
+
+	return UnknownResourceTypesError{Types: types}
+}
@@ -0,0 +1,54 @@
+package resource_test
+
+import (
+	"github.com/concourse/atc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/concourse/atc/resource"
+)
+
+var _ = Describe("ResourceMapping", func() {
+	var mapping ResourceMapping
+
+	BeforeEach(func() {
+		mapping = NewResourceMapping([]atc.WorkerResourceType{
+			{Type: "git", Image: "docker:///concourse/git-resource"},
+			{Type: "s3", Image: "docker:///concourse/s3-resource"},
+		})
+	})
+
+	It("maps each type to its image", func() {
+		Ω(mapping).Should(Equal(ResourceMapping{
+			"git": "docker:///concourse/git-resource",
+			"s3":  "docker:///concourse/s3-resource",
+		}))
+	})
+
+	Describe("Validate", func() {
+		It("accepts a config whose resource types are all known", func() {
+			Ω(mapping.Validate(atc.Config{
+				Resources: atc.ResourceConfigs{
+					{Name: "some-resource", Type: "git"},
+					{Name: "some-other-resource", Type: "s3"},
+				},
+			})).Should(Succeed())
+		})
+
+		It("rejects a config referencing unknown types, listing each once", func() {
+			err := mapping.Validate(atc.Config{
+				Resources: atc.ResourceConfigs{
+					{Name: "some-resource", Type: "gti"},
+					{Name: "some-other-resource", Type: "s3"},
+					{Name: "another-resource", Type: "gti"},
+					{Name: "yet-another-resource", Type: "bogus"},
+				},
+			})
+			Ω(err).Should(Equal(UnknownResourceTypesError{
+				Types: []string{"bogus", "gti"},
+			}))
+
+			Ω(err.Error()).Should(Equal("unknown resource type: bogus, gti"))
+		})
+	})
+})
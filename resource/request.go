@@ -0,0 +1,31 @@
+package resource
+
+import "github.com/concourse/atc"
+
+// InRequest is sent on stdin to /opt/resource/in.
+type InRequest struct {
+	Source  atc.Source  `json:"source"`
+	Params  atc.Params  `json:"params,omitempty"`
+	Version atc.Version `json:"version,omitempty"`
+}
+
+// OutRequest is sent on stdin to /opt/resource/out.
+type OutRequest struct {
+	Source atc.Source `json:"source"`
+	Params atc.Params `json:"params,omitempty"`
+}
+
+// CheckRequest is sent on stdin to /opt/resource/check, which responds with a
+// list of versions.
+type CheckRequest struct {
+	Source  atc.Source  `json:"source"`
+	Version atc.Version `json:"version"`
+}
+
+// VersionResult is the response printed by both /opt/resource/in and
+// /opt/resource/out.
+type VersionResult struct {
+	Version atc.Version `json:"version"`
+
+	Metadata []atc.MetadataField `json:"metadata,omitempty"`
+}
@@ -0,0 +1,62 @@
+package resource_test
+
+import (
+	"encoding/json"
+
+	"github.com/concourse/atc"
+	. "github.com/concourse/atc/resource"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Requests", func() {
+	Describe("InRequest", func() {
+		It("omits params and version when empty", func() {
+			payload, err := json.Marshal(InRequest{
+				Source: atc.Source{"some": "source"},
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(payload).Should(MatchJSON(`{"source":{"some":"source"}}`))
+		})
+	})
+
+	Describe("OutRequest", func() {
+		It("omits params when empty", func() {
+			payload, err := json.Marshal(OutRequest{
+				Source: atc.Source{"some": "source"},
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(payload).Should(MatchJSON(`{"source":{"some":"source"}}`))
+		})
+	})
+
+	Describe("CheckRequest", func() {
+		It("always includes the version, even when there is none yet", func() {
+			payload, err := json.Marshal(CheckRequest{
+				Source: atc.Source{"some": "source"},
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(payload).Should(MatchJSON(`{"source":{"some":"source"},"version":null}`))
+		})
+	})
+
+	Describe("VersionResult", func() {
+		It("decodes the version and metadata printed by a script", func() {
+			var result VersionResult
+			err := json.Unmarshal([]byte(`{
+				"version": {"ref": "abc"},
+				"metadata": [{"name": "author", "value": "someone"}]
+			}`), &result)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(result).Should(Equal(VersionResult{
+				Version:  atc.Version{"ref": "abc"},
+				Metadata: []atc.MetadataField{{Name: "author", Value: "someone"}},
+			}))
+		})
+	})
+})
@@ -16,9 +16,10 @@ type Resource interface {
 	Type() ResourceType
 
 	Get(IOConfig, atc.Source, atc.Params, atc.Version) VersionedSource
+	CachedGet(atc.Version, []atc.MetadataField) VersionedSource
 	Put(IOConfig, atc.Source, atc.Params, ArtifactSource) VersionedSource
 
-	Check(atc.Source, atc.Version) ([]atc.Version, error)
+	Check(IOConfig, atc.Source, atc.Version) ([]atc.Version, error)
 
 	Release()
 	Destroy() error
@@ -61,6 +62,12 @@ type resource struct {
 	container worker.Container
 	typ       ResourceType
 
+	// the most a script may print to stdout; zero for no limit
+	maxOutputSize int64
+
+	// the proxy the scripts reach external services through
+	proxy atc.ProxyConfig
+
 	releaseOnce sync.Once
 
 	ScriptFailure bool
@@ -69,10 +76,14 @@ type resource struct {
 func NewResource(
 	container worker.Container,
 	typ ResourceType,
+	maxOutputSize int64,
+	proxy atc.ProxyConfig,
 ) Resource {
 	return &resource{
-		container: container,
-		typ:       typ,
+		container:     container,
+		typ:           typ,
+		maxOutputSize: maxOutputSize,
+		proxy:         proxy,
 	}
 }
 
@@ -5,20 +5,16 @@ import (
 	"github.com/tedsuo/ifrit"
 )
 
-type checkRequest struct {
-	Source  atc.Source  `json:"source"`
-	Version atc.Version `json:"version"`
-}
-
-func (resource *resource) Check(source atc.Source, fromVersion atc.Version) ([]atc.Version, error) {
+func (resource *resource) Check(ioConfig IOConfig, source atc.Source, fromVersion atc.Version) ([]atc.Version, error) {
 	var versions []atc.Version
 
 	checking := ifrit.Invoke(resource.runScript(
 		"/opt/resource/check",
 		nil,
-		checkRequest{source, fromVersion},
+		CheckRequest{source, fromVersion},
 		&versions,
-		nil,
+		ioConfig.Stdout,
+		ioConfig.Stderr,
 		nil,
 		nil,
 		false,
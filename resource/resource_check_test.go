@@ -1,12 +1,15 @@
 package resource_test
 
 import (
+	"bytes"
 	"errors"
 	"io/ioutil"
+	"strings"
 
 	"github.com/cloudfoundry-incubator/garden"
 	gfakes "github.com/cloudfoundry-incubator/garden/fakes"
 	"github.com/concourse/atc"
+	. "github.com/concourse/atc/resource"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -24,6 +27,8 @@ var _ = Describe("Resource Check", func() {
 
 		checkScriptProcess *gfakes.FakeProcess
 
+		ioConfig IOConfig
+
 		checkResult []atc.Version
 		checkErr    error
 	)
@@ -42,6 +47,8 @@ var _ = Describe("Resource Check", func() {
 			return checkScriptExitStatus, nil
 		}
 
+		ioConfig = IOConfig{}
+
 		checkResult = nil
 		checkErr = nil
 	})
@@ -61,7 +68,7 @@ var _ = Describe("Resource Check", func() {
 			return checkScriptProcess, nil
 		}
 
-		checkResult, checkErr = resource.Check(source, version)
+		checkResult, checkErr = resource.Check(ioConfig, source, version)
 	})
 
 	It("runs /opt/resource/check the request on stdin", func() {
@@ -78,6 +85,33 @@ var _ = Describe("Resource Check", func() {
 		Ω(string(request)).Should(Equal(`{"source":{"some":"source"},"version":{"some":"version"}}`))
 	})
 
+	It("runs it without a proxy", func() {
+		spec, _ := fakeContainer.RunArgsForCall(0)
+		Ω(spec.Env).Should(BeEmpty())
+	})
+
+	Context("when a proxy is configured", func() {
+		BeforeEach(func() {
+			resource = NewResource(fakeContainer, "some-type", maxOutputSize, atc.ProxyConfig{
+				HTTP:    "http://proxy:3128",
+				HTTPS:   "https://proxy:3129",
+				NoProxy: "localhost",
+			})
+		})
+
+		It("runs it with the proxy in its environment", func() {
+			spec, _ := fakeContainer.RunArgsForCall(0)
+			Ω(spec.Env).Should(Equal([]string{
+				"http_proxy=http://proxy:3128",
+				"HTTP_PROXY=http://proxy:3128",
+				"https_proxy=https://proxy:3129",
+				"HTTPS_PROXY=https://proxy:3129",
+				"no_proxy=localhost",
+				"NO_PROXY=localhost",
+			}))
+		})
+	})
+
 	Context("when /check outputs versions", func() {
 		BeforeEach(func() {
 			checkScriptStdout = `[{"ver":"abc"}, {"ver":"def"}, {"ver":"ghi"}]`
@@ -120,6 +154,39 @@ var _ = Describe("Resource Check", func() {
 		})
 	})
 
+	Context("when a stderr writer is given", func() {
+		var stderrBuf *bytes.Buffer
+
+		BeforeEach(func() {
+			stderrBuf = new(bytes.Buffer)
+			ioConfig = IOConfig{Stderr: stderrBuf}
+
+			checkScriptStderr = "some-stderr"
+		})
+
+		It("streams the process's stderr to it", func() {
+			Ω(checkErr).ShouldNot(HaveOccurred())
+			Ω(stderrBuf.String()).Should(Equal("some-stderr"))
+		})
+	})
+
+	Context("when a stdout writer is given", func() {
+		var stdoutBuf *bytes.Buffer
+
+		BeforeEach(func() {
+			stdoutBuf = new(bytes.Buffer)
+			ioConfig = IOConfig{Stdout: stdoutBuf}
+
+			checkScriptStdout = `[{"ver":"abc"}]`
+		})
+
+		It("streams the process's stdout to it, still parsing the versions", func() {
+			Ω(checkErr).ShouldNot(HaveOccurred())
+			Ω(stdoutBuf.String()).Should(Equal(`[{"ver":"abc"}]`))
+			Ω(checkResult).Should(Equal([]atc.Version{{"ver": "abc"}}))
+		})
+	})
+
 	Context("when the output of /opt/resource/check is malformed", func() {
 		BeforeEach(func() {
 			checkScriptStdout = "ß"
@@ -127,6 +194,82 @@ var _ = Describe("Resource Check", func() {
 
 		It("returns an error", func() {
 			Ω(checkErr).Should(HaveOccurred())
+			Ω(checkErr).Should(BeAssignableToTypeOf(ErrResourceOutputMalformed{}))
+		})
+	})
+
+	Context("when the output of /opt/resource/check is cut off", func() {
+		BeforeEach(func() {
+			checkScriptStdout = `[{"ver":"abc"}, {"ver":"d`
+		})
+
+		It("returns an error saying the output was truncated, rather than malformed", func() {
+			Ω(checkResult).Should(BeNil())
+
+			Ω(checkErr).Should(Equal(ErrResourceOutputTruncated{
+				Path:   "/opt/resource/check",
+				Stdout: `[{"ver":"abc"}, {"ver":"d`,
+			}))
+		})
+	})
+
+	Context("when /opt/resource/check prints trailing data after the versions", func() {
+		BeforeEach(func() {
+			checkScriptStdout = `[{"ver":"abc"}] garbage`
+		})
+
+		It("returns an error mentioning the trailing data", func() {
+			Ω(checkResult).Should(BeNil())
+
+			Ω(checkErr).Should(Equal(ErrResourceOutputMalformed{
+				Path:   "/opt/resource/check",
+				Reason: "unexpected trailing data after JSON value",
+				Stdout: `[{"ver":"abc"}] garbage`,
+			}))
+
+			Ω(checkErr.Error()).Should(ContainSubstring("trailing data"))
+		})
+	})
+
+	Context("when /opt/resource/check prints more than the maximum output size", func() {
+		BeforeEach(func() {
+			checkScriptStdout = `[{"ver":"` + strings.Repeat("x", maxOutputSize) + `"}]`
+		})
+
+		It("returns an error saying the output was too large", func() {
+			Ω(checkErr).Should(Equal(ErrResourceOutputTooLarge{
+				Path:  "/opt/resource/check",
+				Limit: maxOutputSize,
+			}))
+		})
+
+		Context("while the script is still running", func() {
+			BeforeEach(func() {
+				waiting := make(chan struct{})
+
+				checkScriptProcess.WaitStub = func() (int, error) {
+					<-waiting
+					return 0, nil
+				}
+			})
+
+			It("stops the container without waiting for the script", func() {
+				Ω(checkErr).Should(BeAssignableToTypeOf(ErrResourceOutputTooLarge{}))
+
+				Ω(fakeContainer.StopCallCount()).Should(Equal(1))
+				Ω(fakeContainer.StopArgsForCall(0)).Should(BeFalse())
+			})
+		})
+	})
+
+	Context("when /opt/resource/check prints trailing whitespace", func() {
+		BeforeEach(func() {
+			checkScriptStdout = "[{\"ver\":\"abc\"}]\n\n"
+		})
+
+		It("returns the versions", func() {
+			Ω(checkErr).ShouldNot(HaveOccurred())
+			Ω(checkResult).Should(Equal([]atc.Version{{"ver": "abc"}}))
 		})
 	})
 })
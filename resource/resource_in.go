@@ -1,12 +1,11 @@
 package resource
 
-import "github.com/concourse/atc"
+import (
+	"os"
 
-type inRequest struct {
-	Source  atc.Source  `json:"source"`
-	Params  atc.Params  `json:"params,omitempty"`
-	Version atc.Version `json:"version,omitempty"`
-}
+	"github.com/concourse/atc"
+	"github.com/tedsuo/ifrit"
+)
 
 func (resource *resource) Get(ioConfig IOConfig, source atc.Source, params atc.Params, version atc.Version) VersionedSource {
 	resourceDir := ResourcesDir("get")
@@ -16,16 +15,45 @@ func (resource *resource) Get(ioConfig IOConfig, source atc.Source, params atc.P
 		resourceDir: resourceDir,
 	}
 
-	vs.Runner = resource.runScript(
-		"/opt/resource/in",
-		[]string{resourceDir},
-		inRequest{source, params, version},
-		&vs.versionResult,
-		ioConfig.Stderr,
-		nil,
-		nil,
-		true,
-	)
+	vs.Runner = ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		var result VersionResult
+
+		err := resource.runScript(
+			"/opt/resource/in",
+			[]string{resourceDir},
+			InRequest{source, params, version},
+			&result,
+			nil,
+			ioConfig.Stderr,
+			nil,
+			nil,
+			true,
+		).Run(signals, ready)
+		if err != nil {
+			return err
+		}
+
+		vs.setVersionResult(result)
+
+		return nil
+	})
 
 	return vs
 }
+
+func (resource *resource) CachedGet(version atc.Version, metadata []atc.MetadataField) VersionedSource {
+	return &versionedSource{
+		Runner: ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			close(ready)
+			return nil
+		}),
+
+		versionResult: VersionResult{
+			Version:  version,
+			Metadata: metadata,
+		},
+
+		container:   resource.container,
+		resourceDir: ResourcesDir("get"),
+	}
+}
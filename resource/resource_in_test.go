@@ -414,6 +414,48 @@ var _ = Describe("Resource In", func() {
 				Ω(name).Should(Equal("concourse:resource-result"))
 				Ω(value).Should(Equal(inScriptStdout))
 			})
+
+			Context("while /opt/resource/in is still running", func() {
+				var exit chan struct{}
+
+				BeforeEach(func() {
+					exit = make(chan struct{})
+
+					inScriptProcess.WaitStub = func() (int, error) {
+						<-exit
+						return 0, nil
+					}
+				})
+
+				It("can be accessed concurrently, only returning the response once it exits", func() {
+					reading := make(chan struct{})
+					readingDone := make(chan struct{})
+
+					go func() {
+						defer close(readingDone)
+
+						for {
+							select {
+							case <-reading:
+								return
+							default:
+								versionedSource.Version()
+								versionedSource.Metadata()
+							}
+						}
+					}()
+
+					Consistently(versionedSource.Version).Should(BeNil())
+
+					close(exit)
+					Eventually(inProcess.Wait()).Should(Receive(BeNil()))
+
+					close(reading)
+					Eventually(readingDone).Should(BeClosed())
+
+					Ω(versionedSource.Version()).Should(Equal(atc.Version{"some": "new-version"}))
+				})
+			})
 		})
 
 		Context("when /in outputs to stderr", func() {
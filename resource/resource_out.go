@@ -7,11 +7,6 @@ import (
 	"github.com/tedsuo/ifrit"
 )
 
-type outRequest struct {
-	Source atc.Source `json:"source"`
-	Params atc.Params `json:"params,omitempty"`
-}
-
 func (resource *resource) Put(ioConfig IOConfig, source atc.Source, params atc.Params, artifactSource ArtifactSource) VersionedSource {
 	resourceDir := ResourcesDir("put")
 
@@ -21,19 +16,29 @@ func (resource *resource) Put(ioConfig IOConfig, source atc.Source, params atc.P
 	}
 
 	vs.Runner = ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
-		return resource.runScript(
+		var result VersionResult
+
+		err := resource.runScript(
 			"/opt/resource/out",
 			[]string{resourceDir},
-			outRequest{
+			OutRequest{
 				Params: params,
 				Source: source,
 			},
-			&vs.versionResult,
+			&result,
+			nil,
 			ioConfig.Stderr,
 			artifactSource,
 			vs,
 			true,
 		).Run(signals, ready)
+		if err != nil {
+			return err
+		}
+
+		vs.setVersionResult(result)
+
+		return nil
 	})
 
 	return vs
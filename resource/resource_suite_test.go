@@ -3,6 +3,7 @@ package resource_test
 import (
 	"testing"
 
+	"github.com/concourse/atc"
 	wfakes "github.com/concourse/atc/worker/fakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -10,6 +11,8 @@ import (
 	. "github.com/concourse/atc/resource"
 )
 
+const maxOutputSize = 1024
+
 var (
 	workerClient  *wfakes.FakeClient
 	fakeContainer *wfakes.FakeContainer
@@ -22,7 +25,7 @@ var _ = BeforeEach(func() {
 
 	fakeContainer = new(wfakes.FakeContainer)
 
-	resource = NewResource(fakeContainer, "some-type")
+	resource = NewResource(fakeContainer, "some-type", maxOutputSize, atc.ProxyConfig{})
 })
 
 func TestResource(t *testing.T) {
@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"sync"
 
 	"github.com/cloudfoundry-incubator/garden"
 	"github.com/tedsuo/ifrit"
@@ -40,11 +42,150 @@ func (err ErrResourceScriptFailed) Error() string {
 	return msg
 }
 
+type ErrResourceOutputMalformed struct {
+	Path   string
+	Reason string
+
+	Stdout string
+}
+
+func (err ErrResourceOutputMalformed) Error() string {
+	return fmt.Sprintf(
+		"resource script '%s' printed malformed output: %s\n\nstdout:\n%s",
+		err.Path,
+		err.Reason,
+		err.Stdout,
+	)
+}
+
+// ErrResourceOutputTruncated is returned when a script's output ends partway
+// through its JSON value, e.g. because the stream from the container was cut
+// off. Unlike malformed output, it is not the script's fault, and checking or
+// fetching again may succeed.
+type ErrResourceOutputTruncated struct {
+	Path string
+
+	Stdout string
+}
+
+func (err ErrResourceOutputTruncated) Error() string {
+	return fmt.Sprintf(
+		"resource script '%s' output was cut off before it was complete\n\nstdout:\n%s",
+		err.Path,
+		err.Stdout,
+	)
+}
+
+// ErrResourceOutputTooLarge is returned when a script prints more to stdout
+// than the resource's maximum output size. The script is stopped as soon as
+// it goes over, rather than its output being held in memory.
+type ErrResourceOutputTooLarge struct {
+	Path  string
+	Limit int64
+}
+
+func (err ErrResourceOutputTooLarge) Error() string {
+	return fmt.Sprintf(
+		"resource script '%s' printed more than the maximum of %d bytes of output",
+		err.Path,
+		err.Limit,
+	)
+}
+
+// limitedBuffer collects a script's stdout up to a limit, discarding anything
+// past it and closing exceeded. A limit of zero means no limit.
+type limitedBuffer struct {
+	limit int64
+
+	buf  bytes.Buffer
+	bufL sync.Mutex
+
+	exceeded     chan struct{}
+	exceededOnce sync.Once
+}
+
+func newLimitedBuffer(limit int64) *limitedBuffer {
+	return &limitedBuffer{
+		limit:    limit,
+		exceeded: make(chan struct{}),
+	}
+}
+
+func (buffer *limitedBuffer) Write(p []byte) (int, error) {
+	buffer.bufL.Lock()
+	defer buffer.bufL.Unlock()
+
+	if buffer.limit > 0 && int64(buffer.buf.Len()+len(p)) > buffer.limit {
+		buffer.exceededOnce.Do(func() {
+			close(buffer.exceeded)
+		})
+
+		return len(p), nil
+	}
+
+	return buffer.buf.Write(p)
+}
+
+func (buffer *limitedBuffer) Exceeded() bool {
+	select {
+	case <-buffer.exceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+func (buffer *limitedBuffer) Bytes() []byte {
+	buffer.bufL.Lock()
+	defer buffer.bufL.Unlock()
+
+	return buffer.buf.Bytes()
+}
+
+// decodeOutput decodes a script's output as a single JSON value, rejecting
+// anything other than whitespace after it.
+func decodeOutput(path string, stdout []byte, output interface{}) error {
+	reader := bytes.NewReader(stdout)
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(output)
+	if err == io.ErrUnexpectedEOF {
+		return ErrResourceOutputTruncated{
+			Path:   path,
+			Stdout: string(stdout),
+		}
+	}
+
+	if err != nil {
+		return ErrResourceOutputMalformed{
+			Path:   path,
+			Reason: err.Error(),
+			Stdout: string(stdout),
+		}
+	}
+
+	trailing, err := ioutil.ReadAll(io.MultiReader(decoder.Buffered(), reader))
+	if err != nil {
+		return err
+	}
+
+	if len(bytes.TrimSpace(trailing)) > 0 {
+		return ErrResourceOutputMalformed{
+			Path:   path,
+			Reason: "unexpected trailing data after JSON value",
+			Stdout: string(stdout),
+		}
+	}
+
+	return nil
+}
+
 func (resource *resource) runScript(
 	path string,
 	args []string,
 	input interface{},
 	output interface{},
+	outputDest io.Writer,
 	logDest io.Writer,
 	inputSource ArtifactSource,
 	inputDestination ArtifactDestination,
@@ -59,11 +200,11 @@ func (resource *resource) runScript(
 		if recoverable {
 			result, err := resource.container.Property(resourceResultPropertyName)
 			if err == nil {
-				return json.Unmarshal([]byte(result), &output)
+				return decodeOutput(path, []byte(result), output)
 			}
 		}
 
-		stdout := new(bytes.Buffer)
+		stdout := newLimitedBuffer(resource.maxOutputSize)
 		stderr := new(bytes.Buffer)
 
 		processIO := garden.ProcessIO{
@@ -71,6 +212,10 @@ func (resource *resource) runScript(
 			Stdout: stdout,
 		}
 
+		if outputDest != nil {
+			processIO.Stdout = io.MultiWriter(stdout, outputDest)
+		}
+
 		if logDest != nil {
 			processIO.Stderr = logDest
 		} else {
@@ -109,6 +254,7 @@ func (resource *resource) runScript(
 			process, err = resource.container.Run(garden.ProcessSpec{
 				Path: path,
 				Args: args,
+				Env:  resource.proxy.Env(),
 				User: "root",
 			}, processIO)
 			if err != nil {
@@ -139,8 +285,17 @@ func (resource *resource) runScript(
 			}
 		}()
 
+		tooLarge := ErrResourceOutputTooLarge{
+			Path:  path,
+			Limit: resource.maxOutputSize,
+		}
+
 		select {
 		case status := <-statusCh:
+			if stdout.Exceeded() {
+				return tooLarge
+			}
+
 			if status != 0 {
 				return ErrResourceScriptFailed{
 					Path:       path,
@@ -151,18 +306,27 @@ func (resource *resource) runScript(
 				}
 			}
 
+			err := decodeOutput(path, stdout.Bytes(), output)
+			if err != nil {
+				return err
+			}
+
 			if recoverable {
-				err := resource.container.SetProperty(resourceResultPropertyName, stdout.String())
+				err := resource.container.SetProperty(resourceResultPropertyName, string(stdout.Bytes()))
 				if err != nil {
 					return err
 				}
 			}
 
-			return json.Unmarshal(stdout.Bytes(), output)
+			return nil
 
 		case err := <-errCh:
 			return err
 
+		case <-stdout.exceeded:
+			resource.container.Stop(false)
+			return tooLarge
+
 		case <-signals:
 			resource.container.Stop(false)
 			return ErrAborted
@@ -1,7 +1,10 @@
 package resource
 
 import (
+	"encoding/json"
 	"errors"
+	"sync"
+	"time"
 
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/worker"
@@ -13,23 +16,85 @@ type ContainerImage string
 type Session struct {
 	ID        worker.Identifier
 	Ephemeral bool
+
+	// overrides the tracker's proxy for the resource's scripts, if set
+	Proxy *atc.ProxyConfig
 }
 
 //go:generate counterfeiter . Tracker
 
 type Tracker interface {
 	Init(Session, ResourceType, atc.Tags) (Resource, error)
+	Lookup(Session, ResourceType) (Resource, error)
+}
+
+//go:generate counterfeiter . ImageFetcher
+
+// ImageFetcher provides the images of custom resource types, i.e. types whose
+// image is itself fetched by a resource rather than provided by the workers.
+type ImageFetcher interface {
+	// ImageVersion returns the version of the type's image that should be
+	// used, or false if the type is not a custom type.
+	ImageVersion(ResourceType) (atc.Version, bool, error)
+
+	// FetchImage fetches the type's image at the given version, returning
+	// the rootfs to create its containers with.
+	FetchImage(ResourceType, atc.Version) (ContainerImage, error)
 }
 
 type tracker struct {
 	workerClient worker.Client
+	imageFetcher ImageFetcher
+
+	checkGraceTime time.Duration
+	buildGraceTime time.Duration
+
+	maxOutputSize int64
+
+	proxy atc.ProxyConfig
+
+	// the latest fetch of each custom type's image; a type's older versions
+	// are forgotten once a newer one is fetched
+	images  map[ResourceType]*imageFetch
+	imagesL sync.Mutex
+}
+
+// imageFetch is the result of fetching an image at a version, available once
+// done is closed.
+type imageFetch struct {
+	version string
+	done    chan struct{}
+
+	image ContainerImage
+	err   error
 }
 
 var ErrUnknownResourceType = errors.New("unknown resource type")
 
-func NewTracker(workerClient worker.Client) Tracker {
+// NewTracker returns a Tracker that creates containers for ephemeral
+// sessions, i.e. checks, with the check grace time, and containers for
+// builds' gets and puts with the build grace time.
+//
+// The resources' scripts may print at most maxOutputSize bytes to stdout,
+// and reach external services through the given proxy unless the session
+// configures its own.
+//
+// Containers for custom resource types use images from the image fetcher,
+// each fetched once per version; if it is nil, only the types provided by the
+// workers can be used.
+func NewTracker(workerClient worker.Client, imageFetcher ImageFetcher, checkGraceTime time.Duration, buildGraceTime time.Duration, maxOutputSize int64, proxy atc.ProxyConfig) Tracker {
 	return &tracker{
 		workerClient: workerClient,
+		imageFetcher: imageFetcher,
+
+		checkGraceTime: checkGraceTime,
+		buildGraceTime: buildGraceTime,
+
+		maxOutputSize: maxOutputSize,
+
+		proxy: proxy,
+
+		images: map[ResourceType]*imageFetch{},
 	}
 }
 
@@ -39,10 +104,23 @@ func (tracker *tracker) Init(session Session, typ ResourceType, tags atc.Tags) (
 	switch err {
 	case nil:
 	case worker.ErrContainerNotFound:
+		graceTime := tracker.buildGraceTime
+		if session.Ephemeral {
+			graceTime = tracker.checkGraceTime
+		}
+
+		var image ContainerImage
+		image, err = tracker.customImage(typ)
+		if err != nil {
+			return nil, err
+		}
+
 		container, err = tracker.workerClient.CreateContainer(session.ID, worker.ResourceTypeContainerSpec{
 			Type:      string(typ),
 			Ephemeral: session.Ephemeral,
 			Tags:      tags,
+			Image:     string(image),
+			GraceTime: graceTime,
 		})
 	}
 
@@ -50,5 +128,83 @@ func (tracker *tracker) Init(session Session, typ ResourceType, tags atc.Tags) (
 		return nil, err
 	}
 
-	return NewResource(container, typ), nil
+	return NewResource(container, typ, tracker.maxOutputSize, tracker.proxyFor(session)), nil
+}
+
+// customImage returns the image to use for a custom resource type, fetching
+// it if the version to use has not been fetched before. It returns an empty
+// image for types provided by the workers.
+//
+// Concurrent inits of the same type and version wait for a single fetch,
+// without holding up inits of any other. Only the latest version's image is
+// kept for each type, and failed fetches are not kept, so the next init
+// tries again.
+func (tracker *tracker) customImage(typ ResourceType) (ContainerImage, error) {
+	if tracker.imageFetcher == nil {
+		return "", nil
+	}
+
+	version, found, err := tracker.imageFetcher.ImageVersion(typ)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", nil
+	}
+
+	versionJSON, err := json.Marshal(version)
+	if err != nil {
+		return "", err
+	}
+
+	tracker.imagesL.Lock()
+
+	fetch, found := tracker.images[typ]
+	if found && fetch.version == string(versionJSON) {
+		tracker.imagesL.Unlock()
+
+		<-fetch.done
+
+		return fetch.image, fetch.err
+	}
+
+	fetch = &imageFetch{
+		version: string(versionJSON),
+		done:    make(chan struct{}),
+	}
+
+	tracker.images[typ] = fetch
+
+	tracker.imagesL.Unlock()
+
+	fetch.image, fetch.err = tracker.imageFetcher.FetchImage(typ, version)
+	if fetch.err != nil {
+		tracker.imagesL.Lock()
+		if tracker.images[typ] == fetch {
+			delete(tracker.images, typ)
+		}
+		tracker.imagesL.Unlock()
+	}
+
+	close(fetch.done)
+
+	return fetch.image, fetch.err
+}
+
+func (tracker *tracker) proxyFor(session Session) atc.ProxyConfig {
+	if session.Proxy != nil {
+		return *session.Proxy
+	}
+
+	return tracker.proxy
+}
+
+func (tracker *tracker) Lookup(session Session, typ ResourceType) (Resource, error) {
+	container, err := tracker.workerClient.LookupContainer(session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResource(container, typ, tracker.maxOutputSize, tracker.proxyFor(session)), nil
 }
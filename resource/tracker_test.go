@@ -2,7 +2,12 @@ package resource_test
 
 import (
 	"errors"
+	"time"
 
+	"github.com/cloudfoundry-incubator/garden"
+	gfakes "github.com/cloudfoundry-incubator/garden/fakes"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/resource/fakes"
 	"github.com/concourse/atc/worker"
 	wfakes "github.com/concourse/atc/worker/fakes"
 	. "github.com/onsi/ginkgo"
@@ -13,22 +18,47 @@ import (
 
 var _ = Describe("Tracker", func() {
 	var (
+		fakeImageFetcher *fakes.FakeImageFetcher
+		proxy            atc.ProxyConfig
+
 		tracker Tracker
 	)
 
-	var session = Session{
-		ID: worker.Identifier{
-			Name: "some-name",
-		},
-		Ephemeral: true,
-	}
+	var session Session
 
 	BeforeEach(func() {
+		session = Session{
+			ID: worker.Identifier{
+				Name: "some-name",
+			},
+			Ephemeral: true,
+		}
+
 		workerClient.CreateContainerReturns(fakeContainer, nil)
 
-		tracker = NewTracker(workerClient)
+		fakeImageFetcher = new(fakes.FakeImageFetcher)
+		proxy = atc.ProxyConfig{}
 	})
 
+	JustBeforeEach(func() {
+		tracker = NewTracker(workerClient, fakeImageFetcher, 5*time.Minute, time.Hour, 1024, proxy)
+	})
+
+	checkEnv := func(resource Resource) []string {
+		fakeContainer.RunStub = func(spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
+			_, err := io.Stdout.Write([]byte("[]"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			return new(gfakes.FakeProcess), nil
+		}
+
+		_, err := resource.Check(IOConfig{}, atc.Source{}, nil)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		spec, _ := fakeContainer.RunArgsForCall(0)
+		return spec.Env
+	}
+
 	Describe("Init", func() {
 		var (
 			initType ResourceType
@@ -66,6 +96,205 @@ var _ = Describe("Tracker", func() {
 				Ω(resourceSpec.Tags).Should(ConsistOf("resource", "tags"))
 			})
 
+			It("creates the container with the check grace time", func() {
+				_, spec := workerClient.CreateContainerArgsForCall(0)
+				Ω(spec.(worker.ResourceTypeContainerSpec).GraceTime).Should(Equal(5 * time.Minute))
+			})
+
+			Context("when the session is not ephemeral", func() {
+				BeforeEach(func() {
+					session.Ephemeral = false
+				})
+
+				It("creates the container with the build grace time", func() {
+					_, spec := workerClient.CreateContainerArgsForCall(0)
+					Ω(spec.(worker.ResourceTypeContainerSpec).GraceTime).Should(Equal(time.Hour))
+				})
+			})
+
+			It("runs the resource's scripts without a proxy", func() {
+				Ω(checkEnv(initResource)).Should(BeEmpty())
+			})
+
+			Context("when a proxy is configured", func() {
+				BeforeEach(func() {
+					proxy = atc.ProxyConfig{HTTP: "http://proxy:3128"}
+				})
+
+				It("runs the resource's scripts through it", func() {
+					Ω(checkEnv(initResource)).Should(Equal([]string{
+						"http_proxy=http://proxy:3128",
+						"HTTP_PROXY=http://proxy:3128",
+					}))
+				})
+
+				Context("when the session configures its own proxy", func() {
+					BeforeEach(func() {
+						session.Proxy = &atc.ProxyConfig{HTTPS: "http://other-proxy:3128"}
+					})
+
+					It("runs the resource's scripts through the session's proxy instead", func() {
+						Ω(checkEnv(initResource)).Should(Equal([]string{
+							"https_proxy=http://other-proxy:3128",
+							"HTTPS_PROXY=http://other-proxy:3128",
+						}))
+					})
+				})
+			})
+
+			It("does not fetch an image for a type provided by the workers", func() {
+				Ω(fakeImageFetcher.FetchImageCallCount()).Should(BeZero())
+
+				_, spec := workerClient.CreateContainerArgsForCall(0)
+				Ω(spec.(worker.ResourceTypeContainerSpec).Image).Should(BeEmpty())
+			})
+
+			Context("when the type is a custom type", func() {
+				BeforeEach(func() {
+					fakeImageFetcher.ImageVersionReturns(atc.Version{"digest": "some-digest"}, true, nil)
+					fakeImageFetcher.FetchImageReturns("docker:///some-image", nil)
+				})
+
+				It("fetches the type's image at its version", func() {
+					Ω(fakeImageFetcher.ImageVersionCallCount()).Should(Equal(1))
+					Ω(fakeImageFetcher.ImageVersionArgsForCall(0)).Should(Equal(initType))
+
+					Ω(fakeImageFetcher.FetchImageCallCount()).Should(Equal(1))
+					fetchedType, fetchedVersion := fakeImageFetcher.FetchImageArgsForCall(0)
+					Ω(fetchedType).Should(Equal(initType))
+					Ω(fetchedVersion).Should(Equal(atc.Version{"digest": "some-digest"}))
+				})
+
+				It("creates the container with the fetched image", func() {
+					Ω(initErr).ShouldNot(HaveOccurred())
+
+					_, spec := workerClient.CreateContainerArgsForCall(0)
+					Ω(spec.(worker.ResourceTypeContainerSpec).Image).Should(Equal("docker:///some-image"))
+				})
+
+				It("reuses the fetched image for subsequent inits", func() {
+					_, err := tracker.Init(session, initType, nil)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(fakeImageFetcher.FetchImageCallCount()).Should(Equal(1))
+
+					Ω(workerClient.CreateContainerCallCount()).Should(Equal(2))
+					_, spec := workerClient.CreateContainerArgsForCall(1)
+					Ω(spec.(worker.ResourceTypeContainerSpec).Image).Should(Equal("docker:///some-image"))
+				})
+
+				It("fetches the image again once there is a new version of it", func() {
+					fakeImageFetcher.ImageVersionReturns(atc.Version{"digest": "some-other-digest"}, true, nil)
+					fakeImageFetcher.FetchImageReturns("docker:///some-other-image", nil)
+
+					_, err := tracker.Init(session, initType, nil)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(fakeImageFetcher.FetchImageCallCount()).Should(Equal(2))
+					_, fetchedVersion := fakeImageFetcher.FetchImageArgsForCall(1)
+					Ω(fetchedVersion).Should(Equal(atc.Version{"digest": "some-other-digest"}))
+
+					_, spec := workerClient.CreateContainerArgsForCall(1)
+					Ω(spec.(worker.ResourceTypeContainerSpec).Image).Should(Equal("docker:///some-other-image"))
+				})
+
+				It("forgets the older version's image once a new version is fetched", func() {
+					fakeImageFetcher.ImageVersionReturns(atc.Version{"digest": "some-other-digest"}, true, nil)
+
+					_, err := tracker.Init(session, initType, nil)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					fakeImageFetcher.ImageVersionReturns(atc.Version{"digest": "some-digest"}, true, nil)
+
+					_, err = tracker.Init(session, initType, nil)
+					Ω(err).ShouldNot(HaveOccurred())
+
+					Ω(fakeImageFetcher.FetchImageCallCount()).Should(Equal(3))
+				})
+
+				Context("while an image is being fetched", func() {
+					var fetching chan struct{}
+					var fetched chan struct{}
+
+					BeforeEach(func() {
+						fetching = make(chan struct{})
+						fetched = make(chan struct{})
+
+						fakeImageFetcher.FetchImageStub = func(typ ResourceType, version atc.Version) (ContainerImage, error) {
+							if typ == "some-slow-type" {
+								close(fetching)
+								<-fetched
+							}
+
+							return "docker:///some-image", nil
+						}
+					})
+
+					It("does not hold up inits of other types", func() {
+						go tracker.Init(session, "some-slow-type", nil)
+						Eventually(fetching).Should(BeClosed())
+
+						initted := make(chan struct{})
+						go func() {
+							defer GinkgoRecover()
+
+							_, err := tracker.Init(session, "some-other-type", nil)
+							Ω(err).ShouldNot(HaveOccurred())
+
+							close(initted)
+						}()
+
+						Eventually(initted).Should(BeClosed())
+
+						close(fetched)
+					})
+
+					It("waits for it rather than fetching it again", func() {
+						go tracker.Init(session, "some-slow-type", nil)
+						Eventually(fetching).Should(BeClosed())
+
+						initted := make(chan struct{})
+						go func() {
+							defer GinkgoRecover()
+
+							_, err := tracker.Init(session, "some-slow-type", nil)
+							Ω(err).ShouldNot(HaveOccurred())
+
+							close(initted)
+						}()
+
+						Consistently(initted).ShouldNot(BeClosed())
+
+						close(fetched)
+
+						Eventually(initted).Should(BeClosed())
+
+						// the initial init, plus one for the slow type
+						Ω(fakeImageFetcher.FetchImageCallCount()).Should(Equal(2))
+					})
+				})
+
+				Context("when fetching the image fails", func() {
+					disaster := errors.New("oh no!")
+
+					BeforeEach(func() {
+						fakeImageFetcher.FetchImageReturns("", disaster)
+					})
+
+					It("returns the error without creating a container", func() {
+						Ω(initErr).Should(Equal(disaster))
+						Ω(initResource).Should(BeNil())
+
+						Ω(workerClient.CreateContainerCallCount()).Should(BeZero())
+					})
+
+					It("tries fetching it again on the next init", func() {
+						tracker.Init(session, initType, nil)
+						Ω(fakeImageFetcher.FetchImageCallCount()).Should(Equal(2))
+					})
+				})
+			})
+
 			Context("when creating the container fails", func() {
 				disaster := errors.New("oh no!")
 
@@ -115,4 +344,41 @@ var _ = Describe("Tracker", func() {
 			})
 		})
 	})
+
+	Describe("Lookup", func() {
+		var (
+			lookupResource Resource
+			lookupErr      error
+		)
+
+		JustBeforeEach(func() {
+			lookupResource, lookupErr = tracker.Lookup(session, "type1")
+		})
+
+		Context("when a container exists for the session", func() {
+			BeforeEach(func() {
+				workerClient.LookupContainerReturns(fakeContainer, nil)
+			})
+
+			It("returns a resource of the given type", func() {
+				Ω(lookupErr).ShouldNot(HaveOccurred())
+				Ω(lookupResource.Type()).Should(Equal(ResourceType("type1")))
+
+				Ω(workerClient.LookupContainerArgsForCall(0)).Should(Equal(session.ID))
+			})
+		})
+
+		Context("when a container does not exist for the session", func() {
+			BeforeEach(func() {
+				workerClient.LookupContainerReturns(nil, worker.ErrContainerNotFound)
+			})
+
+			It("returns the error and does not create a container", func() {
+				Ω(lookupErr).Should(Equal(worker.ErrContainerNotFound))
+				Ω(lookupResource).Should(BeNil())
+
+				Ω(workerClient.CreateContainerCallCount()).Should(BeZero())
+			})
+		})
+	})
 })
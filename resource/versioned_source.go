@@ -3,22 +3,18 @@ package resource
 import (
 	"io"
 	"path"
+	"sync"
 
 	"github.com/cloudfoundry-incubator/garden"
 	"github.com/concourse/atc"
 	"github.com/tedsuo/ifrit"
 )
 
-type versionResult struct {
-	Version atc.Version `json:"version"`
-
-	Metadata []atc.MetadataField `json:"metadata,omitempty"`
-}
-
 type versionedSource struct {
 	ifrit.Runner
 
-	versionResult versionResult
+	versionResult  VersionResult
+	versionResultL sync.Mutex
 
 	container garden.Container
 
@@ -26,13 +22,27 @@ type versionedSource struct {
 }
 
 func (vs *versionedSource) Version() atc.Version {
+	vs.versionResultL.Lock()
+	defer vs.versionResultL.Unlock()
+
 	return vs.versionResult.Version
 }
 
 func (vs *versionedSource) Metadata() []atc.MetadataField {
+	vs.versionResultL.Lock()
+	defer vs.versionResultL.Unlock()
+
 	return vs.versionResult.Metadata
 }
 
+// setVersionResult is called once the script has exited successfully; until
+// then, Version and Metadata return zero values.
+func (vs *versionedSource) setVersionResult(result VersionResult) {
+	vs.versionResultL.Lock()
+	vs.versionResult = result
+	vs.versionResultL.Unlock()
+}
+
 func (vs *versionedSource) StreamOut(src string) (io.ReadCloser, error) {
 	return vs.container.StreamOut(garden.StreamOutSpec{
 		// don't use path.Join; it strips trailing slashes
@@ -3,8 +3,9 @@ package atc
 import "github.com/tedsuo/rata"
 
 const (
-	SaveConfig = "SaveConfig"
-	GetConfig  = "GetConfig"
+	SaveConfig         = "SaveConfig"
+	GetConfig          = "GetConfig"
+	GetEffectiveConfig = "GetEffectiveConfig"
 
 	Hijack = "Hijack"
 
@@ -14,18 +15,25 @@ const (
 	BuildEvents = "BuildEvents"
 	AbortBuild  = "AbortBuild"
 
-	GetJob        = "GetJob"
-	ListJobs      = "ListJobs"
-	ListJobBuilds = "ListJobBuilds"
-	GetJobBuild   = "GetJobBuild"
-	PauseJob      = "PauseJob"
-	UnpauseJob    = "UnpauseJob"
-
-	ListResources          = "ListResources"
-	EnableResourceVersion  = "EnableResourceVersion"
-	DisableResourceVersion = "DisableResourceVersion"
-	PauseResource          = "PauseResource"
-	UnpauseResource        = "UnpauseResource"
+	GetJob         = "GetJob"
+	ListJobs       = "ListJobs"
+	ListJobBuilds  = "ListJobBuilds"
+	GetJobBuild    = "GetJobBuild"
+	CreateJobBuild = "CreateJobBuild"
+	GetJobBadge    = "GetJobBadge"
+	PauseJob       = "PauseJob"
+	UnpauseJob     = "UnpauseJob"
+	GetJobPlan     = "GetJobPlan"
+
+	ListResources             = "ListResources"
+	ListResourceVersions      = "ListResourceVersions"
+	EnableResourceVersion     = "EnableResourceVersion"
+	DisableResourceVersion    = "DisableResourceVersion"
+	ClearResourceVersionCache = "ClearResourceVersionCache"
+	CheckResourceVersion      = "CheckResourceVersion"
+	DebugCheckResource        = "DebugCheckResource"
+	PauseResource             = "PauseResource"
+	UnpauseResource           = "UnpauseResource"
 
 	ListPipelines   = "ListPipelines"
 	DeletePipeline  = "DeletePipeline"
@@ -33,6 +41,8 @@ const (
 	PausePipeline   = "PausePipeline"
 	UnpausePipeline = "UnpausePipeline"
 
+	GetDashboard = "GetDashboard"
+
 	CreatePipe = "CreatePipe"
 	WritePipe  = "WritePipe"
 	ReadPipe   = "ReadPipe"
@@ -49,6 +59,7 @@ const (
 var Routes = rata.Routes{
 	{Path: "/api/v1/pipelines/:pipeline_name/config", Method: "PUT", Name: SaveConfig},
 	{Path: "/api/v1/pipelines/:pipeline_name/config", Method: "GET", Name: GetConfig},
+	{Path: "/api/v1/pipelines/:pipeline_name/config/effective", Method: "GET", Name: GetEffectiveConfig},
 
 	{Path: "/api/v1/builds/:build_id", Method: "GET", Name: GetBuild},
 	{Path: "/api/v1/builds", Method: "POST", Name: CreateBuild},
@@ -60,9 +71,12 @@ var Routes = rata.Routes{
 	{Path: "/api/v1/pipelines/:pipeline_name/jobs", Method: "GET", Name: ListJobs},
 	{Path: "/api/v1/pipelines/:pipeline_name/jobs/:job_name", Method: "GET", Name: GetJob},
 	{Path: "/api/v1/pipelines/:pipeline_name/jobs/:job_name/builds", Method: "GET", Name: ListJobBuilds},
+	{Path: "/api/v1/pipelines/:pipeline_name/jobs/:job_name/builds", Method: "POST", Name: CreateJobBuild},
 	{Path: "/api/v1/pipelines/:pipeline_name/jobs/:job_name/builds/:build_name", Method: "GET", Name: GetJobBuild},
+	{Path: "/api/v1/pipelines/:pipeline_name/jobs/:job_name/badge", Method: "GET", Name: GetJobBadge},
 	{Path: "/api/v1/pipelines/:pipeline_name/jobs/:job_name/pause", Method: "PUT", Name: PauseJob},
 	{Path: "/api/v1/pipelines/:pipeline_name/jobs/:job_name/unpause", Method: "PUT", Name: UnpauseJob},
+	{Path: "/api/v1/pipelines/:pipeline_name/jobs/:job_name/plan", Method: "GET", Name: GetJobPlan},
 
 	{Path: "/api/v1/pipelines", Method: "GET", Name: ListPipelines},
 	{Path: "/api/v1/pipelines/:pipeline_name", Method: "DELETE", Name: DeletePipeline},
@@ -70,9 +84,15 @@ var Routes = rata.Routes{
 	{Path: "/api/v1/pipelines/:pipeline_name/pause", Method: "PUT", Name: PausePipeline},
 	{Path: "/api/v1/pipelines/:pipeline_name/unpause", Method: "PUT", Name: UnpausePipeline},
 
+	{Path: "/api/v1/dashboard", Method: "GET", Name: GetDashboard},
+
 	{Path: "/api/v1/pipelines/:pipeline_name/resources", Method: "GET", Name: ListResources},
+	{Path: "/api/v1/pipelines/:pipeline_name/resources/:resource_name/versions", Method: "GET", Name: ListResourceVersions},
 	{Path: "/api/v1/pipelines/:pipeline_name/resources/:resource_name/versions/:resource_version_id/enable", Method: "PUT", Name: EnableResourceVersion},
 	{Path: "/api/v1/pipelines/:pipeline_name/resources/:resource_name/versions/:resource_version_id/disable", Method: "PUT", Name: DisableResourceVersion},
+	{Path: "/api/v1/pipelines/:pipeline_name/resources/:resource_name/versions/:resource_version_id/clear-cache", Method: "POST", Name: ClearResourceVersionCache},
+	{Path: "/api/v1/pipelines/:pipeline_name/resources/:resource_name/versions/:resource_version_id/exists", Method: "GET", Name: CheckResourceVersion},
+	{Path: "/api/v1/pipelines/:pipeline_name/resources/:resource_name/check/debug", Method: "POST", Name: DebugCheckResource},
 	{Path: "/api/v1/pipelines/:pipeline_name/resources/:resource_name/pause", Method: "PUT", Name: PauseResource},
 	{Path: "/api/v1/pipelines/:pipeline_name/resources/:resource_name/unpause", Method: "PUT", Name: UnpauseResource},
 
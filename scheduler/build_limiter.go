@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pivotal-golang/clock"
+)
+
+// how long a job that was refused a slot keeps its place in line without
+// asking again; long enough to outlast a few scheduler ticks, so that only
+// jobs that stopped asking (their builds aborted, or their pipeline paused
+// or removed) lose it
+const buildWaiterTimeout = 30 * time.Second
+
+// BuildLimiter bounds how many builds the schedulers start and run at once,
+// across every pipeline's scheduler. Unlike checks, builds beyond the limit
+// do not wait for a slot; they are left pending for a later tick to start.
+//
+// Jobs refused a slot queue up, across all pipelines, and free slots go to
+// them in the order they were refused, so that the jobs take turns rather
+// than whichever scheduler happens to tick first getting every free slot.
+//
+// The limit is per ATC: only builds started by this process's schedulers
+// take a slot. Builds started by other ATCs, and builds resumed by the build
+// tracker after a restart, are not counted.
+type BuildLimiter struct {
+	slots chan struct{}
+
+	clock clock.Clock
+
+	// jobs refused a slot, longest waiting first
+	waiting  []buildWaiter
+	waitingL sync.Mutex
+}
+
+type buildWaiter struct {
+	job       string
+	lastAsked time.Time
+}
+
+// NewBuildLimiter returns a limiter allowing max concurrent builds; 0 means
+// no limit.
+func NewBuildLimiter(max int, clock clock.Clock) *BuildLimiter {
+	limiter := &BuildLimiter{
+		clock: clock,
+	}
+
+	if max > 0 {
+		limiter.slots = make(chan struct{}, max)
+	}
+
+	return limiter
+}
+
+// TryAcquire takes a slot for a build of the job, identified by its scoped
+// name, if one is free and no job that has been waiting longer is in line
+// for it. Otherwise it returns false, and the job is put in line.
+func (limiter *BuildLimiter) TryAcquire(job string) bool {
+	if limiter == nil || limiter.slots == nil {
+		return true
+	}
+
+	limiter.waitingL.Lock()
+	defer limiter.waitingL.Unlock()
+
+	now := limiter.clock.Now()
+
+	limiter.forgetStaleWaiters(job, now)
+
+	position := len(limiter.waiting)
+	for i, waiter := range limiter.waiting {
+		if waiter.job == job {
+			position = i
+			limiter.waiting[i].lastAsked = now
+			break
+		}
+	}
+
+	free := cap(limiter.slots) - len(limiter.slots)
+
+	if position < free {
+		select {
+		case limiter.slots <- struct{}{}:
+			if position < len(limiter.waiting) {
+				limiter.waiting = append(limiter.waiting[:position], limiter.waiting[position+1:]...)
+			}
+
+			return true
+		default:
+		}
+	}
+
+	if position == len(limiter.waiting) {
+		limiter.waiting = append(limiter.waiting, buildWaiter{
+			job:       job,
+			lastAsked: now,
+		})
+	}
+
+	return false
+}
+
+// Release frees a slot taken by TryAcquire.
+func (limiter *BuildLimiter) Release() {
+	if limiter == nil || limiter.slots == nil {
+		return
+	}
+
+	<-limiter.slots
+}
+
+func (limiter *BuildLimiter) forgetStaleWaiters(asking string, now time.Time) {
+	waiting := limiter.waiting[:0]
+
+	for _, waiter := range limiter.waiting {
+		if waiter.job != asking && now.Sub(waiter.lastAsked) > buildWaiterTimeout {
+			continue
+		}
+
+		waiting = append(waiting, waiter)
+	}
+
+	limiter.waiting = waiting
+}
@@ -0,0 +1,114 @@
+package scheduler_test
+
+import (
+	"time"
+
+	"github.com/pivotal-golang/clock/fakeclock"
+
+	. "github.com/concourse/atc/scheduler"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BuildLimiter", func() {
+	var fakeClock *fakeclock.FakeClock
+
+	BeforeEach(func() {
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 456))
+	})
+
+	It("allows up to the maximum number of builds at once", func() {
+		limiter := NewBuildLimiter(2, fakeClock)
+
+		Ω(limiter.TryAcquire("some-pipeline:some-job")).Should(BeTrue())
+		Ω(limiter.TryAcquire("some-pipeline:some-job")).Should(BeTrue())
+		Ω(limiter.TryAcquire("some-pipeline:some-job")).Should(BeFalse())
+
+		limiter.Release()
+
+		Ω(limiter.TryAcquire("some-pipeline:some-job")).Should(BeTrue())
+		Ω(limiter.TryAcquire("some-pipeline:some-job")).Should(BeFalse())
+	})
+
+	Context("when jobs across pipelines are waiting for a slot", func() {
+		var limiter *BuildLimiter
+
+		BeforeEach(func() {
+			limiter = NewBuildLimiter(1, fakeClock)
+
+			Ω(limiter.TryAcquire("pipeline-a:some-job")).Should(BeTrue())
+
+			Ω(limiter.TryAcquire("pipeline-a:some-other-job")).Should(BeFalse())
+			Ω(limiter.TryAcquire("pipeline-b:some-job")).Should(BeFalse())
+			Ω(limiter.TryAcquire("pipeline-a:some-job")).Should(BeFalse())
+		})
+
+		It("gives free slots to the jobs in the order they were refused, whichever pipeline asks first", func() {
+			limiter.Release()
+
+			// pipeline b's scheduler ticks first, but pipeline a's job has
+			// been waiting longer
+			Ω(limiter.TryAcquire("pipeline-b:some-job")).Should(BeFalse())
+			Ω(limiter.TryAcquire("pipeline-a:some-job")).Should(BeFalse())
+			Ω(limiter.TryAcquire("pipeline-a:some-other-job")).Should(BeTrue())
+
+			limiter.Release()
+
+			Ω(limiter.TryAcquire("pipeline-a:some-job")).Should(BeFalse())
+			Ω(limiter.TryAcquire("pipeline-b:some-job")).Should(BeTrue())
+
+			limiter.Release()
+
+			Ω(limiter.TryAcquire("pipeline-a:some-job")).Should(BeTrue())
+		})
+
+		It("puts a job that got a slot at the back of the line", func() {
+			limiter.Release()
+
+			Ω(limiter.TryAcquire("pipeline-a:some-other-job")).Should(BeTrue())
+			Ω(limiter.TryAcquire("pipeline-a:some-other-job")).Should(BeFalse())
+
+			limiter.Release()
+
+			Ω(limiter.TryAcquire("pipeline-a:some-other-job")).Should(BeFalse())
+			Ω(limiter.TryAcquire("pipeline-b:some-job")).Should(BeTrue())
+		})
+
+		Context("when a waiting job stops asking for a slot", func() {
+			It("gives its place to the next job in line", func() {
+				limiter.Release()
+
+				fakeClock.Increment(20 * time.Second)
+
+				Ω(limiter.TryAcquire("pipeline-b:some-job")).Should(BeFalse())
+				Ω(limiter.TryAcquire("pipeline-a:some-job")).Should(BeFalse())
+
+				fakeClock.Increment(20 * time.Second)
+
+				Ω(limiter.TryAcquire("pipeline-b:some-job")).Should(BeTrue())
+			})
+		})
+	})
+
+	Context("with a maximum of 0", func() {
+		It("does not limit builds", func() {
+			limiter := NewBuildLimiter(0, fakeClock)
+
+			for i := 0; i < 100; i++ {
+				Ω(limiter.TryAcquire("some-pipeline:some-job")).Should(BeTrue())
+			}
+
+			limiter.Release()
+		})
+	})
+
+	Context("when nil", func() {
+		It("does not limit builds", func() {
+			var limiter *BuildLimiter
+
+			Ω(limiter.TryAcquire("some-pipeline:some-job")).Should(BeTrue())
+			Ω(limiter.Release).ShouldNot(Panic())
+		})
+	})
+})
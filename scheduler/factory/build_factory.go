@@ -26,20 +26,33 @@ func (factory *BuildFactory) Create(
 		return atc.Plan{}, errors.New("you cannot have a plan with hooks and conditionals")
 	}
 
+	var plan atc.Plan
 	if hasConditionals {
-		return factory.constructPlanSequenceBasedPlan(
+		plan = factory.constructPlanSequenceBasedPlan(
 			job.Plan,
 			resources,
-			inputs), nil
+			inputs)
 	} else {
 		populateLocations(&job.Plan)
 
-		plan := factory.constructPlanHookBasedPlan(
+		plan = factory.constructPlanHookBasedPlan(
 			job.Plan,
 			resources,
 			inputs)
-		return plan, nil
 	}
+
+	// the job's timeout is saved with the build's plan, so that it still
+	// applies to a build resumed after a restart
+	if job.BuildTimeout != "" {
+		plan = atc.Plan{
+			Timeout: &atc.TimeoutPlan{
+				Duration: job.BuildTimeout,
+				Step:     plan,
+			},
+		}
+	}
+
+	return plan, nil
 }
 
 func (factory *BuildFactory) hasConditionals(planSequence atc.PlanSequence) bool {
@@ -363,6 +376,7 @@ func (factory *BuildFactory) constructPlanFromConfig(
 			Source:   resource.Source,
 			Params:   planConfig.Params,
 			Tags:     planConfig.Tags,
+			Proxy:    resource.Proxy,
 		}
 
 		dependentGetPlan := &atc.DependentGetPlan{
@@ -373,6 +387,7 @@ func (factory *BuildFactory) constructPlanFromConfig(
 			Params:   planConfig.GetParams,
 			Tags:     planConfig.Tags,
 			Source:   resource.Source,
+			Proxy:    resource.Proxy,
 		}
 
 		stepLocation := &atc.Location{}
@@ -434,6 +449,7 @@ func (factory *BuildFactory) constructPlanFromConfig(
 				Params:   planConfig.Params,
 				Version:  atc.Version(version),
 				Tags:     planConfig.Tags,
+				Proxy:    resource.Proxy,
 			},
 		}
 
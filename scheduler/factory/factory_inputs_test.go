@@ -0,0 +1,204 @@
+package factory_test
+
+import (
+	"encoding/json"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/scheduler/factory"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Factory Inputs", func() {
+	var (
+		buildFactory *factory.BuildFactory
+
+		resources atc.ResourceConfigs
+		inputs    []db.BuildInput
+
+		input atc.JobConfig
+	)
+
+	BeforeEach(func() {
+		buildFactory = &factory.BuildFactory{
+			PipelineName: "some-pipeline",
+		}
+
+		resources = atc.ResourceConfigs{
+			{
+				Name:   "some-resource",
+				Type:   "git",
+				Source: atc.Source{"uri": "git://some-resource"},
+			},
+			{
+				Name:   "some-other-resource",
+				Type:   "git",
+				Source: atc.Source{"uri": "git://some-other-resource"},
+			},
+			{
+				Name:   "some-output-resource",
+				Type:   "s3",
+				Source: atc.Source{"bucket": "some-bucket"},
+			},
+		}
+
+		inputs = []db.BuildInput{
+			{
+				Name: "some-input",
+				VersionedResource: db.VersionedResource{
+					Resource: "some-resource",
+					Version:  db.Version{"ref": "abc"},
+				},
+			},
+			{
+				Name: "some-other-input",
+				VersionedResource: db.VersionedResource{
+					Resource: "some-other-resource",
+					Version:  db.Version{"ref": "def"},
+				},
+			},
+		}
+
+		input = atc.JobConfig{
+			Plan: atc.PlanSequence{
+				{
+					Aggregate: &atc.PlanSequence{
+						{
+							Get:      "some-input",
+							Resource: "some-resource",
+						},
+						{
+							Get:      "some-other-input",
+							Resource: "some-other-resource",
+							Params:   atc.Params{"depth": float64(1)},
+						},
+					},
+				},
+				{
+					Task:           "build",
+					TaskConfigPath: "some-input/build.yml",
+				},
+				{
+					Put:    "some-output-resource",
+					Params: atc.Params{"file": "some-file"},
+				},
+			},
+		}
+	})
+
+	Context("when a job gets two inputs, runs a task, and puts an output", func() {
+		var expected atc.Plan
+
+		BeforeEach(func() {
+			expected = atc.Plan{
+				OnSuccess: &atc.OnSuccessPlan{
+					Step: atc.Plan{
+						Aggregate: &atc.AggregatePlan{
+							{
+								Location: &atc.Location{
+									ParentID:      0,
+									ID:            3,
+									ParallelGroup: 2,
+								},
+								Get: &atc.GetPlan{
+									Type:     "git",
+									Name:     "some-input",
+									Resource: "some-resource",
+									Pipeline: "some-pipeline",
+									Source:   atc.Source{"uri": "git://some-resource"},
+									Version:  atc.Version{"ref": "abc"},
+								},
+							},
+							{
+								Location: &atc.Location{
+									ParentID:      0,
+									ID:            4,
+									ParallelGroup: 2,
+								},
+								Get: &atc.GetPlan{
+									Type:     "git",
+									Name:     "some-other-input",
+									Resource: "some-other-resource",
+									Pipeline: "some-pipeline",
+									Source:   atc.Source{"uri": "git://some-other-resource"},
+									Params:   atc.Params{"depth": float64(1)},
+									Version:  atc.Version{"ref": "def"},
+								},
+							},
+						},
+					},
+					Next: atc.Plan{
+						OnSuccess: &atc.OnSuccessPlan{
+							Step: atc.Plan{
+								Location: &atc.Location{
+									ParentID:      0,
+									ID:            5,
+									ParallelGroup: 0,
+								},
+								Task: &atc.TaskPlan{
+									Name:       "build",
+									ConfigPath: "some-input/build.yml",
+								},
+							},
+							Next: atc.Plan{
+								OnSuccess: &atc.OnSuccessPlan{
+									Step: atc.Plan{
+										Location: &atc.Location{
+											ParentID:      0,
+											ID:            6,
+											ParallelGroup: 0,
+										},
+										Put: &atc.PutPlan{
+											Type:     "s3",
+											Name:     "some-output-resource",
+											Resource: "some-output-resource",
+											Pipeline: "some-pipeline",
+											Source:   atc.Source{"bucket": "some-bucket"},
+											Params:   atc.Params{"file": "some-file"},
+										},
+									},
+									Next: atc.Plan{
+										Location: &atc.Location{
+											ParentID:      6,
+											ID:            7,
+											ParallelGroup: 0,
+										},
+										DependentGet: &atc.DependentGetPlan{
+											Type:     "s3",
+											Name:     "some-output-resource",
+											Resource: "some-output-resource",
+											Pipeline: "some-pipeline",
+											Source:   atc.Source{"bucket": "some-bucket"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		It("gets each input at its determined version, then runs the task, then puts the output", func() {
+			actual, err := buildFactory.Create(input, resources, inputs)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(actual).Should(Equal(expected))
+		})
+
+		It("returns a plan that survives a round-trip through JSON", func() {
+			actual, err := buildFactory.Create(input, resources, inputs)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			payload, err := json.Marshal(actual)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			var decoded atc.Plan
+			err = json.Unmarshal(payload, &decoded)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(decoded).Should(Equal(actual))
+		})
+	})
+})
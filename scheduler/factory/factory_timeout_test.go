@@ -51,4 +51,43 @@ var _ = Describe("Factory Timeout Step", func() {
 			Ω(actual).Should(Equal(expected))
 		})
 	})
+
+	Context("When the job has a build timeout", func() {
+		It("wraps the whole plan in a timeout", func() {
+			actual, err := buildFactory.Create(atc.JobConfig{
+				BuildTimeout: "1h",
+				Plan: atc.PlanSequence{
+					{
+						Task:    "first task",
+						Timeout: "10s",
+					},
+				},
+			}, nil, nil)
+
+			Ω(err).ShouldNot(HaveOccurred())
+
+			expected := atc.Plan{
+				Timeout: &atc.TimeoutPlan{
+					Duration: "1h",
+					Step: atc.Plan{
+						Timeout: &atc.TimeoutPlan{
+							Duration: "10s",
+							Step: atc.Plan{
+								Location: &atc.Location{
+									ParentID: 0,
+									ID:       1,
+									Hook:     "",
+								},
+								Task: &atc.TaskPlan{
+									Name: "first task",
+								},
+							},
+						},
+					},
+				},
+			}
+
+			Ω(actual).Should(Equal(expected))
+		})
+	})
 })
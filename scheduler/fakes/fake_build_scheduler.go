@@ -30,6 +30,16 @@ type FakeBuildScheduler struct {
 	buildLatestInputsReturns struct {
 		result1 error
 	}
+	RetrySystemErroredBuildStub        func(lager.Logger, atc.JobConfig, atc.ResourceConfigs) error
+	retrySystemErroredBuildMutex       sync.RWMutex
+	retrySystemErroredBuildArgsForCall []struct {
+		arg1 lager.Logger
+		arg2 atc.JobConfig
+		arg3 atc.ResourceConfigs
+	}
+	retrySystemErroredBuildReturns struct {
+		result1 error
+	}
 }
 
 func (fake *FakeBuildScheduler) TryNextPendingBuild(arg1 lager.Logger, arg2 atc.JobConfig, arg3 atc.ResourceConfigs) scheduler.Waiter {
@@ -100,4 +110,38 @@ func (fake *FakeBuildScheduler) BuildLatestInputsReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeBuildScheduler) RetrySystemErroredBuild(arg1 lager.Logger, arg2 atc.JobConfig, arg3 atc.ResourceConfigs) error {
+	fake.retrySystemErroredBuildMutex.Lock()
+	fake.retrySystemErroredBuildArgsForCall = append(fake.retrySystemErroredBuildArgsForCall, struct {
+		arg1 lager.Logger
+		arg2 atc.JobConfig
+		arg3 atc.ResourceConfigs
+	}{arg1, arg2, arg3})
+	fake.retrySystemErroredBuildMutex.Unlock()
+	if fake.RetrySystemErroredBuildStub != nil {
+		return fake.RetrySystemErroredBuildStub(arg1, arg2, arg3)
+	} else {
+		return fake.retrySystemErroredBuildReturns.result1
+	}
+}
+
+func (fake *FakeBuildScheduler) RetrySystemErroredBuildCallCount() int {
+	fake.retrySystemErroredBuildMutex.RLock()
+	defer fake.retrySystemErroredBuildMutex.RUnlock()
+	return len(fake.retrySystemErroredBuildArgsForCall)
+}
+
+func (fake *FakeBuildScheduler) RetrySystemErroredBuildArgsForCall(i int) (lager.Logger, atc.JobConfig, atc.ResourceConfigs) {
+	fake.retrySystemErroredBuildMutex.RLock()
+	defer fake.retrySystemErroredBuildMutex.RUnlock()
+	return fake.retrySystemErroredBuildArgsForCall[i].arg1, fake.retrySystemErroredBuildArgsForCall[i].arg2, fake.retrySystemErroredBuildArgsForCall[i].arg3
+}
+
+func (fake *FakeBuildScheduler) RetrySystemErroredBuildReturns(result1 error) {
+	fake.RetrySystemErroredBuildStub = nil
+	fake.retrySystemErroredBuildReturns = struct {
+		result1 error
+	}{result1}
+}
+
 var _ scheduler.BuildScheduler = new(FakeBuildScheduler)
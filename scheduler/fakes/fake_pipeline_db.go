@@ -10,6 +10,14 @@ import (
 )
 
 type FakePipelineDB struct {
+	ScopedNameStub        func(string) string
+	scopedNameMutex       sync.RWMutex
+	scopedNameArgsForCall []struct {
+		arg1 string
+	}
+	scopedNameReturns struct {
+		result1 string
+	}
 	CreateJobBuildStub        func(job string) (db.Build, error)
 	createJobBuildMutex       sync.RWMutex
 	createJobBuildArgsForCall []struct {
@@ -19,6 +27,16 @@ type FakePipelineDB struct {
 		result1 db.Build
 		result2 error
 	}
+	CreateJobBuildWithPinnedInputsStub        func(job string, inputs []db.BuildInput) (db.Build, error)
+	createJobBuildWithPinnedInputsMutex       sync.RWMutex
+	createJobBuildWithPinnedInputsArgsForCall []struct {
+		job    string
+		inputs []db.BuildInput
+	}
+	createJobBuildWithPinnedInputsReturns struct {
+		result1 db.Build
+		result2 error
+	}
 	CreateJobBuildForCandidateInputsStub        func(job string) (db.Build, bool, error)
 	createJobBuildForCandidateInputsMutex       sync.RWMutex
 	createJobBuildForCandidateInputsArgsForCall []struct {
@@ -29,6 +47,16 @@ type FakePipelineDB struct {
 		result2 bool
 		result3 error
 	}
+	CreateJobBuildRetryStub        func(build db.Build) (db.Build, bool, error)
+	createJobBuildRetryMutex       sync.RWMutex
+	createJobBuildRetryArgsForCall []struct {
+		build db.Build
+	}
+	createJobBuildRetryReturns struct {
+		result1 db.Build
+		result2 bool
+		result3 error
+	}
 	ScheduleBuildStub        func(buildID int, jobConfig atc.JobConfig) (bool, error)
 	scheduleBuildMutex       sync.RWMutex
 	scheduleBuildArgsForCall []struct {
@@ -58,6 +86,53 @@ type FakePipelineDB struct {
 		result1 db.Build
 		result2 error
 	}
+	GetPendingJobBuildsStub        func(job string) ([]db.Build, error)
+	getPendingJobBuildsMutex       sync.RWMutex
+	getPendingJobBuildsArgsForCall []struct {
+		job string
+	}
+	getPendingJobBuildsReturns struct {
+		result1 []db.Build
+		result2 error
+	}
+	GetJobFinishedAndNextBuildStub        func(job string) (*db.Build, *db.Build, error)
+	getJobFinishedAndNextBuildMutex       sync.RWMutex
+	getJobFinishedAndNextBuildArgsForCall []struct {
+		job string
+	}
+	getJobFinishedAndNextBuildReturns struct {
+		result1 *db.Build
+		result2 *db.Build
+		result3 error
+	}
+	GetBuildResourcesStub        func(buildID int) ([]db.BuildInput, []db.BuildOutput, error)
+	getBuildResourcesMutex       sync.RWMutex
+	getBuildResourcesArgsForCall []struct {
+		buildID int
+	}
+	getBuildResourcesReturns struct {
+		result1 []db.BuildInput
+		result2 []db.BuildOutput
+		result3 error
+	}
+	GetUnavailableBuildInputsStub        func(buildID int) ([]string, error)
+	getUnavailableBuildInputsMutex       sync.RWMutex
+	getUnavailableBuildInputsArgsForCall []struct {
+		buildID int
+	}
+	getUnavailableBuildInputsReturns struct {
+		result1 []string
+		result2 error
+	}
+	GetPinnedBuildInputsStub        func(buildID int) ([]db.BuildInput, error)
+	getPinnedBuildInputsMutex       sync.RWMutex
+	getPinnedBuildInputsArgsForCall []struct {
+		buildID int
+	}
+	getPinnedBuildInputsReturns struct {
+		result1 []db.BuildInput
+		result2 error
+	}
 	GetLatestInputVersionsStub        func(job string, inputs []atc.JobInput) ([]db.BuildInput, error)
 	getLatestInputVersionsMutex       sync.RWMutex
 	getLatestInputVersionsArgsForCall []struct {
@@ -68,6 +143,16 @@ type FakePipelineDB struct {
 		result1 []db.BuildInput
 		result2 error
 	}
+	GetVersionedResourceStub        func(versionedResourceID int) (db.SavedVersionedResource, bool, error)
+	getVersionedResourceMutex       sync.RWMutex
+	getVersionedResourceArgsForCall []struct {
+		versionedResourceID int
+	}
+	getVersionedResourceReturns struct {
+		result1 db.SavedVersionedResource
+		result2 bool
+		result3 error
+	}
 	SaveResourceVersionsStub        func(atc.ResourceConfig, []atc.Version) error
 	saveResourceVersionsMutex       sync.RWMutex
 	saveResourceVersionsArgsForCall []struct {
@@ -88,6 +173,38 @@ type FakePipelineDB struct {
 	}
 }
 
+func (fake *FakePipelineDB) ScopedName(arg1 string) string {
+	fake.scopedNameMutex.Lock()
+	fake.scopedNameArgsForCall = append(fake.scopedNameArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.scopedNameMutex.Unlock()
+	if fake.ScopedNameStub != nil {
+		return fake.ScopedNameStub(arg1)
+	} else {
+		return fake.scopedNameReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) ScopedNameCallCount() int {
+	fake.scopedNameMutex.RLock()
+	defer fake.scopedNameMutex.RUnlock()
+	return len(fake.scopedNameArgsForCall)
+}
+
+func (fake *FakePipelineDB) ScopedNameArgsForCall(i int) string {
+	fake.scopedNameMutex.RLock()
+	defer fake.scopedNameMutex.RUnlock()
+	return fake.scopedNameArgsForCall[i].arg1
+}
+
+func (fake *FakePipelineDB) ScopedNameReturns(result1 string) {
+	fake.ScopedNameStub = nil
+	fake.scopedNameReturns = struct {
+		result1 string
+	}{result1}
+}
+
 func (fake *FakePipelineDB) CreateJobBuild(job string) (db.Build, error) {
 	fake.createJobBuildMutex.Lock()
 	fake.createJobBuildArgsForCall = append(fake.createJobBuildArgsForCall, struct {
@@ -121,6 +238,40 @@ func (fake *FakePipelineDB) CreateJobBuildReturns(result1 db.Build, result2 erro
 	}{result1, result2}
 }
 
+func (fake *FakePipelineDB) CreateJobBuildWithPinnedInputs(job string, inputs []db.BuildInput) (db.Build, error) {
+	fake.createJobBuildWithPinnedInputsMutex.Lock()
+	fake.createJobBuildWithPinnedInputsArgsForCall = append(fake.createJobBuildWithPinnedInputsArgsForCall, struct {
+		job    string
+		inputs []db.BuildInput
+	}{job, inputs})
+	fake.createJobBuildWithPinnedInputsMutex.Unlock()
+	if fake.CreateJobBuildWithPinnedInputsStub != nil {
+		return fake.CreateJobBuildWithPinnedInputsStub(job, inputs)
+	} else {
+		return fake.createJobBuildWithPinnedInputsReturns.result1, fake.createJobBuildWithPinnedInputsReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) CreateJobBuildWithPinnedInputsCallCount() int {
+	fake.createJobBuildWithPinnedInputsMutex.RLock()
+	defer fake.createJobBuildWithPinnedInputsMutex.RUnlock()
+	return len(fake.createJobBuildWithPinnedInputsArgsForCall)
+}
+
+func (fake *FakePipelineDB) CreateJobBuildWithPinnedInputsArgsForCall(i int) (string, []db.BuildInput) {
+	fake.createJobBuildWithPinnedInputsMutex.RLock()
+	defer fake.createJobBuildWithPinnedInputsMutex.RUnlock()
+	return fake.createJobBuildWithPinnedInputsArgsForCall[i].job, fake.createJobBuildWithPinnedInputsArgsForCall[i].inputs
+}
+
+func (fake *FakePipelineDB) CreateJobBuildWithPinnedInputsReturns(result1 db.Build, result2 error) {
+	fake.CreateJobBuildWithPinnedInputsStub = nil
+	fake.createJobBuildWithPinnedInputsReturns = struct {
+		result1 db.Build
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePipelineDB) CreateJobBuildForCandidateInputs(job string) (db.Build, bool, error) {
 	fake.createJobBuildForCandidateInputsMutex.Lock()
 	fake.createJobBuildForCandidateInputsArgsForCall = append(fake.createJobBuildForCandidateInputsArgsForCall, struct {
@@ -155,6 +306,40 @@ func (fake *FakePipelineDB) CreateJobBuildForCandidateInputsReturns(result1 db.B
 	}{result1, result2, result3}
 }
 
+func (fake *FakePipelineDB) CreateJobBuildRetry(build db.Build) (db.Build, bool, error) {
+	fake.createJobBuildRetryMutex.Lock()
+	fake.createJobBuildRetryArgsForCall = append(fake.createJobBuildRetryArgsForCall, struct {
+		build db.Build
+	}{build})
+	fake.createJobBuildRetryMutex.Unlock()
+	if fake.CreateJobBuildRetryStub != nil {
+		return fake.CreateJobBuildRetryStub(build)
+	} else {
+		return fake.createJobBuildRetryReturns.result1, fake.createJobBuildRetryReturns.result2, fake.createJobBuildRetryReturns.result3
+	}
+}
+
+func (fake *FakePipelineDB) CreateJobBuildRetryCallCount() int {
+	fake.createJobBuildRetryMutex.RLock()
+	defer fake.createJobBuildRetryMutex.RUnlock()
+	return len(fake.createJobBuildRetryArgsForCall)
+}
+
+func (fake *FakePipelineDB) CreateJobBuildRetryArgsForCall(i int) db.Build {
+	fake.createJobBuildRetryMutex.RLock()
+	defer fake.createJobBuildRetryMutex.RUnlock()
+	return fake.createJobBuildRetryArgsForCall[i].build
+}
+
+func (fake *FakePipelineDB) CreateJobBuildRetryReturns(result1 db.Build, result2 bool, result3 error) {
+	fake.CreateJobBuildRetryStub = nil
+	fake.createJobBuildRetryReturns = struct {
+		result1 db.Build
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakePipelineDB) ScheduleBuild(buildID int, jobConfig atc.JobConfig) (bool, error) {
 	fake.scheduleBuildMutex.Lock()
 	fake.scheduleBuildArgsForCall = append(fake.scheduleBuildArgsForCall, struct {
@@ -256,6 +441,173 @@ func (fake *FakePipelineDB) GetNextPendingBuildReturns(result1 db.Build, result2
 	}{result1, result2}
 }
 
+func (fake *FakePipelineDB) GetPendingJobBuilds(job string) ([]db.Build, error) {
+	fake.getPendingJobBuildsMutex.Lock()
+	fake.getPendingJobBuildsArgsForCall = append(fake.getPendingJobBuildsArgsForCall, struct {
+		job string
+	}{job})
+	fake.getPendingJobBuildsMutex.Unlock()
+	if fake.GetPendingJobBuildsStub != nil {
+		return fake.GetPendingJobBuildsStub(job)
+	} else {
+		return fake.getPendingJobBuildsReturns.result1, fake.getPendingJobBuildsReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetPendingJobBuildsCallCount() int {
+	fake.getPendingJobBuildsMutex.RLock()
+	defer fake.getPendingJobBuildsMutex.RUnlock()
+	return len(fake.getPendingJobBuildsArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetPendingJobBuildsArgsForCall(i int) string {
+	fake.getPendingJobBuildsMutex.RLock()
+	defer fake.getPendingJobBuildsMutex.RUnlock()
+	return fake.getPendingJobBuildsArgsForCall[i].job
+}
+
+func (fake *FakePipelineDB) GetPendingJobBuildsReturns(result1 []db.Build, result2 error) {
+	fake.GetPendingJobBuildsStub = nil
+	fake.getPendingJobBuildsReturns = struct {
+		result1 []db.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) GetJobFinishedAndNextBuild(job string) (*db.Build, *db.Build, error) {
+	fake.getJobFinishedAndNextBuildMutex.Lock()
+	fake.getJobFinishedAndNextBuildArgsForCall = append(fake.getJobFinishedAndNextBuildArgsForCall, struct {
+		job string
+	}{job})
+	fake.getJobFinishedAndNextBuildMutex.Unlock()
+	if fake.GetJobFinishedAndNextBuildStub != nil {
+		return fake.GetJobFinishedAndNextBuildStub(job)
+	} else {
+		return fake.getJobFinishedAndNextBuildReturns.result1, fake.getJobFinishedAndNextBuildReturns.result2, fake.getJobFinishedAndNextBuildReturns.result3
+	}
+}
+
+func (fake *FakePipelineDB) GetJobFinishedAndNextBuildCallCount() int {
+	fake.getJobFinishedAndNextBuildMutex.RLock()
+	defer fake.getJobFinishedAndNextBuildMutex.RUnlock()
+	return len(fake.getJobFinishedAndNextBuildArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetJobFinishedAndNextBuildArgsForCall(i int) string {
+	fake.getJobFinishedAndNextBuildMutex.RLock()
+	defer fake.getJobFinishedAndNextBuildMutex.RUnlock()
+	return fake.getJobFinishedAndNextBuildArgsForCall[i].job
+}
+
+func (fake *FakePipelineDB) GetJobFinishedAndNextBuildReturns(result1 *db.Build, result2 *db.Build, result3 error) {
+	fake.GetJobFinishedAndNextBuildStub = nil
+	fake.getJobFinishedAndNextBuildReturns = struct {
+		result1 *db.Build
+		result2 *db.Build
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePipelineDB) GetBuildResources(buildID int) ([]db.BuildInput, []db.BuildOutput, error) {
+	fake.getBuildResourcesMutex.Lock()
+	fake.getBuildResourcesArgsForCall = append(fake.getBuildResourcesArgsForCall, struct {
+		buildID int
+	}{buildID})
+	fake.getBuildResourcesMutex.Unlock()
+	if fake.GetBuildResourcesStub != nil {
+		return fake.GetBuildResourcesStub(buildID)
+	} else {
+		return fake.getBuildResourcesReturns.result1, fake.getBuildResourcesReturns.result2, fake.getBuildResourcesReturns.result3
+	}
+}
+
+func (fake *FakePipelineDB) GetBuildResourcesCallCount() int {
+	fake.getBuildResourcesMutex.RLock()
+	defer fake.getBuildResourcesMutex.RUnlock()
+	return len(fake.getBuildResourcesArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetBuildResourcesArgsForCall(i int) int {
+	fake.getBuildResourcesMutex.RLock()
+	defer fake.getBuildResourcesMutex.RUnlock()
+	return fake.getBuildResourcesArgsForCall[i].buildID
+}
+
+func (fake *FakePipelineDB) GetBuildResourcesReturns(result1 []db.BuildInput, result2 []db.BuildOutput, result3 error) {
+	fake.GetBuildResourcesStub = nil
+	fake.getBuildResourcesReturns = struct {
+		result1 []db.BuildInput
+		result2 []db.BuildOutput
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePipelineDB) GetUnavailableBuildInputs(buildID int) ([]string, error) {
+	fake.getUnavailableBuildInputsMutex.Lock()
+	fake.getUnavailableBuildInputsArgsForCall = append(fake.getUnavailableBuildInputsArgsForCall, struct {
+		buildID int
+	}{buildID})
+	fake.getUnavailableBuildInputsMutex.Unlock()
+	if fake.GetUnavailableBuildInputsStub != nil {
+		return fake.GetUnavailableBuildInputsStub(buildID)
+	} else {
+		return fake.getUnavailableBuildInputsReturns.result1, fake.getUnavailableBuildInputsReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetUnavailableBuildInputsCallCount() int {
+	fake.getUnavailableBuildInputsMutex.RLock()
+	defer fake.getUnavailableBuildInputsMutex.RUnlock()
+	return len(fake.getUnavailableBuildInputsArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetUnavailableBuildInputsArgsForCall(i int) int {
+	fake.getUnavailableBuildInputsMutex.RLock()
+	defer fake.getUnavailableBuildInputsMutex.RUnlock()
+	return fake.getUnavailableBuildInputsArgsForCall[i].buildID
+}
+
+func (fake *FakePipelineDB) GetUnavailableBuildInputsReturns(result1 []string, result2 error) {
+	fake.GetUnavailableBuildInputsStub = nil
+	fake.getUnavailableBuildInputsReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) GetPinnedBuildInputs(buildID int) ([]db.BuildInput, error) {
+	fake.getPinnedBuildInputsMutex.Lock()
+	fake.getPinnedBuildInputsArgsForCall = append(fake.getPinnedBuildInputsArgsForCall, struct {
+		buildID int
+	}{buildID})
+	fake.getPinnedBuildInputsMutex.Unlock()
+	if fake.GetPinnedBuildInputsStub != nil {
+		return fake.GetPinnedBuildInputsStub(buildID)
+	} else {
+		return fake.getPinnedBuildInputsReturns.result1, fake.getPinnedBuildInputsReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetPinnedBuildInputsCallCount() int {
+	fake.getPinnedBuildInputsMutex.RLock()
+	defer fake.getPinnedBuildInputsMutex.RUnlock()
+	return len(fake.getPinnedBuildInputsArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetPinnedBuildInputsArgsForCall(i int) int {
+	fake.getPinnedBuildInputsMutex.RLock()
+	defer fake.getPinnedBuildInputsMutex.RUnlock()
+	return fake.getPinnedBuildInputsArgsForCall[i].buildID
+}
+
+func (fake *FakePipelineDB) GetPinnedBuildInputsReturns(result1 []db.BuildInput, result2 error) {
+	fake.GetPinnedBuildInputsStub = nil
+	fake.getPinnedBuildInputsReturns = struct {
+		result1 []db.BuildInput
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePipelineDB) GetLatestInputVersions(job string, inputs []atc.JobInput) ([]db.BuildInput, error) {
 	fake.getLatestInputVersionsMutex.Lock()
 	fake.getLatestInputVersionsArgsForCall = append(fake.getLatestInputVersionsArgsForCall, struct {
@@ -290,6 +642,40 @@ func (fake *FakePipelineDB) GetLatestInputVersionsReturns(result1 []db.BuildInpu
 	}{result1, result2}
 }
 
+func (fake *FakePipelineDB) GetVersionedResource(versionedResourceID int) (db.SavedVersionedResource, bool, error) {
+	fake.getVersionedResourceMutex.Lock()
+	fake.getVersionedResourceArgsForCall = append(fake.getVersionedResourceArgsForCall, struct {
+		versionedResourceID int
+	}{versionedResourceID})
+	fake.getVersionedResourceMutex.Unlock()
+	if fake.GetVersionedResourceStub != nil {
+		return fake.GetVersionedResourceStub(versionedResourceID)
+	} else {
+		return fake.getVersionedResourceReturns.result1, fake.getVersionedResourceReturns.result2, fake.getVersionedResourceReturns.result3
+	}
+}
+
+func (fake *FakePipelineDB) GetVersionedResourceCallCount() int {
+	fake.getVersionedResourceMutex.RLock()
+	defer fake.getVersionedResourceMutex.RUnlock()
+	return len(fake.getVersionedResourceArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetVersionedResourceArgsForCall(i int) int {
+	fake.getVersionedResourceMutex.RLock()
+	defer fake.getVersionedResourceMutex.RUnlock()
+	return fake.getVersionedResourceArgsForCall[i].versionedResourceID
+}
+
+func (fake *FakePipelineDB) GetVersionedResourceReturns(result1 db.SavedVersionedResource, result2 bool, result3 error) {
+	fake.GetVersionedResourceStub = nil
+	fake.getVersionedResourceReturns = struct {
+		result1 db.SavedVersionedResource
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakePipelineDB) SaveResourceVersions(arg1 atc.ResourceConfig, arg2 []atc.Version) error {
 	fake.saveResourceVersionsMutex.Lock()
 	fake.saveResourceVersionsArgsForCall = append(fake.saveResourceVersionsArgsForCall, struct {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
+	"github.com/pivotal-golang/clock"
 	"github.com/pivotal-golang/lager"
 )
 
@@ -23,6 +24,7 @@ type Locker interface {
 type BuildScheduler interface {
 	TryNextPendingBuild(lager.Logger, atc.JobConfig, atc.ResourceConfigs) Waiter
 	BuildLatestInputs(lager.Logger, atc.JobConfig, atc.ResourceConfigs) error
+	RetrySystemErroredBuild(lager.Logger, atc.JobConfig, atc.ResourceConfigs) error
 }
 
 type Runner struct {
@@ -36,6 +38,7 @@ type Runner struct {
 	Noop bool
 
 	Interval time.Duration
+	Clock    clock.Clock
 }
 
 func (runner *Runner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
@@ -58,9 +61,12 @@ dance:
 			return err
 		}
 
+		timer := runner.Clock.NewTimer(runner.Interval)
+
 		select {
-		case <-time.After(runner.Interval):
+		case <-timer.C():
 		case <-signals:
+			timer.Stop()
 			break dance
 		}
 	}
@@ -83,6 +89,8 @@ func (runner *Runner) tick(logger lager.Logger) error {
 		return nil
 	}
 
+	config = config.WithDefaults()
+
 	if runner.Noop {
 		return nil
 	}
@@ -113,4 +121,9 @@ func (runner *Runner) schedule(logger lager.Logger, job atc.JobConfig, resources
 	if err != nil {
 		logger.Error("failed-to-build-from-latest-inputs", err)
 	}
+
+	err = runner.Scheduler.RetrySystemErroredBuild(logger, job, resources)
+	if err != nil {
+		logger.Error("failed-to-retry-system-errored-build", err)
+	}
 }
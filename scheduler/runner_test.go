@@ -10,6 +10,7 @@ import (
 	dbfakes "github.com/concourse/atc/db/fakes"
 	. "github.com/concourse/atc/scheduler"
 	"github.com/concourse/atc/scheduler/fakes"
+	"github.com/pivotal-golang/clock"
 	"github.com/pivotal-golang/lager"
 	"github.com/pivotal-golang/lager/lagertest"
 	"github.com/tedsuo/ifrit"
@@ -84,6 +85,7 @@ var _ = Describe("Runner", func() {
 			Scheduler: scheduler,
 			Noop:      noop,
 			Interval:  100 * time.Millisecond,
+			Clock:     clock.NewClock(),
 		})
 	})
 
@@ -144,6 +146,54 @@ var _ = Describe("Runner", func() {
 		Ω(resources).Should(Equal(initialConfig.Resources))
 	})
 
+	It("retries builds that errored due to system errors", func() {
+		Eventually(scheduler.RetrySystemErroredBuildCallCount).Should(Equal(2))
+
+		_, job, resources := scheduler.RetrySystemErroredBuildArgsForCall(0)
+		Ω(job).Should(Equal(atc.JobConfig{Name: "some-job"}))
+		Ω(resources).Should(Equal(initialConfig.Resources))
+
+		_, job, resources = scheduler.RetrySystemErroredBuildArgsForCall(1)
+		Ω(job).Should(Equal(atc.JobConfig{Name: "some-other-job"}))
+		Ω(resources).Should(Equal(initialConfig.Resources))
+	})
+
+	Context("when the jobs are reconfigured", func() {
+		var updateConfig chan<- atc.Config
+
+		BeforeEach(func() {
+			configs := make(chan atc.Config)
+			updateConfig = configs
+
+			config := initialConfig
+
+			pipelineDB.GetConfigStub = func() (atc.Config, db.ConfigVersion, error) {
+				select {
+				case config = <-configs:
+				default:
+				}
+
+				return config, 1, nil
+			}
+		})
+
+		It("schedules the new set of jobs on the next tick", func() {
+			Eventually(scheduler.TryNextPendingBuildCallCount).Should(Equal(2))
+
+			newConfig := initialConfig
+			newConfig.Jobs = atc.JobConfigs{
+				{Name: "some-new-job"},
+			}
+
+			updateConfig <- newConfig
+
+			Eventually(scheduler.TryNextPendingBuildCallCount).Should(BeNumerically(">=", 3))
+
+			_, job, _ := scheduler.TryNextPendingBuildArgsForCall(scheduler.TryNextPendingBuildCallCount() - 1)
+			Ω(job).Should(Equal(atc.JobConfig{Name: "some-new-job"}))
+		})
+	})
+
 	Context("when in noop mode", func() {
 		BeforeEach(func() {
 			noop = true
@@ -1,26 +1,43 @@
 package scheduler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
+	"github.com/pivotal-golang/clock"
 	"github.com/pivotal-golang/lager"
 
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/engine"
+	"github.com/concourse/atc/exec"
 )
 
 //go:generate counterfeiter . PipelineDB
 
 type PipelineDB interface {
+	ScopedName(string) string
+
 	CreateJobBuild(job string) (db.Build, error)
+	CreateJobBuildWithPinnedInputs(job string, inputs []db.BuildInput) (db.Build, error)
 	CreateJobBuildForCandidateInputs(job string) (db.Build, bool, error)
+	CreateJobBuildRetry(build db.Build) (db.Build, bool, error)
 	ScheduleBuild(buildID int, jobConfig atc.JobConfig) (bool, error)
 
 	GetJobBuildForInputs(job string, inputs []db.BuildInput) (db.Build, error)
 	GetNextPendingBuild(job string) (db.Build, error)
+	GetPendingJobBuilds(job string) ([]db.Build, error)
+	GetJobFinishedAndNextBuild(job string) (*db.Build, *db.Build, error)
+	GetBuildResources(buildID int) ([]db.BuildInput, []db.BuildOutput, error)
+	GetUnavailableBuildInputs(buildID int) ([]string, error)
+	GetPinnedBuildInputs(buildID int) ([]db.BuildInput, error)
 
 	GetLatestInputVersions(job string, inputs []atc.JobInput) ([]db.BuildInput, error)
+	GetVersionedResource(versionedResourceID int) (db.SavedVersionedResource, bool, error)
 	SaveResourceVersions(atc.ResourceConfig, []atc.Version) error
 	UseInputsForBuild(buildID int, inputs []db.BuildInput) error
 }
@@ -48,12 +65,46 @@ type Scanner interface {
 	Scan(lager.Logger, string) error
 }
 
+// InputVersionsUnavailableError is the reason given for erroring a pending
+// build whose determined or pinned input versions have since been disabled.
+type InputVersionsUnavailableError struct {
+	Inputs []string
+}
+
+func (err InputVersionsUnavailableError) Error() string {
+	return fmt.Sprintf("input version no longer available: %s", strings.Join(err.Inputs, ", "))
+}
+
+// InputOverrides pins inputs of a manually triggered build, by name, to the
+// ID of the versioned resource to use rather than the latest one.
+type InputOverrides map[string]int
+
+// InvalidInputOverrideError is returned when an override names an input the
+// job does not have, or a version that cannot be used for it.
+type InvalidInputOverrideError struct {
+	Input  string
+	Reason string
+}
+
+func (err InvalidInputOverrideError) Error() string {
+	return fmt.Sprintf("invalid version for input '%s': %s", err.Input, err.Reason)
+}
+
 type Scheduler struct {
 	PipelineDB PipelineDB
 	BuildsDB   BuildsDB
 	Factory    BuildFactory
 	Engine     engine.Engine
 	Scanner    Scanner
+	Locker     Locker
+
+	// bounds how many builds run at once, across all pipelines; may be nil
+	Limiter *BuildLimiter
+
+	// how many times a build that errored due to a system error is retried
+	MaxSystemRetries int
+
+	Clock clock.Clock
 }
 
 func (s *Scheduler) BuildLatestInputs(logger lager.Logger, job atc.JobConfig, resources atc.ResourceConfigs) error {
@@ -153,10 +204,102 @@ func (s *Scheduler) TryNextPendingBuild(logger lager.Logger, job atc.JobConfig,
 	return wg
 }
 
+func (s *Scheduler) RetrySystemErroredBuild(logger lager.Logger, job atc.JobConfig, resources atc.ResourceConfigs) error {
+	if s.MaxSystemRetries == 0 {
+		return nil
+	}
+
+	logger = logger.Session("retry-system-errored")
+
+	finished, _, err := s.PipelineDB.GetJobFinishedAndNextBuild(job.Name)
+	if err != nil {
+		logger.Error("failed-to-get-finished-build", err)
+		return err
+	}
+
+	if finished == nil ||
+		finished.Status != db.StatusErrored ||
+		finished.ErrorCategory != string(exec.StepErrorCategorySystem) {
+		return nil
+	}
+
+	if finished.Attempt > s.MaxSystemRetries {
+		logger.Debug("out-of-retries", lager.Data{
+			"build":   finished.ID,
+			"attempt": finished.Attempt,
+		})
+
+		return nil
+	}
+
+	build, created, err := s.PipelineDB.CreateJobBuildRetry(*finished)
+	if err != nil {
+		logger.Error("failed-to-create-retry", err)
+		return err
+	}
+
+	if !created {
+		return nil
+	}
+
+	logger.Info("retrying", lager.Data{
+		"errored-build": finished.ID,
+		"build":         build.ID,
+		"attempt":       build.Attempt,
+	})
+
+	s.scheduleAndResumePendingBuild(logger, build, job, resources)
+
+	return nil
+}
+
 func (s *Scheduler) TriggerImmediately(logger lager.Logger, job atc.JobConfig, resources atc.ResourceConfigs) (db.Build, error) {
+	return s.TriggerWithInputOverrides(logger, job, resources, nil)
+}
+
+// TriggerWithInputOverrides creates a pending build of the job whose
+// overridden inputs use the given versions. The overrides are validated
+// before the build is created and saved with it, so they are used however
+// long the build stays pending; the remaining inputs are determined as usual.
+//
+// If a pending build of the job already exists for the same inputs, it is
+// returned instead of creating a duplicate.
+func (s *Scheduler) TriggerWithInputOverrides(logger lager.Logger, job atc.JobConfig, resources atc.ResourceConfigs, overrides InputOverrides) (db.Build, error) {
 	logger = logger.Session("trigger-immediately")
 
-	build, err := s.PipelineDB.CreateJobBuild(job.Name)
+	pinned, err := s.resolveInputOverrides(job, overrides)
+	if err != nil {
+		logger.Info("invalid-input-overrides", lager.Data{"error": err.Error()})
+		return db.Build{}, err
+	}
+
+	// serialize with the scheduler's own build creation for the job
+	lock, err := s.Locker.AcquireWriteLock([]db.NamedLock{db.JobSchedulingLock(s.PipelineDB.ScopedName(job.Name))})
+	if err != nil {
+		logger.Error("failed-to-acquire-scheduling-lock", err)
+		return db.Build{}, err
+	}
+
+	defer lock.Release()
+
+	existingBuild, found, err := s.findPendingBuild(job, pinned)
+	if err != nil {
+		logger.Error("failed-to-find-pending-build", err)
+		return db.Build{}, err
+	}
+
+	if found {
+		logger.Info("build-already-pending", lager.Data{"existing-build": existingBuild.ID})
+		return existingBuild, nil
+	}
+
+	var build db.Build
+	if len(pinned) == 0 {
+		build, err = s.PipelineDB.CreateJobBuild(job.Name)
+	} else {
+		build, err = s.PipelineDB.CreateJobBuildWithPinnedInputs(job.Name, pinned)
+	}
+
 	if err != nil {
 		logger.Error("failed-to-create-build", err)
 		return db.Build{}, err
@@ -168,9 +311,191 @@ func (s *Scheduler) TriggerImmediately(logger lager.Logger, job atc.JobConfig, r
 	return build, nil
 }
 
+// PreviewPlan returns the plan a build of the job triggered now would run,
+// using the latest versions already known for its inputs. No build is
+// created and no resources are checked.
+func (s *Scheduler) PreviewPlan(logger lager.Logger, job atc.JobConfig, resources atc.ResourceConfigs) (atc.Plan, error) {
+	logger = logger.Session("preview-plan")
+
+	inputs, err := s.candidateInputs(job, nil)
+	if err != nil {
+		logger.Info("failed-to-get-latest-input-versions", lager.Data{"error": err.Error()})
+		return atc.Plan{}, err
+	}
+
+	plan, err := s.Factory.Create(job, resources, inputs)
+	if err != nil {
+		logger.Error("failed-to-create-build-plan", err)
+		return atc.Plan{}, err
+	}
+
+	return plan, nil
+}
+
+func (s *Scheduler) resolveInputOverrides(job atc.JobConfig, overrides InputOverrides) ([]db.BuildInput, error) {
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var pinned []db.BuildInput
+
+	for _, name := range names {
+		input, found := lookupJobInput(job.Inputs(), name)
+		if !found {
+			return nil, InvalidInputOverrideError{Input: name, Reason: "job has no such input"}
+		}
+
+		versionID := overrides[name]
+
+		svr, found, err := s.PipelineDB.GetVersionedResource(versionID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !found || svr.Resource != input.Resource {
+			return nil, InvalidInputOverrideError{
+				Input:  name,
+				Reason: fmt.Sprintf("resource '%s' has no version %d", input.Resource, versionID),
+			}
+		}
+
+		if !svr.Enabled {
+			return nil, InvalidInputOverrideError{
+				Input:  name,
+				Reason: fmt.Sprintf("version %d is disabled", versionID),
+			}
+		}
+
+		pinned = append(pinned, db.BuildInput{
+			Name:              name,
+			VersionedResource: svr.VersionedResource,
+		})
+	}
+
+	return pinned, nil
+}
+
+// findPendingBuild finds a pending build of the job that uses, or will use,
+// the same inputs as a build triggered now with the given pinned inputs.
+//
+// A build whose inputs are not yet determined will use the latest versions
+// for its unpinned inputs, so it is only a match if it pinned the same ones.
+func (s *Scheduler) findPendingBuild(job atc.JobConfig, pinned []db.BuildInput) (db.Build, bool, error) {
+	pending, err := s.PipelineDB.GetPendingJobBuilds(job.Name)
+	if err != nil {
+		return db.Build{}, false, err
+	}
+
+	var candidateHash string
+
+	for _, build := range pending {
+		if !build.InputsDetermined {
+			buildPinned, err := s.PipelineDB.GetPinnedBuildInputs(build.ID)
+			if err != nil {
+				return db.Build{}, false, err
+			}
+
+			if hashInputs(buildPinned) == hashInputs(pinned) {
+				return build, true, nil
+			}
+
+			continue
+		}
+
+		if candidateHash == "" {
+			candidate, err := s.candidateInputs(job, pinned)
+			if err == db.ErrNoVersions {
+				return db.Build{}, false, nil
+			}
+
+			if err != nil {
+				return db.Build{}, false, err
+			}
+
+			candidateHash = hashInputs(candidate)
+		}
+
+		inputs, _, err := s.PipelineDB.GetBuildResources(build.ID)
+		if err != nil {
+			return db.Build{}, false, err
+		}
+
+		if hashInputs(inputs) == candidateHash {
+			return build, true, nil
+		}
+	}
+
+	return db.Build{}, false, nil
+}
+
+// candidateInputs returns the inputs a build triggered now would use, as of
+// the versions already known; no resources are checked.
+func (s *Scheduler) candidateInputs(job atc.JobConfig, pinned []db.BuildInput) ([]db.BuildInput, error) {
+	var unpinned []atc.JobInput
+	for _, input := range job.Inputs() {
+		if !isPinned(pinned, input.Name) {
+			unpinned = append(unpinned, input)
+		}
+	}
+
+	var inputs []db.BuildInput
+
+	if len(unpinned) > 0 {
+		var err error
+		inputs, err = s.PipelineDB.GetLatestInputVersions(job.Name, unpinned)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return append(inputs, pinned...), nil
+}
+
+func lookupJobInput(inputs []atc.JobInput, name string) (atc.JobInput, bool) {
+	for _, input := range inputs {
+		if input.Name == name {
+			return input, true
+		}
+	}
+
+	return atc.JobInput{}, false
+}
+
 func (s *Scheduler) scheduleAndResumePendingBuild(logger lager.Logger, build db.Build, job atc.JobConfig, resources atc.ResourceConfigs) engine.Build {
 	logger = logger.WithData(lager.Data{"build": build.ID})
 
+	// checked before anything that could leave the build pending, so that a
+	// build that can never run doesn't hold up the builds queued after it
+	if !s.inputsAvailable(logger, build) {
+		return nil
+	}
+
+	if !s.Limiter.TryAcquire(s.PipelineDB.ScopedName(job.Name)) {
+		// left pending; a later tick will start it once a slot frees up
+		logger.Debug("build-limit-reached")
+		return nil
+	}
+
+	createdBuild := s.scheduleAndCreateBuild(logger, build, job, resources)
+	if createdBuild == nil {
+		s.Limiter.Release()
+		return nil
+	}
+
+	logger.Info("building")
+
+	go func() {
+		defer s.Limiter.Release()
+		createdBuild.Resume(logger)
+	}()
+
+	return createdBuild
+}
+
+func (s *Scheduler) scheduleAndCreateBuild(logger lager.Logger, build db.Build, job atc.JobConfig, resources atc.ResourceConfigs) engine.Build {
 	scheduled, err := s.PipelineDB.ScheduleBuild(build.ID, job)
 	if err != nil {
 		logger.Error("failed-to-schedule-build", err)
@@ -182,7 +507,78 @@ func (s *Scheduler) scheduleAndResumePendingBuild(logger lager.Logger, build db.
 		return nil
 	}
 
-	buildInputs := job.Inputs()
+	// checked again, as a version may have been disabled while the build
+	// was being scheduled
+	if !s.inputsAvailable(logger, build) {
+		return nil
+	}
+
+	var inputs []db.BuildInput
+	if build.InputsDetermined {
+		inputs, _, err = s.PipelineDB.GetBuildResources(build.ID)
+		if err != nil {
+			logger.Error("failed-to-get-build-inputs", err)
+			return nil
+		}
+	} else {
+		pinned, err := s.PipelineDB.GetPinnedBuildInputs(build.ID)
+		if err != nil {
+			logger.Error("failed-to-get-pinned-build-inputs", err)
+			return nil
+		}
+
+		inputs, err = s.determineInputs(logger, build, job, pinned)
+		if err != nil {
+			return nil
+		}
+	}
+
+	plan, err := s.Factory.Create(job, resources, inputs)
+	if err != nil {
+		logger.Error("failed-to-create-build-plan", err)
+		return nil
+	}
+
+	createdBuild, err := s.Engine.CreateBuild(build, plan)
+	if err != nil {
+		logger.Error("failed-to-create-build", err)
+		return nil
+	}
+
+	return createdBuild
+}
+
+// inputsAvailable errors the build if the version of any input it has saved
+// has since been disabled: those of a build whose inputs are determined, and
+// those pinned when it was triggered.
+func (s *Scheduler) inputsAvailable(logger lager.Logger, build db.Build) bool {
+	unavailable, err := s.PipelineDB.GetUnavailableBuildInputs(build.ID)
+	if err != nil {
+		logger.Error("failed-to-get-unavailable-build-inputs", err)
+		return false
+	}
+
+	if len(unavailable) == 0 {
+		return true
+	}
+
+	logger.Info("inputs-no-longer-available", lager.Data{"inputs": unavailable})
+
+	err = s.BuildsDB.ErrorBuild(build.ID, InputVersionsUnavailableError{Inputs: unavailable})
+	if err != nil {
+		logger.Error("failed-to-mark-build-as-errored", err)
+	}
+
+	return false
+}
+
+func (s *Scheduler) determineInputs(logger lager.Logger, build db.Build, job atc.JobConfig, pinned []db.BuildInput) ([]db.BuildInput, error) {
+	var buildInputs []atc.JobInput
+	for _, input := range job.Inputs() {
+		if !isPinned(pinned, input.Name) {
+			buildInputs = append(buildInputs, input)
+		}
+	}
 
 	for _, input := range buildInputs {
 		scanLog := logger.Session("scan", lager.Data{
@@ -194,45 +590,61 @@ func (s *Scheduler) scheduleAndResumePendingBuild(logger lager.Logger, build db.
 		if err != nil {
 			scanLog.Error("failed-to-scan", err)
 
-			err := s.BuildsDB.ErrorBuild(build.ID, err)
-			if err != nil {
-				logger.Error("failed-to-mark-build-as-errored", err)
+			errorErr := s.BuildsDB.ErrorBuild(build.ID, err)
+			if errorErr != nil {
+				logger.Error("failed-to-mark-build-as-errored", errorErr)
 			}
 
-			return nil
+			return nil, err
 		}
 
 		scanLog.Info("done")
 	}
 
-	inputs, err := s.PipelineDB.GetLatestInputVersions(job.Name, buildInputs)
-	if err != nil {
-		logger.Error("failed-to-get-latest-input-versions", err)
-		return nil
+	var inputs []db.BuildInput
+
+	// every input may have been pinned, leaving nothing to look up
+	if len(buildInputs) > 0 || len(pinned) == 0 {
+		var err error
+		inputs, err = s.PipelineDB.GetLatestInputVersions(job.Name, buildInputs)
+		if err != nil {
+			logger.Error("failed-to-get-latest-input-versions", err)
+			return nil, err
+		}
 	}
 
-	err = s.PipelineDB.UseInputsForBuild(build.ID, inputs)
+	inputs = append(inputs, pinned...)
+
+	err := s.PipelineDB.UseInputsForBuild(build.ID, inputs)
 	if err != nil {
 		logger.Error("failed-to-use-inputs-for-build", err)
-		return nil
+		return nil, err
 	}
 
-	plan, err := s.Factory.Create(job, resources, inputs)
-	if err != nil {
-		logger.Error("failed-to-create-build-plan", err)
-		return nil
-	}
+	return inputs, nil
+}
 
-	createdBuild, err := s.Engine.CreateBuild(build, plan)
-	if err != nil {
-		logger.Error("failed-to-create-build", err)
-		return nil
+func isPinned(pinned []db.BuildInput, name string) bool {
+	for _, input := range pinned {
+		if input.Name == name {
+			return true
+		}
 	}
 
-	if createdBuild != nil {
-		logger.Info("building")
-		go createdBuild.Resume(logger)
+	return false
+}
+
+// hashInputs identifies a set of inputs by each input's name, resource, and
+// version, regardless of their order.
+func hashInputs(inputs []db.BuildInput) string {
+	keys := make([]string, len(inputs))
+	for i, input := range inputs {
+		keys[i] = fmt.Sprintf("%s\x00%s\x00%s", input.Name, input.Resource, atc.Version(input.Version).Hash())
 	}
 
-	return createdBuild
+	sort.Strings(keys)
+
+	hash := sha256.Sum256([]byte(strings.Join(keys, "\n")))
+
+	return hex.EncodeToString(hash[:])
 }
@@ -2,12 +2,16 @@ package scheduler_test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
+	dbfakes "github.com/concourse/atc/db/fakes"
 	enginefakes "github.com/concourse/atc/engine/fakes"
 	. "github.com/concourse/atc/scheduler"
 	"github.com/concourse/atc/scheduler/fakes"
+	"github.com/pivotal-golang/clock/fakeclock"
+	"github.com/pivotal-golang/lager"
 	"github.com/pivotal-golang/lager/lagertest"
 
 	. "github.com/onsi/ginkgo"
@@ -21,6 +25,8 @@ var _ = Describe("Scheduler", func() {
 		factory        *fakes.FakeBuildFactory
 		fakeEngine     *enginefakes.FakeEngine
 		fakeScanner    *fakes.FakeScanner
+		fakeLocker     *fakes.FakeLocker
+		fakeClock      *fakeclock.FakeClock
 
 		createdPlan atc.Plan
 
@@ -38,6 +44,10 @@ var _ = Describe("Scheduler", func() {
 		factory = new(fakes.FakeBuildFactory)
 		fakeEngine = new(enginefakes.FakeEngine)
 		fakeScanner = new(fakes.FakeScanner)
+		fakeLocker = new(fakes.FakeLocker)
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+
+		fakeLocker.AcquireWriteLockReturns(new(dbfakes.FakeLock), nil)
 
 		createdPlan = atc.Plan{
 			Task: &atc.TaskPlan{
@@ -55,6 +65,8 @@ var _ = Describe("Scheduler", func() {
 			Factory:    factory,
 			Engine:     fakeEngine,
 			Scanner:    fakeScanner,
+			Locker:     fakeLocker,
+			Clock:      fakeClock,
 		}
 
 		logger = lagertest.NewTestLogger("test")
@@ -465,6 +477,47 @@ var _ = Describe("Scheduler", func() {
 						Eventually(createdBuild.ResumeCallCount).Should(Equal(1))
 					})
 
+					Context("when builds are limited", func() {
+						var limiter *BuildLimiter
+						var resuming chan struct{}
+
+						BeforeEach(func() {
+							limiter = NewBuildLimiter(1, fakeClock)
+							scheduler.Limiter = limiter
+
+							resuming = make(chan struct{})
+							createdBuild.ResumeStub = func(lager.Logger) {
+								<-resuming
+							}
+						})
+
+						AfterEach(func() {
+							close(resuming)
+						})
+
+						It("holds a slot until the build is done", func() {
+							Eventually(createdBuild.ResumeCallCount).Should(Equal(1))
+							Ω(limiter.TryAcquire("some-other-job")).Should(BeFalse())
+
+							resuming <- struct{}{}
+
+							Eventually(func() bool {
+								return limiter.TryAcquire("some-other-job")
+							}).Should(BeTrue())
+						})
+
+						Context("and the limit has been reached", func() {
+							BeforeEach(func() {
+								Ω(limiter.TryAcquire("some-other-job")).Should(BeTrue())
+							})
+
+							It("leaves the build pending", func() {
+								Ω(fakePipelineDB.ScheduleBuildCallCount()).Should(BeZero())
+								Ω(fakeEngine.CreateBuildCallCount()).Should(BeZero())
+							})
+						})
+					})
+
 					It("scans for new versions for each input, and queries for the latest job inputs", func() {
 						Ω(fakeScanner.ScanCallCount()).Should(Equal(2))
 
@@ -525,6 +578,61 @@ var _ = Describe("Scheduler", func() {
 				})
 			})
 
+			Context("and its inputs have already been determined", func() {
+				BeforeEach(func() {
+					determinedBuild := pendingBuild
+					determinedBuild.InputsDetermined = true
+
+					fakePipelineDB.GetNextPendingBuildReturns(determinedBuild, nil)
+					fakePipelineDB.GetBuildResourcesReturns(pendingInputs, []db.BuildOutput{}, nil)
+					fakePipelineDB.ScheduleBuildReturns(true, nil)
+
+					fakeEngine.CreateBuildReturns(new(enginefakes.FakeBuild), nil)
+				})
+
+				It("builds with the determined inputs, without scanning", func() {
+					Ω(fakeScanner.ScanCallCount()).Should(BeZero())
+					Ω(fakePipelineDB.GetLatestInputVersionsCallCount()).Should(BeZero())
+					Ω(fakePipelineDB.UseInputsForBuildCallCount()).Should(BeZero())
+
+					Ω(fakePipelineDB.GetBuildResourcesArgsForCall(0)).Should(Equal(128))
+
+					Ω(factory.CreateCallCount()).Should(Equal(1))
+					_, _, createInputs := factory.CreateArgsForCall(0)
+					Ω(createInputs).Should(Equal(pendingInputs))
+				})
+
+				Context("when an input version has since been disabled", func() {
+					BeforeEach(func() {
+						fakePipelineDB.GetUnavailableBuildInputsReturns([]string{"some-input"}, nil)
+					})
+
+					It("errors the build instead of running it", func() {
+						Ω(fakePipelineDB.GetUnavailableBuildInputsArgsForCall(0)).Should(Equal(128))
+
+						Ω(fakeBuildsDB.ErrorBuildCallCount()).Should(Equal(1))
+
+						buildID, err := fakeBuildsDB.ErrorBuildArgsForCall(0)
+						Ω(buildID).Should(Equal(128))
+						Ω(err).Should(MatchError("input version no longer available: some-input"))
+
+						Ω(factory.CreateCallCount()).Should(BeZero())
+						Ω(fakeEngine.CreateBuildCallCount()).Should(BeZero())
+					})
+				})
+
+				Context("when looking up unavailable inputs fails", func() {
+					BeforeEach(func() {
+						fakePipelineDB.GetUnavailableBuildInputsReturns(nil, errors.New("oh no"))
+					})
+
+					It("does not start the build", func() {
+						Ω(fakeBuildsDB.ErrorBuildCallCount()).Should(BeZero())
+						Ω(fakeEngine.CreateBuildCallCount()).Should(BeZero())
+					})
+				})
+			})
+
 			Context("when the build cannot be scheduled", func() {
 				BeforeEach(func() {
 					fakePipelineDB.ScheduleBuildReturns(false, nil)
@@ -543,6 +651,42 @@ var _ = Describe("Scheduler", func() {
 						Ω(fakeScanner.ScanCallCount()).Should(Equal(0))
 					})
 				})
+
+				Context("and an input version it has saved has since been disabled", func() {
+					BeforeEach(func() {
+						fakePipelineDB.GetUnavailableBuildInputsReturns([]string{"some-input"}, nil)
+					})
+
+					It("errors the build rather than leaving it pending", func() {
+						Ω(fakePipelineDB.ScheduleBuildCallCount()).Should(BeZero())
+
+						Ω(fakeBuildsDB.ErrorBuildCallCount()).Should(Equal(1))
+
+						buildID, err := fakeBuildsDB.ErrorBuildArgsForCall(0)
+						Ω(buildID).Should(Equal(128))
+						Ω(err).Should(MatchError("input version no longer available: some-input"))
+					})
+				})
+			})
+
+			Context("when the build limit has been reached", func() {
+				BeforeEach(func() {
+					limiter := NewBuildLimiter(1, fakeClock)
+					Ω(limiter.TryAcquire("some-other-job")).Should(BeTrue())
+
+					scheduler.Limiter = limiter
+				})
+
+				Context("and an input version it has saved has since been disabled", func() {
+					BeforeEach(func() {
+						fakePipelineDB.GetUnavailableBuildInputsReturns([]string{"some-input"}, nil)
+					})
+
+					It("errors the build rather than leaving it pending", func() {
+						Ω(fakeBuildsDB.ErrorBuildCallCount()).Should(Equal(1))
+						Ω(fakePipelineDB.ScheduleBuildCallCount()).Should(BeZero())
+					})
+				})
 			})
 		})
 
@@ -571,6 +715,156 @@ var _ = Describe("Scheduler", func() {
 		})
 	})
 
+	Describe("RetrySystemErroredBuild", func() {
+		var (
+			finishedBuild db.Build
+			retryBuild    db.Build
+
+			retryErr error
+		)
+
+		BeforeEach(func() {
+			scheduler.MaxSystemRetries = 1
+
+			finishedBuild = db.Build{
+				ID:            128,
+				Name:          "42",
+				JobName:       "some-job",
+				Status:        db.StatusErrored,
+				ErrorCategory: "system",
+				Attempt:       1,
+			}
+
+			retryBuild = db.Build{
+				ID:               129,
+				Name:             "43",
+				JobName:          "some-job",
+				Status:           db.StatusPending,
+				InputsDetermined: true,
+				Attempt:          2,
+			}
+
+			fakePipelineDB.GetJobFinishedAndNextBuildReturns(&finishedBuild, nil, nil)
+			fakePipelineDB.CreateJobBuildRetryReturns(retryBuild, true, nil)
+			fakePipelineDB.ScheduleBuildReturns(true, nil)
+		})
+
+		JustBeforeEach(func() {
+			retryErr = scheduler.RetrySystemErroredBuild(logger, job, resources)
+		})
+
+		Context("when the latest build errored with a system error", func() {
+			var createdBuild *enginefakes.FakeBuild
+
+			BeforeEach(func() {
+				createdBuild = new(enginefakes.FakeBuild)
+				fakeEngine.CreateBuildReturns(createdBuild, nil)
+			})
+
+			It("creates a retry of the build", func() {
+				Ω(retryErr).ShouldNot(HaveOccurred())
+
+				Ω(fakePipelineDB.GetJobFinishedAndNextBuildArgsForCall(0)).Should(Equal("some-job"))
+
+				Ω(fakePipelineDB.CreateJobBuildRetryCallCount()).Should(Equal(1))
+				Ω(fakePipelineDB.CreateJobBuildRetryArgsForCall(0)).Should(Equal(finishedBuild))
+			})
+
+			It("starts the retry without determining new inputs", func() {
+				Ω(fakeEngine.CreateBuildCallCount()).Should(Equal(1))
+				builtBuild, _ := fakeEngine.CreateBuildArgsForCall(0)
+				Ω(builtBuild).Should(Equal(retryBuild))
+
+				Ω(fakePipelineDB.GetBuildResourcesArgsForCall(0)).Should(Equal(129))
+				Ω(fakeScanner.ScanCallCount()).Should(BeZero())
+
+				Eventually(createdBuild.ResumeCallCount).Should(Equal(1))
+			})
+
+			Context("when the build has already been retried", func() {
+				BeforeEach(func() {
+					fakePipelineDB.CreateJobBuildRetryReturns(db.Build{}, false, nil)
+				})
+
+				It("does not start a build", func() {
+					Ω(retryErr).ShouldNot(HaveOccurred())
+					Ω(fakeEngine.CreateBuildCallCount()).Should(BeZero())
+				})
+			})
+
+			Context("when creating the retry fails", func() {
+				disaster := errors.New("nope")
+
+				BeforeEach(func() {
+					fakePipelineDB.CreateJobBuildRetryReturns(db.Build{}, false, disaster)
+				})
+
+				It("returns the error", func() {
+					Ω(retryErr).Should(Equal(disaster))
+					Ω(fakeEngine.CreateBuildCallCount()).Should(BeZero())
+				})
+			})
+
+			Context("when the build is out of retries", func() {
+				BeforeEach(func() {
+					finishedBuild.Attempt = 2
+				})
+
+				It("does not retry it", func() {
+					Ω(retryErr).ShouldNot(HaveOccurred())
+					Ω(fakePipelineDB.CreateJobBuildRetryCallCount()).Should(BeZero())
+				})
+			})
+
+			Context("when retries are disabled", func() {
+				BeforeEach(func() {
+					scheduler.MaxSystemRetries = 0
+				})
+
+				It("does not retry it", func() {
+					Ω(retryErr).ShouldNot(HaveOccurred())
+					Ω(fakePipelineDB.CreateJobBuildRetryCallCount()).Should(BeZero())
+				})
+			})
+		})
+
+		Context("when the latest build errored with a user error", func() {
+			BeforeEach(func() {
+				finishedBuild.ErrorCategory = "user"
+			})
+
+			It("does not retry it", func() {
+				Ω(retryErr).ShouldNot(HaveOccurred())
+				Ω(fakePipelineDB.CreateJobBuildRetryCallCount()).Should(BeZero())
+				Ω(fakeEngine.CreateBuildCallCount()).Should(BeZero())
+			})
+		})
+
+		Context("when the latest build failed", func() {
+			BeforeEach(func() {
+				finishedBuild.Status = db.StatusFailed
+				finishedBuild.ErrorCategory = ""
+			})
+
+			It("does not retry it", func() {
+				Ω(retryErr).ShouldNot(HaveOccurred())
+				Ω(fakePipelineDB.CreateJobBuildRetryCallCount()).Should(BeZero())
+			})
+		})
+
+		Context("when getting the latest build fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakePipelineDB.GetJobFinishedAndNextBuildReturns(nil, nil, disaster)
+			})
+
+			It("returns the error", func() {
+				Ω(retryErr).Should(Equal(disaster))
+			})
+		})
+	})
+
 	Describe("TriggerImmediately", func() {
 		It("creates a build without any specific inputs", func() {
 			_, err := scheduler.TriggerImmediately(logger, job, resources)
@@ -653,6 +947,137 @@ var _ = Describe("Scheduler", func() {
 			})
 		})
 
+		It("creates the build while holding the job's scheduling lock", func() {
+			fakePipelineDB.ScopedNameStub = func(name string) string {
+				return "some-pipeline:" + name
+			}
+
+			lock := new(dbfakes.FakeLock)
+			fakeLocker.AcquireWriteLockStub = func([]db.NamedLock) (db.Lock, error) {
+				Ω(fakePipelineDB.CreateJobBuildCallCount()).Should(BeZero())
+				return lock, nil
+			}
+
+			_, err := scheduler.TriggerImmediately(logger, job, resources)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(fakeLocker.AcquireWriteLockCallCount()).Should(Equal(1))
+			Ω(fakeLocker.AcquireWriteLockArgsForCall(0)).Should(Equal([]db.NamedLock{
+				db.JobSchedulingLock("some-pipeline:some-job"),
+			}))
+
+			Ω(fakePipelineDB.CreateJobBuildCallCount()).Should(Equal(1))
+			Ω(lock.ReleaseCallCount()).Should(Equal(1))
+		})
+
+		Context("when triggered twice in quick succession", func() {
+			BeforeEach(func() {
+				fakePipelineDB.CreateJobBuildStub = func(string) (db.Build, error) {
+					build := db.Build{ID: 128, Name: "42", Status: db.StatusPending}
+					fakePipelineDB.GetPendingJobBuildsReturns([]db.Build{build}, nil)
+					return build, nil
+				}
+			})
+
+			It("creates only one build, returning it for both triggers", func() {
+				firstBuild, err := scheduler.TriggerImmediately(logger, job, resources)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				secondBuild, err := scheduler.TriggerImmediately(logger, job, resources)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(fakePipelineDB.CreateJobBuildCallCount()).Should(Equal(1))
+				Ω(secondBuild).Should(Equal(firstBuild))
+			})
+		})
+
+		Context("when a pending build with determined inputs exists", func() {
+			var pendingBuild db.Build
+
+			BeforeEach(func() {
+				pendingBuild = db.Build{ID: 127, Name: "41", Status: db.StatusPending, InputsDetermined: true}
+				fakePipelineDB.GetPendingJobBuildsReturns([]db.Build{pendingBuild}, nil)
+
+				fakePipelineDB.GetBuildResourcesReturns([]db.BuildInput{
+					{
+						Name: "some-other-input",
+						VersionedResource: db.VersionedResource{
+							Resource: "some-other-resource",
+							Version:  db.Version{"ref": "def"},
+						},
+					},
+					{
+						Name: "some-input",
+						VersionedResource: db.VersionedResource{
+							Resource: "some-resource",
+							Version:  db.Version{"ref": "abc"},
+						},
+					},
+				}, nil, nil)
+
+				fakePipelineDB.CreateJobBuildReturns(db.Build{ID: 128, Name: "42"}, nil)
+			})
+
+			Context("and it has the same inputs as the latest versions", func() {
+				BeforeEach(func() {
+					fakePipelineDB.GetLatestInputVersionsReturns([]db.BuildInput{
+						{
+							Name: "some-input",
+							VersionedResource: db.VersionedResource{
+								Resource: "some-resource",
+								Version:  db.Version{"ref": "abc"},
+							},
+						},
+						{
+							Name: "some-other-input",
+							VersionedResource: db.VersionedResource{
+								Resource: "some-other-resource",
+								Version:  db.Version{"ref": "def"},
+							},
+						},
+					}, nil)
+				})
+
+				It("returns the pending build rather than creating another", func() {
+					build, err := scheduler.TriggerImmediately(logger, job, resources)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(build).Should(Equal(pendingBuild))
+
+					Ω(fakePipelineDB.GetBuildResourcesArgsForCall(0)).Should(Equal(127))
+					Ω(fakePipelineDB.CreateJobBuildCallCount()).Should(BeZero())
+				})
+			})
+
+			Context("and a newer version of an input is available", func() {
+				BeforeEach(func() {
+					fakePipelineDB.GetLatestInputVersionsReturns([]db.BuildInput{
+						{
+							Name: "some-input",
+							VersionedResource: db.VersionedResource{
+								Resource: "some-resource",
+								Version:  db.Version{"ref": "newer"},
+							},
+						},
+						{
+							Name: "some-other-input",
+							VersionedResource: db.VersionedResource{
+								Resource: "some-other-resource",
+								Version:  db.Version{"ref": "def"},
+							},
+						},
+					}, nil)
+				})
+
+				It("creates a new build", func() {
+					build, err := scheduler.TriggerImmediately(logger, job, resources)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(build).Should(Equal(db.Build{ID: 128, Name: "42"}))
+
+					Ω(fakePipelineDB.CreateJobBuildCallCount()).Should(Equal(1))
+				})
+			})
+		})
+
 		Context("when creating the build fails", func() {
 			disaster := errors.New("oh no!")
 
@@ -671,4 +1096,388 @@ var _ = Describe("Scheduler", func() {
 			})
 		})
 	})
+
+	Describe("PreviewPlan", func() {
+		var latestInputs []db.BuildInput
+
+		BeforeEach(func() {
+			latestInputs = []db.BuildInput{
+				{
+					Name: "some-input",
+					VersionedResource: db.VersionedResource{
+						Resource: "some-resource",
+						Version:  db.Version{"ref": "abc"},
+					},
+				},
+				{
+					Name: "some-other-input",
+					VersionedResource: db.VersionedResource{
+						Resource: "some-other-resource",
+						Version:  db.Version{"ref": "def"},
+					},
+				},
+			}
+
+			fakePipelineDB.GetLatestInputVersionsReturns(latestInputs, nil)
+
+			factory.CreateStub = func(job atc.JobConfig, resources atc.ResourceConfigs, inputs []db.BuildInput) (atc.Plan, error) {
+				var gets atc.AggregatePlan
+				for _, input := range inputs {
+					gets = append(gets, atc.Plan{
+						Get: &atc.GetPlan{
+							Name:     input.Name,
+							Resource: input.Resource,
+							Version:  atc.Version(input.Version),
+						},
+					})
+				}
+
+				return atc.Plan{Aggregate: &gets}, nil
+			}
+		})
+
+		It("creates the plan using the latest versions of the job's inputs", func() {
+			plan, err := scheduler.PreviewPlan(logger, job, resources)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(fakePipelineDB.GetLatestInputVersionsCallCount()).Should(Equal(1))
+			jobName, inputs := fakePipelineDB.GetLatestInputVersionsArgsForCall(0)
+			Ω(jobName).Should(Equal("some-job"))
+			Ω(inputs).Should(Equal(job.Inputs()))
+
+			Ω(factory.CreateCallCount()).Should(Equal(1))
+			createJob, createResources, createInputs := factory.CreateArgsForCall(0)
+			Ω(createJob).Should(Equal(job))
+			Ω(createResources).Should(Equal(resources))
+			Ω(createInputs).Should(Equal(latestInputs))
+
+			Ω(*plan.Aggregate).Should(HaveLen(2))
+		})
+
+		It("does not create a build or check any resources", func() {
+			_, err := scheduler.PreviewPlan(logger, job, resources)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(fakePipelineDB.CreateJobBuildCallCount()).Should(BeZero())
+			Ω(fakePipelineDB.UseInputsForBuildCallCount()).Should(BeZero())
+			Ω(fakeScanner.ScanCallCount()).Should(BeZero())
+			Ω(fakeEngine.CreateBuildCallCount()).Should(BeZero())
+		})
+
+		It("matches the plan a build triggered now would run", func() {
+			preview, err := scheduler.PreviewPlan(logger, job, resources)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			fakePipelineDB.CreateJobBuildReturns(db.Build{ID: 128, Name: "42"}, nil)
+			fakePipelineDB.ScheduleBuildReturns(true, nil)
+			fakeEngine.CreateBuildReturns(new(enginefakes.FakeBuild), nil)
+
+			_, err = scheduler.TriggerImmediately(logger, job, resources)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Eventually(fakeEngine.CreateBuildCallCount).Should(Equal(1))
+			_, plan := fakeEngine.CreateBuildArgsForCall(0)
+			Ω(plan).Should(Equal(preview))
+		})
+
+		Context("when the job has no versions for its inputs", func() {
+			BeforeEach(func() {
+				fakePipelineDB.GetLatestInputVersionsReturns(nil, db.ErrNoVersions)
+			})
+
+			It("returns the error without creating a plan", func() {
+				_, err := scheduler.PreviewPlan(logger, job, resources)
+				Ω(err).Should(Equal(db.ErrNoVersions))
+
+				Ω(factory.CreateCallCount()).Should(BeZero())
+			})
+		})
+
+		Context("when creating the plan fails", func() {
+			disaster := errors.New("oh no!")
+
+			BeforeEach(func() {
+				factory.CreateReturns(atc.Plan{}, disaster)
+			})
+
+			It("returns the error", func() {
+				_, err := scheduler.PreviewPlan(logger, job, resources)
+				Ω(err).Should(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("TriggerWithInputOverrides", func() {
+		var overriddenVersion db.SavedVersionedResource
+		var savedPinnedInputs []db.BuildInput
+
+		BeforeEach(func() {
+			overriddenVersion = db.SavedVersionedResource{
+				ID:      42,
+				Enabled: true,
+				VersionedResource: db.VersionedResource{
+					Resource: "some-other-resource",
+					Type:     "git",
+					Version:  db.Version{"ref": "pinned"},
+				},
+			}
+
+			savedPinnedInputs = nil
+
+			fakePipelineDB.GetVersionedResourceReturns(overriddenVersion, true, nil)
+			fakePipelineDB.CreateJobBuildWithPinnedInputsStub = func(job string, inputs []db.BuildInput) (db.Build, error) {
+				savedPinnedInputs = inputs
+				return db.Build{ID: 128, Name: "42"}, nil
+			}
+			fakePipelineDB.GetPinnedBuildInputsStub = func(buildID int) ([]db.BuildInput, error) {
+				return savedPinnedInputs, nil
+			}
+			fakePipelineDB.ScheduleBuildReturns(true, nil)
+			fakePipelineDB.GetLatestInputVersionsReturns([]db.BuildInput{
+				{
+					Name: "some-input",
+					VersionedResource: db.VersionedResource{
+						Resource: "some-resource",
+						Version:  db.Version{"ref": "latest"},
+					},
+				},
+			}, nil)
+
+			fakeEngine.CreateBuildReturns(new(enginefakes.FakeBuild), nil)
+		})
+
+		It("uses the overridden version, determining only the other inputs", func() {
+			build, err := scheduler.TriggerWithInputOverrides(logger, job, resources, InputOverrides{
+				"some-other-input": 42,
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(build).Should(Equal(db.Build{ID: 128, Name: "42"}))
+
+			Ω(fakePipelineDB.GetVersionedResourceArgsForCall(0)).Should(Equal(42))
+
+			Eventually(fakePipelineDB.UseInputsForBuildCallCount).Should(Equal(1))
+
+			Ω(fakeScanner.ScanCallCount()).Should(Equal(1))
+			_, scanned := fakeScanner.ScanArgsForCall(0)
+			Ω(scanned).Should(Equal("some-resource"))
+
+			_, determinedInputs := fakePipelineDB.GetLatestInputVersionsArgsForCall(0)
+			Ω(determinedInputs).Should(Equal([]atc.JobInput{
+				{
+					Name:     "some-input",
+					Resource: "some-resource",
+					Trigger:  true,
+				},
+			}))
+
+			expectedInputs := []db.BuildInput{
+				{
+					Name: "some-input",
+					VersionedResource: db.VersionedResource{
+						Resource: "some-resource",
+						Version:  db.Version{"ref": "latest"},
+					},
+				},
+				{
+					Name:              "some-other-input",
+					VersionedResource: overriddenVersion.VersionedResource,
+				},
+			}
+
+			usedBuildID, usedInputs := fakePipelineDB.UseInputsForBuildArgsForCall(0)
+			Ω(usedBuildID).Should(Equal(128))
+			Ω(usedInputs).Should(Equal(expectedInputs))
+
+			Eventually(factory.CreateCallCount).Should(Equal(1))
+			_, _, createInputs := factory.CreateArgsForCall(0)
+			Ω(createInputs).Should(Equal(expectedInputs))
+		})
+
+		It("saves the overridden versions with the build", func() {
+			_, err := scheduler.TriggerWithInputOverrides(logger, job, resources, InputOverrides{
+				"some-other-input": 42,
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(fakePipelineDB.CreateJobBuildCallCount()).Should(BeZero())
+			Ω(fakePipelineDB.CreateJobBuildWithPinnedInputsCallCount()).Should(Equal(1))
+
+			jobName, pinned := fakePipelineDB.CreateJobBuildWithPinnedInputsArgsForCall(0)
+			Ω(jobName).Should(Equal(job.Name))
+			Ω(pinned).Should(Equal([]db.BuildInput{
+				{
+					Name:              "some-other-input",
+					VersionedResource: overriddenVersion.VersionedResource,
+				},
+			}))
+
+			Eventually(fakePipelineDB.UseInputsForBuildCallCount).Should(Equal(1))
+		})
+
+		Context("when the build cannot be scheduled at first", func() {
+			BeforeEach(func() {
+				fakePipelineDB.ScheduleBuildReturns(false, nil)
+			})
+
+			It("uses the overridden version once the pending build is scheduled", func() {
+				build, err := scheduler.TriggerWithInputOverrides(logger, job, resources, InputOverrides{
+					"some-other-input": 42,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Eventually(fakePipelineDB.ScheduleBuildCallCount).Should(Equal(1))
+				Consistently(fakePipelineDB.UseInputsForBuildCallCount).Should(BeZero())
+
+				fakePipelineDB.ScheduleBuildReturns(true, nil)
+				fakePipelineDB.GetNextPendingBuildReturns(build, nil)
+
+				scheduler.TryNextPendingBuild(logger, job, resources).Wait()
+
+				Ω(fakePipelineDB.GetPinnedBuildInputsArgsForCall(0)).Should(Equal(128))
+
+				Ω(fakePipelineDB.UseInputsForBuildCallCount()).Should(Equal(1))
+				_, usedInputs := fakePipelineDB.UseInputsForBuildArgsForCall(0)
+				Ω(usedInputs).Should(ContainElement(db.BuildInput{
+					Name:              "some-other-input",
+					VersionedResource: overriddenVersion.VersionedResource,
+				}))
+			})
+		})
+
+		Context("when a pending build with the same overrides exists", func() {
+			BeforeEach(func() {
+				fakePipelineDB.ScheduleBuildReturns(false, nil)
+			})
+
+			It("returns the pending build rather than creating another", func() {
+				build, err := scheduler.TriggerWithInputOverrides(logger, job, resources, InputOverrides{
+					"some-other-input": 42,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				fakePipelineDB.GetPendingJobBuildsReturns([]db.Build{build}, nil)
+
+				again, err := scheduler.TriggerWithInputOverrides(logger, job, resources, InputOverrides{
+					"some-other-input": 42,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(again).Should(Equal(build))
+
+				Ω(fakePipelineDB.CreateJobBuildWithPinnedInputsCallCount()).Should(Equal(1))
+			})
+
+			It("does not match a trigger without the overrides", func() {
+				build, err := scheduler.TriggerWithInputOverrides(logger, job, resources, InputOverrides{
+					"some-other-input": 42,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				fakePipelineDB.GetPendingJobBuildsReturns([]db.Build{build}, nil)
+				fakePipelineDB.CreateJobBuildReturns(db.Build{ID: 129, Name: "43"}, nil)
+
+				other, err := scheduler.TriggerImmediately(logger, job, resources)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(other.ID).Should(Equal(129))
+			})
+		})
+
+		Context("when every input is overridden", func() {
+			BeforeEach(func() {
+				fakePipelineDB.GetVersionedResourceStub = func(id int) (db.SavedVersionedResource, bool, error) {
+					if id == 41 {
+						return db.SavedVersionedResource{
+							ID:      41,
+							Enabled: true,
+							VersionedResource: db.VersionedResource{
+								Resource: "some-resource",
+								Version:  db.Version{"ref": "also-pinned"},
+							},
+						}, true, nil
+					}
+
+					return overriddenVersion, true, nil
+				}
+			})
+
+			It("does not look up any latest versions", func() {
+				_, err := scheduler.TriggerWithInputOverrides(logger, job, resources, InputOverrides{
+					"some-input":       41,
+					"some-other-input": 42,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Eventually(fakePipelineDB.UseInputsForBuildCallCount).Should(Equal(1))
+				Ω(fakeScanner.ScanCallCount()).Should(BeZero())
+				Ω(fakePipelineDB.GetLatestInputVersionsCallCount()).Should(BeZero())
+
+				_, usedInputs := fakePipelineDB.UseInputsForBuildArgsForCall(0)
+				Ω(usedInputs).Should(HaveLen(2))
+			})
+		})
+
+		Context("when the overridden version is disabled", func() {
+			BeforeEach(func() {
+				overriddenVersion.Enabled = false
+				fakePipelineDB.GetVersionedResourceReturns(overriddenVersion, true, nil)
+			})
+
+			It("returns an error without creating a build", func() {
+				_, err := scheduler.TriggerWithInputOverrides(logger, job, resources, InputOverrides{
+					"some-other-input": 42,
+				})
+				Ω(err).Should(Equal(InvalidInputOverrideError{
+					Input:  "some-other-input",
+					Reason: "version 42 is disabled",
+				}))
+
+				Ω(fakePipelineDB.CreateJobBuildWithPinnedInputsCallCount()).Should(BeZero())
+			})
+		})
+
+		Context("when the overridden version does not exist", func() {
+			BeforeEach(func() {
+				fakePipelineDB.GetVersionedResourceReturns(db.SavedVersionedResource{}, false, nil)
+			})
+
+			It("returns an error without creating a build", func() {
+				_, err := scheduler.TriggerWithInputOverrides(logger, job, resources, InputOverrides{
+					"some-other-input": 42,
+				})
+				Ω(err).Should(Equal(InvalidInputOverrideError{
+					Input:  "some-other-input",
+					Reason: "resource 'some-other-resource' has no version 42",
+				}))
+
+				Ω(fakePipelineDB.CreateJobBuildWithPinnedInputsCallCount()).Should(BeZero())
+			})
+		})
+
+		Context("when the overridden version belongs to another resource", func() {
+			It("returns an error without creating a build", func() {
+				_, err := scheduler.TriggerWithInputOverrides(logger, job, resources, InputOverrides{
+					"some-input": 42,
+				})
+				Ω(err).Should(Equal(InvalidInputOverrideError{
+					Input:  "some-input",
+					Reason: "resource 'some-resource' has no version 42",
+				}))
+
+				Ω(fakePipelineDB.CreateJobBuildWithPinnedInputsCallCount()).Should(BeZero())
+			})
+		})
+
+		Context("when the overridden input does not exist", func() {
+			It("returns an error without creating a build", func() {
+				_, err := scheduler.TriggerWithInputOverrides(logger, job, resources, InputOverrides{
+					"bogus-input": 42,
+				})
+				Ω(err).Should(Equal(InvalidInputOverrideError{
+					Input:  "bogus-input",
+					Reason: "job has no such input",
+				}))
+
+				Ω(fakePipelineDB.CreateJobBuildWithPinnedInputsCallCount()).Should(BeZero())
+			})
+		})
+	})
 })
@@ -1,9 +1,6 @@
 package atc
 
-import (
-	"fmt"
-	"strings"
-)
+import "strings"
 
 type Build struct {
 	ID      int    `json:"id"`
@@ -34,6 +31,11 @@ type TaskConfig struct {
 	// platform, this may or may not be required (e.g. Windows/OS X vs. Linux).
 	Image string `json:"image,omitempty"   yaml:"image,omitempty"`
 
+	// Optional name of an input containing a fetched Docker image to use
+	// instead of Image, i.e. a get of a docker-image resource. The rootfs is
+	// the image's 'repository', pinned to the 'digest' that was fetched.
+	ImageResource string `json:"image_resource,omitempty" yaml:"image_resource,omitempty"`
+
 	// Parameters to pass to the task via environment variables.
 	Params map[string]string `json:"params,omitempty"  yaml:"params,omitempty"`
 
@@ -42,6 +44,10 @@ type TaskConfig struct {
 
 	// The set of (logical, name-only) inputs required by the task.
 	Inputs []TaskInputConfig `json:"inputs,omitempty"  yaml:"inputs,omitempty"`
+
+	// The set of (logical, name-only) outputs produced by the task, made
+	// available to later steps.
+	Outputs []TaskOutputConfig `json:"outputs,omitempty" yaml:"outputs,omitempty"`
 }
 
 func (a TaskConfig) Merge(b TaskConfig) TaskConfig {
@@ -53,6 +59,10 @@ func (a TaskConfig) Merge(b TaskConfig) TaskConfig {
 		a.Image = b.Image
 	}
 
+	if b.ImageResource != "" {
+		a.ImageResource = b.ImageResource
+	}
+
 	if len(a.Params) > 0 {
 		newParams := map[string]string{}
 
@@ -94,6 +104,10 @@ func (a TaskConfig) Merge(b TaskConfig) TaskConfig {
 		a.Inputs = b.Inputs
 	}
 
+	if len(b.Outputs) != 0 {
+		a.Outputs = b.Outputs
+	}
+
 	if b.Run.Path != "" {
 		a.Run = b.Run
 	}
@@ -101,22 +115,28 @@ func (a TaskConfig) Merge(b TaskConfig) TaskConfig {
 	return a
 }
 
-func (config TaskConfig) Validate() error {
-	messages := []string{"invalid task configuration:"}
+// InvalidTaskConfigError is returned when a task's config is missing what it
+// needs to run. Running the task again will not help.
+type InvalidTaskConfigError struct {
+	Errors []string
+}
 
-	var invalid bool
+func (err InvalidTaskConfigError) Error() string {
+	return "invalid task configuration:\n  " + strings.Join(err.Errors, "\n  ")
+}
+
+func (config TaskConfig) Validate() error {
+	var errors []string
 	if config.Platform == "" {
-		messages = append(messages, "  missing 'platform'")
-		invalid = true
+		errors = append(errors, "missing 'platform'")
 	}
 
 	if config.Run.Path == "" {
-		messages = append(messages, "  missing path to executable to run")
-		invalid = true
+		errors = append(errors, "missing path to executable to run")
 	}
 
-	if invalid {
-		return fmt.Errorf(strings.Join(messages, "\n"))
+	if len(errors) > 0 {
+		return InvalidTaskConfigError{Errors: errors}
 	}
 
 	return nil
@@ -125,11 +145,46 @@ func (config TaskConfig) Validate() error {
 type TaskRunConfig struct {
 	Path string   `json:"path" yaml:"path"`
 	Args []string `json:"args,omitempty" yaml:"args"`
+
+	// the working directory, relative to the task's build directory unless
+	// absolute; defaults to the build directory itself
+	Dir string `json:"dir,omitempty" yaml:"dir,omitempty"`
+
+	// the user to run as; defaults to root
+	User string `json:"user,omitempty" yaml:"user,omitempty"`
+
+	// values to append to environment variables, separated by ':', e.g. to
+	// add to PATH. each is appended to the param of the same name if there
+	// is one, and otherwise to the variable's value in the image.
+	//
+	// params are otherwise layered on top of the image's environment,
+	// replacing any variables of the same name.
+	AppendEnv map[string]string `json:"append_env,omitempty" yaml:"append_env,omitempty"`
+
+	// controls the TTY allocated to the process; defaults to a TTY of the
+	// default size
+	TTY *TaskTTYConfig `json:"tty,omitempty" yaml:"tty,omitempty"`
+}
+
+type TaskTTYConfig struct {
+	// run the process without a TTY
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+
+	Columns int `json:"columns,omitempty" yaml:"columns,omitempty"`
+	Rows    int `json:"rows,omitempty" yaml:"rows,omitempty"`
 }
 
 type TaskInputConfig struct {
 	Name string `json:"name" yaml:"name"`
 	Path string `json:"path,omitempty" yaml:"path"`
+
+	// if set, the task runs without the input when it is not present
+	Optional bool `json:"optional,omitempty" yaml:"optional,omitempty"`
+}
+
+type TaskOutputConfig struct {
+	Name string `json:"name" yaml:"name"`
+	Path string `json:"path,omitempty" yaml:"path"`
 }
 
 type MetadataField struct {
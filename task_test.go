@@ -29,6 +29,9 @@ var _ = Describe("TaskConfig", func() {
 
 			It("returns an error", func() {
 				Ω(invalidConfig.Validate()).Should(MatchError(ContainSubstring("missing 'platform'")))
+				Ω(invalidConfig.Validate()).Should(Equal(InvalidTaskConfigError{
+					Errors: []string{"missing 'platform'"},
+				}))
 			})
 		})
 
@@ -94,6 +97,16 @@ var _ = Describe("TaskConfig", func() {
 			}))
 		})
 
+		It("overrides the image resource", func() {
+			Ω(TaskConfig{
+				ImageResource: "some-image-resource",
+			}.Merge(TaskConfig{
+				ImageResource: "better-image-resource",
+			})).Should(Equal(TaskConfig{
+				ImageResource: "better-image-resource",
+			}))
+		})
+
 		It("overrides the run config", func() {
 			Ω(TaskConfig{
 				Run: TaskRunConfig{
@@ -149,5 +162,21 @@ var _ = Describe("TaskConfig", func() {
 				},
 			}))
 		})
+
+		It("overrides output configuration", func() {
+			Ω(TaskConfig{
+				Outputs: []TaskOutputConfig{
+					{Name: "some-output", Path: "some-destination"},
+				},
+			}.Merge(TaskConfig{
+				Outputs: []TaskOutputConfig{
+					{Name: "another-output", Path: "another-destination"},
+				},
+			})).Should(Equal(TaskConfig{
+				Outputs: []TaskOutputConfig{
+					{Name: "another-output", Path: "another-destination"},
+				},
+			}))
+		})
 	})
 })
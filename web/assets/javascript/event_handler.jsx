@@ -101,6 +101,10 @@ var eventHandlers = {
         processError(data);
       },
 
+      "drain": function(data) {
+        processError(data);
+      },
+
       "status": function(data) {
         processStatus(data);
       },
@@ -55,6 +55,16 @@ type FakeResourcesDB struct {
 		result1 int
 		result2 error
 	}
+	GetResourceCheckHistoryStub        func(db.SavedResource, int) ([]db.ResourceCheckResult, error)
+	getResourceCheckHistoryMutex       sync.RWMutex
+	getResourceCheckHistoryArgsForCall []struct {
+		arg1 db.SavedResource
+		arg2 int
+	}
+	getResourceCheckHistoryReturns struct {
+		result1 []db.ResourceCheckResult
+		result2 error
+	}
 }
 
 func (fake *FakeResourcesDB) GetPipelineName() string {
@@ -210,4 +220,38 @@ func (fake *FakeResourcesDB) GetResourceHistoryMaxIDReturns(result1 int, result2
 	}{result1, result2}
 }
 
+func (fake *FakeResourcesDB) GetResourceCheckHistory(arg1 db.SavedResource, arg2 int) ([]db.ResourceCheckResult, error) {
+	fake.getResourceCheckHistoryMutex.Lock()
+	fake.getResourceCheckHistoryArgsForCall = append(fake.getResourceCheckHistoryArgsForCall, struct {
+		arg1 db.SavedResource
+		arg2 int
+	}{arg1, arg2})
+	fake.getResourceCheckHistoryMutex.Unlock()
+	if fake.GetResourceCheckHistoryStub != nil {
+		return fake.GetResourceCheckHistoryStub(arg1, arg2)
+	} else {
+		return fake.getResourceCheckHistoryReturns.result1, fake.getResourceCheckHistoryReturns.result2
+	}
+}
+
+func (fake *FakeResourcesDB) GetResourceCheckHistoryCallCount() int {
+	fake.getResourceCheckHistoryMutex.RLock()
+	defer fake.getResourceCheckHistoryMutex.RUnlock()
+	return len(fake.getResourceCheckHistoryArgsForCall)
+}
+
+func (fake *FakeResourcesDB) GetResourceCheckHistoryArgsForCall(i int) (db.SavedResource, int) {
+	fake.getResourceCheckHistoryMutex.RLock()
+	defer fake.getResourceCheckHistoryMutex.RUnlock()
+	return fake.getResourceCheckHistoryArgsForCall[i].arg1, fake.getResourceCheckHistoryArgsForCall[i].arg2
+}
+
+func (fake *FakeResourcesDB) GetResourceCheckHistoryReturns(result1 []db.ResourceCheckResult, result2 error) {
+	fake.GetResourceCheckHistoryStub = nil
+	fake.getResourceCheckHistoryReturns = struct {
+		result1 []db.ResourceCheckResult
+		result2 error
+	}{result1, result2}
+}
+
 var _ getresource.ResourcesDB = new(FakeResourcesDB)
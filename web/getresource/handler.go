@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/api/present"
@@ -37,8 +38,15 @@ type TemplateData struct {
 	Resource atc.Resource
 	History  []*db.VersionHistory
 
+	// each version's metadata marked up for display, by versioned resource ID
+	Metadata map[int][]present.MetadataField
+
 	FailingToCheck bool
 	CheckError     error
+	CheckHistory   []db.ResourceCheckResult
+
+	LastChecked      time.Time
+	LastCheckErrored time.Time
 
 	GroupStates  []group.State
 	PipelineName string
@@ -62,8 +70,12 @@ type ResourcesDB interface {
 	GetResource(string) (db.SavedResource, error)
 	GetResourceHistoryCursor(string, int, bool, int) ([]*db.VersionHistory, bool, error)
 	GetResourceHistoryMaxID(int) (int, error)
+	GetResourceCheckHistory(db.SavedResource, int) ([]db.ResourceCheckResult, error)
 }
 
+// how many of the most recent check results are shown on the resource page
+const checkHistoryLimit = 10
+
 var ErrResourceConfigNotFound = errors.New("could not find resource")
 
 func FetchTemplateData(resourceDB ResourcesDB, authenticated bool, resourceName string, id int, newerResourceVersions bool) (TemplateData, error) {
@@ -100,6 +112,14 @@ func FetchTemplateData(resourceDB ResourcesDB, authenticated bool, resourceName
 
 	resource := present.Resource(configResource, config.Groups, dbResource, authenticated)
 
+	var checkHistory []db.ResourceCheckResult
+	if authenticated {
+		checkHistory, err = resourceDB.GetResourceCheckHistory(dbResource, checkHistoryLimit)
+		if err != nil {
+			return TemplateData{}, err
+		}
+	}
+
 	maxIDFromResults := maxID
 	var olderStartID int
 	var newerStartID int
@@ -111,13 +131,24 @@ func FetchTemplateData(resourceDB ResourcesDB, authenticated bool, resourceName
 		newerStartID = maxIDFromResults + 1
 	}
 
+	metadata := map[int][]present.MetadataField{}
+	for _, version := range history {
+		metadata[version.VersionedResource.ID] = present.Metadata(version.VersionedResource.Metadata)
+	}
+
 	hasNewer := maxID > maxIDFromResults
 	hasOlder := newerResourceVersions || hasNext
 	hasPagination := hasOlder || hasNewer
 
 	templateData := TemplateData{
-		Resource: resource,
-		History:  history,
+		Resource:     resource,
+		History:      history,
+		Metadata:     metadata,
+		CheckHistory: checkHistory,
+
+		LastChecked:      dbResource.LastChecked,
+		LastCheckErrored: dbResource.LastCheckErrored,
+
 		PaginationData: PaginationData{
 			HasPagination: hasPagination,
 			HasOlder:      hasOlder,
@@ -2,11 +2,13 @@ package getresource_test
 
 import (
 	"errors"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/web/getresource/fakes"
 	"github.com/concourse/atc/web/group"
@@ -144,6 +146,51 @@ var _ = Describe("FetchTemplateData", func() {
 								fakeDB.GetResourceHistoryCursorReturns(history, false, nil)
 							})
 
+							Context("when the versions have well-known metadata", func() {
+								longMessage := "a commit message whose first line goes on for much longer than anyone would care to read\n\nand a body"
+
+								BeforeEach(func() {
+									history[0].VersionedResource.Metadata = []db.MetadataField{
+										{Name: "url", Value: "https://example.com/commit/abcdef"},
+										{Name: "message", Value: longMessage},
+										{Name: "author", Value: "some-author"},
+									}
+
+									history[1].VersionedResource.Metadata = []db.MetadataField{
+										{Name: "message", Value: "short"},
+									}
+								})
+
+								It("marks up each version's metadata for display", func() {
+									templateData, err := FetchTemplateData(fakeDB, false, "resource-name", 0, false)
+									Ω(err).ShouldNot(HaveOccurred())
+
+									Ω(templateData.Metadata[90]).Should(Equal([]present.MetadataField{
+										{
+											Name:  "url",
+											Value: "https://example.com/commit/abcdef",
+											Link:  true,
+										},
+										{
+											Name:  "message",
+											Value: longMessage,
+											Short: "a commit message whose first line goes on for much longer than anyone wo…",
+										},
+										{
+											Name:  "author",
+											Value: "some-author",
+										},
+									}))
+
+									Ω(templateData.Metadata[1]).Should(Equal([]present.MetadataField{
+										{
+											Name:  "message",
+											Value: "short",
+										},
+									}))
+								})
+							})
+
 							It("does not have pagination", func() {
 								templateData, err := FetchTemplateData(fakeDB, false, "resource-name", 0, false)
 								Ω(err).ShouldNot(HaveOccurred())
@@ -158,6 +205,22 @@ var _ = Describe("FetchTemplateData", func() {
 								Ω(templateData.PaginationData.HasOlder).Should(BeFalse())
 								Ω(templateData.PaginationData.HasNewer).Should(BeFalse())
 							})
+
+							Context("when the resource has been checked", func() {
+								BeforeEach(func() {
+									resource.LastChecked = time.Unix(1434000000, 0)
+									resource.LastCheckErrored = time.Unix(1435000000, 0)
+									fakeDB.GetResourceReturns(resource, nil)
+								})
+
+								It("includes when it was last checked and when it last errored", func() {
+									templateData, err := FetchTemplateData(fakeDB, false, "resource-name", 0, false)
+									Ω(err).ShouldNot(HaveOccurred())
+
+									Ω(templateData.LastChecked).Should(Equal(time.Unix(1434000000, 0)))
+									Ω(templateData.LastCheckErrored).Should(Equal(time.Unix(1435000000, 0)))
+								})
+							})
 						})
 
 						Context("when there are more than 100 results", func() {
@@ -343,6 +406,42 @@ var _ = Describe("FetchTemplateData", func() {
 								}))
 								Ω(templateData.PaginationData.HasPagination).Should(BeTrue())
 							})
+
+							Context("when the resource has been checked", func() {
+								var checkHistory []db.ResourceCheckResult
+
+								BeforeEach(func() {
+									checkHistory = []db.ResourceCheckResult{
+										{CheckError: errors.New("a disaster!"), CheckedAt: time.Unix(2, 0)},
+										{CheckedAt: time.Unix(1, 0)},
+									}
+
+									fakeDB.GetResourceCheckHistoryReturns(checkHistory, nil)
+								})
+
+								It("includes the most recent check results", func() {
+									templateData, err := FetchTemplateData(fakeDB, authenticated, "resource-name", 0, false)
+									Ω(err).ShouldNot(HaveOccurred())
+
+									Ω(templateData.CheckHistory).Should(Equal(checkHistory))
+
+									Ω(fakeDB.GetResourceCheckHistoryCallCount()).Should(Equal(1))
+									savedResource, limit := fakeDB.GetResourceCheckHistoryArgsForCall(0)
+									Ω(savedResource).Should(Equal(resource))
+									Ω(limit).Should(Equal(10))
+								})
+							})
+
+							Context("when looking up the check history fails", func() {
+								BeforeEach(func() {
+									fakeDB.GetResourceCheckHistoryReturns(nil, errors.New("disaster"))
+								})
+
+								It("returns an error", func() {
+									_, err := FetchTemplateData(fakeDB, authenticated, "resource-name", 0, false)
+									Ω(err).Should(HaveOccurred())
+								})
+							})
 						})
 					})
 				})
@@ -379,6 +478,14 @@ var _ = Describe("FetchTemplateData", func() {
 							CheckError:     "",
 						}))
 					})
+
+					It("does not include the check history", func() {
+						templateData, err := FetchTemplateData(fakeDB, authenticated, "resource-name", 0, false)
+						Ω(err).ShouldNot(HaveOccurred())
+
+						Ω(templateData.CheckHistory).Should(BeEmpty())
+						Ω(fakeDB.GetResourceCheckHistoryCallCount()).Should(BeZero())
+					})
 				})
 			})
 		})
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -36,12 +37,14 @@ var _ = Describe("Handler", func() {
 		handler, err = web.NewHandler(
 			logger,
 			auth.NoopValidator{},
+			auth.NoopValidator{},
 			radarSchedulerFactory,
 			db,
 			pipelineDBFactory,
 			configDB,
 			"templatefixtures",
 			"../public",
+			time.Hour,
 			engine,
 		)
 		Ω(err).ShouldNot(HaveOccurred())
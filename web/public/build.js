@@ -102,6 +102,10 @@ var eventHandlers = {
         processError(data);
       },
 
+      "drain": function(data) {
+        processError(data);
+      },
+
       "status": function(data) {
         processStatus(data);
       },
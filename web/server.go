@@ -4,6 +4,7 @@ import (
 	"html/template"
 	"net/http"
 	"path/filepath"
+	"time"
 
 	"github.com/pivotal-golang/lager"
 	"github.com/tedsuo/rata"
@@ -21,6 +22,7 @@ import (
 	"github.com/concourse/atc/web/login"
 	"github.com/concourse/atc/web/pipeline"
 	"github.com/concourse/atc/web/routes"
+	"github.com/concourse/atc/web/static"
 	"github.com/concourse/atc/web/triggerbuild"
 )
 
@@ -33,17 +35,20 @@ type WebDB interface {
 
 func NewHandler(
 	logger lager.Logger,
-	validator auth.Validator,
+	readValidator auth.Validator,
+	writeValidator auth.Validator,
 	radarSchedulerFactory pipelines.RadarSchedulerFactory,
 	db WebDB,
 	pipelineDBFactory db.PipelineDBFactory,
 	configDB db.ConfigDB,
 	templatesDir, publicDir string,
+	publicMaxAge time.Duration,
 	engine engine.Engine,
 ) (http.Handler, error) {
+	assets := static.NewAssets(publicDir, publicMaxAge)
+
 	tfuncs := &templateFuncs{
-		assetsDir: publicDir,
-		assetIDs:  map[string]string{},
+		assets: assets,
 	}
 
 	funcs := template.FuncMap{
@@ -88,13 +93,8 @@ func NewHandler(
 		return nil, err
 	}
 
-	absPublicDir, err := filepath.Abs(publicDir)
-	if err != nil {
-		return nil, err
-	}
-
 	jobServer := getjob.NewServer(logger, jobTemplate)
-	resourceServer := getresource.NewServer(logger, resourceTemplate, validator)
+	resourceServer := getresource.NewServer(logger, resourceTemplate, readValidator)
 	pipelineServer := pipeline.NewServer(logger, pipelineTemplate)
 	buildServer := getbuild.NewServer(logger, buildTemplate)
 	triggerBuildServer := triggerbuild.NewServer(logger, radarSchedulerFactory)
@@ -103,7 +103,7 @@ func NewHandler(
 		// public
 		routes.Index:    index.NewHandler(logger, pipelineDBFactory, pipelineServer.GetPipeline, indexTemplate),
 		routes.Pipeline: pipelineHandlerFactory.HandlerFor(pipelineServer.GetPipeline),
-		routes.Public:   http.FileServer(http.Dir(filepath.Dir(absPublicDir))),
+		routes.Public:   http.StripPrefix("/public/", assets),
 
 		routes.GetJob: pipelineHandlerFactory.HandlerFor(jobServer.GetJob),
 
@@ -115,12 +115,12 @@ func NewHandler(
 		// private
 		routes.LogIn: auth.Handler{
 			Handler:   login.NewHandler(logger),
-			Validator: validator,
+			Validator: readValidator,
 		},
 
 		routes.TriggerBuild: auth.Handler{
 			Handler:   pipelineHandlerFactory.HandlerFor(triggerBuildServer.TriggerBuild),
-			Validator: validator,
+			Validator: writeValidator,
 		},
 	}
 
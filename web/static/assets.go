@@ -0,0 +1,153 @@
+// Package static serves the web UI's assets (JS, CSS, fonts and images).
+//
+// Templates refer to assets by fingerprinted names, which embed a hash of the
+// asset's content, e.g. main.0123456789abcdef0123456789abcdef.css. A
+// fingerprinted name only ever refers to one version of the asset, so browsers
+// can cache it for a long time; a deploy that changes the asset changes its
+// name. Assets requested by their plain names (e.g. fonts referred to from the
+// CSS) must be revalidated instead.
+package static
+
+import (
+	"crypto/md5"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var fingerprintPattern = regexp.MustCompile(`\.([0-9a-f]{32})(\.[^./]*)?$`)
+
+type Assets struct {
+	dir    string
+	maxAge time.Duration
+
+	hashes  map[string]assetHash
+	hashesL sync.Mutex
+}
+
+type assetHash struct {
+	modTime time.Time
+	hash    string
+}
+
+// NewAssets serves the assets in the given directory, letting browsers cache
+// those requested by fingerprinted name for maxAge.
+func NewAssets(dir string, maxAge time.Duration) *Assets {
+	return &Assets{
+		dir:    dir,
+		maxAge: maxAge,
+
+		hashes: map[string]assetHash{},
+	}
+}
+
+// Fingerprint returns the fingerprinted name of the asset, given its path
+// relative to the assets directory.
+func (assets *Assets) Fingerprint(asset string) (string, error) {
+	hash, err := assets.hash(asset)
+	if err != nil {
+		return "", err
+	}
+
+	ext := path.Ext(asset)
+
+	return strings.TrimSuffix(asset, ext) + "." + hash + ext, nil
+}
+
+// ServeHTTP serves the asset at the request's path, which is relative to the
+// assets directory and may be fingerprinted.
+func (assets *Assets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	asset := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+
+	var fingerprint string
+	if match := fingerprintPattern.FindStringSubmatch(asset); match != nil {
+		fingerprint = match[1]
+		asset = strings.TrimSuffix(asset, match[0]) + match[2]
+	}
+
+	file, err := os.Open(filepath.Join(assets.dir, filepath.FromSlash(asset)))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	hash, err := assets.hash(asset)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	etag := `"` + hash + `"`
+
+	w.Header().Set("ETag", etag)
+
+	if fingerprint == hash {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(assets.maxAge.Seconds())))
+	} else {
+		// either not fingerprinted, or fingerprinted for an older version of the
+		// asset; either way the name doesn't pin down the content
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+}
+
+// hash returns the hash of the asset's content, only rehashing it when the
+// file has been modified since it was last hashed.
+func (assets *Assets) hash(asset string) (string, error) {
+	file, err := os.Open(filepath.Join(assets.dir, filepath.FromSlash(asset)))
+	if err != nil {
+		return "", err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	assets.hashesL.Lock()
+	defer assets.hashesL.Unlock()
+
+	cached, found := assets.hashes[asset]
+	if found && cached.modTime.Equal(info.ModTime()) {
+		return cached.hash, nil
+	}
+
+	hasher := md5.New()
+
+	_, err = io.Copy(hasher, file)
+	if err != nil {
+		return "", err
+	}
+
+	hash := fmt.Sprintf("%x", hasher.Sum(nil))
+
+	assets.hashes[asset] = assetHash{
+		modTime: info.ModTime(),
+		hash:    hash,
+	}
+
+	return hash, nil
+}
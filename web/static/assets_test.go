@@ -0,0 +1,191 @@
+package static_test
+
+import (
+	"crypto/md5"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/web/static"
+)
+
+var _ = Describe("Assets", func() {
+	var (
+		dir    string
+		assets *static.Assets
+
+		mainHash string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "assets")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(dir, "main.css"), []byte("body {}"), 0644)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		err = os.Mkdir(filepath.Join(dir, "fonts"), 0755)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(dir, "fonts", "some.woff"), []byte("font"), 0644)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		mainHash = fmt.Sprintf("%x", md5.Sum([]byte("body {}")))
+
+		assets = static.NewAssets(dir, 24*time.Hour)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Describe("Fingerprint", func() {
+		It("puts the hash of the asset's content before its extension", func() {
+			Ω(assets.Fingerprint("main.css")).Should(Equal("main." + mainHash + ".css"))
+		})
+
+		It("fingerprints assets in subdirectories", func() {
+			fontHash := fmt.Sprintf("%x", md5.Sum([]byte("font")))
+			Ω(assets.Fingerprint("fonts/some.woff")).Should(Equal("fonts/some." + fontHash + ".woff"))
+		})
+
+		It("changes when the asset's content changes", func() {
+			before, err := assets.Fingerprint("main.css")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			err = ioutil.WriteFile(filepath.Join(dir, "main.css"), []byte("body { color: red; }"), 0644)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			later := time.Now().Add(time.Minute)
+			err = os.Chtimes(filepath.Join(dir, "main.css"), later, later)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(assets.Fingerprint("main.css")).ShouldNot(Equal(before))
+		})
+
+		It("errors when the asset does not exist", func() {
+			_, err := assets.Fingerprint("bogus.css")
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("serving", func() {
+		var (
+			path        string
+			ifNoneMatch string
+
+			recorder *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			ifNoneMatch = ""
+		})
+
+		JustBeforeEach(func() {
+			request, err := http.NewRequest("GET", "http://example.com/"+path, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			if ifNoneMatch != "" {
+				request.Header.Set("If-None-Match", ifNoneMatch)
+			}
+
+			recorder = httptest.NewRecorder()
+			assets.ServeHTTP(recorder, request)
+		})
+
+		Context("when the asset is requested by its fingerprinted name", func() {
+			BeforeEach(func() {
+				path = "main." + mainHash + ".css"
+			})
+
+			It("serves the asset", func() {
+				Ω(recorder.Code).Should(Equal(http.StatusOK))
+				Ω(recorder.Body.String()).Should(Equal("body {}"))
+				Ω(recorder.HeaderMap.Get("Content-Type")).Should(HavePrefix("text/css"))
+			})
+
+			It("lets it be cached for the max age", func() {
+				Ω(recorder.HeaderMap.Get("Cache-Control")).Should(Equal("public, max-age=86400"))
+				Ω(recorder.HeaderMap.Get("ETag")).Should(Equal(`"` + mainHash + `"`))
+			})
+		})
+
+		Context("when the asset is requested by its plain name", func() {
+			BeforeEach(func() {
+				path = "main.css"
+			})
+
+			It("serves the asset", func() {
+				Ω(recorder.Code).Should(Equal(http.StatusOK))
+				Ω(recorder.Body.String()).Should(Equal("body {}"))
+			})
+
+			It("requires it to be revalidated", func() {
+				Ω(recorder.HeaderMap.Get("Cache-Control")).Should(Equal("no-cache"))
+				Ω(recorder.HeaderMap.Get("ETag")).Should(Equal(`"` + mainHash + `"`))
+			})
+
+			Context("when the client has the current version", func() {
+				BeforeEach(func() {
+					ifNoneMatch = `"` + mainHash + `"`
+				})
+
+				It("returns 304", func() {
+					Ω(recorder.Code).Should(Equal(http.StatusNotModified))
+					Ω(recorder.Body.String()).Should(BeEmpty())
+				})
+			})
+		})
+
+		Context("when the asset is requested by a stale fingerprint", func() {
+			BeforeEach(func() {
+				path = "main.0123456789abcdef0123456789abcdef.css"
+			})
+
+			It("serves the current version, requiring it to be revalidated", func() {
+				Ω(recorder.Code).Should(Equal(http.StatusOK))
+				Ω(recorder.Body.String()).Should(Equal("body {}"))
+				Ω(recorder.HeaderMap.Get("Cache-Control")).Should(Equal("no-cache"))
+			})
+		})
+
+		Context("when the asset is in a subdirectory", func() {
+			BeforeEach(func() {
+				path = "fonts/some.woff"
+			})
+
+			It("serves it", func() {
+				Ω(recorder.Code).Should(Equal(http.StatusOK))
+				Ω(recorder.Body.String()).Should(Equal("font"))
+			})
+		})
+
+		Context("when the asset does not exist", func() {
+			BeforeEach(func() {
+				path = "bogus.css"
+			})
+
+			It("returns 404", func() {
+				Ω(recorder.Code).Should(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the path escapes the assets directory", func() {
+			BeforeEach(func() {
+				path = "../../etc/passwd"
+			})
+
+			It("returns 404", func() {
+				Ω(recorder.Code).Should(Equal(http.StatusNotFound))
+			})
+		})
+	})
+})
@@ -1,50 +1,29 @@
 package web
 
 import (
-	"crypto/md5"
 	"fmt"
-	"io"
 	"net/url"
-	"os"
-	"path/filepath"
 	"strconv"
-	"sync"
 
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/web/getresource"
 	"github.com/concourse/atc/web/routes"
+	"github.com/concourse/atc/web/static"
 	"github.com/tedsuo/rata"
 )
 
 type templateFuncs struct {
-	assetsDir string
-	assetIDs  map[string]string
-	assetsL   sync.Mutex
+	assets *static.Assets
 }
 
 func (funcs templateFuncs) asset(asset string) (string, error) {
-	funcs.assetsL.Lock()
-	defer funcs.assetsL.Unlock()
-
-	id, found := funcs.assetIDs[asset]
-	if !found {
-		hash := md5.New()
-
-		file, err := os.Open(filepath.Join(funcs.assetsDir, asset))
-		if err != nil {
-			return "", err
-		}
-
-		_, err = io.Copy(hash, file)
-		if err != nil {
-			return "", err
-		}
-
-		id = fmt.Sprintf("%x", hash.Sum(nil))
+	fingerprinted, err := funcs.assets.Fingerprint(asset)
+	if err != nil {
+		return "", err
 	}
 
-	return funcs.url("Public", asset+"?id="+id)
+	return funcs.url("Public", fingerprinted)
 }
 
 func (funcs templateFuncs) url(route string, args ...interface{}) (string, error) {
@@ -15,6 +15,10 @@ import (
 type Client interface {
 	CreateContainer(Identifier, ContainerSpec) (Container, error)
 	LookupContainer(Identifier) (Container, error)
+
+	// FindContainersByProperties returns every container with all of the
+	// given properties. The containers must be released by the caller.
+	FindContainersByProperties(map[string]string) ([]Container, error)
 }
 
 //go:generate counterfeiter . Container
@@ -88,6 +92,18 @@ const (
 	ContainerTypeTask  ContainerType = "task"
 )
 
+// WorkerGoneError is returned when the worker running a container's process
+// can no longer be reached, e.g. because it went away partway through a
+// build. It is never the process's fault.
+type WorkerGoneError struct {
+	Handle string
+	Err    error
+}
+
+func (err WorkerGoneError) Error() string {
+	return fmt.Sprintf("lost connection to the worker running container '%s': %s", err.Handle, err.Err)
+}
+
 type MultipleContainersError struct {
 	Handles []string
 }
@@ -3,6 +3,7 @@ package worker
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 type ContainerSpec interface {
@@ -13,6 +14,15 @@ type ResourceTypeContainerSpec struct {
 	Type      string
 	Ephemeral bool
 	Tags      []string
+
+	// the rootfs of a custom resource type; if empty, the worker's own image
+	// for the type is used
+	Image string
+
+	// how long Garden keeps the container around once it stops being
+	// heartbeated, e.g. because the ATC that created it went away; zero
+	// leaves it up to Garden
+	GraceTime time.Duration
 }
 
 func (spec ResourceTypeContainerSpec) Description() string {
@@ -25,6 +35,8 @@ type TaskContainerSpec struct {
 
 	Image      string
 	Privileged bool
+
+	GraceTime time.Duration
 }
 
 func (spec TaskContainerSpec) Description() string {
@@ -27,6 +27,15 @@ type FakeClient struct {
 		result1 worker.Container
 		result2 error
 	}
+	FindContainersByPropertiesStub        func(map[string]string) ([]worker.Container, error)
+	findContainersByPropertiesMutex       sync.RWMutex
+	findContainersByPropertiesArgsForCall []struct {
+		arg1 map[string]string
+	}
+	findContainersByPropertiesReturns struct {
+		result1 []worker.Container
+		result2 error
+	}
 }
 
 func (fake *FakeClient) CreateContainer(arg1 worker.Identifier, arg2 worker.ContainerSpec) (worker.Container, error) {
@@ -96,4 +105,37 @@ func (fake *FakeClient) LookupContainerReturns(result1 worker.Container, result2
 	}{result1, result2}
 }
 
+func (fake *FakeClient) FindContainersByProperties(arg1 map[string]string) ([]worker.Container, error) {
+	fake.findContainersByPropertiesMutex.Lock()
+	fake.findContainersByPropertiesArgsForCall = append(fake.findContainersByPropertiesArgsForCall, struct {
+		arg1 map[string]string
+	}{arg1})
+	fake.findContainersByPropertiesMutex.Unlock()
+	if fake.FindContainersByPropertiesStub != nil {
+		return fake.FindContainersByPropertiesStub(arg1)
+	} else {
+		return fake.findContainersByPropertiesReturns.result1, fake.findContainersByPropertiesReturns.result2
+	}
+}
+
+func (fake *FakeClient) FindContainersByPropertiesCallCount() int {
+	fake.findContainersByPropertiesMutex.RLock()
+	defer fake.findContainersByPropertiesMutex.RUnlock()
+	return len(fake.findContainersByPropertiesArgsForCall)
+}
+
+func (fake *FakeClient) FindContainersByPropertiesArgsForCall(i int) map[string]string {
+	fake.findContainersByPropertiesMutex.RLock()
+	defer fake.findContainersByPropertiesMutex.RUnlock()
+	return fake.findContainersByPropertiesArgsForCall[i].arg1
+}
+
+func (fake *FakeClient) FindContainersByPropertiesReturns(result1 []worker.Container, result2 error) {
+	fake.FindContainersByPropertiesStub = nil
+	fake.findContainersByPropertiesReturns = struct {
+		result1 []worker.Container
+		result2 error
+	}{result1, result2}
+}
+
 var _ worker.Client = new(FakeClient)
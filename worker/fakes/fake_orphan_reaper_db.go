@@ -0,0 +1,88 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/worker"
+)
+
+type FakeOrphanReaperDB struct {
+	GetBuildStub        func(buildID int) (db.Build, error)
+	getBuildMutex       sync.RWMutex
+	getBuildArgsForCall []struct {
+		buildID int
+	}
+	getBuildReturns struct {
+		result1 db.Build
+		result2 error
+	}
+	GetAllActivePipelinesStub        func() ([]db.SavedPipeline, error)
+	getAllActivePipelinesMutex       sync.RWMutex
+	getAllActivePipelinesArgsForCall []struct{}
+	getAllActivePipelinesReturns     struct {
+		result1 []db.SavedPipeline
+		result2 error
+	}
+}
+
+func (fake *FakeOrphanReaperDB) GetBuild(buildID int) (db.Build, error) {
+	fake.getBuildMutex.Lock()
+	fake.getBuildArgsForCall = append(fake.getBuildArgsForCall, struct {
+		buildID int
+	}{buildID})
+	fake.getBuildMutex.Unlock()
+	if fake.GetBuildStub != nil {
+		return fake.GetBuildStub(buildID)
+	} else {
+		return fake.getBuildReturns.result1, fake.getBuildReturns.result2
+	}
+}
+
+func (fake *FakeOrphanReaperDB) GetBuildCallCount() int {
+	fake.getBuildMutex.RLock()
+	defer fake.getBuildMutex.RUnlock()
+	return len(fake.getBuildArgsForCall)
+}
+
+func (fake *FakeOrphanReaperDB) GetBuildArgsForCall(i int) int {
+	fake.getBuildMutex.RLock()
+	defer fake.getBuildMutex.RUnlock()
+	return fake.getBuildArgsForCall[i].buildID
+}
+
+func (fake *FakeOrphanReaperDB) GetBuildReturns(result1 db.Build, result2 error) {
+	fake.GetBuildStub = nil
+	fake.getBuildReturns = struct {
+		result1 db.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOrphanReaperDB) GetAllActivePipelines() ([]db.SavedPipeline, error) {
+	fake.getAllActivePipelinesMutex.Lock()
+	fake.getAllActivePipelinesArgsForCall = append(fake.getAllActivePipelinesArgsForCall, struct{}{})
+	fake.getAllActivePipelinesMutex.Unlock()
+	if fake.GetAllActivePipelinesStub != nil {
+		return fake.GetAllActivePipelinesStub()
+	} else {
+		return fake.getAllActivePipelinesReturns.result1, fake.getAllActivePipelinesReturns.result2
+	}
+}
+
+func (fake *FakeOrphanReaperDB) GetAllActivePipelinesCallCount() int {
+	fake.getAllActivePipelinesMutex.RLock()
+	defer fake.getAllActivePipelinesMutex.RUnlock()
+	return len(fake.getAllActivePipelinesArgsForCall)
+}
+
+func (fake *FakeOrphanReaperDB) GetAllActivePipelinesReturns(result1 []db.SavedPipeline, result2 error) {
+	fake.GetAllActivePipelinesStub = nil
+	fake.getAllActivePipelinesReturns = struct {
+		result1 []db.SavedPipeline
+		result2 error
+	}{result1, result2}
+}
+
+var _ worker.OrphanReaperDB = new(FakeOrphanReaperDB)
@@ -27,6 +27,15 @@ type FakeWorker struct {
 		result1 worker.Container
 		result2 error
 	}
+	FindContainersByPropertiesStub        func(map[string]string) ([]worker.Container, error)
+	findContainersByPropertiesMutex       sync.RWMutex
+	findContainersByPropertiesArgsForCall []struct {
+		arg1 map[string]string
+	}
+	findContainersByPropertiesReturns struct {
+		result1 []worker.Container
+		result2 error
+	}
 	ActiveContainersStub        func() int
 	activeContainersMutex       sync.RWMutex
 	activeContainersArgsForCall []struct{}
@@ -116,6 +125,39 @@ func (fake *FakeWorker) LookupContainerReturns(result1 worker.Container, result2
 	}{result1, result2}
 }
 
+func (fake *FakeWorker) FindContainersByProperties(arg1 map[string]string) ([]worker.Container, error) {
+	fake.findContainersByPropertiesMutex.Lock()
+	fake.findContainersByPropertiesArgsForCall = append(fake.findContainersByPropertiesArgsForCall, struct {
+		arg1 map[string]string
+	}{arg1})
+	fake.findContainersByPropertiesMutex.Unlock()
+	if fake.FindContainersByPropertiesStub != nil {
+		return fake.FindContainersByPropertiesStub(arg1)
+	} else {
+		return fake.findContainersByPropertiesReturns.result1, fake.findContainersByPropertiesReturns.result2
+	}
+}
+
+func (fake *FakeWorker) FindContainersByPropertiesCallCount() int {
+	fake.findContainersByPropertiesMutex.RLock()
+	defer fake.findContainersByPropertiesMutex.RUnlock()
+	return len(fake.findContainersByPropertiesArgsForCall)
+}
+
+func (fake *FakeWorker) FindContainersByPropertiesArgsForCall(i int) map[string]string {
+	fake.findContainersByPropertiesMutex.RLock()
+	defer fake.findContainersByPropertiesMutex.RUnlock()
+	return fake.findContainersByPropertiesArgsForCall[i].arg1
+}
+
+func (fake *FakeWorker) FindContainersByPropertiesReturns(result1 []worker.Container, result2 error) {
+	fake.FindContainersByPropertiesStub = nil
+	fake.findContainersByPropertiesReturns = struct {
+		result1 []worker.Container
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeWorker) ActiveContainers() int {
 	fake.activeContainersMutex.Lock()
 	fake.activeContainersArgsForCall = append(fake.activeContainersArgsForCall, struct{}{})
@@ -0,0 +1,155 @@
+package worker
+
+import (
+	"database/sql"
+	"encoding/json"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/pivotal-golang/clock"
+	"github.com/pivotal-golang/lager"
+)
+
+//go:generate counterfeiter . OrphanReaperDB
+
+type OrphanReaperDB interface {
+	GetBuild(buildID int) (db.Build, error)
+	GetAllActivePipelines() ([]db.SavedPipeline, error)
+}
+
+// OrphanReaper destroys, once on start, the containers left on the workers by
+// a previous run of the ATC that nothing will use again: those of builds that
+// no longer exist or that finished longer than the build grace time ago, and
+// those checking resources that are no longer configured with the same type
+// and source.
+//
+// Containers of builds that finished more recently are left for garden to
+// expire once their grace time is up, so that they can still be hijacked.
+// Containers that were not created by the ATC are never touched.
+type OrphanReaper struct {
+	Logger lager.Logger
+
+	Client Client
+	DB     OrphanReaperDB
+
+	BuildGraceTime time.Duration
+
+	Clock clock.Clock
+}
+
+func (reaper OrphanReaper) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	reaper.reap(reaper.Logger.Session("reap"))
+
+	<-signals
+
+	return nil
+}
+
+func (reaper OrphanReaper) reap(logger lager.Logger) {
+	containers, err := reaper.Client.FindContainersByProperties(map[string]string{})
+	if err != nil {
+		logger.Error("failed-to-find-containers", err)
+		return
+	}
+
+	pipelines, err := reaper.DB.GetAllActivePipelines()
+	if err != nil {
+		logger.Error("failed-to-get-pipelines", err)
+
+		for _, container := range containers {
+			container.Release()
+		}
+
+		return
+	}
+
+	for _, container := range containers {
+		cLog := logger.Session("container", lager.Data{"handle": container.Handle()})
+
+		orphaned, err := reaper.isOrphaned(container, pipelines)
+		if err != nil {
+			cLog.Error("failed-to-determine-owner", err)
+		}
+
+		if !orphaned {
+			container.Release()
+			continue
+		}
+
+		cLog.Info("destroying-orphan")
+
+		err = container.Destroy()
+		if err != nil {
+			cLog.Error("failed-to-destroy-orphan", err)
+		}
+	}
+}
+
+func (reaper OrphanReaper) isOrphaned(container Container, pipelines []db.SavedPipeline) (bool, error) {
+	properties, err := container.Properties()
+	if err != nil {
+		return false, err
+	}
+
+	if buildID, found := properties[propertyPrefix+"build-id"]; found {
+		return reaper.isBuildOrphaned(buildID)
+	}
+
+	if properties[propertyPrefix+"type"] == string(ContainerTypeCheck) {
+		return isCheckOrphaned(properties, pipelines), nil
+	}
+
+	return false, nil
+}
+
+func (reaper OrphanReaper) isBuildOrphaned(buildIDProp string) (bool, error) {
+	buildID, err := strconv.Atoi(buildIDProp)
+	if err != nil {
+		return false, err
+	}
+
+	build, err := reaper.DB.GetBuild(buildID)
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	switch build.Status {
+	case db.StatusPending, db.StatusStarted:
+		return false, nil
+	}
+
+	return reaper.Clock.Now().Sub(build.EndTime) > reaper.BuildGraceTime, nil
+}
+
+func isCheckOrphaned(properties map[string]string, pipelines []db.SavedPipeline) bool {
+	for _, pipeline := range pipelines {
+		if pipeline.Name != properties[propertyPrefix+"pipeline-name"] {
+			continue
+		}
+
+		resource, found := pipeline.Config.Resources.Lookup(properties[propertyPrefix+"name"])
+		if !found {
+			return true
+		}
+
+		var source atc.Source
+		err := json.Unmarshal([]byte(properties[propertyPrefix+"check-source"]), &source)
+		if err != nil {
+			return true
+		}
+
+		return resource.Type != properties[propertyPrefix+"check-type"] ||
+			source.Hash() != resource.Source.Hash()
+	}
+
+	return true
+}
@@ -0,0 +1,241 @@
+package worker_test
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/cloudfoundry-incubator/garden"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	. "github.com/concourse/atc/worker"
+	"github.com/concourse/atc/worker/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/clock/fakeclock"
+	"github.com/pivotal-golang/lager/lagertest"
+	"github.com/tedsuo/ifrit"
+)
+
+var _ = Describe("OrphanReaper", func() {
+	var (
+		fakeClient *fakes.FakeClient
+		fakeDB     *fakes.FakeOrphanReaperDB
+		fakeClock  *fakeclock.FakeClock
+
+		builds map[int]db.Build
+
+		containers []*fakes.FakeContainer
+		findErr    error
+
+		process ifrit.Process
+	)
+
+	container := func(properties garden.Properties) *fakes.FakeContainer {
+		container := new(fakes.FakeContainer)
+		container.HandleReturns("some-handle")
+		container.PropertiesReturns(properties, nil)
+		containers = append(containers, container)
+		return container
+	}
+
+	destroyed := func(container *fakes.FakeContainer) bool {
+		return container.DestroyCallCount() == 1
+	}
+
+	kept := func(container *fakes.FakeContainer) bool {
+		return container.DestroyCallCount() == 0 && container.ReleaseCallCount() == 1
+	}
+
+	BeforeEach(func() {
+		fakeClient = new(fakes.FakeClient)
+		fakeDB = new(fakes.FakeOrphanReaperDB)
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0).Add(24 * time.Hour))
+
+		containers = nil
+		findErr = nil
+
+		builds = map[int]db.Build{
+			1: {ID: 1, Status: db.StatusStarted},
+			2: {ID: 2, Status: db.StatusSucceeded, EndTime: fakeClock.Now().Add(-time.Minute)},
+			3: {ID: 3, Status: db.StatusFailed, EndTime: fakeClock.Now().Add(-2 * time.Hour)},
+		}
+
+		fakeDB.GetBuildStub = func(buildID int) (db.Build, error) {
+			build, found := builds[buildID]
+			if !found {
+				return db.Build{}, sql.ErrNoRows
+			}
+
+			return build, nil
+		}
+
+		fakeDB.GetAllActivePipelinesReturns([]db.SavedPipeline{
+			{
+				Pipeline: db.Pipeline{
+					Name: "some-pipeline",
+					Config: atc.Config{
+						Resources: atc.ResourceConfigs{
+							{
+								Name:   "some-resource",
+								Type:   "git",
+								Source: atc.Source{"uri": "https://example.com/repo"},
+							},
+						},
+					},
+				},
+			},
+		}, nil)
+	})
+
+	JustBeforeEach(func() {
+		workerContainers := make([]Container, len(containers))
+		for i, c := range containers {
+			workerContainers[i] = c
+		}
+
+		fakeClient.FindContainersByPropertiesReturns(workerContainers, findErr)
+
+		process = ifrit.Invoke(OrphanReaper{
+			Logger: lagertest.NewTestLogger("test"),
+
+			Client: fakeClient,
+			DB:     fakeDB,
+
+			BuildGraceTime: time.Hour,
+
+			Clock: fakeClock,
+		})
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive())
+	})
+
+	It("looks through every container", func() {
+		Eventually(fakeClient.FindContainersByPropertiesCallCount).Should(Equal(1))
+		Ω(fakeClient.FindContainersByPropertiesArgsForCall(0)).Should(BeEmpty())
+	})
+
+	Describe("build containers", func() {
+		var (
+			runningBuildContainer     *fakes.FakeContainer
+			recentlyFinishedContainer *fakes.FakeContainer
+			longFinishedContainer     *fakes.FakeContainer
+			nonexistentBuildContainer *fakes.FakeContainer
+		)
+
+		BeforeEach(func() {
+			runningBuildContainer = container(garden.Properties{"concourse:build-id": "1", "concourse:type": "task"})
+			recentlyFinishedContainer = container(garden.Properties{"concourse:build-id": "2", "concourse:type": "get"})
+			longFinishedContainer = container(garden.Properties{"concourse:build-id": "3", "concourse:type": "put"})
+			nonexistentBuildContainer = container(garden.Properties{"concourse:build-id": "4", "concourse:type": "task"})
+		})
+
+		It("keeps the containers of running builds", func() {
+			Eventually(func() bool { return kept(runningBuildContainer) }).Should(BeTrue())
+		})
+
+		It("keeps the containers of builds that finished within the grace time", func() {
+			Eventually(func() bool { return kept(recentlyFinishedContainer) }).Should(BeTrue())
+		})
+
+		It("destroys the containers of builds that finished before the grace time", func() {
+			Eventually(func() bool { return destroyed(longFinishedContainer) }).Should(BeTrue())
+		})
+
+		It("destroys the containers of builds that no longer exist", func() {
+			Eventually(func() bool { return destroyed(nonexistentBuildContainer) }).Should(BeTrue())
+		})
+
+		Context("when looking up a build fails", func() {
+			BeforeEach(func() {
+				fakeDB.GetBuildReturns(db.Build{}, errors.New("disaster"))
+				fakeDB.GetBuildStub = nil
+			})
+
+			It("keeps its containers", func() {
+				Eventually(func() bool { return kept(longFinishedContainer) }).Should(BeTrue())
+				Eventually(func() bool { return kept(nonexistentBuildContainer) }).Should(BeTrue())
+			})
+		})
+	})
+
+	Describe("check containers", func() {
+		checkContainer := func(pipeline string, name string, typ string, source string) *fakes.FakeContainer {
+			return container(garden.Properties{
+				"concourse:type":          "check",
+				"concourse:pipeline-name": pipeline,
+				"concourse:name":          name,
+				"concourse:check-type":    typ,
+				"concourse:check-source":  source,
+			})
+		}
+
+		var (
+			configuredContainer      *fakes.FakeContainer
+			changedSourceContainer   *fakes.FakeContainer
+			changedTypeContainer     *fakes.FakeContainer
+			removedResourceContainer *fakes.FakeContainer
+			removedPipelineContainer *fakes.FakeContainer
+		)
+
+		BeforeEach(func() {
+			configuredContainer = checkContainer("some-pipeline", "some-resource", "git", `{"uri":"https://example.com/repo"}`)
+			changedSourceContainer = checkContainer("some-pipeline", "some-resource", "git", `{"uri":"https://example.com/old-repo"}`)
+			changedTypeContainer = checkContainer("some-pipeline", "some-resource", "hg", `{"uri":"https://example.com/repo"}`)
+			removedResourceContainer = checkContainer("some-pipeline", "some-other-resource", "git", `{"uri":"https://example.com/repo"}`)
+			removedPipelineContainer = checkContainer("some-other-pipeline", "some-resource", "git", `{"uri":"https://example.com/repo"}`)
+		})
+
+		It("keeps the containers of configured resources", func() {
+			Eventually(func() bool { return kept(configuredContainer) }).Should(BeTrue())
+		})
+
+		It("destroys the containers of resources whose source or type changed", func() {
+			Eventually(func() bool { return destroyed(changedSourceContainer) }).Should(BeTrue())
+			Eventually(func() bool { return destroyed(changedTypeContainer) }).Should(BeTrue())
+		})
+
+		It("destroys the containers of resources or pipelines that were removed", func() {
+			Eventually(func() bool { return destroyed(removedResourceContainer) }).Should(BeTrue())
+			Eventually(func() bool { return destroyed(removedPipelineContainer) }).Should(BeTrue())
+		})
+
+		Context("when getting the pipelines fails", func() {
+			BeforeEach(func() {
+				fakeDB.GetAllActivePipelinesReturns(nil, errors.New("disaster"))
+			})
+
+			It("keeps every container", func() {
+				Eventually(func() bool { return kept(removedPipelineContainer) }).Should(BeTrue())
+				Ω(destroyed(changedSourceContainer)).Should(BeFalse())
+			})
+		})
+	})
+
+	Describe("containers not created by the ATC", func() {
+		var otherContainer *fakes.FakeContainer
+
+		BeforeEach(func() {
+			otherContainer = container(garden.Properties{"some": "property"})
+		})
+
+		It("keeps them", func() {
+			Eventually(func() bool { return kept(otherContainer) }).Should(BeTrue())
+		})
+	})
+
+	Context("when finding the containers fails", func() {
+		BeforeEach(func() {
+			findErr = errors.New("disaster")
+		})
+
+		It("does not look any further, but keeps running", func() {
+			Consistently(process.Wait()).ShouldNot(Receive())
+			Ω(fakeDB.GetAllActivePipelinesCallCount()).Should(BeZero())
+		})
+	})
+})
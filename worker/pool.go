@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
 	"sync"
 	"time"
 )
@@ -71,7 +72,20 @@ func (pool *Pool) CreateContainer(id Identifier, spec ContainerSpec) (Container,
 		}
 	}
 
-	randomWorker := compatibleWorkers[pool.rand.Intn(len(compatibleWorkers))]
+	// place the container on the least-loaded worker; only pick at random
+	// between workers that are equally loaded
+	sort.Sort(byActiveContainers(compatibleWorkers))
+
+	leastActive := compatibleWorkers[0].ActiveContainers()
+
+	leastLoadedWorkers := []Worker{}
+	for _, worker := range compatibleWorkers {
+		if worker.ActiveContainers() == leastActive {
+			leastLoadedWorkers = append(leastLoadedWorkers, worker)
+		}
+	}
+
+	randomWorker := leastLoadedWorkers[pool.rand.Intn(len(leastLoadedWorkers))]
 
 	return randomWorker.CreateContainer(id, spec)
 }
@@ -148,6 +162,45 @@ func (pool *Pool) LookupContainer(id Identifier) (Container, error) {
 	}
 }
 
+// FindContainersByProperties finds the containers on every worker, skipping
+// any workers that fail to list their containers.
+func (pool *Pool) FindContainersByProperties(properties map[string]string) ([]Container, error) {
+	workers, err := pool.provider.Workers()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(workers) == 0 {
+		return nil, ErrNoWorkers
+	}
+
+	wg := new(sync.WaitGroup)
+	wg.Add(len(workers))
+
+	found := make(chan []Container, len(workers))
+
+	for _, worker := range workers {
+		go func(worker Worker) {
+			defer wg.Done()
+
+			containers, err := worker.FindContainersByProperties(properties)
+			if err == nil {
+				found <- containers
+			}
+		}(worker)
+	}
+
+	wg.Wait()
+	close(found)
+
+	containers := []Container{}
+	for workerContainers := range found {
+		containers = append(containers, workerContainers...)
+	}
+
+	return containers, nil
+}
+
 type byActiveContainers []Worker
 
 func (cs byActiveContainers) Len() int { return len(cs) }
@@ -88,17 +88,46 @@ var _ = Describe("Pool", func() {
 				Ω(workerC.SatisfiesArgsForCall(0)).Should(Equal(spec))
 			})
 
-			It("creates using a random worker", func() {
+			It("creates using the least-loaded compatible worker", func() {
 				for i := 1; i < 100; i++ { // account for initial create in JustBefore
 					createdContainer, createErr := pool.CreateContainer(id, spec)
 					Ω(createErr).ShouldNot(HaveOccurred())
 					Ω(createdContainer).Should(Equal(fakeContainer))
 				}
 
-				Ω(workerA.CreateContainerCallCount()).Should(BeNumerically("~", workerB.CreateContainerCallCount(), 50))
+				Ω(workerA.CreateContainerCallCount()).Should(BeZero())
+				Ω(workerB.CreateContainerCallCount()).Should(Equal(100))
 				Ω(workerC.CreateContainerCallCount()).Should(BeZero())
 			})
 
+			Context("when the least-loaded compatible workers are tied", func() {
+				BeforeEach(func() {
+					workerA.ActiveContainersReturns(2)
+				})
+
+				It("creates using a random one of them", func() {
+					for i := 1; i < 100; i++ { // account for initial create in JustBefore
+						createdContainer, createErr := pool.CreateContainer(id, spec)
+						Ω(createErr).ShouldNot(HaveOccurred())
+						Ω(createdContainer).Should(Equal(fakeContainer))
+					}
+
+					Ω(workerA.CreateContainerCallCount()).Should(BeNumerically("~", workerB.CreateContainerCallCount(), 50))
+					Ω(workerC.CreateContainerCallCount()).Should(BeZero())
+				})
+			})
+
+			Context("when a less-loaded worker does not satisfy the spec", func() {
+				BeforeEach(func() {
+					workerC.ActiveContainersReturns(0)
+				})
+
+				It("is not used", func() {
+					Ω(workerB.CreateContainerCallCount()).Should(Equal(1))
+					Ω(workerC.CreateContainerCallCount()).Should(BeZero())
+				})
+			})
+
 			Context("when creating the container fails", func() {
 				disaster := errors.New("nope")
 
@@ -207,6 +236,30 @@ var _ = Describe("Pool", func() {
 				})
 			})
 
+			Context("when only the second worker can locate the container", func() {
+				BeforeEach(func() {
+					workerA.LookupContainerReturns(nil, ErrContainerNotFound)
+					workerB.LookupContainerReturns(fakeContainer, nil)
+				})
+
+				It("returns the container", func() {
+					Ω(lookupErr).ShouldNot(HaveOccurred())
+					Ω(foundContainer).Should(Equal(fakeContainer))
+				})
+			})
+
+			Context("when a worker fails to look up the container", func() {
+				BeforeEach(func() {
+					workerA.LookupContainerReturns(nil, errors.New("worker unreachable"))
+					workerB.LookupContainerReturns(fakeContainer, nil)
+				})
+
+				It("returns the container found by the other worker", func() {
+					Ω(lookupErr).ShouldNot(HaveOccurred())
+					Ω(foundContainer).Should(Equal(fakeContainer))
+				})
+			})
+
 			Context("when no workers can locate the container", func() {
 				BeforeEach(func() {
 					workerA.LookupContainerReturns(nil, ErrContainerNotFound)
@@ -296,4 +349,104 @@ var _ = Describe("Pool", func() {
 			})
 		})
 	})
+
+	Describe("FindContainersByProperties", func() {
+		var (
+			properties map[string]string
+
+			foundContainers []Container
+			findErr         error
+		)
+
+		BeforeEach(func() {
+			properties = map[string]string{"concourse:build-id": "42"}
+		})
+
+		JustBeforeEach(func() {
+			foundContainers, findErr = pool.FindContainersByProperties(properties)
+		})
+
+		Context("with multiple workers", func() {
+			var (
+				workerA *fakes.FakeWorker
+				workerB *fakes.FakeWorker
+
+				taskContainer *fakes.FakeContainer
+				getContainer  *fakes.FakeContainer
+				putContainer  *fakes.FakeContainer
+			)
+
+			BeforeEach(func() {
+				workerA = new(fakes.FakeWorker)
+				workerB = new(fakes.FakeWorker)
+
+				taskContainer = new(fakes.FakeContainer)
+				getContainer = new(fakes.FakeContainer)
+				putContainer = new(fakes.FakeContainer)
+
+				workerA.FindContainersByPropertiesReturns([]Container{taskContainer, getContainer}, nil)
+				workerB.FindContainersByPropertiesReturns([]Container{putContainer}, nil)
+
+				fakeProvider.WorkersReturns([]Worker{workerA, workerB}, nil)
+			})
+
+			It("queries every worker by the given properties", func() {
+				Ω(workerA.FindContainersByPropertiesCallCount()).Should(Equal(1))
+				Ω(workerA.FindContainersByPropertiesArgsForCall(0)).Should(Equal(properties))
+
+				Ω(workerB.FindContainersByPropertiesCallCount()).Should(Equal(1))
+				Ω(workerB.FindContainersByPropertiesArgsForCall(0)).Should(Equal(properties))
+			})
+
+			It("returns the matching containers across all of them", func() {
+				Ω(findErr).ShouldNot(HaveOccurred())
+				Ω(foundContainers).Should(ConsistOf(taskContainer, getContainer, putContainer))
+			})
+
+			Context("when a worker fails to find containers", func() {
+				BeforeEach(func() {
+					workerA.FindContainersByPropertiesReturns(nil, errors.New("worker unreachable"))
+				})
+
+				It("returns the containers found by the other workers", func() {
+					Ω(findErr).ShouldNot(HaveOccurred())
+					Ω(foundContainers).Should(ConsistOf(putContainer))
+				})
+			})
+
+			Context("when no workers have matching containers", func() {
+				BeforeEach(func() {
+					workerA.FindContainersByPropertiesReturns([]Container{}, nil)
+					workerB.FindContainersByPropertiesReturns([]Container{}, nil)
+				})
+
+				It("returns none, without erroring", func() {
+					Ω(findErr).ShouldNot(HaveOccurred())
+					Ω(foundContainers).Should(BeEmpty())
+				})
+			})
+		})
+
+		Context("with no workers", func() {
+			BeforeEach(func() {
+				fakeProvider.WorkersReturns([]Worker{}, nil)
+			})
+
+			It("returns ErrNoWorkers", func() {
+				Ω(findErr).Should(Equal(ErrNoWorkers))
+			})
+		})
+
+		Context("when getting the workers fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeProvider.WorkersReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				Ω(findErr).Should(Equal(disaster))
+			})
+		})
+	})
 })
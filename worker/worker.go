@@ -4,6 +4,8 @@ import (
 	"errors"
 	"expvar"
 	"fmt"
+	"io"
+	"net"
 	"strings"
 	"sync"
 	"time"
@@ -70,14 +72,21 @@ func (worker *gardenWorker) CreateContainer(id Identifier, spec ContainerSpec) (
 dance:
 	switch s := spec.(type) {
 	case ResourceTypeContainerSpec:
-		gardenSpec.Privileged = true
+		gardenSpec.GraceTime = s.GraceTime
 
 		if s.Ephemeral {
 			gardenSpec.Properties[ephemeralPropertyName] = "true"
 		}
 
+		if s.Image != "" {
+			// supplied by a pipeline, so never trusted with privileges
+			gardenSpec.RootFSPath = s.Image
+			break dance
+		}
+
 		for _, t := range worker.resourceTypes {
 			if t.Type == s.Type {
+				gardenSpec.Privileged = true
 				gardenSpec.RootFSPath = t.Image
 				break dance
 			}
@@ -88,6 +97,7 @@ dance:
 	case TaskContainerSpec:
 		gardenSpec.RootFSPath = s.Image
 		gardenSpec.Privileged = s.Privileged
+		gardenSpec.GraceTime = s.GraceTime
 
 	default:
 		return nil, fmt.Errorf("unknown container spec type: %T (%#v)", s, s)
@@ -102,7 +112,7 @@ dance:
 }
 
 func (worker *gardenWorker) LookupContainer(id Identifier) (Container, error) {
-	containers, err := worker.gardenClient.Containers(id.gardenProperties())
+	containers, err := worker.FindContainersByProperties(id.gardenProperties())
 	if err != nil {
 		return nil, err
 	}
@@ -111,12 +121,13 @@ func (worker *gardenWorker) LookupContainer(id Identifier) (Container, error) {
 	case 0:
 		return nil, ErrContainerNotFound
 	case 1:
-		return newGardenWorkerContainer(containers[0], worker.gardenClient, worker.clock), nil
+		return containers[0], nil
 	default:
 		handles := []string{}
 
 		for _, c := range containers {
 			handles = append(handles, c.Handle())
+			c.Release()
 		}
 
 		return nil, MultipleContainersError{
@@ -125,6 +136,20 @@ func (worker *gardenWorker) LookupContainer(id Identifier) (Container, error) {
 	}
 }
 
+func (worker *gardenWorker) FindContainersByProperties(properties map[string]string) ([]Container, error) {
+	gardenContainers, err := worker.gardenClient.Containers(garden.Properties(properties))
+	if err != nil {
+		return nil, err
+	}
+
+	containers := make([]Container, len(gardenContainers))
+	for i, c := range gardenContainers {
+		containers[i] = newGardenWorkerContainer(c, worker.gardenClient, worker.clock)
+	}
+
+	return containers, nil
+}
+
 func (worker *gardenWorker) ActiveContainers() int {
 	return worker.activeContainers
 }
@@ -132,6 +157,10 @@ func (worker *gardenWorker) ActiveContainers() int {
 func (worker *gardenWorker) Satisfies(spec ContainerSpec) bool {
 	switch s := spec.(type) {
 	case ResourceTypeContainerSpec:
+		if s.Image != "" {
+			return worker.tagsMatch(s.Tags)
+		}
+
 		for _, t := range worker.resourceTypes {
 			if t.Type == s.Type {
 				return worker.tagsMatch(s.Tags)
@@ -228,6 +257,52 @@ func (container *gardenWorkerContainer) Release() {
 	})
 }
 
+func (container *gardenWorkerContainer) Run(spec garden.ProcessSpec, processIO garden.ProcessIO) (garden.Process, error) {
+	process, err := container.Container.Run(spec, processIO)
+	if err != nil {
+		return nil, container.wrapConnectionError(err)
+	}
+
+	return &gardenWorkerProcess{Process: process, container: container}, nil
+}
+
+func (container *gardenWorkerContainer) Attach(processID uint32, processIO garden.ProcessIO) (garden.Process, error) {
+	process, err := container.Container.Attach(processID, processIO)
+	if err != nil {
+		return nil, container.wrapConnectionError(err)
+	}
+
+	return &gardenWorkerProcess{Process: process, container: container}, nil
+}
+
+// wrapConnectionError converts errors caused by losing the connection to
+// the worker into a WorkerGoneError, leaving any other errors alone.
+func (container *gardenWorkerContainer) wrapConnectionError(err error) error {
+	if _, ok := err.(net.Error); ok || err == io.EOF || err == io.ErrUnexpectedEOF {
+		return WorkerGoneError{Handle: container.Handle(), Err: err}
+	}
+
+	return err
+}
+
+// gardenWorkerProcess is a process running in a worker's container. Waiting
+// for it only fails if the stream of its exit status was cut off, which means
+// the worker went away.
+type gardenWorkerProcess struct {
+	garden.Process
+
+	container *gardenWorkerContainer
+}
+
+func (process *gardenWorkerProcess) Wait() (int, error) {
+	status, err := process.Process.Wait()
+	if err != nil {
+		return 0, WorkerGoneError{Handle: process.container.Handle(), Err: err}
+	}
+
+	return status, nil
+}
+
 func (container *gardenWorkerContainer) heartbeat(pacemaker clock.Ticker) {
 	defer container.heartbeating.Done()
 	defer pacemaker.Stop()
@@ -2,6 +2,8 @@ package worker_test
 
 import (
 	"errors"
+	"io"
+	"net"
 	"time"
 
 	"github.com/cloudfoundry-incubator/garden"
@@ -111,6 +113,20 @@ var _ = Describe("Worker", func() {
 						}))
 					})
 
+					Context("with a grace time", func() {
+						BeforeEach(func() {
+							spec = ResourceTypeContainerSpec{
+								Type:      "some-resource",
+								GraceTime: 5 * time.Minute,
+							}
+						})
+
+						It("creates the container with the grace time", func() {
+							Ω(fakeGardenClient.CreateCallCount()).Should(Equal(1))
+							Ω(fakeGardenClient.CreateArgsForCall(0).GraceTime).Should(Equal(5 * time.Minute))
+						})
+					})
+
 					Context("if the container is marked as ephemeral", func() {
 						BeforeEach(func() {
 							spec = ResourceTypeContainerSpec{
@@ -175,6 +191,86 @@ var _ = Describe("Worker", func() {
 
 							Consistently(fakeContainer.SetPropertyCallCount).Should(Equal(2))
 						})
+
+						Describe("running a process", func() {
+							var fakeProcess *gfakes.FakeProcess
+
+							BeforeEach(func() {
+								fakeProcess = new(gfakes.FakeProcess)
+								fakeContainer.RunReturns(fakeProcess, nil)
+								fakeContainer.AttachReturns(fakeProcess, nil)
+							})
+
+							It("returns the process's exit status", func() {
+								fakeProcess.WaitReturns(3, nil)
+
+								process, err := createdContainer.Run(garden.ProcessSpec{Path: "some-path"}, garden.ProcessIO{})
+								Ω(err).ShouldNot(HaveOccurred())
+
+								Ω(fakeContainer.RunArgsForCall(0)).Should(Equal(garden.ProcessSpec{Path: "some-path"}))
+
+								Ω(process.Wait()).Should(Equal(3))
+							})
+
+							Context("when waiting for the process fails", func() {
+								BeforeEach(func() {
+									fakeProcess.WaitReturns(0, io.ErrUnexpectedEOF)
+								})
+
+								It("returns an error saying the worker went away", func() {
+									process, err := createdContainer.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+									Ω(err).ShouldNot(HaveOccurred())
+
+									_, err = process.Wait()
+									Ω(err).Should(Equal(WorkerGoneError{
+										Handle: "some-handle",
+										Err:    io.ErrUnexpectedEOF,
+									}))
+								})
+
+								It("does the same for processes that were attached to", func() {
+									process, err := createdContainer.Attach(42, garden.ProcessIO{})
+									Ω(err).ShouldNot(HaveOccurred())
+
+									Ω(fakeContainer.AttachCallCount()).Should(Equal(1))
+									processID, _ := fakeContainer.AttachArgsForCall(0)
+									Ω(processID).Should(Equal(uint32(42)))
+
+									_, err = process.Wait()
+									Ω(err).Should(BeAssignableToTypeOf(WorkerGoneError{}))
+								})
+							})
+
+							Context("when the worker cannot be reached to run the process", func() {
+								var dialErr error
+
+								BeforeEach(func() {
+									dialErr = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+									fakeContainer.RunReturns(nil, dialErr)
+								})
+
+								It("returns an error saying the worker went away", func() {
+									_, err := createdContainer.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+									Ω(err).Should(Equal(WorkerGoneError{
+										Handle: "some-handle",
+										Err:    dialErr,
+									}))
+								})
+							})
+
+							Context("when running the process fails for any other reason", func() {
+								disaster := errors.New("oh no!")
+
+								BeforeEach(func() {
+									fakeContainer.RunReturns(nil, disaster)
+								})
+
+								It("returns the error as-is", func() {
+									_, err := createdContainer.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+									Ω(err).Should(Equal(disaster))
+								})
+							})
+						})
 					})
 				})
 
@@ -191,6 +287,33 @@ var _ = Describe("Worker", func() {
 				})
 			})
 
+			Context("when the spec has an image of its own", func() {
+				var fakeContainer *gfakes.FakeContainer
+
+				BeforeEach(func() {
+					spec = ResourceTypeContainerSpec{
+						Type:  "some-custom-resource",
+						Image: "docker:///some-custom-resource-image",
+					}
+
+					fakeContainer = new(gfakes.FakeContainer)
+					fakeGardenClient.CreateReturns(fakeContainer, nil)
+				})
+
+				It("creates the container with the image, even though the worker doesn't provide the type", func() {
+					Ω(createErr).ShouldNot(HaveOccurred())
+
+					Ω(fakeGardenClient.CreateCallCount()).Should(Equal(1))
+					Ω(fakeGardenClient.CreateArgsForCall(0).RootFSPath).Should(Equal("docker:///some-custom-resource-image"))
+				})
+
+				It("does not make the container privileged", func() {
+					Ω(createErr).ShouldNot(HaveOccurred())
+
+					Ω(fakeGardenClient.CreateArgsForCall(0).Privileged).Should(BeFalse())
+				})
+			})
+
 			Context("when the type is unknown", func() {
 				BeforeEach(func() {
 					spec = ResourceTypeContainerSpec{
@@ -243,6 +366,21 @@ var _ = Describe("Worker", func() {
 					}))
 				})
 
+				Context("with a grace time", func() {
+					BeforeEach(func() {
+						spec = TaskContainerSpec{
+							Image:      "some-image",
+							Privileged: true,
+							GraceTime:  time.Hour,
+						}
+					})
+
+					It("creates the container with the grace time", func() {
+						Ω(fakeGardenClient.CreateCallCount()).Should(Equal(1))
+						Ω(fakeGardenClient.CreateArgsForCall(0).GraceTime).Should(Equal(time.Hour))
+					})
+				})
+
 				Describe("the created container", func() {
 					It("can be destroyed", func() {
 						err := createdContainer.Destroy()
@@ -297,6 +435,78 @@ var _ = Describe("Worker", func() {
 		})
 	})
 
+	Describe("FindContainersByProperties", func() {
+		var (
+			foundContainers []Container
+			findErr         error
+		)
+
+		JustBeforeEach(func() {
+			foundContainers, findErr = worker.FindContainersByProperties(map[string]string{
+				"concourse:build-id": "42",
+			})
+		})
+
+		Context("when containers can be found", func() {
+			var fakeContainer1 *gfakes.FakeContainer
+			var fakeContainer2 *gfakes.FakeContainer
+
+			BeforeEach(func() {
+				fakeContainer1 = new(gfakes.FakeContainer)
+				fakeContainer1.HandleReturns("handle-1")
+
+				fakeContainer2 = new(gfakes.FakeContainer)
+				fakeContainer2.HandleReturns("handle-2")
+
+				fakeGardenClient.ContainersReturns([]garden.Container{fakeContainer1, fakeContainer2}, nil)
+			})
+
+			AfterEach(func() {
+				for _, container := range foundContainers {
+					container.Release()
+				}
+			})
+
+			It("looks for containers with the properties via the Garden client", func() {
+				Ω(findErr).ShouldNot(HaveOccurred())
+
+				Ω(fakeGardenClient.ContainersCallCount()).Should(Equal(1))
+				Ω(fakeGardenClient.ContainersArgsForCall(0)).Should(Equal(garden.Properties{
+					"concourse:build-id": "42",
+				}))
+			})
+
+			It("returns all of them", func() {
+				Ω(foundContainers).Should(HaveLen(2))
+				Ω(foundContainers[0].Handle()).Should(Equal("handle-1"))
+				Ω(foundContainers[1].Handle()).Should(Equal("handle-2"))
+			})
+		})
+
+		Context("when no containers can be found", func() {
+			BeforeEach(func() {
+				fakeGardenClient.ContainersReturns([]garden.Container{}, nil)
+			})
+
+			It("returns none, without erroring", func() {
+				Ω(findErr).ShouldNot(HaveOccurred())
+				Ω(foundContainers).Should(BeEmpty())
+			})
+		})
+
+		Context("when finding the containers fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeGardenClient.ContainersReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				Ω(findErr).Should(Equal(disaster))
+			})
+		})
+	})
+
 	Describe("LookupContainer", func() {
 		var (
 			id Identifier
@@ -502,6 +712,16 @@ var _ = Describe("Worker", func() {
 				It("returns false", func() {
 					Ω(satisfies).Should(BeFalse())
 				})
+
+				Context("even when all of the requested tags are present", func() {
+					BeforeEach(func() {
+						spec.Tags = []string{"some", "tags"}
+					})
+
+					It("returns false", func() {
+						Ω(satisfies).Should(BeFalse())
+					})
+				})
 			})
 		})
 
@@ -557,6 +777,35 @@ var _ = Describe("Worker", func() {
 				})
 			})
 
+			Context("when the spec has an image of its own", func() {
+				BeforeEach(func() {
+					spec = ResourceTypeContainerSpec{
+						Type:  "some-custom-resource",
+						Image: "docker:///some-custom-resource-image",
+					}
+				})
+
+				Context("when all of the requested tags are present", func() {
+					BeforeEach(func() {
+						spec.Tags = []string{"some", "tags"}
+					})
+
+					It("returns true", func() {
+						Ω(satisfies).Should(BeTrue())
+					})
+				})
+
+				Context("when any of the requested tags are not present", func() {
+					BeforeEach(func() {
+						spec.Tags = []string{"bogus", "tags"}
+					})
+
+					It("returns false", func() {
+						Ω(satisfies).Should(BeFalse())
+					})
+				})
+			})
+
 			Context("when the type is not supported by the worker", func() {
 				BeforeEach(func() {
 					spec.Type = "some-other-resource"